@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// APIError — ошибка в формате {"error": {"code", "message", "rows"}}
+type APIError struct {
+	Status  int
+	Code    string
+	Message string
+	// Rows — построчные ошибки импорта
+	Rows []RowError
+}
+
+type RowError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s: %s (HTTP %d)", e.Code, e.Message, e.Status)
+}
+
+// Client вызывает HTTP API сервиса
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+func NewClient(baseURL, token string, timeout time.Duration) *Client {
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		token:      token,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Get выполняет GET и возвращает тело ответа
+func (c *Client) Get(ctx context.Context, path string, query url.Values) ([]byte, error) {
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	return c.do(ctx, http.MethodGet, path, nil, "")
+}
+
+// Post отправляет body в JSON и возвращает тело ответа
+func (c *Client) Post(ctx context.Context, path string, body any) ([]byte, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(ctx, http.MethodPost, path, bytes.NewReader(payload), "application/json")
+}
+
+// PostRaw отправляет тело как есть с указанным Content-Type
+func (c *Client) PostRaw(ctx context.Context, path string, query url.Values, body io.Reader, contentType string) ([]byte, error) {
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	return c.do(ctx, http.MethodPost, path, body, contentType)
+}
+
+// Download выполняет GET и копирует тело ответа в w, не читая его целиком в память
+func (c *Client) Download(ctx context.Context, path string, query url.Values, w io.Writer) (int64, error) {
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	resp, err := c.send(ctx, http.MethodGet, path, nil, "")
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	return io.Copy(w, resp.Body)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body io.Reader, contentType string) ([]byte, error) {
+	resp, err := c.send(ctx, method, path, body, contentType)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+// send выполняет запрос; ответ с ошибкой сразу превращает в *APIError
+func (c *Client) send(ctx context.Context, method, path string, body io.Reader, contentType string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		return nil, parseAPIError(resp.StatusCode, data)
+	}
+	return resp, nil
+}
+
+func parseAPIError(status int, data []byte) error {
+	var body struct {
+		Error struct {
+			Code    string     `json:"code"`
+			Message string     `json:"message"`
+			Rows    []RowError `json:"rows"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(data, &body); err != nil || body.Error.Code == "" {
+		return &APIError{Status: status, Code: "HTTP_" + fmt.Sprint(status), Message: strings.TrimSpace(string(data))}
+	}
+	return &APIError{Status: status, Code: body.Error.Code, Message: body.Error.Message, Rows: body.Error.Rows}
+}
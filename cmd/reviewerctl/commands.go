@@ -0,0 +1,614 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// env — общее окружение подкоманд
+type env struct {
+	client *Client
+	out    printer
+}
+
+// command — подкоманда вида "<группа> <действие>"
+type command struct {
+	usage string
+	run   func(ctx context.Context, e env, args []string) error
+}
+
+var commands = map[string]map[string]command{
+	"team": {
+		"add":    {"team add --name NAME --member ID:USERNAME[:inactive]...", teamAdd},
+		"get":    {"team get NAME", teamGet},
+		"import": {"team import [--dry-run] [--format yaml|csv] FILE", teamImport},
+		"reconcile": {
+			"team reconcile [--apply [--plan-hash HASH]] [--deactivate-missing] [--format yaml|csv] FILE",
+			teamReconcile,
+		},
+	},
+	"user": {
+		"activate":   {"user activate USER_ID", userSetActive(true)},
+		"deactivate": {"user deactivate USER_ID", userSetActive(false)},
+	},
+	"pr": {
+		"create":   {"pr create --id ID --name NAME --author USER_ID", prCreate},
+		"merge":    {"pr merge PR_ID", prMerge},
+		"reassign": {"pr reassign PR_ID OLD_REVIEWER_ID", prReassign},
+		"list":     {"pr list --reviewer USER_ID", prList},
+	},
+	"snapshot": {
+		"export":  {"snapshot export [-f FILE]", snapshotExport},
+		"restore": {"snapshot restore [--force] FILE", snapshotRestore},
+	},
+	"stats": {
+		"": {"stats [--team NAME] [--from RFC3339] [--to RFC3339] [--bucket day|week]", stats},
+	},
+}
+
+// DTO ответов API — только поля, нужные для табличного вывода
+
+type memberDTO struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	IsActive bool   `json:"is_active"`
+}
+
+type teamDTO struct {
+	TeamName string      `json:"team_name"`
+	Members  []memberDTO `json:"members"`
+}
+
+type userDTO struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	TeamName string `json:"team_name"`
+	IsActive bool   `json:"is_active"`
+}
+
+type pullRequestDTO struct {
+	ID        string     `json:"pull_request_id"`
+	Name      string     `json:"pull_request_name"`
+	AuthorID  string     `json:"author_id"`
+	Status    string     `json:"status"`
+	Reviewers []string   `json:"assigned_reviewers"`
+	CreatedAt *time.Time `json:"created_at"`
+	MergedAt  *time.Time `json:"mergedAt"`
+}
+
+func teamAdd(ctx context.Context, e env, args []string) error {
+	fs := newFlagSet("team add")
+	name := fs.String("name", "", "team name")
+	var members memberFlags
+	fs.Var(&members, "member", "member as ID:USERNAME[:inactive], repeatable")
+	if err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+	if *name == "" {
+		return usageError{"team add: --name is required"}
+	}
+
+	raw, err := e.client.Post(ctx, "/team/add", teamDTO{TeamName: *name, Members: members})
+	if err != nil {
+		return err
+	}
+	return printTeam(e, raw)
+}
+
+func teamGet(ctx context.Context, e env, args []string) error {
+	fs := newFlagSet("team get")
+	if err := parseFlags(fs, args, 1); err != nil {
+		return err
+	}
+
+	raw, err := e.client.Get(ctx, "/team/get", url.Values{"team_name": {fs.Arg(0)}})
+	if err != nil {
+		return err
+	}
+	return printTeam(e, raw)
+}
+
+// teamImport загружает YAML или CSV в POST /team/import. Сервер проверяет документ целиком
+// и применяет его одной транзакцией; --dry-run показывает изменения без сохранения.
+func teamImport(ctx context.Context, e env, args []string) error {
+	fs := newFlagSet("team import")
+	dryRun := fs.Bool("dry-run", false, "show changes without applying them")
+	format := fs.String("format", "", "document format: yaml or csv (default: by file extension)")
+	if err := parseFlags(fs, args, 1); err != nil {
+		return err
+	}
+
+	path := fs.Arg(0)
+	contentType, err := documentContentType("team import", path, *format)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	query := url.Values{}
+	if *dryRun {
+		query.Set("dry_run", "true")
+	}
+	raw, err := e.client.PostRaw(ctx, "/team/import", query, f, contentType)
+	if err != nil {
+		return err
+	}
+	return e.out.Print(raw, func(t *tabwriter.Writer) {
+		var resp struct {
+			DryRun       bool     `json:"dry_run"`
+			TeamsCreated []string `json:"teams_created"`
+			Users        []struct {
+				UserID       string   `json:"user_id"`
+				Username     string   `json:"username"`
+				TeamName     string   `json:"team_name"`
+				Action       string   `json:"action"`
+				PreviousTeam string   `json:"previous_team"`
+				Fields       []string `json:"fields"`
+			} `json:"users"`
+		}
+		_ = json.Unmarshal(raw, &resp)
+		if resp.DryRun {
+			row(t, "DRY RUN: nothing was saved")
+			row(t)
+		}
+		if len(resp.TeamsCreated) > 0 {
+			row(t, "NEW TEAMS", strings.Join(resp.TeamsCreated, ", "))
+			row(t)
+		}
+		row(t, "USER_ID", "USERNAME", "TEAM", "ACTION", "DETAILS")
+		for _, u := range resp.Users {
+			details := strings.Join(u.Fields, ", ")
+			if u.Action == "moved" && u.PreviousTeam != "" {
+				details = strings.TrimSuffix("from "+u.PreviousTeam+"; "+details, "; ")
+			}
+			row(t, u.UserID, u.Username, u.TeamName, u.Action, details)
+		}
+	})
+}
+
+// teamReconcile приводит команды к файлу желаемого состояния. Без --apply показывает план;
+// --apply --plan-hash применяет ровно показанный план или завершается с ошибкой, если он устарел.
+func teamReconcile(ctx context.Context, e env, args []string) error {
+	fs := newFlagSet("team reconcile")
+	apply := fs.Bool("apply", false, "apply the plan instead of only showing it")
+	planHash := fs.String("plan-hash", "", "apply only if the plan still has this hash")
+	deactivateMissing := fs.Bool("deactivate-missing", false, "deactivate users missing from the file")
+	format := fs.String("format", "", "document format: yaml or csv (default: by file extension)")
+	if err := parseFlags(fs, args, 1); err != nil {
+		return err
+	}
+	if *planHash != "" && !*apply {
+		return usageError{"team reconcile: --plan-hash requires --apply"}
+	}
+
+	path := fs.Arg(0)
+	contentType, err := documentContentType("team reconcile", path, *format)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	query := url.Values{}
+	if *deactivateMissing {
+		query.Set("deactivate_missing", "true")
+	}
+	endpoint := "/team/reconcile/plan"
+	if *apply {
+		endpoint = "/team/reconcile/apply"
+		if *planHash != "" {
+			query.Set("plan_hash", *planHash)
+		}
+	}
+	raw, err := e.client.PostRaw(ctx, endpoint, query, f, contentType)
+	if err != nil {
+		return err
+	}
+	return e.out.Print(raw, func(t *tabwriter.Writer) {
+		var resp struct {
+			Applied      bool     `json:"applied"`
+			PlanHash     string   `json:"plan_hash"`
+			TeamsCreated []string `json:"teams_created"`
+			TeamsEmptied []string `json:"teams_emptied"`
+			Summary      struct {
+				Unchanged int `json:"unchanged"`
+			} `json:"summary"`
+			Changes []struct {
+				UserID       string   `json:"user_id"`
+				Username     string   `json:"username"`
+				TeamName     string   `json:"team_name"`
+				Action       string   `json:"action"`
+				PreviousTeam string   `json:"previous_team"`
+				Fields       []string `json:"fields"`
+			} `json:"changes"`
+		}
+		_ = json.Unmarshal(raw, &resp)
+		if len(resp.TeamsCreated) > 0 {
+			row(t, "NEW TEAMS", strings.Join(resp.TeamsCreated, ", "))
+		}
+		if len(resp.TeamsEmptied) > 0 {
+			row(t, "EMPTIED TEAMS", strings.Join(resp.TeamsEmptied, ", "))
+		}
+		row(t, "UNCHANGED USERS", resp.Summary.Unchanged)
+		row(t)
+		row(t, "USER_ID", "USERNAME", "ACTION", "FROM", "TO", "FIELDS")
+		for _, c := range resp.Changes {
+			row(t, c.UserID, c.Username, c.Action, c.PreviousTeam, c.TeamName, strings.Join(c.Fields, ", "))
+		}
+		row(t)
+		if resp.Applied {
+			row(t, "Applied plan "+resp.PlanHash+".")
+		} else {
+			applyCmd := "reviewerctl team reconcile --apply --plan-hash " + resp.PlanHash
+			if *deactivateMissing {
+				applyCmd += " --deactivate-missing"
+			}
+			row(t, "Plan "+resp.PlanHash+". To apply: "+applyCmd+" "+path)
+		}
+	})
+}
+
+// documentContentType выбирает Content-Type документа команд по --format или расширению файла
+func documentContentType(cmd, path, format string) (string, error) {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".csv":
+			format = "csv"
+		case ".yml", ".yaml":
+			format = "yaml"
+		default:
+			return "", usageError{cmd + ": cannot detect format from file extension, pass --format"}
+		}
+	}
+	contentType, ok := map[string]string{"yaml": "application/yaml", "csv": "text/csv"}[format]
+	if !ok {
+		return "", usageError{fmt.Sprintf("%s: unknown format %q", cmd, format)}
+	}
+	return contentType, nil
+}
+
+func userSetActive(active bool) func(ctx context.Context, e env, args []string) error {
+	return func(ctx context.Context, e env, args []string) error {
+		fs := newFlagSet("user")
+		if err := parseFlags(fs, args, 1); err != nil {
+			return err
+		}
+
+		raw, err := e.client.Post(ctx, "/users/setIsActive", map[string]any{
+			"user_id":   fs.Arg(0),
+			"is_active": active,
+		})
+		if err != nil {
+			return err
+		}
+		return e.out.Print(raw, func(t *tabwriter.Writer) {
+			var resp struct {
+				User userDTO `json:"user"`
+			}
+			_ = json.Unmarshal(raw, &resp)
+			row(t, "USER_ID", "USERNAME", "TEAM", "ACTIVE")
+			row(t, resp.User.UserID, resp.User.Username, resp.User.TeamName, resp.User.IsActive)
+		})
+	}
+}
+
+func prCreate(ctx context.Context, e env, args []string) error {
+	fs := newFlagSet("pr create")
+	id := fs.String("id", "", "pull request ID")
+	name := fs.String("name", "", "pull request name")
+	author := fs.String("author", "", "author user ID")
+	if err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+	if *id == "" || *name == "" || *author == "" {
+		return usageError{"pr create: --id, --name and --author are required"}
+	}
+
+	raw, err := e.client.Post(ctx, "/pullRequest/create", map[string]string{
+		"pull_request_id":   *id,
+		"pull_request_name": *name,
+		"author_id":         *author,
+	})
+	if err != nil {
+		return err
+	}
+	return printPR(e, raw)
+}
+
+func prMerge(ctx context.Context, e env, args []string) error {
+	fs := newFlagSet("pr merge")
+	if err := parseFlags(fs, args, 1); err != nil {
+		return err
+	}
+
+	raw, err := e.client.Post(ctx, "/pullRequest/merge", map[string]string{"pull_request_id": fs.Arg(0)})
+	if err != nil {
+		return err
+	}
+	return printPR(e, raw)
+}
+
+func prReassign(ctx context.Context, e env, args []string) error {
+	fs := newFlagSet("pr reassign")
+	if err := parseFlags(fs, args, 2); err != nil {
+		return err
+	}
+
+	raw, err := e.client.Post(ctx, "/pullRequest/reassign", map[string]string{
+		"pull_request_id": fs.Arg(0),
+		"old_reviewer_id": fs.Arg(1),
+	})
+	if err != nil {
+		return err
+	}
+	return e.out.Print(raw, func(t *tabwriter.Writer) {
+		var resp struct {
+			PR         pullRequestDTO `json:"pr"`
+			ReplacedBy string         `json:"replaced_by"`
+		}
+		_ = json.Unmarshal(raw, &resp)
+		writePR(t, resp.PR)
+		row(t, "REPLACED_BY", resp.ReplacedBy)
+	})
+}
+
+func prList(ctx context.Context, e env, args []string) error {
+	fs := newFlagSet("pr list")
+	reviewer := fs.String("reviewer", "", "reviewer user ID")
+	if err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+	if *reviewer == "" {
+		return usageError{"pr list: --reviewer is required"}
+	}
+
+	raw, err := e.client.Get(ctx, "/users/getReview", url.Values{"user_id": {*reviewer}})
+	if err != nil {
+		return err
+	}
+	return e.out.Print(raw, func(t *tabwriter.Writer) {
+		var resp struct {
+			PullRequests []pullRequestDTO `json:"pull_requests"`
+		}
+		_ = json.Unmarshal(raw, &resp)
+		row(t, "PR_ID", "NAME", "AUTHOR", "STATUS")
+		for _, pr := range resp.PullRequests {
+			row(t, pr.ID, pr.Name, pr.AuthorID, pr.Status)
+		}
+	})
+}
+
+// snapshotExport сохраняет снимок базы в файл или печатает его в stdout. Файл сначала пишется
+// во временный рядом и переименовывается после успешной загрузки, чтобы не оставить обрезанный снимок.
+func snapshotExport(ctx context.Context, e env, args []string) error {
+	fs := newFlagSet("snapshot export")
+	file := fs.String("f", "", "write the snapshot to FILE instead of stdout")
+	if err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+	if *file == "" {
+		_, err := e.client.Download(ctx, "/snapshot/export", nil, e.out.w)
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(*file), "."+filepath.Base(*file)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	size, err := e.client.Download(ctx, "/snapshot/export", nil, tmp)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), *file); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(e.out.w, "Snapshot saved to %s (%d bytes).\n", *file, size)
+	return err
+}
+
+func snapshotRestore(ctx context.Context, e env, args []string) error {
+	fs := newFlagSet("snapshot restore")
+	force := fs.Bool("force", false, "overwrite a non-empty database")
+	if err := parseFlags(fs, args, 1); err != nil {
+		return err
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	query := url.Values{}
+	if *force {
+		query.Set("force", "true")
+	}
+	raw, err := e.client.PostRaw(ctx, "/snapshot/restore", query, f, "application/x-ndjson")
+	if err != nil {
+		return err
+	}
+	return e.out.Print(raw, func(t *tabwriter.Writer) {
+		var resp struct {
+			SchemaVersion uint `json:"schema_version"`
+			Rows          int  `json:"rows"`
+			Tables        []struct {
+				Name string `json:"name"`
+				Rows int    `json:"rows"`
+			} `json:"tables"`
+		}
+		_ = json.Unmarshal(raw, &resp)
+		row(t, "TABLE", "ROWS")
+		for _, tbl := range resp.Tables {
+			row(t, tbl.Name, tbl.Rows)
+		}
+		row(t)
+		row(t, fmt.Sprintf("Restored %d rows at schema version %d.", resp.Rows, resp.SchemaVersion))
+	})
+}
+
+func stats(ctx context.Context, e env, args []string) error {
+	fs := newFlagSet("stats")
+	team := fs.String("team", "", "team name")
+	from := fs.String("from", "", "period start, RFC 3339")
+	to := fs.String("to", "", "period end, RFC 3339")
+	bucket := fs.String("bucket", "", "throughput bucket: day or week")
+	if err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	for key, value := range map[string]string{"team_name": *team, "from": *from, "to": *to, "bucket": *bucket} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	raw, err := e.client.Get(ctx, "/stats", query)
+	if err != nil {
+		return err
+	}
+	return e.out.Print(raw, func(t *tabwriter.Writer) {
+		var resp struct {
+			From        time.Time `json:"from"`
+			To          time.Time `json:"to"`
+			TimeToMerge struct {
+				Merged      int     `json:"merged"`
+				MedianHours float64 `json:"median_hours"`
+				P90Hours    float64 `json:"p90_hours"`
+			} `json:"time_to_merge"`
+			Reassignments int `json:"reassignments"`
+			Reviewers     []struct {
+				UserID         string `json:"user_id"`
+				Assigned       int    `json:"assigned"`
+				Reviewed       int    `json:"reviewed"`
+				ReassignedAway int    `json:"reassigned_away"`
+			} `json:"reviewers"`
+		}
+		_ = json.Unmarshal(raw, &resp)
+		row(t, "PERIOD", resp.From.Format(time.RFC3339)+" – "+resp.To.Format(time.RFC3339))
+		row(t, "MERGED", resp.TimeToMerge.Merged)
+		row(t, "MEDIAN_HOURS", fmt.Sprintf("%.1f", resp.TimeToMerge.MedianHours))
+		row(t, "P90_HOURS", fmt.Sprintf("%.1f", resp.TimeToMerge.P90Hours))
+		row(t, "REASSIGNMENTS", resp.Reassignments)
+		row(t)
+		row(t, "REVIEWER", "ASSIGNED", "REVIEWED", "REASSIGNED_AWAY")
+		for _, r := range resp.Reviewers {
+			row(t, r.UserID, r.Assigned, r.Reviewed, r.ReassignedAway)
+		}
+	})
+}
+
+func printTeam(e env, raw []byte) error {
+	return e.out.Print(raw, func(t *tabwriter.Writer) {
+		var resp struct {
+			Team teamDTO `json:"team"`
+		}
+		_ = json.Unmarshal(raw, &resp)
+		writeTeam(t, resp.Team)
+	})
+}
+
+func writeTeam(t *tabwriter.Writer, team teamDTO) {
+	members := append([]memberDTO(nil), team.Members...)
+	sort.Slice(members, func(i, j int) bool { return members[i].UserID < members[j].UserID })
+
+	row(t, "TEAM", team.TeamName)
+	row(t)
+	row(t, "USER_ID", "USERNAME", "ACTIVE")
+	for _, m := range members {
+		row(t, m.UserID, m.Username, m.IsActive)
+	}
+}
+
+func printPR(e env, raw []byte) error {
+	return e.out.Print(raw, func(t *tabwriter.Writer) {
+		var resp struct {
+			PR pullRequestDTO `json:"pr"`
+		}
+		_ = json.Unmarshal(raw, &resp)
+		writePR(t, resp.PR)
+	})
+}
+
+func writePR(t *tabwriter.Writer, pr pullRequestDTO) {
+	row(t, "PR_ID", pr.ID)
+	row(t, "NAME", pr.Name)
+	row(t, "AUTHOR", pr.AuthorID)
+	row(t, "STATUS", pr.Status)
+	row(t, "REVIEWERS", strings.Join(pr.Reviewers, ", "))
+	if pr.CreatedAt != nil {
+		row(t, "CREATED_AT", pr.CreatedAt.Format(time.RFC3339))
+	}
+	if pr.MergedAt != nil {
+		row(t, "MERGED_AT", pr.MergedAt.Format(time.RFC3339))
+	}
+}
+
+// memberFlags разбирает повторяемый флаг --member ID:USERNAME[:inactive]
+type memberFlags []memberDTO
+
+func (m *memberFlags) String() string { return "" }
+
+func (m *memberFlags) Set(value string) error {
+	parts := strings.Split(value, ":")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf("expected ID:USERNAME[:inactive], got %q", value)
+	}
+	member := memberDTO{UserID: parts[0], Username: parts[1], IsActive: true}
+	if len(parts) == 3 {
+		if parts[2] != "inactive" {
+			return fmt.Errorf("expected ID:USERNAME[:inactive], got %q", value)
+		}
+		member.IsActive = false
+	}
+	*m = append(*m, member)
+	return nil
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+// parseFlags разбирает флаги и проверяет число позиционных аргументов
+func parseFlags(fs *flag.FlagSet, args []string, positional int) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return usageError{fs.Name() + ": see reviewerctl help"}
+		}
+		return usageError{fs.Name() + ": " + err.Error()}
+	}
+	if fs.NArg() != positional {
+		return usageError{fmt.Sprintf("%s: expected %d argument(s), got %d", fs.Name(), positional, fs.NArg())}
+	}
+	for i := 0; i < positional; i++ {
+		if fs.Arg(i) == "" {
+			return usageError{fmt.Sprintf("%s: argument %d must not be empty", fs.Name(), i+1)}
+		}
+	}
+	return nil
+}
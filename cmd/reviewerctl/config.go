@@ -0,0 +1,52 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// cliConfig — настройки подключения к сервису
+type cliConfig struct {
+	Server string `yaml:"server"`
+	Token  string `yaml:"token"`
+}
+
+// defaultConfigPath — ~/.config/reviewerctl/config.yml
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "reviewerctl", "config.yml")
+}
+
+// loadCLIConfig читает файл настроек и переопределяет его переменными REVIEWERCTL_SERVER и REVIEWERCTL_TOKEN.
+// Отсутствие файла по умолчанию не ошибка; явно указанный файл должен существовать.
+func loadCLIConfig(path string, explicit bool) (cliConfig, error) {
+	cfg := cliConfig{Server: "http://localhost:8080"}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			if err := yaml.Unmarshal(data, &cfg); err != nil {
+				return cfg, fmt.Errorf("failed to parse %s: %w", path, err)
+			}
+		case errors.Is(err, os.ErrNotExist) && !explicit:
+		default:
+			return cfg, err
+		}
+	}
+
+	if v := os.Getenv("REVIEWERCTL_SERVER"); v != "" {
+		cfg.Server = v
+	}
+	if v := os.Getenv("REVIEWERCTL_TOKEN"); v != "" {
+		cfg.Token = v
+	}
+	return cfg, nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"errors"
+)
+
+// Коды выхода. Ошибки API различаются кодом, чтобы скрипты могли реагировать без разбора вывода.
+const (
+	exitOK           = 0
+	exitFailure      = 1 // сеть, непредвиденный ответ
+	exitUsage        = 2
+	exitInvalid      = 3  // INVALID_PARAM
+	exitUnauthorized = 4  // UNAUTHORIZED
+	exitNotFound     = 5  // NOT_FOUND
+	exitTeamExists   = 6  // TEAM_EXISTS
+	exitPRExists     = 7  // PR_EXISTS
+	exitPRMerged     = 8  // PR_MERGED
+	exitNotAssigned  = 9  // NOT_ASSIGNED
+	exitNoCandidate  = 10 // NO_CANDIDATE
+	exitConflict     = 11 // CONCURRENT_UPDATE, PRECONDITION_FAILED, IDENTITY_EXISTS, DATABASE_NOT_EMPTY, PR_CLOSED
+	exitInternal     = 12 // INTERNAL
+)
+
+var apiExitCodes = map[string]int{
+	"INVALID_PARAM":       exitInvalid,
+	"UNAUTHORIZED":        exitUnauthorized,
+	"NOT_FOUND":           exitNotFound,
+	"TEAM_EXISTS":         exitTeamExists,
+	"PR_EXISTS":           exitPRExists,
+	"PR_MERGED":           exitPRMerged,
+	"NOT_ASSIGNED":        exitNotAssigned,
+	"NO_CANDIDATE":        exitNoCandidate,
+	"CONCURRENT_UPDATE":   exitConflict,
+	"PRECONDITION_FAILED": exitConflict,
+	"IDENTITY_EXISTS":     exitConflict,
+	"DATABASE_NOT_EMPTY":  exitConflict,
+	"PR_CLOSED":           exitConflict,
+	"INTERNAL":            exitInternal,
+}
+
+// usageError — неверные аргументы командной строки
+type usageError struct{ msg string }
+
+func (e usageError) Error() string { return e.msg }
+
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+	var usage usageError
+	if errors.As(err, &usage) {
+		return exitUsage
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		if code, ok := apiExitCodes[apiErr.Code]; ok {
+			return code
+		}
+	}
+	return exitFailure
+}
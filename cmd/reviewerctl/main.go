@@ -0,0 +1,136 @@
+// reviewerctl — консольный клиент для администрирования сервиса через HTTP API.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"time"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("reviewerctl", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	configPath := fs.String("config", defaultConfigPath(), "path to CLI config file")
+	server := fs.String("server", "", "service base URL, overrides config and REVIEWERCTL_SERVER")
+	token := fs.String("token", "", "admin token, overrides config and REVIEWERCTL_TOKEN")
+	output := fs.String("o", outputTable, "output format: table or json")
+	timeout := fs.Duration("timeout", 30*time.Second, "request timeout")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			printUsage(stdout, fs)
+			return exitOK
+		}
+		fmt.Fprintln(stderr, "error:", err)
+		printUsage(stderr, fs)
+		return exitUsage
+	}
+
+	cmd, cmdArgs, err := resolveCommand(fs.Args())
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		printUsage(stderr, fs)
+		return exitUsage
+	}
+	if *output != outputTable && *output != outputJSON {
+		fmt.Fprintf(stderr, "error: unknown output format %q\n", *output)
+		return exitUsage
+	}
+
+	explicitConfig := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "config" {
+			explicitConfig = true
+		}
+	})
+	cfg, err := loadCLIConfig(*configPath, explicitConfig)
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return exitUsage
+	}
+	if *server != "" {
+		cfg.Server = *server
+	}
+	if *token != "" {
+		cfg.Token = *token
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	e := env{
+		client: NewClient(cfg.Server, cfg.Token, *timeout),
+		out:    printer{w: stdout, format: *output},
+	}
+	if err := cmd.run(ctx, e, cmdArgs); err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			for _, r := range apiErr.Rows {
+				fmt.Fprintf(stderr, "  line %d: %s\n", r.Line, r.Message)
+			}
+		}
+		var usage usageError
+		if errors.As(err, &usage) {
+			fmt.Fprintln(stderr, "usage: reviewerctl [flags]", cmd.usage)
+		}
+		return exitCode(err)
+	}
+	return exitOK
+}
+
+// resolveCommand находит подкоманду по первым аргументам
+func resolveCommand(args []string) (command, []string, error) {
+	if len(args) == 0 {
+		return command{}, nil, errors.New("missing command")
+	}
+	group, ok := commands[args[0]]
+	if !ok {
+		return command{}, nil, fmt.Errorf("unknown command %q", args[0])
+	}
+	if cmd, ok := group[""]; ok {
+		return cmd, args[1:], nil
+	}
+	if len(args) < 2 {
+		return command{}, nil, fmt.Errorf("%s: missing subcommand", args[0])
+	}
+	cmd, ok := group[args[1]]
+	if !ok {
+		return command{}, nil, fmt.Errorf("unknown command %q", args[0]+" "+args[1])
+	}
+	return cmd, args[2:], nil
+}
+
+func printUsage(w io.Writer, fs *flag.FlagSet) {
+	fmt.Fprintln(w, "usage: reviewerctl [flags] <command> [args]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+
+	var usages []string
+	for _, group := range commands {
+		for _, cmd := range group {
+			usages = append(usages, cmd.usage)
+		}
+	}
+	sort.Strings(usages)
+	for _, u := range usages {
+		fmt.Fprintln(w, "  "+u)
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "flags:")
+	fs.SetOutput(w)
+	fs.PrintDefaults()
+	fs.SetOutput(io.Discard)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// printer выводит ответ API: JSON как есть (с отступами) или таблицей
+type printer struct {
+	w      io.Writer
+	format string
+}
+
+// JSON печатает тело ответа без изменений
+func (p printer) JSON(raw []byte) error {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, raw, "", "  "); err != nil {
+		return err
+	}
+	buf.WriteByte('\n')
+	_, err := p.w.Write(buf.Bytes())
+	return err
+}
+
+// Print печатает raw в JSON-режиме или вызывает table в табличном
+func (p printer) Print(raw []byte, table func(t *tabwriter.Writer)) error {
+	if p.format == outputJSON {
+		return p.JSON(raw)
+	}
+	t := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	table(t)
+	return t.Flush()
+}
+
+// row пишет строку таблицы
+func row(t *tabwriter.Writer, cols ...any) {
+	parts := make([]string, len(cols))
+	for i, c := range cols {
+		parts[i] = fmt.Sprint(c)
+	}
+	fmt.Fprintln(t, strings.Join(parts, "\t"))
+}
@@ -2,170 +2,670 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	// Адаптеры
 	"github.com/Skorpsrgvch/reviewer-service/internal/adapter/http/middleware"
-	"github.com/Skorpsrgvch/reviewer-service/internal/adapter/postgres"
+	"github.com/Skorpsrgvch/reviewer-service/internal/adapter/notify"
+	"github.com/Skorpsrgvch/reviewer-service/internal/adapter/notify/email"
+	"github.com/Skorpsrgvch/reviewer-service/internal/adapter/notify/slack"
+	"github.com/Skorpsrgvch/reviewer-service/internal/adapter/outbox"
+	"github.com/Skorpsrgvch/reviewer-service/internal/adapter/scheduler"
+	"github.com/Skorpsrgvch/reviewer-service/internal/adapter/sse"
+	"github.com/Skorpsrgvch/reviewer-service/internal/adapter/vcs"
+	"github.com/Skorpsrgvch/reviewer-service/internal/adapter/vcs/github"
+	"github.com/Skorpsrgvch/reviewer-service/internal/adapter/vcs/gitlab"
+	"github.com/Skorpsrgvch/reviewer-service/internal/adapter/webhook"
 
 	// Юзкейсы
 	statsUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/stats/get"
+	slaReportUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/stats/slaReport"
+	teamImportUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/team/bulkImport"
 	teamCreateUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/team/create"
 	teamGetUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/team/get"
+	teamGetReviewPolicyUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/team/getReviewPolicy"
+	teamReconcileUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/team/reconcile"
+	teamSetReviewPolicyUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/team/setReviewPolicy"
+	teamSetReviewSLAUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/team/setReviewSLA"
 
+	userGetEventsUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/user/getEvents"
 	userGetReviewUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/user/getReview"
 	userSetActiveUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/user/setActive"
+	userSetEmailUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/user/setEmail"
 
+	prCloseUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/pullrequest/close"
 	prCreateUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/pullrequest/create"
+	prGetUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/pullrequest/get"
+	prListSLAViolationsUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/pullrequest/listSLAViolations"
 	prMergeUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/pullrequest/merge"
 	prReassignUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/pullrequest/reassign"
+	prRecordReviewUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/pullrequest/recordReview"
+	prRemindStaleUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/pullrequest/remindStale"
+	prReopenUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/pullrequest/reopen"
+
+	webhookCreateUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/webhook/create"
+	webhookDeleteUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/webhook/delete"
+	webhookListUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/webhook/list"
+	webhookDeliveriesUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/webhook/listDeliveries"
+
+	identityImportUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/identity/bulkImport"
+	identityCreateUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/identity/create"
+	identityDeleteUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/identity/delete"
+	identityListUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/identity/list"
+	identityLookupUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/identity/lookup"
+
+	integrationSyncUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/integration/syncPullRequest"
+
+	snapshotExportUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/snapshot/export"
+	snapshotRestoreUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/snapshot/restore"
 
 	// Хендлеры
+	"github.com/Skorpsrgvch/reviewer-service/internal/adapter/http/health"
+	identityHttp "github.com/Skorpsrgvch/reviewer-service/internal/adapter/http/identity"
+	integrationHttp "github.com/Skorpsrgvch/reviewer-service/internal/adapter/http/integration"
 	prHttp "github.com/Skorpsrgvch/reviewer-service/internal/adapter/http/pullrequest"
+	snapshotHttp "github.com/Skorpsrgvch/reviewer-service/internal/adapter/http/snapshot"
 	statsHttp "github.com/Skorpsrgvch/reviewer-service/internal/adapter/http/stats"
 	teamHttp "github.com/Skorpsrgvch/reviewer-service/internal/adapter/http/team"
 	userHttp "github.com/Skorpsrgvch/reviewer-service/internal/adapter/http/user"
+	webhookHttp "github.com/Skorpsrgvch/reviewer-service/internal/adapter/http/webhook"
+	"github.com/Skorpsrgvch/reviewer-service/internal/adapter/metrics"
+
+	// Домен
+	"github.com/Skorpsrgvch/reviewer-service/internal/config"
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
 
-	// База
-	"github.com/Skorpsrgvch/reviewer-service/pkg/db"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/logger"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/tracing"
 
 	// Миграции
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite3"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
-func runMigrations(dbURL string) error {
-	log.Println("Running migrations...")
+// runMigrations применяет миграции из sourceURL и возвращает версию схемы
+func runMigrations(sourceURL, dbURL string) (uint, error) {
+	slog.Info("Running migrations", "source", sourceURL)
 
-	m, err := migrate.New("file://migrations", dbURL)
+	m, err := migrate.New(sourceURL, dbURL)
 	if err != nil {
-		return fmt.Errorf("failed to create migrate instance: %w", err)
+		return 0, fmt.Errorf("failed to create migrate instance: %w", err)
 	}
 	defer m.Close()
 
 	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		return fmt.Errorf("failed to run migrations: %w", err)
+		return 0, fmt.Errorf("failed to run migrations: %w", err)
+	}
+	version, _, err := m.Version()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read migration version: %w", err)
 	}
 
-	log.Println("Migrations completed successfully")
-	return nil
+	slog.Info("Migrations completed successfully", "schema_version", version)
+	return version, nil
+}
+
+// purgeExpiredIdempotencyKeys периодически удаляет просроченные ключи идемпотентности
+func purgeExpiredIdempotencyKeys(ctx context.Context, repo idempotencyRepository, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := repo.DeleteExpired(ctx)
+			if err != nil {
+				logger.FromContext(ctx).Error("Failed to purge idempotency keys", "error", err)
+				continue
+			}
+			if n > 0 {
+				logger.FromContext(ctx).Info("Purged expired idempotency keys", "count", n)
+			}
+		}
+	}
+}
+
+// stalePRLockKey — ключ advisory lock задачи напоминаний о зависших PR
+const stalePRLockKey int64 = 0x7265766965770001
+
+// fatal пишет ошибку в лог и завершает процесс
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
+
+// defaultReviewPolicy — политика зависших PR для команд без своей
+func defaultReviewPolicy(cfg config.AssignmentConfig) (*domain.ReviewPolicy, error) {
+	// Лида у политики по умолчанию нет, поэтому эскалация — только переназначением
+	return domain.NewReviewPolicy("*", cfg.StaleRemindAfter, cfg.StaleEscalateAfter, domain.EscalationReassign, "")
+}
+
+// withPoller переносит настройки опроса очереди в конфигурацию диспетчера
+func withPoller(interval *time.Duration, batchSize *int, p config.PollerConfig) {
+	*interval, *batchSize = p.PollInterval, p.BatchSize
 }
 
 func main() {
 	gin.SetMode(gin.ReleaseMode)
 
-	dbURL := os.Getenv("DB_URL")
-	if dbURL == "" {
-		dbURL = "postgres://user:password@db:5432/avito?sslmode=disable"
+	cfg, opts, err := config.Load(os.Args[1:], os.Getenv)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fatal("Failed to load config", err)
+	}
+	if opts.PrintConfig {
+		if err := config.Print(os.Stdout, cfg); err != nil {
+			fatal("Failed to print config", err)
+		}
+		return
 	}
 
-	// Запускаем миграции
-	if err := runMigrations(dbURL); err != nil {
-		log.Fatalf("Migrations failed: %v", err)
+	logLevel, err := logger.ParseLevel(cfg.Log.Level)
+	if err != nil {
+		fatal("Invalid log level", err)
 	}
+	// Стандартный log из сторонних библиотек тоже пишет через slog
+	slog.SetDefault(logger.New(os.Stdout, logLevel))
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing.Exporter, cfg.Tracing.ServiceName)
+	if err != nil {
+		fatal("Failed to init tracing", err)
+	}
 
-	// Подключаемся к БД
-	dbConn, err := db.NewPostgresDB(ctx, dbURL)
+	// Хранилище выбирается схемой db.url
+	store, err := openStorage(cfg.DB)
 	if err != nil {
-		log.Fatalf("Failed to connect to DB: %v", err)
+		fatal("Failed to open storage", err)
 	}
-	defer dbConn.Close()
+	defer store.close()
+
+	// Контекст фоновых задач, отменяется при остановке
+	bgCtx, bgCancel := context.WithCancel(context.Background())
+	defer bgCancel()
 
 	// === Адаптеры (репозитории) ===
-	teamRepo := postgres.NewTeamRepo(dbConn)
-	userRepo := postgres.NewUserRepo(dbConn)
-	prRepo := postgres.NewPullRequestRepo(dbConn)
+	teamRepo := store.teams
+	userRepo := store.users
+	prRepo := store.pullRequests
+
+	statsRepo := store.stats
+	dbRepo := store.health
+	idempotencyRepo := store.idempotency
+	webhookRepo := store.webhooks
+	outboxRepo := store.outbox
+	identityRepo := store.identities
+	reviewRequestRepo := store.reviewRequests
+	notificationRepo := store.notifications
+	emailDigestRepo := store.emailDigests
+	reviewPolicyRepo := store.reviewPolicies
+	stalePRRepo := store.stalePRs
+	reviewSLARepo := store.reviewSLAs
+	snapshotRepo := store.snapshots
+
+	// === Клиенты VCS: назначенные ревьюеры запрашиваются на реальном PR ===
+	vcsClients := map[domain.VCSProvider]vcs.Client{}
+	if gh := cfg.Integrations.GitHub; gh.Token != "" {
+		vcsClients[domain.VCSGitHub] = github.NewClient(gh.APIURL, gh.Token, nil)
+	}
 
-	statsRepo := postgres.NewPullRequestRepo(dbConn)
+	// === Outbox: sink'и получают все доменные события ===
+	// EventNotifySink оповещает все реплики, чтобы они раздали событие своим SSE-клиентам
+	sinks := []outbox.Sink{webhook.NewSink(webhookRepo), store.eventSink}
+	if len(vcsClients) > 0 {
+		providers := make([]domain.VCSProvider, 0, len(vcsClients))
+		for p := range vcsClients {
+			providers = append(providers, p)
+		}
+		sinks = append(sinks, vcs.NewSink(reviewRequestRepo, providers...))
+	}
+	// === Уведомления: каналы и отправители ===
+	notifySenders := map[domain.NotificationChannel]notify.Sender{}
+	if slackWebhooks := cfg.Integrations.Slack.WebhookURLs; len(slackWebhooks) > 0 {
+		notifySenders[domain.NotificationSlack] = slack.NewSender(nil)
+		sinks = append(sinks, slack.NewSink(notificationRepo, userRepo, identityRepo, slack.DefaultTemplates(), slackWebhooks))
+	}
+	var emailDigestJob *email.DigestJob
+	if smtp := cfg.Integrations.SMTP; smtp.Host != "" {
+		emailSender, err := email.NewSender(email.SMTPConfig{
+			Host:     smtp.Host,
+			Port:     smtp.Port,
+			Username: smtp.Username,
+			Password: smtp.Password,
+			From:     smtp.From,
+			TLS:      email.TLSMode(smtp.TLS),
+		})
+		if err != nil {
+			fatal("Invalid SMTP configuration", err)
+		}
+		emailTemplates, err := email.LoadTemplates()
+		if err != nil {
+			fatal("Failed to load email templates", err)
+		}
+		notifySenders[domain.NotificationEmail] = emailSender
+		sinks = append(sinks, email.NewSink(notificationRepo, emailDigestRepo, userRepo, identityRepo, emailTemplates))
+		emailDigestJob = email.NewDigestJob(emailDigestRepo, emailTemplates, cfg.Jobs.EmailDigestHour)
+	}
+	if cfg.Jobs.OutboxLogEvents {
+		sinks = append(sinks, outbox.NewLogSink())
+	}
+	outboxConfig := outbox.DefaultConfig()
+	withPoller(&outboxConfig.PollInterval, &outboxConfig.BatchSize, cfg.Jobs.Outbox)
+	outboxRelay, err := outbox.NewRelay(outboxRepo, outboxConfig, sinks...)
+	if err != nil {
+		fatal("Failed to init outbox relay", err)
+	}
+	eventBroker := sse.NewBroker()
+	eventListener := store.eventListener
+
+	metricsRegistry := prometheus.NewRegistry()
+	metricsRegistry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		metrics.NewReviewCollector(statsRepo, cfg.Jobs.MetricsTimeout),
+		metrics.NewOutboxCollector(outboxRelay),
+	)
+	if store.sqlDB != nil {
+		metricsRegistry.MustRegister(collectors.NewDBStatsCollector(store.sqlDB, "reviewer"))
+	}
+	httpMetrics, err := metrics.NewHTTPMetrics(metricsRegistry)
+	if err != nil {
+		fatal("Failed to init HTTP metrics", err)
+	}
 
-	getStatsUC, _ := statsUC.NewUsecase(statsRepo)
+	getStatsUC, err := statsUC.NewUsecase(statsRepo)
+	if err != nil {
+		fatal("Failed to init stats usecase", err)
+	}
 	getStatsHandler := statsHttp.NewGetHandler(getStatsUC)
 
 	// === Юзкейсы ===
 	createTeamUC, err := teamCreateUC.NewUsecase(teamRepo)
 	if err != nil {
-		log.Fatalf("Failed to init createTeamUC: %v", err)
+		fatal("Failed to init createTeamUC", err)
 	}
 
 	getTeamUC, err := teamGetUC.NewUsecase(teamRepo)
 	if err != nil {
-		log.Fatalf("Failed to init getTeamUC: %v", err)
+		fatal("Failed to init getTeamUC", err)
+	}
+
+	importTeamsUC, err := teamImportUC.NewUsecase(teamRepo, userRepo)
+	if err != nil {
+		fatal("Failed to init importTeamsUC", err)
+	}
+
+	reconcileTeamsUC, err := teamReconcileUC.NewUsecase(teamRepo)
+	if err != nil {
+		fatal("Failed to init reconcileTeamsUC", err)
 	}
 
 	setActiveUC, err := userSetActiveUC.NewUsecase(userRepo, userRepo)
 	if err != nil {
-		log.Fatalf("Failed to init setActiveUC: %v", err)
+		fatal("Failed to init setActiveUC", err)
+	}
+
+	setEmailUC, err := userSetEmailUC.NewUsecase(userRepo, userRepo)
+	if err != nil {
+		fatal("Failed to init setEmailUC", err)
 	}
 
 	getReviewUC, err := userGetReviewUC.NewUsecase(prRepo, userRepo)
 	if err != nil {
-		log.Fatalf("Failed to init getReviewUC: %v", err)
+		fatal("Failed to init getReviewUC", err)
+	}
+
+	getEventsUC, err := userGetEventsUC.NewUsecase(outboxRepo, userRepo)
+	if err != nil {
+		fatal("Failed to init getEventsUC", err)
+	}
+
+	createPRUC, err := prCreateUC.NewUsecase(prRepo, userRepo, teamRepo, cfg.Assignment.ReviewersPerPR)
+	if err != nil {
+		fatal("Failed to init createPRUC", err)
 	}
 
-	createPRUC, err := prCreateUC.NewUsecase(prRepo, userRepo, teamRepo)
+	getPRUC, err := prGetUC.NewUsecase(prRepo)
 	if err != nil {
-		log.Fatalf("Failed to init createPRUC: %v", err)
+		fatal("Failed to init getPRUC", err)
 	}
 
 	mergePRUC, err := prMergeUC.NewUsecase(prRepo, prRepo)
 	if err != nil {
-		log.Fatalf("Failed to init mergePRUC: %v", err)
+		fatal("Failed to init mergePRUC", err)
+	}
+
+	closePRUC, err := prCloseUC.NewUsecase(prRepo, prRepo)
+	if err != nil {
+		fatal("Failed to init closePRUC", err)
+	}
+
+	reopenPRUC, err := prReopenUC.NewUsecase(prRepo, prRepo)
+	if err != nil {
+		fatal("Failed to init reopenPRUC", err)
 	}
 
 	reassignPRUC, err := prReassignUC.NewUsecase(prRepo, userRepo, teamRepo)
 	if err != nil {
-		log.Fatalf("Failed to init reassignPRUC: %v", err)
+		fatal("Failed to init reassignPRUC", err)
+	}
+
+	createIdentityUC, err := identityCreateUC.NewUsecase(identityRepo, userRepo)
+	if err != nil {
+		fatal("Failed to init createIdentityUC", err)
+	}
+
+	listIdentitiesUC, err := identityListUC.NewUsecase(identityRepo)
+	if err != nil {
+		fatal("Failed to init listIdentitiesUC", err)
+	}
+
+	deleteIdentityUC, err := identityDeleteUC.NewUsecase(identityRepo)
+	if err != nil {
+		fatal("Failed to init deleteIdentityUC", err)
+	}
+
+	lookupIdentityUC, err := identityLookupUC.NewUsecase(identityRepo)
+	if err != nil {
+		fatal("Failed to init lookupIdentityUC", err)
+	}
+
+	importIdentitiesUC, err := identityImportUC.NewUsecase(identityRepo, userRepo)
+	if err != nil {
+		fatal("Failed to init importIdentitiesUC", err)
+	}
+
+	defaultReviewPolicy, err := defaultReviewPolicy(cfg.Assignment)
+	if err != nil {
+		fatal("Invalid default review policy", err)
+	}
+
+	remindStaleUC, err := prRemindStaleUC.NewUsecase(stalePRRepo, prRepo, reassignPRUC, defaultReviewPolicy)
+	if err != nil {
+		fatal("Failed to init remindStaleUC", err)
+	}
+
+	setReviewPolicyUC, err := teamSetReviewPolicyUC.NewUsecase(teamRepo, userRepo, reviewPolicyRepo)
+	if err != nil {
+		fatal("Failed to init setReviewPolicyUC", err)
+	}
+
+	getReviewPolicyUC, err := teamGetReviewPolicyUC.NewUsecase(reviewPolicyRepo, teamRepo, defaultReviewPolicy)
+	if err != nil {
+		fatal("Failed to init getReviewPolicyUC", err)
+	}
+
+	recordReviewUC, err := prRecordReviewUC.NewUsecase(prRepo, prRepo)
+	if err != nil {
+		fatal("Failed to init recordReviewUC", err)
+	}
+
+	listSLAViolationsUC, err := prListSLAViolationsUC.NewUsecase(reviewSLARepo, cfg.Assignment.ReviewSLA)
+	if err != nil {
+		fatal("Failed to init listSLAViolationsUC", err)
+	}
+
+	getSLAReportUC, err := slaReportUC.NewUsecase(reviewSLARepo, cfg.Assignment.ReviewSLA)
+	if err != nil {
+		fatal("Failed to init getSLAReportUC", err)
+	}
+
+	setReviewSLAUC, err := teamSetReviewSLAUC.NewUsecase(teamRepo, reviewSLARepo)
+	if err != nil {
+		fatal("Failed to init setReviewSLAUC", err)
+	}
+
+	syncPRUC, err := integrationSyncUC.NewUsecase(userRepo, identityRepo, createPRUC, mergePRUC, closePRUC, reopenPRUC,
+		reassignPRUC, recordReviewUC)
+	if err != nil {
+		fatal("Failed to init syncPRUC", err)
+	}
+
+	createWebhookUC, err := webhookCreateUC.NewUsecase(webhookRepo)
+	if err != nil {
+		fatal("Failed to init createWebhookUC", err)
+	}
+
+	listWebhooksUC, err := webhookListUC.NewUsecase(webhookRepo)
+	if err != nil {
+		fatal("Failed to init listWebhooksUC", err)
+	}
+
+	deleteWebhookUC, err := webhookDeleteUC.NewUsecase(webhookRepo)
+	if err != nil {
+		fatal("Failed to init deleteWebhookUC", err)
+	}
+
+	listDeliveriesUC, err := webhookDeliveriesUC.NewUsecase(webhookRepo)
+	if err != nil {
+		fatal("Failed to init listDeliveriesUC", err)
+	}
+
+	exportSnapshotUC, err := snapshotExportUC.NewUsecase(snapshotRepo, dbRepo)
+	if err != nil {
+		fatal("Failed to init exportSnapshotUC", err)
+	}
+
+	restoreSnapshotUC, err := snapshotRestoreUC.NewUsecase(snapshotRepo, dbRepo)
+	if err != nil {
+		fatal("Failed to init restoreSnapshotUC", err)
 	}
 
 	// === Хендлеры ===
 	createTeamHandler := teamHttp.NewCreateHandler(createTeamUC)
+	importTeamsHandler := teamHttp.NewImportHandler(importTeamsUC)
+	reconcileTeamsHandler := teamHttp.NewReconcileHandler(reconcileTeamsUC)
 	getTeamHandler := teamHttp.NewGetHandler(getTeamUC)
+	setReviewPolicyHandler := teamHttp.NewSetReviewPolicyHandler(setReviewPolicyUC)
+	getReviewPolicyHandler := teamHttp.NewGetReviewPolicyHandler(getReviewPolicyUC)
+	setReviewSLAHandler := teamHttp.NewSetReviewSLAHandler(setReviewSLAUC)
+	slaReportHandler := statsHttp.NewSLAReportHandler(getSLAReportUC)
 
 	setActiveHandler := userHttp.NewSetActiveHandler(setActiveUC)
+	setEmailHandler := userHttp.NewSetEmailHandler(setEmailUC)
 	getReviewHandler := userHttp.NewGetReviewHandler(getReviewUC)
+	userEventsHandler := userHttp.NewEventsHandler(getEventsUC, eventBroker)
 
 	createPRHandler := prHttp.NewCreateHandler(createPRUC)
+	getPRHandler := prHttp.NewGetHandler(getPRUC)
 	mergePRHandler := prHttp.NewMergeHandler(mergePRUC)
 	reassignPRHandler := prHttp.NewReassignHandler(reassignPRUC)
+	recordReviewHandler := prHttp.NewRecordReviewHandler(recordReviewUC)
+	slaViolationsHandler := prHttp.NewSLAViolationsHandler(listSLAViolationsUC)
+
+	createWebhookHandler := webhookHttp.NewCreateHandler(createWebhookUC)
+	listWebhooksHandler := webhookHttp.NewListHandler(listWebhooksUC)
+	deleteWebhookHandler := webhookHttp.NewDeleteHandler(deleteWebhookUC)
+	listDeliveriesHandler := webhookHttp.NewDeliveriesHandler(listDeliveriesUC)
+
+	createIdentityHandler := identityHttp.NewCreateHandler(createIdentityUC)
+	listIdentitiesHandler := identityHttp.NewListHandler(listIdentitiesUC)
+	deleteIdentityHandler := identityHttp.NewDeleteHandler(deleteIdentityUC)
+	lookupIdentityHandler := identityHttp.NewLookupHandler(lookupIdentityUC)
+	importIdentitiesHandler := identityHttp.NewImportHandler(importIdentitiesUC)
+
+	exportSnapshotHandler := snapshotHttp.NewExportHandler(exportSnapshotUC)
+	restoreSnapshotHandler := snapshotHttp.NewRestoreHandler(restoreSnapshotUC)
+
+	workers := health.NewWorkers()
+	healthHandler := health.NewHandler(dbRepo, dbRepo, store.schemaVersion, workers)
 
 	// === Роутер ===
 	r := gin.New()
+	// RequestID, Tracing, AccessLog и метрики — до Recovery, чтобы запрос с паникой тоже попал
+	// в лог со своим ID и был посчитан как 500
+	r.Use(middleware.RequestIDMiddleware(slog.Default()))
+	r.Use(middleware.TracingMiddleware())
+	r.Use(middleware.AccessLogMiddleware())
+	r.Use(httpMetrics.Middleware())
 	r.Use(gin.Recovery())
 
 	adminGroup := r.Group("/")
-	adminGroup.Use(middleware.AuthMiddleware())
+	adminGroup.Use(middleware.AuthMiddleware(cfg.Auth.AdminToken))
 	{
-		adminGroup.POST("/team/add", createTeamHandler.Handle)
-
-		adminGroup.POST("/users/setIsActive", setActiveHandler.Handle)
+		adminGroup.GET("/webhooks/list", listWebhooksHandler.Handle)
+		adminGroup.GET("/webhooks/deliveries", listDeliveriesHandler.Handle)
+		adminGroup.GET("/identities/list", listIdentitiesHandler.Handle)
+		adminGroup.GET("/identities/lookup", lookupIdentityHandler.Handle)
+
+		// Снимок — это вся база: его нельзя буферизовать и хранить как ответ идемпотентности
+		adminGroup.GET("/snapshot/export", exportSnapshotHandler.Handle)
+		adminGroup.POST("/snapshot/restore", restoreSnapshotHandler.Handle)
+	}
 
-		adminGroup.POST("/pullRequest/create", createPRHandler.Handle)
-		adminGroup.POST("/pullRequest/merge", mergePRHandler.Handle)
-		adminGroup.POST("/pullRequest/reassign", reassignPRHandler.Handle)
+	// Idempotency-Key принимают только мутации: middleware держит в памяти и сохраняет
+	// тело запроса и ответа целиком
+	mutationGroup := adminGroup.Group("/")
+	mutationGroup.Use(middleware.IdempotencyMiddleware(idempotencyRepo, cfg.Server.IdempotencyTTL))
+	{
+		mutationGroup.POST("/team/add", createTeamHandler.Handle)
+		mutationGroup.POST("/team/import", importTeamsHandler.Handle)
+		mutationGroup.POST("/team/reconcile/plan", reconcileTeamsHandler.Plan)
+		mutationGroup.POST("/team/reconcile/apply", reconcileTeamsHandler.Apply)
+		mutationGroup.POST("/team/setReviewPolicy", setReviewPolicyHandler.Handle)
+		mutationGroup.POST("/team/setReviewSLA", setReviewSLAHandler.Handle)
+
+		mutationGroup.POST("/users/setIsActive", setActiveHandler.Handle)
+		mutationGroup.POST("/users/setEmail", setEmailHandler.Handle)
+
+		mutationGroup.POST("/pullRequest/create", createPRHandler.Handle)
+		mutationGroup.POST("/pullRequest/merge", mergePRHandler.Handle)
+		mutationGroup.POST("/pullRequest/reassign", reassignPRHandler.Handle)
+		mutationGroup.POST("/pullRequest/recordReview", recordReviewHandler.Handle)
+
+		mutationGroup.POST("/webhooks/add", createWebhookHandler.Handle)
+		mutationGroup.POST("/webhooks/delete", deleteWebhookHandler.Handle)
+
+		mutationGroup.POST("/identities/add", createIdentityHandler.Handle)
+		mutationGroup.POST("/identities/delete", deleteIdentityHandler.Handle)
+		mutationGroup.POST("/identities/import", importIdentitiesHandler.Handle)
 	}
+	r.GET("/healthz", healthHandler.Live)
+	r.GET("/readyz", healthHandler.Ready)
+	// Ошибка сбора доменных метрик не должна скрывать остальные
+	r.GET("/metrics", gin.WrapH(promhttp.HandlerFor(metricsRegistry, promhttp.HandlerOpts{
+		ErrorHandling: promhttp.ContinueOnError,
+	})))
 	r.GET("/stats", getStatsHandler.Handle)
+	r.GET("/stats/sla", slaReportHandler.Handle)
+	r.GET("/pullRequest/slaViolations", slaViolationsHandler.Handle)
 	r.GET("/team/get", getTeamHandler.Handle)
+	r.GET("/team/reviewPolicy", getReviewPolicyHandler.Handle)
 	r.GET("/users/getReview", getReviewHandler.Handle)
+	r.GET("/users/events", userEventsHandler.Handle)
+	r.GET("/pullRequest/get", getPRHandler.Handle)
+
+	// Интеграции с VCS аутентифицируются подписью провайдера, а не токеном админа
+	if secret := cfg.Integrations.GitHub.WebhookSecret; secret != "" {
+		githubHandler := integrationHttp.NewWebhookHandler(github.NewWebhookParser(secret), syncPRUC)
+		r.POST("/integrations/github/webhook", githubHandler.Handle)
+	} else {
+		slog.Info("GITHUB_WEBHOOK_SECRET is not set, GitHub integration is disabled")
+	}
+	if token := cfg.Integrations.GitLab.WebhookToken; token != "" {
+		gitlabHandler := integrationHttp.NewWebhookHandler(gitlab.NewWebhookParser(token), syncPRUC)
+		r.POST("/integrations/gitlab/webhook", gitlabHandler.Handle)
+	} else {
+		slog.Info("GITLAB_WEBHOOK_TOKEN is not set, GitLab integration is disabled")
+	}
+
+	// === Фоновые задачи ===
+	var bgWG sync.WaitGroup
+	runBackground := func(name string, task func(ctx context.Context)) {
+		bgWG.Add(1)
+		workers.Started(name)
+		go func() {
+			defer bgWG.Done()
+			defer workers.Stopped(name)
+			task(bgCtx)
+		}()
+	}
+
+	runBackground("idempotency-purge", func(ctx context.Context) {
+		purgeExpiredIdempotencyKeys(ctx, idempotencyRepo, cfg.Jobs.IdempotencyPurgeInterval)
+	})
+
+	runBackground("outbox-relay", outboxRelay.Run)
+
+	webhookConfig := webhook.DefaultConfig()
+	withPoller(&webhookConfig.PollInterval, &webhookConfig.BatchSize, cfg.Jobs.Webhooks)
+	webhookDispatcher := webhook.NewDispatcher(webhookRepo, webhookConfig)
+	runBackground("webhook-dispatcher", webhookDispatcher.Run)
+
+	if len(vcsClients) > 0 {
+		vcsConfig := vcs.DefaultConfig()
+		withPoller(&vcsConfig.PollInterval, &vcsConfig.BatchSize, cfg.Jobs.VCS)
+		reviewRequestDispatcher := vcs.NewDispatcher(reviewRequestRepo, identityRepo, vcsClients, vcsConfig)
+		runBackground("vcs-review-requests", reviewRequestDispatcher.Run)
+	}
+
+	if len(notifySenders) > 0 {
+		notifyConfig := notify.DefaultConfig()
+		withPoller(&notifyConfig.PollInterval, &notifyConfig.BatchSize, cfg.Jobs.Notifications)
+		notifyDispatcher := notify.NewDispatcher(notificationRepo, notifySenders, notifyConfig)
+		runBackground("notify-dispatcher", notifyDispatcher.Run)
+	}
+	if emailDigestJob != nil {
+		runBackground("email-digest", emailDigestJob.Run)
+	}
+
+	if staleCheckInterval := cfg.Jobs.StalePRCheckInterval; staleCheckInterval > 0 {
+		jobs := scheduler.New(store.locker)
+		runBackground("stale-pull-requests", func(ctx context.Context) {
+			jobs.Run(ctx, scheduler.Job{
+				Name:     "stale-pull-requests",
+				Interval: staleCheckInterval,
+				LockKey:  stalePRLockKey,
+				Run: func(ctx context.Context) error {
+					out, err := remindStaleUC.Execute(ctx, prRemindStaleUC.Input{Now: time.Now().UTC()})
+					for _, skipped := range out.Skipped {
+						logger.FromContext(ctx).Warn("Stale PR reviewer not reassigned",
+							"pull_request_id", skipped.PullRequestID, "reviewer_id", skipped.ReviewerID, "error", skipped.Err)
+					}
+					if err == nil && out.Reminded+out.Escalated > 0 {
+						logger.FromContext(ctx).Info("Stale PRs processed",
+							"reminded", out.Reminded, "escalated", out.Escalated, "reassigned", out.Reassigned)
+					}
+					return err
+				},
+			})
+		})
+	} else {
+		slog.Info("STALE_PR_CHECK_INTERVAL is 0, stale PR reminders are disabled")
+	}
+
+	runBackground("event-listener", func(ctx context.Context) {
+		eventListener.Run(ctx, eventBroker.Publish)
+	})
 
 	// Запуск сервера
-	srv := &http.Server{Addr: ":8080", Handler: r}
+	srv := &http.Server{Addr: cfg.Server.Addr, Handler: r, ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout}
+	// SSE-соединения не завершаются сами: закрываем подписки, иначе Shutdown ждал бы таймаута
+	srv.RegisterOnShutdown(eventBroker.Close)
 
 	go func() {
-		log.Println("Server starting on :8080")
+		slog.Info("Server starting", "addr", srv.Addr)
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Server failed: %v", err)
+			fatal("Server failed", err)
 		}
 	}()
 
@@ -174,13 +674,27 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
-	log.Println("Shutting down server...")
+	slog.Info("Shutting down server")
 
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	// Сначала проваливаем /readyz и ждём, пока балансировщик выведет реплику,
+	// и только потом перестаём принимать соединения
+	healthHandler.BeginShutdown()
+	time.Sleep(cfg.Server.ShutdownDrainDelay)
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 	defer shutdownCancel()
 
 	if err := srv.Shutdown(shutdownCtx); err != nil {
-		log.Fatalf("Server forced to shutdown: %v", err)
+		fatal("Server forced to shutdown", err)
+	}
+
+	// Останавливаем фоновые задачи после того, как HTTP-запросы завершились
+	bgCancel()
+	bgWG.Wait()
+
+	// Выгружаем спаны, накопленные в батчере экспортёра
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Error("Failed to flush traces", "error", err)
 	}
-	log.Println("Server exited gracefully")
+	slog.Info("Server exited gracefully")
 }
@@ -0,0 +1,371 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"time"
+
+	httpPostgres "github.com/Skorpsrgvch/reviewer-service/internal/adapter/http/postgres"
+	"github.com/Skorpsrgvch/reviewer-service/internal/adapter/memory"
+	"github.com/Skorpsrgvch/reviewer-service/internal/adapter/outbox"
+	"github.com/Skorpsrgvch/reviewer-service/internal/adapter/postgres"
+	"github.com/Skorpsrgvch/reviewer-service/internal/adapter/scheduler"
+	"github.com/Skorpsrgvch/reviewer-service/internal/adapter/sqlite"
+	"github.com/Skorpsrgvch/reviewer-service/internal/config"
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/db"
+
+	"github.com/golang-migrate/migrate/v4/source"
+)
+
+// Репозитории с полным набором методов: юзкейсы и фоновые задачи получают их
+// через свои узкие интерфейсы, а хранилище выбирается схемой db.url.
+type (
+	teamRepository interface {
+		CreateTeam(ctx context.Context, teamName string, members []domain.User) error
+		SaveTeam(ctx context.Context, team *domain.Team) error
+		ImportTeams(ctx context.Context, teams []domain.Team) error
+		ListMemberships(ctx context.Context) ([]domain.Membership, error)
+		ReconcileTeams(ctx context.Context, teams []domain.Team, removed []domain.User) error
+		TeamExists(ctx context.Context, teamName string) (bool, error)
+		FindTeamByName(ctx context.Context, teamName string) (*domain.Team, error)
+		GetUserByID(ctx context.Context, id string) (*domain.User, error)
+		CreateUser(ctx context.Context, u *domain.User) error
+		UpdateUser(ctx context.Context, u *domain.User) error
+		GetTeamByName(ctx context.Context, teamName string) (*domain.Team, error)
+		GetUsersInTeam(ctx context.Context, teamName string, onlyActive bool) ([]domain.User, error)
+	}
+
+	userRepository interface {
+		GetUserByID(ctx context.Context, id string) (*domain.User, error)
+		UpdateUserEmail(ctx context.Context, u *domain.User) error
+		CreateUser(ctx context.Context, u *domain.User) error
+		UpdateUser(ctx context.Context, u *domain.User) error
+		GetUsersInTeam(ctx context.Context, teamName string, onlyActive bool) ([]domain.User, error)
+		GetTeamByUser(ctx context.Context, userID string) (string, error)
+		ResolveUser(ctx context.Context, provider domain.VCSProvider, login string) (*domain.User, error)
+	}
+
+	pullRequestRepository interface {
+		Save(ctx context.Context, pr *domain.PullRequest) error
+		GetByID(ctx context.Context, id string) (*domain.PullRequest, error)
+		PRExists(ctx context.Context, id string) (bool, error)
+		UpdateReviewers(ctx context.Context, id string, reviewers []string, expectedVersion int) error
+		Merge(ctx context.Context, id string, mergedAt time.Time, expectedVersion int) error
+		Close(ctx context.Context, id string, closedAt time.Time, expectedVersion int) error
+		Reopen(ctx context.Context, id string, reopenedAt time.Time, expectedVersion int) error
+		GetByReviewer(ctx context.Context, reviewerID string) ([]domain.PullRequest, error)
+		RecordReviewAction(ctx context.Context, prID, reviewerID string, at time.Time) (time.Time, bool, error)
+	}
+
+	statsRepository interface {
+		GetReviewerStats(ctx context.Context, teamName string) (map[string]int, error)
+		GetTimeToMerge(ctx context.Context, f domain.StatsFilter) (domain.TimeToMerge, error)
+		GetThroughput(ctx context.Context, f domain.StatsFilter) ([]domain.ThroughputPoint, error)
+		GetReviewerLoad(ctx context.Context, f domain.StatsFilter) ([]domain.ReviewerLoad, error)
+		GetAuthorReviewerMatrix(ctx context.Context, f domain.StatsFilter) ([]domain.AuthorReviewerPair, error)
+		GetReviewSnapshot(ctx context.Context) (*domain.ReviewSnapshot, error)
+	}
+
+	healthRepository interface {
+		Ping(ctx context.Context) error
+		MigrationVersion(ctx context.Context) (version uint, dirty bool, err error)
+	}
+
+	idempotencyRepository interface {
+		domain.IdempotencyStore
+		DeleteExpired(ctx context.Context) (int64, error)
+	}
+
+	webhookRepository interface {
+		CreateSubscription(ctx context.Context, sub *domain.WebhookSubscription) (*domain.WebhookSubscription, error)
+		ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error)
+		DeleteSubscription(ctx context.Context, id int64) error
+		ListDeliveries(ctx context.Context, subscriptionID int64, limit int) ([]domain.WebhookDelivery, error)
+		EnqueueDeliveries(ctx context.Context, eventSequence int64, eventType domain.EventType) error
+		ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]domain.WebhookAttempt, error)
+		MarkDelivered(ctx context.Context, id int64, statusCode int) error
+		MarkFailed(ctx context.Context, id int64, statusCode *int, errMsg string, nextAttemptAt *time.Time) error
+	}
+
+	outboxRepository interface {
+		ClaimDueEvents(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboxAttempt, error)
+		MarkEventPublished(ctx context.Context, sequence int64) error
+		MarkEventFailed(ctx context.Context, sequence int64, errMsg string, nextAttemptAt time.Time) error
+		Backlog(ctx context.Context) (int64, *time.Time, error)
+		GetEvent(ctx context.Context, sequence int64) (*domain.Event, error)
+		LatestSequence(ctx context.Context) (int64, error)
+		ListUserEventsAfter(ctx context.Context, userID string, afterSequence int64, limit int) ([]domain.Event, error)
+	}
+
+	identityRepository interface {
+		CreateIdentity(ctx context.Context, identity *domain.UserIdentity) error
+		ListIdentities(ctx context.Context, userID, provider string) ([]domain.UserIdentity, error)
+		DeleteIdentity(ctx context.Context, provider, externalID string) error
+		LookupUser(ctx context.Context, provider, externalID string) (*domain.User, error)
+		ExternalIDForUser(ctx context.Context, provider, userID string) (string, error)
+		ImportIdentities(ctx context.Context, identities []domain.UserIdentity) (created, updated int, err error)
+	}
+
+	reviewRequestRepository interface {
+		EnqueueReviewRequests(ctx context.Context, eventSequence int64, requests []domain.VCSReviewRequestDraft) error
+		ClaimDueReviewRequests(ctx context.Context, limit int, lease time.Duration) ([]domain.VCSReviewRequest, error)
+		MarkReviewRequestDone(ctx context.Context, id int64) error
+		MarkReviewRequestFailed(ctx context.Context, id int64, errMsg string, nextAttemptAt *time.Time) error
+	}
+
+	notificationRepository interface {
+		EnqueueNotifications(ctx context.Context, eventSequence int64, drafts []domain.NotificationDraft) error
+		ClaimDueNotifications(ctx context.Context, limit int, lease time.Duration) ([]domain.Notification, error)
+		MarkNotificationSent(ctx context.Context, id int64) error
+		MarkNotificationFailed(ctx context.Context, id int64, errMsg string, nextAttemptAt *time.Time) error
+	}
+
+	emailDigestRepository interface {
+		AddDigestItem(ctx context.Context, userID string, eventSequence int64, summary string) error
+		FlushDigests(ctx context.Context, render func(batch domain.DigestBatch) (domain.NotificationDraft, error)) (int, error)
+	}
+
+	reviewPolicyRepository interface {
+		SaveReviewPolicy(ctx context.Context, p *domain.ReviewPolicy) error
+		GetReviewPolicy(ctx context.Context, teamName string) (*domain.ReviewPolicy, error)
+	}
+
+	stalePRRepository interface {
+		ListStalePullRequests(ctx context.Context, now time.Time, defaults *domain.ReviewPolicy, limit int) ([]domain.StalePullRequest, error)
+		RecordStaleStage(ctx context.Context, prID string, stage domain.StaleStage, event domain.Event) (bool, error)
+	}
+
+	reviewSLARepository interface {
+		SaveReviewSLA(ctx context.Context, sla *domain.ReviewSLA) error
+		ListSLAViolations(ctx context.Context, teamName string, now, dueBefore time.Time, defaultSLA time.Duration) ([]domain.SLAViolation, error)
+		SLAReport(ctx context.Context, teamName string, from, to, now time.Time, defaultSLA time.Duration) (*domain.SLAReport, error)
+	}
+
+	snapshotRepository interface {
+		ExportSnapshot(ctx context.Context, emit func(row domain.SnapshotRow) error) error
+		RestoreSnapshot(ctx context.Context, force bool, next func() (*domain.SnapshotRow, error)) (map[string]int, error)
+	}
+
+	eventListener interface {
+		Run(ctx context.Context, handle func(event domain.Event))
+	}
+)
+
+// storage — репозитории и инфраструктура выбранного хранилища
+type storage struct {
+	teams          teamRepository
+	users          userRepository
+	pullRequests   pullRequestRepository
+	stats          statsRepository
+	health         healthRepository
+	idempotency    idempotencyRepository
+	webhooks       webhookRepository
+	outbox         outboxRepository
+	identities     identityRepository
+	reviewRequests reviewRequestRepository
+	notifications  notificationRepository
+	emailDigests   emailDigestRepository
+	reviewPolicies reviewPolicyRepository
+	stalePRs       stalePRRepository
+	reviewSLAs     reviewSLARepository
+	snapshots      snapshotRepository
+
+	// eventSink оповещает реплики об опубликованных событиях, eventListener раздаёт их SSE-клиентам
+	eventSink     outbox.Sink
+	eventListener eventListener
+	locker        scheduler.Locker
+
+	// schemaVersion — версия схемы, с которой работает хранилище
+	schemaVersion uint
+	// sqlDB — пул соединений для метрик; nil у хранилища в памяти
+	sqlDB *sql.DB
+	close func() error
+}
+
+// openStorage выбирает хранилище по схеме db.url: memory:// — данные в памяти процесса,
+// sqlite:// — файл SQLite для одного узла, postgres:// — PostgreSQL. Миграции применяются при открытии.
+func openStorage(cfg config.DBConfig) (*storage, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid db.url: %w", err)
+	}
+	switch u.Scheme {
+	case "memory":
+		return openMemoryStorage()
+	case "sqlite", "sqlite3":
+		return openSQLiteStorage(cfg, u)
+	case "postgres", "postgresql":
+		return openPostgresStorage(cfg)
+	default:
+		return nil, fmt.Errorf("unsupported db.url scheme %q, expected postgres, sqlite or memory", u.Scheme)
+	}
+}
+
+func openPostgresStorage(cfg config.DBConfig) (*storage, error) {
+	// Запускаем миграции
+	schemaVersion, err := runMigrations("file://migrations", cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("migrations failed: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ConnectTimeout)
+	defer cancel()
+
+	// Подключаемся к БД
+	dbConn, err := db.NewPostgresDB(ctx, cfg.URL, db.PoolConfig{
+		MaxOpenConns:    cfg.MaxOpenConns,
+		MaxIdleConns:    cfg.MaxIdleConns,
+		ConnMaxLifetime: cfg.ConnMaxLifetime,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to DB: %w", err)
+	}
+
+	outboxRepo := postgres.NewOutboxRepo(dbConn)
+	return &storage{
+		teams:          postgres.NewTeamRepo(dbConn),
+		users:          postgres.NewUserRepo(dbConn),
+		pullRequests:   postgres.NewPullRequestRepo(dbConn),
+		stats:          postgres.NewStatsRepo(dbConn),
+		health:         httpPostgres.NewDBRepo(dbConn),
+		idempotency:    postgres.NewIdempotencyRepo(dbConn),
+		webhooks:       postgres.NewWebhookRepo(dbConn),
+		outbox:         outboxRepo,
+		identities:     postgres.NewIdentityRepo(dbConn),
+		reviewRequests: postgres.NewVCSReviewRequestRepo(dbConn),
+		notifications:  postgres.NewNotificationRepo(dbConn),
+		emailDigests:   postgres.NewEmailDigestRepo(dbConn),
+		reviewPolicies: postgres.NewReviewPolicyRepo(dbConn),
+		stalePRs:       postgres.NewStalePullRequestRepo(dbConn),
+		reviewSLAs:     postgres.NewReviewSLARepo(dbConn),
+		snapshots:      postgres.NewSnapshotRepo(dbConn),
+		eventSink:      postgres.NewEventNotifySink(dbConn),
+		eventListener:  postgres.NewEventListener(cfg.URL, outboxRepo),
+		locker:         postgres.NewAdvisoryLocker(dbConn),
+		schemaVersion:  schemaVersion,
+		sqlDB:          dbConn,
+		close:          dbConn.Close,
+	}, nil
+}
+
+// openSQLiteStorage открывает файл SQLite: sqlite://reviewer.db — путь относительно рабочего каталога,
+// sqlite:///var/lib/reviewer/reviewer.db — абсолютный; параметры URL передаются драйверу.
+// Блокировки планировщика и оповещения о событиях живут в процессе, поэтому файл
+// должен открывать только один экземпляр сервиса.
+func openSQLiteStorage(cfg config.DBConfig, u *url.URL) (*storage, error) {
+	path := u.Host + u.Path
+	if path == "" {
+		return nil, errors.New("db.url must contain a path to the SQLite database file")
+	}
+
+	// Запускаем миграции. Внешние ключи на время миграций выключены: пересоздание таблицы
+	// иначе каскадом удалит ссылающиеся на неё строки
+	schemaVersion, err := runMigrations("file://migrations/sqlite", "sqlite3://"+path+"?_foreign_keys=off")
+	if err != nil {
+		return nil, fmt.Errorf("migrations failed: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ConnectTimeout)
+	defer cancel()
+
+	dbConn, err := db.NewSQLiteDB(ctx, path, u.Query(), db.PoolConfig{
+		MaxOpenConns:    cfg.MaxOpenConns,
+		MaxIdleConns:    cfg.MaxIdleConns,
+		ConnMaxLifetime: cfg.ConnMaxLifetime,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite database: %w", err)
+	}
+
+	eventSink := sqlite.NewEventNotifySink()
+	return &storage{
+		teams:          sqlite.NewTeamRepo(dbConn),
+		users:          sqlite.NewUserRepo(dbConn),
+		pullRequests:   sqlite.NewPullRequestRepo(dbConn),
+		stats:          sqlite.NewStatsRepo(dbConn),
+		health:         httpPostgres.NewDBRepo(dbConn),
+		idempotency:    sqlite.NewIdempotencyRepo(dbConn),
+		webhooks:       sqlite.NewWebhookRepo(dbConn),
+		outbox:         sqlite.NewOutboxRepo(dbConn),
+		identities:     sqlite.NewIdentityRepo(dbConn),
+		reviewRequests: sqlite.NewVCSReviewRequestRepo(dbConn),
+		notifications:  sqlite.NewNotificationRepo(dbConn),
+		emailDigests:   sqlite.NewEmailDigestRepo(dbConn),
+		reviewPolicies: sqlite.NewReviewPolicyRepo(dbConn),
+		stalePRs:       sqlite.NewStalePullRequestRepo(dbConn),
+		reviewSLAs:     sqlite.NewReviewSLARepo(dbConn),
+		snapshots:      sqlite.NewSnapshotRepo(dbConn),
+		eventSink:      eventSink,
+		eventListener:  sqlite.NewEventListener(eventSink),
+		locker:         memory.NewLocker(),
+		schemaVersion:  schemaVersion,
+		sqlDB:          dbConn,
+		close:          dbConn.Close,
+	}, nil
+}
+
+// openMemoryStorage создаёт пустое хранилище в памяти. Данные теряются при остановке,
+// поэтому оно подходит только для локальной разработки и тестов; реплики его не разделяют.
+func openMemoryStorage() (*storage, error) {
+	// Версия схемы — последняя миграция: под неё написан адаптер, и её же ждут снимки базы
+	schemaVersion, err := latestMigrationVersion()
+	if err != nil {
+		return nil, err
+	}
+	slog.Warn("Using in-memory storage, data will be lost on restart", "schema_version", schemaVersion)
+
+	store := memory.NewStore(schemaVersion)
+	return &storage{
+		teams:          memory.NewTeamRepo(store),
+		users:          memory.NewUserRepo(store),
+		pullRequests:   memory.NewPullRequestRepo(store),
+		stats:          memory.NewStatsRepo(store),
+		health:         memory.NewDBRepo(store),
+		idempotency:    memory.NewIdempotencyRepo(store),
+		webhooks:       memory.NewWebhookRepo(store),
+		outbox:         memory.NewOutboxRepo(store),
+		identities:     memory.NewIdentityRepo(store),
+		reviewRequests: memory.NewVCSReviewRequestRepo(store),
+		notifications:  memory.NewNotificationRepo(store),
+		emailDigests:   memory.NewEmailDigestRepo(store),
+		reviewPolicies: memory.NewReviewPolicyRepo(store),
+		stalePRs:       memory.NewStalePullRequestRepo(store),
+		reviewSLAs:     memory.NewReviewSLARepo(store),
+		snapshots:      memory.NewSnapshotRepo(store),
+		eventSink:      memory.NewEventNotifySink(store),
+		eventListener:  memory.NewEventListener(store),
+		locker:         memory.NewLocker(),
+		schemaVersion:  schemaVersion,
+		close:          func() error { return nil },
+	}, nil
+}
+
+// latestMigrationVersion возвращает номер последней миграции из каталога migrations
+func latestMigrationVersion() (uint, error) {
+	src, err := source.Open("file://migrations")
+	if err != nil {
+		return 0, fmt.Errorf("failed to open migrations: %w", err)
+	}
+	defer src.Close()
+
+	version, err := src.First()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read migrations: %w", err)
+	}
+	for {
+		next, err := src.Next(version)
+		if errors.Is(err, os.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read migrations: %w", err)
+		}
+		version = next
+	}
+}
@@ -2,12 +2,19 @@ package common
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/logger"
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// ErrorCodeKey — ключ контекста gin с кодом ошибки ответа (для метрик)
+const ErrorCodeKey = "error_code"
+
 type httpError struct {
 	msg    string
 	status int
@@ -29,12 +36,46 @@ func mapDomainErrorToCode(err error) (code string, httpStatus int, message strin
 		return "NOT_FOUND", http.StatusNotFound, "pull request not found"
 	case errors.Is(err, domain.ErrPRAlreadyMerged):
 		return "PR_MERGED", http.StatusConflict, "cannot reassign on merged PR"
+	case errors.Is(err, domain.ErrPRClosed):
+		return "PR_CLOSED", http.StatusConflict, "pull request is closed without merge"
 	case errors.Is(err, domain.ErrReviewerNotAssigned):
 		return "NOT_ASSIGNED", http.StatusConflict, "reviewer is not assigned to this PR"
 	case errors.Is(err, domain.ErrNoActiveReviewers):
 		return "NO_CANDIDATE", http.StatusConflict, "no active replacement candidate in team"
 	case errors.Is(err, domain.ErrAuthorNotFound), errors.Is(err, domain.ErrUserNotFound), errors.Is(err, domain.ErrTeamNotFound):
 		return "NOT_FOUND", http.StatusNotFound, "author, user, team or PR not found"
+	case errors.Is(err, domain.ErrPRVersionConflict):
+		return "CONCURRENT_UPDATE", http.StatusConflict, "pull request was modified concurrently, retry the request"
+	case errors.Is(err, domain.ErrPRVersionMismatch):
+		return "PRECONDITION_FAILED", http.StatusPreconditionFailed, "pull request version does not match If-Match"
+	case errors.Is(err, domain.ErrInvalidSnapshot), errors.Is(err, domain.ErrSnapshotSchema):
+		return "INVALID_PARAM", http.StatusBadRequest, err.Error()
+	case errors.Is(err, domain.ErrDatabaseNotEmpty):
+		return "DATABASE_NOT_EMPTY", http.StatusConflict, "database already contains data, pass force=true to overwrite it"
+	case errors.Is(err, domain.ErrReconcilePlanStale):
+		return "PRECONDITION_FAILED", http.StatusPreconditionFailed, err.Error()
+	case errors.Is(err, domain.ErrWebhookNotFound):
+		return "NOT_FOUND", http.StatusNotFound, "webhook subscription not found"
+	case errors.Is(err, domain.ErrInvalidWebhook):
+		return "INVALID_PARAM", http.StatusBadRequest, "invalid webhook subscription: url must be http(s), secret and known event types are required"
+	case errors.Is(err, domain.ErrInvalidVCSSignature):
+		return "UNAUTHORIZED", http.StatusUnauthorized, "invalid webhook signature"
+	case errors.Is(err, domain.ErrInvalidVCSPayload):
+		return "INVALID_PARAM", http.StatusBadRequest, "invalid webhook payload"
+	case errors.Is(err, domain.ErrIdentityExists):
+		return "IDENTITY_EXISTS", http.StatusConflict, "identity is already linked to a user"
+	case errors.Is(err, domain.ErrIdentityNotFound):
+		return "NOT_FOUND", http.StatusNotFound, "identity not found"
+	case errors.Is(err, domain.ErrInvalidIdentity):
+		return "INVALID_PARAM", http.StatusBadRequest, err.Error()
+	case errors.Is(err, domain.ErrInvalidEmail):
+		return "INVALID_PARAM", http.StatusBadRequest, err.Error()
+	case errors.Is(err, domain.ErrInvalidReviewPolicy):
+		return "INVALID_PARAM", http.StatusBadRequest, err.Error()
+	case errors.Is(err, domain.ErrInvalidReviewSLA), errors.Is(err, domain.ErrInvalidPeriod), errors.Is(err, domain.ErrInvalidTeamImport):
+		return "INVALID_PARAM", http.StatusBadRequest, err.Error()
+	case errors.Is(err, domain.ErrReviewPolicyNotFound):
+		return "NOT_FOUND", http.StatusNotFound, "review policy not found"
 	case errors.Is(err, domain.ErrTeamExists):
 		return "TEAM_EXISTS", http.StatusConflict, "team_name already exists"
 	default:
@@ -43,8 +84,15 @@ func mapDomainErrorToCode(err error) (code string, httpStatus int, message strin
 }
 
 func HandleError(c *gin.Context, err error) {
+	// Тело запроса, обрезанное http.MaxBytesReader, — ошибка клиента, а не сервера
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		err = HttpError(fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit), http.StatusRequestEntityTooLarge)
+	}
+
 	// Сначала проверяем, не является ли ошибка кастомной HTTP-ошибкой
 	if httpErr, ok := err.(interface{ Status() int }); ok {
+		c.Set(ErrorCodeKey, "INVALID_PARAM")
 		c.AbortWithStatusJSON(httpErr.Status(), gin.H{
 			"error": gin.H{
 				"code":    "INVALID_PARAM", // или динамически из err
@@ -56,9 +104,18 @@ func HandleError(c *gin.Context, err error) {
 
 	// Иначе — обрабатываем как domain-ошибку
 	code, status, message := mapDomainErrorToCode(err)
+	c.Set(ErrorCodeKey, code)
 
 	if code == "INTERNAL" {
-		// TODO: логирование
+		span := trace.SpanFromContext(c.Request.Context())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, message)
+		logger.FromContext(c.Request.Context()).Error("internal error",
+			"error", err.Error(),
+			"error_chain", logger.ErrorChain(err),
+			"method", c.Request.Method,
+			"route", c.FullPath(),
+		)
 	}
 
 	c.AbortWithStatusJSON(status, gin.H{
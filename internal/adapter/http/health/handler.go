@@ -0,0 +1,120 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	statusOK   = "ok"
+	statusFail = "fail"
+
+	// checkTimeout ограничивает проверку БД, чтобы зонд не зависал дольше своего таймаута
+	checkTimeout = 2 * time.Second
+)
+
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+type MigrationReader interface {
+	MigrationVersion(ctx context.Context) (version uint, dirty bool, err error)
+}
+
+type componentResponse struct {
+	Status  string `json:"status"`
+	Error   string `json:"error,omitempty"`
+	Version uint   `json:"version,omitempty"`
+}
+
+type readyResponse struct {
+	Status     string                       `json:"status"`
+	Components map[string]componentResponse `json:"components"`
+}
+
+// Handler отвечает на зонды живости и готовности
+type Handler struct {
+	db              Pinger
+	migrations      MigrationReader
+	expectedVersion uint
+	workers         *Workers
+	shuttingDown    atomic.Bool
+}
+
+// NewHandler создаёт хендлер. expectedVersion — версия схемы, до которой сервис мигрировал при старте.
+func NewHandler(db Pinger, migrations MigrationReader, expectedVersion uint, workers *Workers) *Handler {
+	return &Handler{db: db, migrations: migrations, expectedVersion: expectedVersion, workers: workers}
+}
+
+// BeginShutdown переводит готовность в fail, чтобы балансировщик перестал слать запросы
+func (h *Handler) BeginShutdown() {
+	h.shuttingDown.Store(true)
+}
+
+// Live — процесс жив и обслуживает HTTP; зависимости не проверяются, чтобы сбой БД не вызывал рестарт
+func (h *Handler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, componentResponse{Status: statusOK})
+}
+
+// Ready проверяет БД, версию схемы и фоновые задачи
+func (h *Handler) Ready(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), checkTimeout)
+	defer cancel()
+
+	resp := readyResponse{
+		Status: statusOK,
+		Components: map[string]componentResponse{
+			"database":   h.checkDatabase(ctx),
+			"migrations": h.checkMigrations(ctx),
+			"workers":    h.checkWorkers(),
+		},
+	}
+	if h.shuttingDown.Load() {
+		resp.Components["shutdown"] = componentResponse{Status: statusFail, Error: "server is shutting down"}
+	}
+
+	code := http.StatusOK
+	for _, component := range resp.Components {
+		if component.Status != statusOK {
+			resp.Status = statusFail
+			code = http.StatusServiceUnavailable
+		}
+	}
+	c.JSON(code, resp)
+}
+
+func (h *Handler) checkDatabase(ctx context.Context) componentResponse {
+	if err := h.db.Ping(ctx); err != nil {
+		return componentResponse{Status: statusFail, Error: err.Error()}
+	}
+	return componentResponse{Status: statusOK}
+}
+
+// checkMigrations допускает более новую схему: при раскатке её мог применить новый релиз
+func (h *Handler) checkMigrations(ctx context.Context) componentResponse {
+	version, dirty, err := h.migrations.MigrationVersion(ctx)
+	switch {
+	case err != nil:
+		return componentResponse{Status: statusFail, Error: err.Error()}
+	case dirty:
+		return componentResponse{Status: statusFail, Version: version, Error: "migration is dirty"}
+	case version < h.expectedVersion:
+		return componentResponse{Status: statusFail, Version: version,
+			Error: fmt.Sprintf("schema version %d is older than expected %d", version, h.expectedVersion)}
+	default:
+		return componentResponse{Status: statusOK, Version: version}
+	}
+}
+
+func (h *Handler) checkWorkers() componentResponse {
+	if stopped := h.workers.stopped(); len(stopped) > 0 && !h.shuttingDown.Load() {
+		return componentResponse{Status: statusFail, Error: "stopped: " + strings.Join(stopped, ", ")}
+	}
+	return componentResponse{Status: statusOK}
+}
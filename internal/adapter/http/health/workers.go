@@ -0,0 +1,44 @@
+package health
+
+import (
+	"sort"
+	"sync"
+)
+
+// Workers отслеживает фоновые задачи: задача, завершившаяся до остановки сервиса, считается упавшей
+type Workers struct {
+	mu      sync.Mutex
+	running map[string]bool
+}
+
+func NewWorkers() *Workers {
+	return &Workers{running: make(map[string]bool)}
+}
+
+// Started отмечает задачу запущенной
+func (w *Workers) Started(name string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.running[name] = true
+}
+
+// Stopped отмечает задачу завершённой
+func (w *Workers) Stopped(name string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.running[name] = false
+}
+
+// stopped возвращает отсортированные имена завершившихся задач
+func (w *Workers) stopped() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var names []string
+	for name, running := range w.running {
+		if !running {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
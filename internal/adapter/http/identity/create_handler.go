@@ -0,0 +1,47 @@
+package identity
+
+import (
+	"net/http"
+
+	common "github.com/Skorpsrgvch/reviewer-service/internal/adapter/http/common"
+	identityCreate "github.com/Skorpsrgvch/reviewer-service/internal/usecase/identity/create"
+	"github.com/gin-gonic/gin"
+)
+
+type createIdentityRequest struct {
+	Provider   string `json:"provider" binding:"required"`
+	ExternalID string `json:"external_id" binding:"required"`
+	UserID     string `json:"user_id" binding:"required"`
+}
+
+type createIdentityResponse struct {
+	Identity identityDTO `json:"identity"`
+}
+
+type CreateHandler struct {
+	usecase *identityCreate.Usecase
+}
+
+func NewCreateHandler(usecase *identityCreate.Usecase) *CreateHandler {
+	return &CreateHandler{usecase: usecase}
+}
+
+func (h *CreateHandler) Handle(c *gin.Context) {
+	var req createIdentityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.HandleError(c, err)
+		return
+	}
+
+	identity, err := h.usecase.Execute(c.Request.Context(), identityCreate.Input{
+		Provider:   req.Provider,
+		ExternalID: req.ExternalID,
+		UserID:     req.UserID,
+	})
+	if err != nil {
+		common.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, createIdentityResponse{Identity: toIdentityDTO(identity)})
+}
@@ -0,0 +1,41 @@
+package identity
+
+import (
+	"net/http"
+
+	common "github.com/Skorpsrgvch/reviewer-service/internal/adapter/http/common"
+	identityDelete "github.com/Skorpsrgvch/reviewer-service/internal/usecase/identity/delete"
+	"github.com/gin-gonic/gin"
+)
+
+type deleteIdentityRequest struct {
+	Provider   string `json:"provider" binding:"required"`
+	ExternalID string `json:"external_id" binding:"required"`
+}
+
+type DeleteHandler struct {
+	usecase *identityDelete.Usecase
+}
+
+func NewDeleteHandler(usecase *identityDelete.Usecase) *DeleteHandler {
+	return &DeleteHandler{usecase: usecase}
+}
+
+func (h *DeleteHandler) Handle(c *gin.Context) {
+	var req deleteIdentityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.HandleError(c, err)
+		return
+	}
+
+	err := h.usecase.Execute(c.Request.Context(), identityDelete.Input{
+		Provider:   req.Provider,
+		ExternalID: req.ExternalID,
+	})
+	if err != nil {
+		common.HandleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
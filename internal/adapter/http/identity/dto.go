@@ -0,0 +1,23 @@
+package identity
+
+import (
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+type identityDTO struct {
+	Provider   string `json:"provider"`
+	ExternalID string `json:"external_id"`
+	UserID     string `json:"user_id"`
+	CreatedAt  string `json:"created_at"`
+}
+
+func toIdentityDTO(identity *domain.UserIdentity) identityDTO {
+	return identityDTO{
+		Provider:   identity.Provider(),
+		ExternalID: identity.ExternalID(),
+		UserID:     identity.UserID(),
+		CreatedAt:  identity.CreatedAt().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,75 @@
+package identity
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	common "github.com/Skorpsrgvch/reviewer-service/internal/adapter/http/common"
+	identityImport "github.com/Skorpsrgvch/reviewer-service/internal/usecase/identity/bulkImport"
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportSize — ограничение размера загружаемого CSV
+const maxImportSize = 10 << 20
+
+type importResponse struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+}
+
+type rowErrorDTO struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+type ImportHandler struct {
+	usecase *identityImport.Usecase
+}
+
+func NewImportHandler(usecase *identityImport.Usecase) *ImportHandler {
+	return &ImportHandler{usecase: usecase}
+}
+
+// Handle принимает CSV телом запроса (text/csv) или файлом "file" в multipart/form-data
+func (h *ImportHandler) Handle(c *gin.Context) {
+	var body io.Reader = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		file, err := c.FormFile("file")
+		if err != nil {
+			common.HandleError(c, common.HttpError("file is required", http.StatusBadRequest))
+			return
+		}
+		f, err := file.Open()
+		if err != nil {
+			common.HandleError(c, err)
+			return
+		}
+		defer f.Close()
+		body = f
+	}
+
+	output, err := h.usecase.Execute(c.Request.Context(), identityImport.Input{CSV: body})
+	if err != nil {
+		var importErr *identityImport.ImportError
+		if errors.As(err, &importErr) {
+			rows := make([]rowErrorDTO, 0, len(importErr.Rows))
+			for _, r := range importErr.Rows {
+				rows = append(rows, rowErrorDTO{Line: r.Line, Message: r.Message})
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": gin.H{
+					"code":    "INVALID_PARAM",
+					"message": "CSV contains invalid rows, nothing was imported",
+					"rows":    rows,
+				},
+			})
+			return
+		}
+		common.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, importResponse{Created: output.Created, Updated: output.Updated})
+}
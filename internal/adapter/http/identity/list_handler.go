@@ -0,0 +1,39 @@
+package identity
+
+import (
+	"net/http"
+
+	common "github.com/Skorpsrgvch/reviewer-service/internal/adapter/http/common"
+	identityList "github.com/Skorpsrgvch/reviewer-service/internal/usecase/identity/list"
+	"github.com/gin-gonic/gin"
+)
+
+type listIdentitiesResponse struct {
+	Identities []identityDTO `json:"identities"`
+}
+
+type ListHandler struct {
+	usecase *identityList.Usecase
+}
+
+func NewListHandler(usecase *identityList.Usecase) *ListHandler {
+	return &ListHandler{usecase: usecase}
+}
+
+func (h *ListHandler) Handle(c *gin.Context) {
+	identities, err := h.usecase.Execute(c.Request.Context(), identityList.Input{
+		UserID:   c.Query("user_id"),
+		Provider: c.Query("provider"),
+	})
+	if err != nil {
+		common.HandleError(c, err)
+		return
+	}
+
+	resp := listIdentitiesResponse{Identities: make([]identityDTO, 0, len(identities))}
+	for i := range identities {
+		resp.Identities = append(resp.Identities, toIdentityDTO(&identities[i]))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,46 @@
+package identity
+
+import (
+	"net/http"
+
+	common "github.com/Skorpsrgvch/reviewer-service/internal/adapter/http/common"
+	identityLookup "github.com/Skorpsrgvch/reviewer-service/internal/usecase/identity/lookup"
+	"github.com/gin-gonic/gin"
+)
+
+type lookupResponse struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	IsActive bool   `json:"is_active"`
+}
+
+type LookupHandler struct {
+	usecase *identityLookup.Usecase
+}
+
+func NewLookupHandler(usecase *identityLookup.Usecase) *LookupHandler {
+	return &LookupHandler{usecase: usecase}
+}
+
+func (h *LookupHandler) Handle(c *gin.Context) {
+	provider, externalID := c.Query("provider"), c.Query("external_id")
+	if provider == "" || externalID == "" {
+		common.HandleError(c, common.HttpError("provider and external_id are required", http.StatusBadRequest))
+		return
+	}
+
+	user, err := h.usecase.Execute(c.Request.Context(), identityLookup.Input{
+		Provider:   provider,
+		ExternalID: externalID,
+	})
+	if err != nil {
+		common.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, lookupResponse{
+		UserID:   user.ID(),
+		Username: user.Username(),
+		IsActive: user.IsActive(),
+	})
+}
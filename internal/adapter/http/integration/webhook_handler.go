@@ -0,0 +1,66 @@
+package integration
+
+import (
+	"io"
+	"net/http"
+
+	common "github.com/Skorpsrgvch/reviewer-service/internal/adapter/http/common"
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	syncPullRequest "github.com/Skorpsrgvch/reviewer-service/internal/usecase/integration/syncPullRequest"
+	"github.com/gin-gonic/gin"
+)
+
+// maxPayloadSize — ограничение размера тела вебхука
+const maxPayloadSize = 5 << 20
+
+// WebhookParser проверяет подлинность вебхука провайдера и разбирает его.
+// Возвращает nil-событие, если вебхук не относится к ревью.
+type WebhookParser interface {
+	Parse(header http.Header, body []byte) (*domain.VCSPullRequestEvent, error)
+}
+
+type webhookResponse struct {
+	Result        string `json:"result"`
+	PullRequestID string `json:"pull_request_id,omitempty"`
+	Reason        string `json:"reason,omitempty"`
+}
+
+// WebhookHandler принимает вебхуки VCS-провайдера; провайдер задаётся парсером
+type WebhookHandler struct {
+	parser  WebhookParser
+	usecase *syncPullRequest.Usecase
+}
+
+func NewWebhookHandler(parser WebhookParser, usecase *syncPullRequest.Usecase) *WebhookHandler {
+	return &WebhookHandler{parser: parser, usecase: usecase}
+}
+
+func (h *WebhookHandler) Handle(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxPayloadSize))
+	if err != nil {
+		common.HandleError(c, common.HttpError("failed to read body", http.StatusBadRequest))
+		return
+	}
+
+	event, err := h.parser.Parse(c.Request.Header, body)
+	if err != nil {
+		common.HandleError(c, err)
+		return
+	}
+	if event == nil {
+		c.JSON(http.StatusOK, webhookResponse{Result: string(syncPullRequest.ResultIgnored)})
+		return
+	}
+
+	output, err := h.usecase.Execute(c.Request.Context(), *event)
+	if err != nil {
+		common.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, webhookResponse{
+		Result:        string(output.Result),
+		PullRequestID: output.PullRequestID,
+		Reason:        output.Reason,
+	})
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// AccessLogMiddleware пишет строку лога на каждый запрос. Должен стоять после RequestIDMiddleware,
+// чтобы в строке был request_id.
+func AccessLogMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		if status >= 500 {
+			level = slog.LevelError
+		}
+		logger.FromContext(c.Request.Context()).LogAttrs(c.Request.Context(), level, "http request",
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.Request.URL.Path),
+			slog.String("route", c.FullPath()),
+			slog.Int("status", status),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.Int("bytes", max(c.Writer.Size(), 0)),
+			slog.String("client_ip", c.ClientIP()),
+		)
+	}
+}
@@ -1,16 +1,18 @@
 package middleware
 
 import (
+	"crypto/subtle"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
 // AuthMiddleware проверяет Bearer токен администратора
-func AuthMiddleware() gin.HandlerFunc {
+func AuthMiddleware(adminToken string) gin.HandlerFunc {
+	expected := []byte("Bearer " + adminToken)
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
-		if authHeader != "Bearer admin" {
+		if subtle.ConstantTimeCompare([]byte(authHeader), expected) != 1 {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
 				"error": gin.H{
 					"code":    "UNAUTHORIZED",
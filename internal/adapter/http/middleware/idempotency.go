@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	common "github.com/Skorpsrgvch/reviewer-service/internal/adapter/http/common"
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotencyReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// IdempotencyMiddleware повторяет сохранённый ответ для запросов с тем же Idempotency-Key.
+// Запросы без заголовка обрабатываются как обычно.
+func IdempotencyMiddleware(store domain.IdempotencyStore, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			common.HandleError(c, common.HttpError("Idempotency-Key is too long", http.StatusBadRequest))
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			common.HandleError(c, common.HttpError("failed to read request body", http.StatusBadRequest))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		route := c.FullPath()
+		requestHash := hashRequest(c.Request.Method, route, c.Request.URL.RawQuery, body)
+
+		existing, err := store.Reserve(c.Request.Context(), key, route, requestHash, ttl)
+		if err != nil {
+			common.HandleError(c, err)
+			return
+		}
+
+		if existing != nil {
+			switch {
+			case existing.RequestHash != requestHash:
+				abortIdempotency(c, http.StatusUnprocessableEntity, "IDEMPOTENCY_KEY_REUSED",
+					"Idempotency-Key was already used with a different payload")
+			case !existing.Completed:
+				abortIdempotency(c, http.StatusConflict, "IDEMPOTENCY_IN_PROGRESS",
+					"request with this Idempotency-Key is still in progress")
+			default:
+				c.Header(IdempotencyReplayedHeader, "true")
+				c.Data(existing.StatusCode, "application/json; charset=utf-8", existing.Body)
+				c.Abort()
+			}
+			return
+		}
+
+		recorder := &bodyRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		// Если обработчик упал с паникой — освобождаем ключ, чтобы повтор выполнился заново
+		completed := false
+		defer func() {
+			if !completed {
+				_ = store.Release(context.WithoutCancel(c.Request.Context()), key, route)
+			}
+		}()
+
+		c.Next()
+
+		// 5xx не сохраняем: повтор должен иметь шанс выполниться успешно
+		if recorder.Status() >= http.StatusInternalServerError {
+			return
+		}
+		if err := store.Complete(context.WithoutCancel(c.Request.Context()), key, route, recorder.Status(), recorder.body.Bytes()); err != nil {
+			return
+		}
+		completed = true
+	}
+}
+
+// hashRequest учитывает строку запроса: dry_run=true и обычный вызов — разные запросы
+func hashRequest(method, route, query string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{'\n'})
+	h.Write([]byte(route))
+	h.Write([]byte{'\n'})
+	h.Write([]byte(query))
+	h.Write([]byte{'\n'})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func abortIdempotency(c *gin.Context, status int, code, message string) {
+	c.AbortWithStatusJSON(status, gin.H{
+		"error": gin.H{
+			"code":    code,
+			"message": message,
+		},
+	})
+}
+
+// bodyRecorder дублирует тело ответа в буфер для сохранения.
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,170 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// fakeIdempotencyStore — IdempotencyStore в памяти без срока жизни ключей
+type fakeIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]*domain.IdempotencyRecord
+}
+
+func newFakeIdempotencyStore() *fakeIdempotencyStore {
+	return &fakeIdempotencyStore{records: make(map[string]*domain.IdempotencyRecord)}
+}
+
+func (s *fakeIdempotencyStore) Reserve(_ context.Context, key, route, requestHash string, _ time.Duration) (*domain.IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.records[key+route]; ok {
+		record := *existing
+		return &record, nil
+	}
+	s.records[key+route] = &domain.IdempotencyRecord{RequestHash: requestHash}
+	return nil, nil
+}
+
+func (s *fakeIdempotencyStore) Complete(_ context.Context, key, route string, statusCode int, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if record, ok := s.records[key+route]; ok {
+		record.StatusCode = statusCode
+		record.Body = append([]byte(nil), body...)
+		record.Completed = true
+	}
+	return nil
+}
+
+func (s *fakeIdempotencyStore) Release(_ context.Context, key, route string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if record, ok := s.records[key+route]; ok && !record.Completed {
+		delete(s.records, key+route)
+	}
+	return nil
+}
+
+// newIdempotentRouter регистрирует handler на POST /items за IdempotencyMiddleware
+func newIdempotentRouter(handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/items", IdempotencyMiddleware(newFakeIdempotencyStore(), time.Hour), handler)
+	return r
+}
+
+func postItem(r http.Handler, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(body))
+	req.Header.Set(IdempotencyKeyHeader, key)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func errorCode(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+	var resp struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode %q: %v", w.Body.String(), err)
+	}
+	return resp.Error.Code
+}
+
+func TestIdempotencyReplaysStoredResponse(t *testing.T) {
+	calls := 0
+	r := newIdempotentRouter(func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusCreated, gin.H{"call": calls})
+	})
+
+	first := postItem(r, "k1", `{"id":"a"}`)
+	second := postItem(r, "k1", `{"id":"a"}`)
+
+	if calls != 1 {
+		t.Errorf("handler called %d times, want 1", calls)
+	}
+	if second.Code != http.StatusCreated || second.Body.String() != first.Body.String() {
+		t.Errorf("replay = %d %s, want %d %s", second.Code, second.Body, first.Code, first.Body)
+	}
+	if second.Header().Get(IdempotencyReplayedHeader) != "true" {
+		t.Errorf("replay has no %s header", IdempotencyReplayedHeader)
+	}
+	if first.Header().Get(IdempotencyReplayedHeader) != "" {
+		t.Errorf("first response must not be marked as replayed")
+	}
+}
+
+func TestIdempotencyRejectsKeyReuse(t *testing.T) {
+	r := newIdempotentRouter(func(c *gin.Context) {
+		c.JSON(http.StatusCreated, gin.H{})
+	})
+
+	postItem(r, "k1", `{"id":"a"}`)
+	w := postItem(r, "k1", `{"id":"b"}`)
+
+	if w.Code != http.StatusUnprocessableEntity || errorCode(t, w) != "IDEMPOTENCY_KEY_REUSED" {
+		t.Errorf("reuse = %d %s, want 422 IDEMPOTENCY_KEY_REUSED", w.Code, w.Body)
+	}
+}
+
+func TestIdempotencyRejectsRequestInProgress(t *testing.T) {
+	var (
+		r     *gin.Engine
+		inner *httptest.ResponseRecorder
+	)
+	r = newIdempotentRouter(func(c *gin.Context) {
+		// Повтор приходит, пока первый запрос с тем же ключом ещё выполняется
+		if inner == nil {
+			inner = postItem(r, "k1", `{"id":"a"}`)
+		}
+		c.JSON(http.StatusCreated, gin.H{})
+	})
+
+	first := postItem(r, "k1", `{"id":"a"}`)
+
+	if first.Code != http.StatusCreated {
+		t.Errorf("first = %d, want 201", first.Code)
+	}
+	if inner.Code != http.StatusConflict || errorCode(t, inner) != "IDEMPOTENCY_IN_PROGRESS" {
+		t.Errorf("concurrent retry = %d %s, want 409 IDEMPOTENCY_IN_PROGRESS", inner.Code, inner.Body)
+	}
+}
+
+func TestIdempotencyDoesNotStoreServerErrors(t *testing.T) {
+	calls := 0
+	r := newIdempotentRouter(func(c *gin.Context) {
+		calls++
+		if calls == 1 {
+			c.JSON(http.StatusInternalServerError, gin.H{})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{})
+	})
+
+	first := postItem(r, "k1", `{"id":"a"}`)
+	second := postItem(r, "k1", `{"id":"a"}`)
+
+	if first.Code != http.StatusInternalServerError {
+		t.Fatalf("first = %d, want 500", first.Code)
+	}
+	if calls != 2 || second.Code != http.StatusCreated {
+		t.Errorf("retry after 500: handler called %d times, status %d, want 2 calls and 201", calls, second.Code)
+	}
+	if second.Header().Get(IdempotencyReplayedHeader) != "" {
+		t.Errorf("retry after 500 must not be a replay")
+	}
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+
+	"github.com/Skorpsrgvch/reviewer-service/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	RequestIDHeader = "X-Request-ID"
+	// maxRequestIDLength — более длинный заголовок от клиента заменяется сгенерированным
+	maxRequestIDLength = 128
+)
+
+// RequestIDMiddleware берёт X-Request-ID из запроса или генерирует новый, возвращает его в ответе
+// и кладёт в контекст запроса логгер с полем request_id
+func RequestIDMiddleware(base *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		c.Header(RequestIDHeader, id)
+
+		ctx := logger.WithContext(c.Request.Context(), base.With("request_id", id))
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// validRequestID допускает только печатные ASCII-символы, чтобы ID нельзя было использовать для подделки строк лога
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
 
 	_ "github.com/lib/pq"
 )
@@ -18,3 +19,12 @@ func NewDBRepo(db *sql.DB) *DBRepo {
 func (r *DBRepo) Ping(ctx context.Context) error {
 	return r.db.PingContext(ctx)
 }
+
+// MigrationVersion возвращает применённую версию схемы из таблицы golang-migrate
+func (r *DBRepo) MigrationVersion(ctx context.Context) (version uint, dirty bool, err error) {
+	err = r.db.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, errors.New("no migrations applied")
+	}
+	return version, dirty, err
+}
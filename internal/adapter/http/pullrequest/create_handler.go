@@ -27,6 +27,7 @@ type pullRequestDTO struct {
 	AssignedReviewers []string `json:"assigned_reviewers"`
 	CreatedAt         string   `json:"created_at"`
 	MergedAt          *string  `json:"mergedAt,omitempty"` // nullable → *string
+	Version           int      `json:"version"`
 }
 
 type CreateHandler struct {
@@ -64,8 +65,10 @@ func (h *CreateHandler) Handle(c *gin.Context) {
 			Status:            string(createdPR.Status()),
 			AssignedReviewers: createdPR.AssignedReviewers(),
 			CreatedAt:         createdPR.CreatedAt().Format(time.RFC3339),
+			Version:           createdPR.Version(),
 		},
 	}
 
+	setETag(c, createdPR)
 	c.JSON(http.StatusCreated, resp)
 }
@@ -0,0 +1,36 @@
+package pullrequest
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	common "github.com/Skorpsrgvch/reviewer-service/internal/adapter/http/common"
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// setETag выставляет ETag с версией PR
+func setETag(c *gin.Context, pr *domain.PullRequest) {
+	c.Header("ETag", strconv.Quote(strconv.Itoa(pr.Version())))
+}
+
+// parseIfMatch разбирает заголовок If-Match в ожидаемую версию PR.
+// Отсутствующий заголовок и "*" означают «любая версия» — возвращается nil.
+func parseIfMatch(c *gin.Context) (*int, error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+
+	tag := strings.TrimPrefix(header, "W/")
+	unquoted, err := strconv.Unquote(tag)
+	if err != nil {
+		return nil, common.HttpError("If-Match must be a quoted PR version, e.g. \"3\"", http.StatusBadRequest)
+	}
+	version, err := strconv.Atoi(unquoted)
+	if err != nil {
+		return nil, common.HttpError("If-Match must be a quoted PR version, e.g. \"3\"", http.StatusBadRequest)
+	}
+	return &version, nil
+}
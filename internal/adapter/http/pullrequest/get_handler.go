@@ -0,0 +1,59 @@
+package pullrequest
+
+import (
+	"net/http"
+	"time"
+
+	common "github.com/Skorpsrgvch/reviewer-service/internal/adapter/http/common"
+	prGet "github.com/Skorpsrgvch/reviewer-service/internal/usecase/pullrequest/get"
+	"github.com/gin-gonic/gin"
+)
+
+type getPRResponse struct {
+	PR pullRequestDTO `json:"pr"`
+}
+
+type GetHandler struct {
+	usecase *prGet.Usecase
+}
+
+func NewGetHandler(usecase *prGet.Usecase) *GetHandler {
+	return &GetHandler{usecase: usecase}
+}
+
+func (h *GetHandler) Handle(c *gin.Context) {
+	prID := c.Query("pull_request_id")
+	if prID == "" {
+		common.HandleError(c, common.HttpError("pull_request_id is required", http.StatusBadRequest))
+		return
+	}
+
+	pr, err := h.usecase.Execute(c.Request.Context(), prGet.Input{PullRequestID: prID})
+	if err != nil {
+		common.HandleError(c, err)
+		return
+	}
+
+	createdAt := pr.CreatedAt().Format(time.RFC3339)
+	var mergedAt *string
+	if pr.MergedAt() != nil {
+		s := pr.MergedAt().Format(time.RFC3339)
+		mergedAt = &s
+	}
+
+	resp := getPRResponse{
+		PR: pullRequestDTO{
+			PullRequestID:     pr.ID(),
+			PullRequestName:   pr.Name(),
+			AuthorID:          pr.AuthorID(),
+			Status:            string(pr.Status()),
+			AssignedReviewers: pr.AssignedReviewers(),
+			CreatedAt:         createdAt,
+			MergedAt:          mergedAt,
+			Version:           pr.Version(),
+		},
+	}
+
+	setETag(c, pr)
+	c.JSON(http.StatusOK, resp)
+}
@@ -32,8 +32,15 @@ func (h *MergeHandler) Handle(c *gin.Context) {
 		return
 	}
 
+	expectedVersion, err := parseIfMatch(c)
+	if err != nil {
+		common.HandleError(c, err)
+		return
+	}
+
 	input := prMerge.Input{
-		PullRequestID: req.PullRequestID,
+		PullRequestID:   req.PullRequestID,
+		ExpectedVersion: expectedVersion,
 	}
 
 	pr, err := h.usecase.Execute(c.Request.Context(), input)
@@ -59,8 +66,10 @@ func (h *MergeHandler) Handle(c *gin.Context) {
 			AssignedReviewers: pr.AssignedReviewers(),
 			CreatedAt:         createdAt,
 			MergedAt:          mergedAt,
+			Version:           pr.Version(),
 		},
 	}
 
+	setETag(c, pr)
 	c.JSON(http.StatusOK, resp)
 }
@@ -34,9 +34,16 @@ func (h *ReassignHandler) Handle(c *gin.Context) {
 		return
 	}
 
+	expectedVersion, err := parseIfMatch(c)
+	if err != nil {
+		common.HandleError(c, err)
+		return
+	}
+
 	input := prReassign.Input{
-		PullRequestID: req.PullRequestID,
-		OldReviewerID: req.OldReviewerID,
+		PullRequestID:   req.PullRequestID,
+		OldReviewerID:   req.OldReviewerID,
+		ExpectedVersion: expectedVersion,
 	}
 
 	pr, newReviewer, err := h.usecase.Execute(c.Request.Context(), input)
@@ -62,9 +69,11 @@ func (h *ReassignHandler) Handle(c *gin.Context) {
 			AssignedReviewers: pr.AssignedReviewers(),
 			CreatedAt:         createdAt,
 			MergedAt:          mergedAt,
+			Version:           pr.Version(),
 		},
 		ReplacedBy: newReviewer,
 	}
 
+	setETag(c, pr)
 	c.JSON(http.StatusOK, resp)
 }
@@ -0,0 +1,54 @@
+package pullrequest
+
+import (
+	"net/http"
+	"time"
+
+	common "github.com/Skorpsrgvch/reviewer-service/internal/adapter/http/common"
+	prRecordReview "github.com/Skorpsrgvch/reviewer-service/internal/usecase/pullrequest/recordReview"
+	"github.com/gin-gonic/gin"
+)
+
+type recordReviewRequest struct {
+	PullRequestID string `json:"pull_request_id" binding:"required"`
+	ReviewerID    string `json:"reviewer_id" binding:"required"`
+}
+
+type recordReviewResponse struct {
+	PullRequestID string    `json:"pull_request_id"`
+	ReviewerID    string    `json:"reviewer_id"`
+	FirstActionAt time.Time `json:"first_action_at"`
+	First         bool      `json:"first"`
+}
+
+type RecordReviewHandler struct {
+	usecase *prRecordReview.Usecase
+}
+
+func NewRecordReviewHandler(usecase *prRecordReview.Usecase) *RecordReviewHandler {
+	return &RecordReviewHandler{usecase: usecase}
+}
+
+func (h *RecordReviewHandler) Handle(c *gin.Context) {
+	var req recordReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.HandleError(c, err)
+		return
+	}
+
+	out, err := h.usecase.Execute(c.Request.Context(), prRecordReview.Input{
+		PullRequestID: req.PullRequestID,
+		ReviewerID:    req.ReviewerID,
+	})
+	if err != nil {
+		common.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, recordReviewResponse{
+		PullRequestID: req.PullRequestID,
+		ReviewerID:    req.ReviewerID,
+		FirstActionAt: out.FirstActionAt,
+		First:         out.First,
+	})
+}
@@ -0,0 +1,68 @@
+package pullrequest
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	common "github.com/Skorpsrgvch/reviewer-service/internal/adapter/http/common"
+	prListSLAViolations "github.com/Skorpsrgvch/reviewer-service/internal/usecase/pullrequest/listSLAViolations"
+	"github.com/gin-gonic/gin"
+)
+
+type slaViolationDTO struct {
+	PullRequestID   string    `json:"pull_request_id"`
+	PullRequestName string    `json:"pull_request_name"`
+	TeamName        string    `json:"team_name"`
+	ReviewerID      string    `json:"reviewer_id"`
+	AssignedAt      time.Time `json:"assigned_at"`
+	DueAt           time.Time `json:"due_at"`
+	Status          string    `json:"status"`
+}
+
+type slaViolationsResponse struct {
+	Violations []slaViolationDTO `json:"violations"`
+}
+
+type SLAViolationsHandler struct {
+	usecase *prListSLAViolations.Usecase
+}
+
+func NewSLAViolationsHandler(usecase *prListSLAViolations.Usecase) *SLAViolationsHandler {
+	return &SLAViolationsHandler{usecase: usecase}
+}
+
+func (h *SLAViolationsHandler) Handle(c *gin.Context) {
+	input := prListSLAViolations.Input{
+		TeamName: c.Query("team_name"),
+		Now:      time.Now().UTC(),
+	}
+	if raw := c.Query("near_breach_hours"); raw != "" {
+		hours, err := strconv.Atoi(raw)
+		if err != nil || hours <= 0 {
+			common.HandleError(c, common.HttpError("near_breach_hours must be a positive integer", http.StatusBadRequest))
+			return
+		}
+		input.NearBreach = time.Duration(hours) * time.Hour
+	}
+
+	violations, err := h.usecase.Execute(c.Request.Context(), input)
+	if err != nil {
+		common.HandleError(c, err)
+		return
+	}
+
+	resp := slaViolationsResponse{Violations: make([]slaViolationDTO, 0, len(violations))}
+	for _, v := range violations {
+		resp.Violations = append(resp.Violations, slaViolationDTO{
+			PullRequestID:   v.PullRequestID,
+			PullRequestName: v.PullRequestName,
+			TeamName:        v.Team,
+			ReviewerID:      v.ReviewerID,
+			AssignedAt:      v.AssignedAt,
+			DueAt:           v.DueAt,
+			Status:          string(v.Status),
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,61 @@
+package snapshot
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	common "github.com/Skorpsrgvch/reviewer-service/internal/adapter/http/common"
+	snapshotExport "github.com/Skorpsrgvch/reviewer-service/internal/usecase/snapshot/export"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+const contentTypeJSONLines = "application/x-ndjson"
+
+type ExportHandler struct {
+	usecase *snapshotExport.Usecase
+}
+
+func NewExportHandler(usecase *snapshotExport.Usecase) *ExportHandler {
+	return &ExportHandler{usecase: usecase}
+}
+
+// Handle отдаёт снимок базы потоком JSON Lines. Заголовки ответа пишутся вместе с первой строкой:
+// если ошибка случилась раньше, клиент получает обычный JSON с ошибкой, иначе поток обрывается
+// без завершающей строки и restore такой снимок не примет.
+func (h *ExportHandler) Handle(c *gin.Context) {
+	now := time.Now()
+	w := &lazyHeaderWriter{c: c, filename: fmt.Sprintf("reviewer-snapshot-%s.jsonl", now.UTC().Format("20060102T150405Z"))}
+
+	output, err := h.usecase.Execute(c.Request.Context(), snapshotExport.Input{W: w, Now: now})
+	if err != nil {
+		if !w.started {
+			common.HandleError(c, err)
+			return
+		}
+		logger.FromContext(c.Request.Context()).Error("snapshot export interrupted", "error", err)
+		c.Abort()
+		return
+	}
+
+	logger.FromContext(c.Request.Context()).Info("snapshot exported",
+		"schema_version", output.SchemaVersion, "rows", output.Rows)
+}
+
+// lazyHeaderWriter выставляет заголовки снимка перед первой записью в тело ответа
+type lazyHeaderWriter struct {
+	c        *gin.Context
+	filename string
+	started  bool
+}
+
+func (w *lazyHeaderWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		w.c.Header("Content-Type", contentTypeJSONLines)
+		w.c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", w.filename))
+		w.c.Status(http.StatusOK)
+	}
+	return w.c.Writer.Write(p)
+}
@@ -0,0 +1,62 @@
+package snapshot
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+
+	common "github.com/Skorpsrgvch/reviewer-service/internal/adapter/http/common"
+	snapshotRestore "github.com/Skorpsrgvch/reviewer-service/internal/usecase/snapshot/restore"
+	"github.com/gin-gonic/gin"
+)
+
+type restoreTableDTO struct {
+	Name string `json:"name"`
+	Rows int    `json:"rows"`
+}
+
+type restoreResponse struct {
+	SchemaVersion uint              `json:"schema_version"`
+	Rows          int               `json:"rows"`
+	Tables        []restoreTableDTO `json:"tables"`
+}
+
+type RestoreHandler struct {
+	usecase *snapshotRestore.Usecase
+}
+
+func NewRestoreHandler(usecase *snapshotRestore.Usecase) *RestoreHandler {
+	return &RestoreHandler{usecase: usecase}
+}
+
+// Handle загружает снимок из тела запроса. Без force=true восстановление в непустую базу
+// отклоняется с DATABASE_NOT_EMPTY.
+func (h *RestoreHandler) Handle(c *gin.Context) {
+	force := false
+	if v := c.Query("force"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			common.HandleError(c, common.HttpError("force must be true or false", http.StatusBadRequest))
+			return
+		}
+		force = parsed
+	}
+
+	output, err := h.usecase.Execute(c.Request.Context(), snapshotRestore.Input{R: c.Request.Body, Force: force})
+	if err != nil {
+		common.HandleError(c, err)
+		return
+	}
+
+	resp := restoreResponse{
+		SchemaVersion: output.SchemaVersion,
+		Rows:          output.Rows,
+		Tables:        make([]restoreTableDTO, 0, len(output.Tables)),
+	}
+	for name, rows := range output.Tables {
+		resp.Tables = append(resp.Tables, restoreTableDTO{Name: name, Rows: rows})
+	}
+	sort.Slice(resp.Tables, func(i, j int) bool { return resp.Tables[i].Name < resp.Tables[j].Name })
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -2,14 +2,50 @@ package stats
 
 import (
 	"net/http"
+	"time"
 
 	common "github.com/Skorpsrgvch/reviewer-service/internal/adapter/http/common"
 	statsUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/stats/get"
 	"github.com/gin-gonic/gin"
 )
 
+type timeToMergeDTO struct {
+	Merged      int     `json:"merged"`
+	MedianHours float64 `json:"median_hours"`
+	P90Hours    float64 `json:"p90_hours"`
+}
+
+type throughputDTO struct {
+	PeriodStart time.Time `json:"period_start"`
+	Created     int       `json:"created"`
+	Merged      int       `json:"merged"`
+}
+
+type reviewerLoadDTO struct {
+	UserID         string `json:"user_id"`
+	Assigned       int    `json:"assigned"`
+	Reviewed       int    `json:"reviewed"`
+	ReassignedAway int    `json:"reassigned_away"`
+}
+
+type matrixCellDTO struct {
+	AuthorID   string `json:"author_id"`
+	ReviewerID string `json:"reviewer_id"`
+	Count      int    `json:"count"`
+}
+
 type getStatsResponse struct {
-	Assignments map[string]int `json:"assignments"`
+	// Assignments — текущие назначения на OPEN PR; поле было в ответе с первой версии
+	Assignments   map[string]int    `json:"assignments"`
+	TeamName      string            `json:"team_name,omitempty"`
+	From          time.Time         `json:"from"`
+	To            time.Time         `json:"to"`
+	Bucket        string            `json:"bucket"`
+	TimeToMerge   timeToMergeDTO    `json:"time_to_merge"`
+	Throughput    []throughputDTO   `json:"throughput"`
+	Reviewers     []reviewerLoadDTO `json:"reviewers"`
+	Reassignments int               `json:"reassignments"`
+	Matrix        []matrixCellDTO   `json:"author_reviewer_matrix"`
 }
 
 type GetHandler struct {
@@ -21,11 +57,59 @@ func NewGetHandler(usecase *statsUC.Usecase) *GetHandler {
 }
 
 func (h *GetHandler) Handle(c *gin.Context) {
-	stats, err := h.usecase.Execute(c.Request.Context())
+	from, err := parseTimeParam(c.Query("from"))
+	if err != nil {
+		common.HandleError(c, common.HttpError("from must be a date (YYYY-MM-DD) or RFC 3339 timestamp", http.StatusBadRequest))
+		return
+	}
+	to, err := parseTimeParam(c.Query("to"))
+	if err != nil {
+		common.HandleError(c, common.HttpError("to must be a date (YYYY-MM-DD) or RFC 3339 timestamp", http.StatusBadRequest))
+		return
+	}
+
+	stats, err := h.usecase.Execute(c.Request.Context(), statsUC.Input{
+		TeamName: c.Query("team_name"),
+		From:     from,
+		To:       to,
+		Bucket:   c.Query("bucket"),
+		Now:      time.Now().UTC(),
+	})
 	if err != nil {
 		common.HandleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, getStatsResponse{Assignments: stats})
+	resp := getStatsResponse{
+		Assignments: stats.OpenAssignments,
+		TeamName:    stats.Filter.TeamName,
+		From:        stats.Filter.From,
+		To:          stats.Filter.To,
+		Bucket:      string(stats.Filter.Bucket),
+		TimeToMerge: timeToMergeDTO{
+			Merged:      stats.TimeToMerge.Merged,
+			MedianHours: stats.TimeToMerge.Median.Hours(),
+			P90Hours:    stats.TimeToMerge.P90.Hours(),
+		},
+		Throughput:    make([]throughputDTO, 0, len(stats.Throughput)),
+		Reviewers:     make([]reviewerLoadDTO, 0, len(stats.Reviewers)),
+		Reassignments: stats.Reassignments,
+		Matrix:        make([]matrixCellDTO, 0, len(stats.Matrix)),
+	}
+	for _, p := range stats.Throughput {
+		resp.Throughput = append(resp.Throughput, throughputDTO{PeriodStart: p.PeriodStart, Created: p.Created, Merged: p.Merged})
+	}
+	for _, r := range stats.Reviewers {
+		resp.Reviewers = append(resp.Reviewers, reviewerLoadDTO{
+			UserID:         r.UserID,
+			Assigned:       r.Assigned,
+			Reviewed:       r.Reviewed,
+			ReassignedAway: r.ReassignedAway,
+		})
+	}
+	for _, m := range stats.Matrix {
+		resp.Matrix = append(resp.Matrix, matrixCellDTO{AuthorID: m.AuthorID, ReviewerID: m.ReviewerID, Count: m.Count})
+	}
+
+	c.JSON(http.StatusOK, resp)
 }
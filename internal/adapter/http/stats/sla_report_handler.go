@@ -0,0 +1,105 @@
+package stats
+
+import (
+	"net/http"
+	"time"
+
+	common "github.com/Skorpsrgvch/reviewer-service/internal/adapter/http/common"
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	slaReportUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/stats/slaReport"
+	"github.com/gin-gonic/gin"
+)
+
+type slaStatsDTO struct {
+	Assignments int     `json:"assignments"`
+	Breached    int     `json:"breached"`
+	InProgress  int     `json:"in_progress"`
+	BreachRate  float64 `json:"breach_rate"`
+}
+
+type teamSLAStatsDTO struct {
+	TeamName string `json:"team_name"`
+	slaStatsDTO
+}
+
+type reviewerSLAStatsDTO struct {
+	ReviewerID string `json:"reviewer_id"`
+	slaStatsDTO
+}
+
+type slaReportResponse struct {
+	From      time.Time             `json:"from"`
+	To        time.Time             `json:"to"`
+	Teams     []teamSLAStatsDTO     `json:"teams"`
+	Reviewers []reviewerSLAStatsDTO `json:"reviewers"`
+}
+
+type SLAReportHandler struct {
+	usecase *slaReportUC.Usecase
+}
+
+func NewSLAReportHandler(usecase *slaReportUC.Usecase) *SLAReportHandler {
+	return &SLAReportHandler{usecase: usecase}
+}
+
+func (h *SLAReportHandler) Handle(c *gin.Context) {
+	from, err := parseTimeParam(c.Query("from"))
+	if err != nil {
+		common.HandleError(c, common.HttpError("from must be a date (YYYY-MM-DD) or RFC 3339 timestamp", http.StatusBadRequest))
+		return
+	}
+	to, err := parseTimeParam(c.Query("to"))
+	if err != nil {
+		common.HandleError(c, common.HttpError("to must be a date (YYYY-MM-DD) or RFC 3339 timestamp", http.StatusBadRequest))
+		return
+	}
+
+	report, err := h.usecase.Execute(c.Request.Context(), slaReportUC.Input{
+		TeamName: c.Query("team_name"),
+		From:     from,
+		To:       to,
+		Now:      time.Now().UTC(),
+	})
+	if err != nil {
+		common.HandleError(c, err)
+		return
+	}
+
+	resp := slaReportResponse{
+		From:      report.From,
+		To:        report.To,
+		Teams:     make([]teamSLAStatsDTO, 0, len(report.Teams)),
+		Reviewers: make([]reviewerSLAStatsDTO, 0, len(report.Reviewers)),
+	}
+	for _, s := range report.Teams {
+		resp.Teams = append(resp.Teams, teamSLAStatsDTO{TeamName: s.Key, slaStatsDTO: toSLAStatsDTO(s)})
+	}
+	for _, s := range report.Reviewers {
+		resp.Reviewers = append(resp.Reviewers, reviewerSLAStatsDTO{ReviewerID: s.Key, slaStatsDTO: toSLAStatsDTO(s)})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func toSLAStatsDTO(s domain.SLABreachStats) slaStatsDTO {
+	return slaStatsDTO{
+		Assignments: s.Assignments,
+		Breached:    s.Breached,
+		InProgress:  s.InProgress,
+		BreachRate:  s.BreachRate(),
+	}
+}
+
+// parseTimeParam разбирает дату (начало дня по UTC) или метку времени RFC 3339; пустая строка — нулевое время
+func parseTimeParam(raw string) (time.Time, error) {
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.DateOnly, raw); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.UTC(), nil
+}
@@ -0,0 +1,189 @@
+package team
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	common "github.com/Skorpsrgvch/reviewer-service/internal/adapter/http/common"
+	teamImport "github.com/Skorpsrgvch/reviewer-service/internal/usecase/team/bulkImport"
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportSize — ограничение размера загружаемого документа
+const maxImportSize = 10 << 20
+
+type importSummaryDTO struct {
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Moved     int `json:"moved"`
+	Unchanged int `json:"unchanged"`
+}
+
+type importUserDTO struct {
+	UserID       string   `json:"user_id"`
+	Username     string   `json:"username"`
+	TeamName     string   `json:"team_name"`
+	Action       string   `json:"action"`
+	PreviousTeam string   `json:"previous_team,omitempty"`
+	Fields       []string `json:"fields,omitempty"`
+}
+
+type importResponse struct {
+	DryRun       bool             `json:"dry_run"`
+	TeamsCreated []string         `json:"teams_created"`
+	Summary      importSummaryDTO `json:"summary"`
+	Users        []importUserDTO  `json:"users"`
+}
+
+type rowErrorDTO struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+type ImportHandler struct {
+	usecase *teamImport.Usecase
+}
+
+func NewImportHandler(usecase *teamImport.Usecase) *ImportHandler {
+	return &ImportHandler{usecase: usecase}
+}
+
+// Handle принимает YAML или CSV телом запроса либо файлом "file" в multipart/form-data.
+// Формат определяется параметром format, затем расширением файла или Content-Type.
+// dry_run=true возвращает изменения без сохранения.
+func (h *ImportHandler) Handle(c *gin.Context) {
+	dryRun, ok := boolQuery(c, "dry_run")
+	if !ok {
+		return
+	}
+
+	body, format, closeBody, ok := readTeamDocument(c)
+	if !ok {
+		return
+	}
+	defer closeBody()
+
+	output, err := h.usecase.Execute(c.Request.Context(), teamImport.Input{Data: body, Format: format, DryRun: dryRun})
+	if err != nil {
+		handleTeamDocumentError(c, err)
+		return
+	}
+
+	resp := importResponse{
+		DryRun:       output.DryRun,
+		TeamsCreated: append([]string{}, output.TeamsCreated...),
+		Summary: importSummaryDTO{
+			Created:   output.Count(teamImport.ActionCreated),
+			Updated:   output.Count(teamImport.ActionUpdated),
+			Moved:     output.Count(teamImport.ActionMoved),
+			Unchanged: output.Count(teamImport.ActionUnchanged),
+		},
+		Users: make([]importUserDTO, 0, len(output.Users)),
+	}
+	for _, u := range output.Users {
+		resp.Users = append(resp.Users, importUserDTO{
+			UserID:       u.UserID,
+			Username:     u.Username,
+			TeamName:     u.TeamName,
+			Action:       string(u.Action),
+			PreviousTeam: u.PreviousTeam,
+			Fields:       u.Fields,
+		})
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// boolQuery читает необязательный логический параметр; при ошибке сам пишет ответ
+func boolQuery(c *gin.Context, name string) (value bool, ok bool) {
+	v := c.Query(name)
+	if v == "" {
+		return false, true
+	}
+	parsed, err := strconv.ParseBool(v)
+	if err != nil {
+		common.HandleError(c, common.HttpError(name+" must be true or false", http.StatusBadRequest))
+		return false, false
+	}
+	return parsed, true
+}
+
+// readTeamDocument достаёт YAML или CSV из тела запроса либо из файла "file" в multipart/form-data.
+// Формат определяется параметром format, затем расширением файла или Content-Type.
+// При ошибке сам пишет ответ и возвращает ok=false.
+func readTeamDocument(c *gin.Context) (body io.Reader, format teamImport.Format, closeBody func(), ok bool) {
+	body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+	format = formatFromContentType(c.ContentType())
+	closeBody = func() {}
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		file, err := c.FormFile("file")
+		if err != nil {
+			common.HandleError(c, common.HttpError("file is required", http.StatusBadRequest))
+			return nil, "", nil, false
+		}
+		f, err := file.Open()
+		if err != nil {
+			common.HandleError(c, err)
+			return nil, "", nil, false
+		}
+		body = f
+		closeBody = func() { _ = f.Close() }
+		format = formatFromExtension(file.Filename)
+	}
+	if v := c.Query("format"); v != "" {
+		format = teamImport.Format(strings.ToLower(v))
+	}
+	if format == "" {
+		closeBody()
+		common.HandleError(c, common.HttpError("unknown document format, pass format=yaml or format=csv", http.StatusBadRequest))
+		return nil, "", nil, false
+	}
+	return body, format, closeBody, true
+}
+
+// handleTeamDocumentError отвечает на ошибки в строках документа списком строк, остальные — как обычно
+func handleTeamDocumentError(c *gin.Context, err error) {
+	var importErr *teamImport.ImportError
+	if !errors.As(err, &importErr) {
+		common.HandleError(c, err)
+		return
+	}
+	rows := make([]rowErrorDTO, 0, len(importErr.Rows))
+	for _, r := range importErr.Rows {
+		rows = append(rows, rowErrorDTO{Line: r.Line, Message: r.Message})
+	}
+	c.Set(common.ErrorCodeKey, "INVALID_PARAM")
+	c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+		"error": gin.H{
+			"code":    "INVALID_PARAM",
+			"message": "document contains invalid rows, nothing was applied",
+			"rows":    rows,
+		},
+	})
+}
+
+func formatFromContentType(contentType string) teamImport.Format {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "text/csv":
+		return teamImport.FormatCSV
+	case "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+		return teamImport.FormatYAML
+	}
+	return ""
+}
+
+func formatFromExtension(filename string) teamImport.Format {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return teamImport.FormatCSV
+	case ".yml", ".yaml":
+		return teamImport.FormatYAML
+	}
+	return ""
+}
@@ -0,0 +1,111 @@
+package team
+
+import (
+	"net/http"
+
+	teamReconcile "github.com/Skorpsrgvch/reviewer-service/internal/usecase/team/reconcile"
+	"github.com/gin-gonic/gin"
+)
+
+type reconcileSummaryDTO struct {
+	Created     int `json:"created"`
+	Updated     int `json:"updated"`
+	Moved       int `json:"moved"`
+	Removed     int `json:"removed"`
+	Deactivated int `json:"deactivated"`
+	Unchanged   int `json:"unchanged"`
+}
+
+type reconcileChangeDTO struct {
+	UserID       string   `json:"user_id"`
+	Username     string   `json:"username"`
+	TeamName     string   `json:"team_name,omitempty"`
+	Action       string   `json:"action"`
+	PreviousTeam string   `json:"previous_team,omitempty"`
+	IsActive     bool     `json:"is_active"`
+	Fields       []string `json:"fields,omitempty"`
+}
+
+type reconcileResponse struct {
+	Applied           bool                 `json:"applied"`
+	PlanHash          string               `json:"plan_hash"`
+	DeactivateMissing bool                 `json:"deactivate_missing"`
+	TeamsCreated      []string             `json:"teams_created"`
+	TeamsEmptied      []string             `json:"teams_emptied"`
+	Summary           reconcileSummaryDTO  `json:"summary"`
+	Changes           []reconcileChangeDTO `json:"changes"`
+}
+
+type ReconcileHandler struct {
+	usecase *teamReconcile.Usecase
+}
+
+func NewReconcileHandler(usecase *teamReconcile.Usecase) *ReconcileHandler {
+	return &ReconcileHandler{usecase: usecase}
+}
+
+// Plan показывает, что изменится при синхронизации с файлом, ничего не сохраняя
+func (h *ReconcileHandler) Plan(c *gin.Context) {
+	h.handle(c, false)
+}
+
+// Apply применяет синхронизацию. plan_hash из ответа Plan гарантирует, что применится
+// именно показанный план: если он устарел, возвращается PRECONDITION_FAILED.
+func (h *ReconcileHandler) Apply(c *gin.Context) {
+	h.handle(c, true)
+}
+
+func (h *ReconcileHandler) handle(c *gin.Context, apply bool) {
+	deactivateMissing, ok := boolQuery(c, "deactivate_missing")
+	if !ok {
+		return
+	}
+
+	body, format, closeBody, ok := readTeamDocument(c)
+	if !ok {
+		return
+	}
+	defer closeBody()
+
+	output, err := h.usecase.Execute(c.Request.Context(), teamReconcile.Input{
+		Data:              body,
+		Format:            format,
+		DeactivateMissing: deactivateMissing,
+		Apply:             apply,
+		PlanHash:          c.Query("plan_hash"),
+	})
+	if err != nil {
+		handleTeamDocumentError(c, err)
+		return
+	}
+
+	resp := reconcileResponse{
+		Applied:           output.Applied,
+		PlanHash:          output.PlanHash,
+		DeactivateMissing: deactivateMissing,
+		TeamsCreated:      append([]string{}, output.TeamsCreated...),
+		TeamsEmptied:      append([]string{}, output.TeamsEmptied...),
+		Summary: reconcileSummaryDTO{
+			Created:     output.Count(teamReconcile.ActionCreated),
+			Updated:     output.Count(teamReconcile.ActionUpdated),
+			Moved:       output.Count(teamReconcile.ActionMoved),
+			Removed:     output.Count(teamReconcile.ActionRemoved),
+			Deactivated: output.Count(teamReconcile.ActionDeactivated),
+			Unchanged:   output.Unchanged,
+		},
+		Changes: make([]reconcileChangeDTO, 0, len(output.Changes)),
+	}
+	for _, ch := range output.Changes {
+		resp.Changes = append(resp.Changes, reconcileChangeDTO{
+			UserID:       ch.UserID,
+			Username:     ch.Username,
+			TeamName:     ch.TeamName,
+			Action:       string(ch.Action),
+			PreviousTeam: ch.PreviousTeam,
+			IsActive:     ch.IsActive,
+			Fields:       ch.Fields,
+		})
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
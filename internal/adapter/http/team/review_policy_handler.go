@@ -0,0 +1,98 @@
+package team
+
+import (
+	"net/http"
+	"time"
+
+	common "github.com/Skorpsrgvch/reviewer-service/internal/adapter/http/common"
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	teamGetReviewPolicy "github.com/Skorpsrgvch/reviewer-service/internal/usecase/team/getReviewPolicy"
+	teamSetReviewPolicy "github.com/Skorpsrgvch/reviewer-service/internal/usecase/team/setReviewPolicy"
+	"github.com/gin-gonic/gin"
+)
+
+type setReviewPolicyRequest struct {
+	TeamName           string `json:"team_name" binding:"required"`
+	RemindAfterHours   int    `json:"remind_after_hours" binding:"required"`
+	EscalateAfterHours int    `json:"escalate_after_hours" binding:"required"`
+	Escalation         string `json:"escalation" binding:"required"`
+	LeadUserID         string `json:"lead_user_id"`
+}
+
+type reviewPolicyDTO struct {
+	TeamName           string `json:"team_name"`
+	RemindAfterHours   int    `json:"remind_after_hours"`
+	EscalateAfterHours int    `json:"escalate_after_hours"`
+	Escalation         string `json:"escalation"`
+	LeadUserID         string `json:"lead_user_id,omitempty"`
+	IsDefault          bool   `json:"is_default"`
+}
+
+type reviewPolicyResponse struct {
+	Policy reviewPolicyDTO `json:"policy"`
+}
+
+func toReviewPolicyDTO(p *domain.ReviewPolicy, isDefault bool) reviewPolicyDTO {
+	return reviewPolicyDTO{
+		TeamName:           p.TeamName(),
+		RemindAfterHours:   int(p.RemindAfter() / time.Hour),
+		EscalateAfterHours: int(p.EscalateAfter() / time.Hour),
+		Escalation:         string(p.Escalation()),
+		LeadUserID:         p.LeadID(),
+		IsDefault:          isDefault,
+	}
+}
+
+type SetReviewPolicyHandler struct {
+	usecase *teamSetReviewPolicy.Usecase
+}
+
+func NewSetReviewPolicyHandler(usecase *teamSetReviewPolicy.Usecase) *SetReviewPolicyHandler {
+	return &SetReviewPolicyHandler{usecase: usecase}
+}
+
+func (h *SetReviewPolicyHandler) Handle(c *gin.Context) {
+	var req setReviewPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.HandleError(c, err)
+		return
+	}
+
+	policy, err := h.usecase.Execute(c.Request.Context(), teamSetReviewPolicy.Input{
+		TeamName:      req.TeamName,
+		RemindAfter:   time.Duration(req.RemindAfterHours) * time.Hour,
+		EscalateAfter: time.Duration(req.EscalateAfterHours) * time.Hour,
+		Escalation:    domain.EscalationAction(req.Escalation),
+		LeadID:        req.LeadUserID,
+	})
+	if err != nil {
+		common.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reviewPolicyResponse{Policy: toReviewPolicyDTO(policy, false)})
+}
+
+type GetReviewPolicyHandler struct {
+	usecase *teamGetReviewPolicy.Usecase
+}
+
+func NewGetReviewPolicyHandler(usecase *teamGetReviewPolicy.Usecase) *GetReviewPolicyHandler {
+	return &GetReviewPolicyHandler{usecase: usecase}
+}
+
+func (h *GetReviewPolicyHandler) Handle(c *gin.Context) {
+	teamName := c.Query("team_name")
+	if teamName == "" {
+		common.HandleError(c, httpError("team_name is required", http.StatusBadRequest))
+		return
+	}
+
+	out, err := h.usecase.Execute(c.Request.Context(), teamGetReviewPolicy.Input{TeamName: teamName})
+	if err != nil {
+		common.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reviewPolicyResponse{Policy: toReviewPolicyDTO(out.Policy, out.IsDefault)})
+}
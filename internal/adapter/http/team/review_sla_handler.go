@@ -0,0 +1,50 @@
+package team
+
+import (
+	"net/http"
+	"time"
+
+	common "github.com/Skorpsrgvch/reviewer-service/internal/adapter/http/common"
+	teamSetReviewSLA "github.com/Skorpsrgvch/reviewer-service/internal/usecase/team/setReviewSLA"
+	"github.com/gin-gonic/gin"
+)
+
+type setReviewSLARequest struct {
+	TeamName         string `json:"team_name" binding:"required"`
+	FirstReviewHours int    `json:"first_review_hours" binding:"required"`
+}
+
+type reviewSLAResponse struct {
+	TeamName         string `json:"team_name"`
+	FirstReviewHours int    `json:"first_review_hours"`
+}
+
+type SetReviewSLAHandler struct {
+	usecase *teamSetReviewSLA.Usecase
+}
+
+func NewSetReviewSLAHandler(usecase *teamSetReviewSLA.Usecase) *SetReviewSLAHandler {
+	return &SetReviewSLAHandler{usecase: usecase}
+}
+
+func (h *SetReviewSLAHandler) Handle(c *gin.Context) {
+	var req setReviewSLARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.HandleError(c, err)
+		return
+	}
+
+	sla, err := h.usecase.Execute(c.Request.Context(), teamSetReviewSLA.Input{
+		TeamName:          req.TeamName,
+		FirstReviewWithin: time.Duration(req.FirstReviewHours) * time.Hour,
+	})
+	if err != nil {
+		common.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reviewSLAResponse{
+		TeamName:         sla.TeamName(),
+		FirstReviewHours: int(sla.FirstReviewWithin() / time.Hour),
+	})
+}
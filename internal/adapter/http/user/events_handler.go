@@ -0,0 +1,141 @@
+package user
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	common "github.com/Skorpsrgvch/reviewer-service/internal/adapter/http/common"
+	"github.com/Skorpsrgvch/reviewer-service/internal/adapter/sse"
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	userGetEvents "github.com/Skorpsrgvch/reviewer-service/internal/usecase/user/getEvents"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	heartbeatInterval = 15 * time.Second
+	// reconnectDelayMillis — рекомендуемая клиенту задержка переподключения
+	reconnectDelayMillis = 3000
+)
+
+// EventsHandler отдаёт ленту событий пользователя по Server-Sent Events.
+// Сначала досылает пропущенные события после Last-Event-ID, затем транслирует новые.
+// Без Last-Event-ID история не досылается.
+type EventsHandler struct {
+	usecase *userGetEvents.Usecase
+	broker  *sse.Broker
+}
+
+func NewEventsHandler(usecase *userGetEvents.Usecase, broker *sse.Broker) *EventsHandler {
+	return &EventsHandler{usecase: usecase, broker: broker}
+}
+
+func (h *EventsHandler) Handle(c *gin.Context) {
+	userID := c.Query("user_id")
+	if userID == "" {
+		common.HandleError(c, common.HttpError("user_id is required", http.StatusBadRequest))
+		return
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	// Без Last-Event-ID клиент подключается впервые: историю не досылаем, лента начинается с текущего события
+	input := userGetEvents.Input{UserID: userID, FromLatest: lastEventID == ""}
+	if lastEventID != "" {
+		var err error
+		input.AfterSequence, err = strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil || input.AfterSequence < 0 {
+			common.HandleError(c, common.HttpError("Last-Event-ID must be a non-negative integer", http.StatusBadRequest))
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+
+	// Подписываемся до чтения истории, чтобы не потерять события между чтением и подпиской
+	sub, err := h.broker.Subscribe(userID)
+	if err != nil {
+		common.HandleError(c, common.HttpError("server is shutting down", http.StatusServiceUnavailable))
+		return
+	}
+	defer h.broker.Unsubscribe(sub)
+
+	page, err := h.usecase.Execute(ctx, input)
+	if err != nil {
+		common.HandleError(c, err)
+		return
+	}
+	// Живые события не новее этого номера уже досланы историей или были до подключения
+	after := page.NextSequence
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	w := c.Writer
+	fmt.Fprintf(w, "retry: %d\n\n", reconnectDelayMillis)
+
+	// Досылаем историю; номера запоминаем, чтобы не отправить событие повторно из живой ленты
+	replayed := make(map[int64]struct{})
+	for {
+		for _, event := range page.Events {
+			if err := writeEvent(w, event); err != nil {
+				return
+			}
+			replayed[event.Sequence] = struct{}{}
+		}
+		if !page.HasMore {
+			break
+		}
+		page, err = h.usecase.Execute(ctx, userGetEvents.Input{UserID: userID, AfterSequence: page.NextSequence})
+		if err != nil {
+			return
+		}
+	}
+	w.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sub.Done():
+			return
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+				return
+			}
+			w.Flush()
+		case event := <-sub.Events():
+			if event.Sequence <= after {
+				continue
+			}
+			if _, ok := replayed[event.Sequence]; ok {
+				continue
+			}
+			if err := writeEvent(w, event); err != nil {
+				return
+			}
+			w.Flush()
+		}
+	}
+}
+
+// writeEvent пишет событие в формате SSE: id, тип и JSON в data
+func writeEvent(w io.Writer, event domain.UserEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Sequence, event.Kind, data)
+	return err
+}
@@ -0,0 +1,53 @@
+package user
+
+import (
+	"net/http"
+
+	common "github.com/Skorpsrgvch/reviewer-service/internal/adapter/http/common"
+	userSetEmail "github.com/Skorpsrgvch/reviewer-service/internal/usecase/user/setEmail"
+	"github.com/gin-gonic/gin"
+)
+
+type setEmailRequest struct {
+	UserID      string `json:"user_id" binding:"required"`
+	Email       string `json:"email"`
+	EmailDigest bool   `json:"email_digest"`
+}
+
+type setEmailResponse struct {
+	UserID      string `json:"user_id"`
+	Email       string `json:"email"`
+	EmailDigest bool   `json:"email_digest"`
+}
+
+type SetEmailHandler struct {
+	usecase *userSetEmail.Usecase
+}
+
+func NewSetEmailHandler(usecase *userSetEmail.Usecase) *SetEmailHandler {
+	return &SetEmailHandler{usecase: usecase}
+}
+
+func (h *SetEmailHandler) Handle(c *gin.Context) {
+	var req setEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.HandleError(c, err)
+		return
+	}
+
+	user, err := h.usecase.Execute(c.Request.Context(), userSetEmail.Input{
+		UserID: req.UserID,
+		Email:  req.Email,
+		Digest: req.EmailDigest,
+	})
+	if err != nil {
+		common.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, setEmailResponse{
+		UserID:      user.ID(),
+		Email:       user.Email(),
+		EmailDigest: user.EmailDigest(),
+	})
+}
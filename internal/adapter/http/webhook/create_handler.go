@@ -0,0 +1,53 @@
+package webhook
+
+import (
+	"net/http"
+
+	common "github.com/Skorpsrgvch/reviewer-service/internal/adapter/http/common"
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	webhookCreate "github.com/Skorpsrgvch/reviewer-service/internal/usecase/webhook/create"
+	"github.com/gin-gonic/gin"
+)
+
+type createWebhookRequest struct {
+	URL        string   `json:"url" binding:"required"`
+	Secret     string   `json:"secret" binding:"required"`
+	EventTypes []string `json:"event_types" binding:"required,min=1"`
+}
+
+type createWebhookResponse struct {
+	Webhook subscriptionDTO `json:"webhook"`
+}
+
+type CreateHandler struct {
+	usecase *webhookCreate.Usecase
+}
+
+func NewCreateHandler(usecase *webhookCreate.Usecase) *CreateHandler {
+	return &CreateHandler{usecase: usecase}
+}
+
+func (h *CreateHandler) Handle(c *gin.Context) {
+	var req createWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.HandleError(c, err)
+		return
+	}
+
+	types := make([]domain.EventType, 0, len(req.EventTypes))
+	for _, t := range req.EventTypes {
+		types = append(types, domain.EventType(t))
+	}
+
+	sub, err := h.usecase.Execute(c.Request.Context(), webhookCreate.Input{
+		URL:        req.URL,
+		Secret:     req.Secret,
+		EventTypes: types,
+	})
+	if err != nil {
+		common.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, createWebhookResponse{Webhook: toSubscriptionDTO(sub)})
+}
@@ -0,0 +1,36 @@
+package webhook
+
+import (
+	"net/http"
+
+	common "github.com/Skorpsrgvch/reviewer-service/internal/adapter/http/common"
+	webhookDelete "github.com/Skorpsrgvch/reviewer-service/internal/usecase/webhook/delete"
+	"github.com/gin-gonic/gin"
+)
+
+type deleteWebhookRequest struct {
+	ID int64 `json:"id" binding:"required"`
+}
+
+type DeleteHandler struct {
+	usecase *webhookDelete.Usecase
+}
+
+func NewDeleteHandler(usecase *webhookDelete.Usecase) *DeleteHandler {
+	return &DeleteHandler{usecase: usecase}
+}
+
+func (h *DeleteHandler) Handle(c *gin.Context) {
+	var req deleteWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.HandleError(c, err)
+		return
+	}
+
+	if err := h.usecase.Execute(c.Request.Context(), webhookDelete.Input{SubscriptionID: req.ID}); err != nil {
+		common.HandleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,88 @@
+package webhook
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	common "github.com/Skorpsrgvch/reviewer-service/internal/adapter/http/common"
+	webhookDeliveries "github.com/Skorpsrgvch/reviewer-service/internal/usecase/webhook/listDeliveries"
+	"github.com/gin-gonic/gin"
+)
+
+type deliveryDTO struct {
+	ID             int64   `json:"id"`
+	EventID        int64   `json:"event_id"`
+	EventType      string  `json:"event_type"`
+	Status         string  `json:"status"`
+	Attempts       int     `json:"attempts"`
+	NextAttemptAt  string  `json:"next_attempt_at"`
+	LastStatusCode *int    `json:"last_status_code,omitempty"`
+	LastError      string  `json:"last_error,omitempty"`
+	CreatedAt      string  `json:"created_at"`
+	DeliveredAt    *string `json:"delivered_at,omitempty"`
+}
+
+type listDeliveriesResponse struct {
+	WebhookID  int64         `json:"webhook_id"`
+	Deliveries []deliveryDTO `json:"deliveries"`
+}
+
+type DeliveriesHandler struct {
+	usecase *webhookDeliveries.Usecase
+}
+
+func NewDeliveriesHandler(usecase *webhookDeliveries.Usecase) *DeliveriesHandler {
+	return &DeliveriesHandler{usecase: usecase}
+}
+
+func (h *DeliveriesHandler) Handle(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Query("webhook_id"), 10, 64)
+	if err != nil {
+		common.HandleError(c, common.HttpError("webhook_id is required", http.StatusBadRequest))
+		return
+	}
+
+	limit := 0
+	if v := c.Query("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil {
+			common.HandleError(c, common.HttpError("limit must be a number", http.StatusBadRequest))
+			return
+		}
+	}
+
+	deliveries, err := h.usecase.Execute(c.Request.Context(), webhookDeliveries.Input{
+		SubscriptionID: id,
+		Limit:          limit,
+	})
+	if err != nil {
+		common.HandleError(c, err)
+		return
+	}
+
+	resp := listDeliveriesResponse{
+		WebhookID:  id,
+		Deliveries: make([]deliveryDTO, 0, len(deliveries)),
+	}
+	for _, d := range deliveries {
+		dto := deliveryDTO{
+			ID:             d.ID,
+			EventID:        d.EventSequence,
+			EventType:      string(d.EventType),
+			Status:         string(d.Status),
+			Attempts:       d.Attempts,
+			NextAttemptAt:  d.NextAttemptAt.Format(time.RFC3339),
+			LastStatusCode: d.LastStatusCode,
+			LastError:      d.LastError,
+			CreatedAt:      d.CreatedAt.Format(time.RFC3339),
+		}
+		if d.DeliveredAt != nil {
+			s := d.DeliveredAt.Format(time.RFC3339)
+			dto.DeliveredAt = &s
+		}
+		resp.Deliveries = append(resp.Deliveries, dto)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
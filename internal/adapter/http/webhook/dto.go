@@ -0,0 +1,30 @@
+package webhook
+
+import (
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+// subscriptionDTO — подписка в ответах API (секрет не возвращается)
+type subscriptionDTO struct {
+	ID         int64    `json:"id"`
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	IsActive   bool     `json:"is_active"`
+	CreatedAt  string   `json:"created_at"`
+}
+
+func toSubscriptionDTO(sub *domain.WebhookSubscription) subscriptionDTO {
+	types := make([]string, 0, len(sub.EventTypes()))
+	for _, t := range sub.EventTypes() {
+		types = append(types, string(t))
+	}
+	return subscriptionDTO{
+		ID:         sub.ID(),
+		URL:        sub.URL(),
+		EventTypes: types,
+		IsActive:   sub.IsActive(),
+		CreatedAt:  sub.CreatedAt().Format(time.RFC3339),
+	}
+}
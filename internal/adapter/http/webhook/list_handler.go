@@ -0,0 +1,36 @@
+package webhook
+
+import (
+	"net/http"
+
+	common "github.com/Skorpsrgvch/reviewer-service/internal/adapter/http/common"
+	webhookList "github.com/Skorpsrgvch/reviewer-service/internal/usecase/webhook/list"
+	"github.com/gin-gonic/gin"
+)
+
+type listWebhooksResponse struct {
+	Webhooks []subscriptionDTO `json:"webhooks"`
+}
+
+type ListHandler struct {
+	usecase *webhookList.Usecase
+}
+
+func NewListHandler(usecase *webhookList.Usecase) *ListHandler {
+	return &ListHandler{usecase: usecase}
+}
+
+func (h *ListHandler) Handle(c *gin.Context) {
+	subs, err := h.usecase.Execute(c.Request.Context())
+	if err != nil {
+		common.HandleError(c, err)
+		return
+	}
+
+	webhooks := make([]subscriptionDTO, 0, len(subs))
+	for i := range subs {
+		webhooks = append(webhooks, toSubscriptionDTO(&subs[i]))
+	}
+
+	c.JSON(http.StatusOK, listWebhooksResponse{Webhooks: webhooks})
+}
@@ -0,0 +1,36 @@
+package memory
+
+import (
+	"context"
+	"sync"
+)
+
+// Locker — аналог AdvisoryLocker для одного процесса: хранилище в памяти не разделяется
+// между репликами, поэтому достаточно не запускать одну задачу дважды параллельно.
+type Locker struct {
+	mu   sync.Mutex
+	held map[int64]bool
+}
+
+func NewLocker() *Locker {
+	return &Locker{held: make(map[int64]bool)}
+}
+
+// RunExclusive пытается взять блокировку key и выполнить fn.
+// Если задача с тем же key уже выполняется, возвращает false без ожидания.
+func (l *Locker) RunExclusive(ctx context.Context, key int64, fn func(ctx context.Context) error) (bool, error) {
+	l.mu.Lock()
+	if l.held[key] {
+		l.mu.Unlock()
+		return false, nil
+	}
+	l.held[key] = true
+	l.mu.Unlock()
+
+	defer func() {
+		l.mu.Lock()
+		delete(l.held, key)
+		l.mu.Unlock()
+	}()
+	return true, fn(ctx)
+}
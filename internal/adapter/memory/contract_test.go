@@ -0,0 +1,120 @@
+package memory_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/adapter/memory"
+	"github.com/Skorpsrgvch/reviewer-service/internal/adapter/storetest"
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+// testSchemaVersion — версия схемы хранилища в тестах; адаптер её только отдаёт
+const testSchemaVersion = 14
+
+func openRepos(s *memory.Store) storetest.Repos {
+	return storetest.Repos{
+		Teams:        memory.NewTeamRepo(s),
+		Users:        memory.NewUserRepo(s),
+		PullRequests: memory.NewPullRequestRepo(s),
+		Stats:        memory.NewStatsRepo(s),
+		Identities:   memory.NewIdentityRepo(s),
+		Policies:     memory.NewReviewPolicyRepo(s),
+		StalePRs:     memory.NewStalePullRequestRepo(s),
+		SLAs:         memory.NewReviewSLARepo(s),
+		Idempotency:  memory.NewIdempotencyRepo(s),
+		Outbox:       memory.NewOutboxRepo(s),
+		Snapshots:    memory.NewSnapshotRepo(s),
+	}
+}
+
+func TestContract(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) storetest.Repos {
+		return openRepos(memory.NewStore(testSchemaVersion))
+	})
+}
+
+// TestConcurrentAccess параллельно меняет и читает одно хранилище; смысл имеет под go test -race
+func TestConcurrentAccess(t *testing.T) {
+	r := openRepos(memory.NewStore(testSchemaVersion))
+	ctx := context.Background()
+
+	var members []domain.User
+	for i := range 6 {
+		u, err := domain.NewUser(fmt.Sprintf("u%d", i), fmt.Sprintf("user%d", i), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		members = append(members, *u)
+	}
+	team, err := domain.NewTeam("backend", members)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Teams.SaveTeam(ctx, team); err != nil {
+		t.Fatal(err)
+	}
+
+	const workers = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- runWorker(ctx, r, w)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	snapshot, err := r.Stats.GetReviewSnapshot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.OpenPullRequests != 0 {
+		t.Errorf("open PRs = %d, want 0: every worker merges its PRs", snapshot.OpenPullRequests)
+	}
+}
+
+// runWorker создаёт свои PR, переназначает ревьюера, фиксирует ревью и мержит, читая между шагами
+// общие для всех воркеров статистику и назначения
+func runWorker(ctx context.Context, r storetest.Repos, worker int) error {
+	for i := range 20 {
+		id := fmt.Sprintf("pr-%d-%d", worker, i)
+		pr, err := domain.NewPullRequest(id, "PR "+id, "u0", []string{"u1", "u2"})
+		if err != nil {
+			return err
+		}
+		if err := r.PullRequests.Save(ctx, pr); err != nil {
+			return fmt.Errorf("save %s: %w", id, err)
+		}
+		if err := r.PullRequests.UpdateReviewers(ctx, id, []string{"u3", "u2"}, 1); err != nil {
+			return fmt.Errorf("reassign %s: %w", id, err)
+		}
+		if _, _, err := r.PullRequests.RecordReviewAction(ctx, id, "u3", time.Now().UTC()); err != nil {
+			return fmt.Errorf("review %s: %w", id, err)
+		}
+		if _, err := r.Stats.GetReviewerStats(ctx, "backend"); err != nil {
+			return err
+		}
+		if _, err := r.PullRequests.GetByReviewer(ctx, "u3"); err != nil {
+			return err
+		}
+		if _, err := r.SLAs.ListSLAViolations(ctx, "", time.Now().UTC(), time.Now().UTC(), time.Hour); err != nil {
+			return err
+		}
+		if err := r.PullRequests.Merge(ctx, id, time.Now().UTC(), 2); err != nil {
+			return fmt.Errorf("merge %s: %w", id, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package memory
+
+import "context"
+
+// DBRepo отвечает на проверки здоровья так же, как адаптер PostgreSQL
+type DBRepo struct {
+	s *Store
+}
+
+func NewDBRepo(s *Store) *DBRepo {
+	return &DBRepo{s: s}
+}
+
+// Ping всегда успешен: хранилище в памяти доступно, пока жив процесс
+func (r *DBRepo) Ping(ctx context.Context) error {
+	return nil
+}
+
+// MigrationVersion возвращает версию схемы, которой соответствует хранилище
+func (r *DBRepo) MigrationVersion(ctx context.Context) (version uint, dirty bool, err error) {
+	return r.s.schemaVersion, false, nil
+}
@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+// EmailDigestRepo хранит события, отложенные до ежедневного дайджеста
+type EmailDigestRepo struct {
+	s *Store
+}
+
+func NewEmailDigestRepo(s *Store) *EmailDigestRepo {
+	return &EmailDigestRepo{s: s}
+}
+
+func (r *EmailDigestRepo) AddDigestItem(ctx context.Context, userID string, eventSequence int64, summary string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.users[userID]; !ok {
+		return domain.ErrUserNotFound
+	}
+	for _, item := range r.s.digestItems {
+		if item.userID == userID && item.eventID == eventSequence {
+			return nil
+		}
+	}
+	r.s.digestItems = append(r.s.digestItems, &digestItemRow{
+		id:        r.s.nextID("email_digest_items"),
+		userID:    userID,
+		eventID:   eventSequence,
+		summary:   summary,
+		createdAt: time.Now().UTC(),
+	})
+	return nil
+}
+
+// FlushDigests собирает накопленные события по пользователям и ставит дайджесты в очередь уведомлений.
+// Пользователи, сменившие адрес на пустой, пропускаются, их события помечаются отправленными.
+// При ошибке render не применяется ничего.
+func (r *EmailDigestRepo) FlushDigests(ctx context.Context, render func(batch domain.DigestBatch) (domain.NotificationDraft, error)) (int, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var items []*digestItemRow
+	for _, item := range r.s.digestItems {
+		if item.sentAt == nil {
+			if _, ok := r.s.users[item.userID]; ok {
+				items = append(items, item)
+			}
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].userID != items[j].userID {
+			return items[i].userID < items[j].userID
+		}
+		return items[i].id < items[j].id
+	})
+
+	var batches []domain.DigestBatch
+	for _, item := range items {
+		if n := len(batches); n == 0 || batches[n-1].UserID != item.userID {
+			user := r.s.users[item.userID]
+			address := ""
+			if user.Email != nil {
+				address = *user.Email
+			}
+			batches = append(batches, domain.DigestBatch{UserID: user.ID, Username: user.Username, Email: address})
+		}
+		batches[len(batches)-1].Items = append(batches[len(batches)-1].Items, item.summary)
+	}
+
+	var drafts []domain.NotificationDraft
+	for _, batch := range batches {
+		if batch.Email == "" {
+			continue
+		}
+		draft, err := render(batch)
+		if err != nil {
+			return 0, err
+		}
+		drafts = append(drafts, draft)
+	}
+
+	for _, draft := range drafts {
+		r.s.insertNotification(nil, draft)
+	}
+	now := time.Now().UTC()
+	for _, item := range items {
+		item.sentAt = &now
+	}
+	return len(drafts), nil
+}
@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+// EventNotifySink — outbox-sink, который передаёт опубликованные события слушателям этого процесса.
+// Хранилище в памяти не разделяется между репликами, поэтому оповещать другие процессы не нужно.
+type EventNotifySink struct {
+	s *Store
+}
+
+func NewEventNotifySink(s *Store) *EventNotifySink {
+	return &EventNotifySink{s: s}
+}
+
+func (n *EventNotifySink) Name() string { return "notify" }
+
+func (n *EventNotifySink) Publish(ctx context.Context, event domain.Event) error {
+	n.s.listenersMu.Lock()
+	defer n.s.listenersMu.Unlock()
+	for _, handle := range n.s.listeners {
+		handle(event)
+	}
+	return nil
+}
+
+// EventListener получает события, опубликованные через EventNotifySink того же хранилища.
+type EventListener struct {
+	s *Store
+}
+
+func NewEventListener(s *Store) *EventListener {
+	return &EventListener{s: s}
+}
+
+// Run вызывает handle для каждого опубликованного события до отмены контекста.
+func (l *EventListener) Run(ctx context.Context, handle func(event domain.Event)) {
+	l.s.listenersMu.Lock()
+	l.s.listenerSeq++
+	id := l.s.listenerSeq
+	l.s.listeners[id] = handle
+	l.s.listenersMu.Unlock()
+
+	<-ctx.Done()
+
+	l.s.listenersMu.Lock()
+	delete(l.s.listeners, id)
+	l.s.listenersMu.Unlock()
+}
@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+// IdempotencyRepo хранит ответы на запросы с заголовком Idempotency-Key.
+type IdempotencyRepo struct {
+	s *Store
+}
+
+func NewIdempotencyRepo(s *Store) *IdempotencyRepo {
+	return &IdempotencyRepo{s: s}
+}
+
+// Reserve занимает ключ для маршрута. Просроченная запись перезаписывается.
+// Если ключ уже занят живой записью — возвращает её, иначе nil.
+func (r *IdempotencyRepo) Reserve(ctx context.Context, key, route, requestHash string, ttl time.Duration) (*domain.IdempotencyRecord, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := time.Now()
+	k := idempotencyKey{key: key, route: route}
+	if row, ok := r.s.idempotency[k]; ok && !row.expiresAt.Before(now) {
+		record := &domain.IdempotencyRecord{
+			RequestHash: row.requestHash,
+			Body:        append([]byte(nil), row.responseBody...),
+			Completed:   row.statusCode != nil,
+		}
+		if row.statusCode != nil {
+			record.StatusCode = *row.statusCode
+		}
+		return record, nil
+	}
+
+	r.s.idempotency[k] = &idempotencyRow{requestHash: requestHash, createdAt: now, expiresAt: now.Add(ttl)}
+	return nil, nil
+}
+
+// Complete сохраняет ответ для ранее зарезервированного ключа.
+func (r *IdempotencyRepo) Complete(ctx context.Context, key, route string, statusCode int, body []byte) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if row, ok := r.s.idempotency[idempotencyKey{key: key, route: route}]; ok {
+		row.statusCode = &statusCode
+		row.responseBody = append([]byte(nil), body...)
+	}
+	return nil
+}
+
+// Release освобождает ключ, чтобы повторный запрос выполнился заново.
+func (r *IdempotencyRepo) Release(ctx context.Context, key, route string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	k := idempotencyKey{key: key, route: route}
+	if row, ok := r.s.idempotency[k]; ok && row.statusCode == nil {
+		delete(r.s.idempotency, k)
+	}
+	return nil
+}
+
+// DeleteExpired удаляет просроченные ключи и возвращает их количество.
+func (r *IdempotencyRepo) DeleteExpired(ctx context.Context) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := time.Now()
+	var deleted int64
+	for k, row := range r.s.idempotency {
+		if row.expiresAt.Before(now) {
+			delete(r.s.idempotency, k)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,135 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+// IdentityRepo хранит привязки внешних учётных записей к пользователям
+type IdentityRepo struct {
+	s *Store
+}
+
+func NewIdentityRepo(s *Store) *IdentityRepo {
+	return &IdentityRepo{s: s}
+}
+
+func (r *IdentityRepo) CreateIdentity(ctx context.Context, identity *domain.UserIdentity) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	key := identityKey{provider: identity.Provider(), externalID: identity.ExternalID()}
+	if _, ok := r.s.identities[key]; ok {
+		return domain.ErrIdentityExists
+	}
+	if _, ok := r.s.users[identity.UserID()]; !ok {
+		return domain.ErrUserNotFound
+	}
+	r.s.identities[key] = newIdentityRow(identity)
+	return nil
+}
+
+// ListIdentities возвращает привязки с необязательными фильтрами по пользователю и провайдеру
+func (r *IdentityRepo) ListIdentities(ctx context.Context, userID, provider string) ([]domain.UserIdentity, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var identities []domain.UserIdentity
+	for _, row := range r.s.identities {
+		if (userID != "" && row.UserID != userID) || (provider != "" && row.Provider != provider) {
+			continue
+		}
+		identities = append(identities, *domain.RestoreUserIdentity(row.Provider, row.ExternalID, row.UserID, row.CreatedAt.Time))
+	}
+	sort.Slice(identities, func(i, j int) bool {
+		if identities[i].Provider() != identities[j].Provider() {
+			return identities[i].Provider() < identities[j].Provider()
+		}
+		return identities[i].ExternalID() < identities[j].ExternalID()
+	})
+	return identities, nil
+}
+
+func (r *IdentityRepo) DeleteIdentity(ctx context.Context, provider, externalID string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	key := identityKey{provider: provider, externalID: externalID}
+	if _, ok := r.s.identities[key]; !ok {
+		return domain.ErrIdentityNotFound
+	}
+	delete(r.s.identities, key)
+	return nil
+}
+
+// LookupUser возвращает пользователя, привязанного к учётной записи провайдера
+func (r *IdentityRepo) LookupUser(ctx context.Context, provider, externalID string) (*domain.User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	return r.s.lookupIdentityUser(provider, externalID)
+}
+
+// ExternalIDForUser возвращает учётную запись пользователя у провайдера (самую раннюю, если их несколько)
+func (r *IdentityRepo) ExternalIDForUser(ctx context.Context, provider, userID string) (string, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var earliest *identityRow
+	for _, row := range r.s.identities {
+		if row.Provider != provider || row.UserID != userID {
+			continue
+		}
+		if earliest == nil || row.CreatedAt.Before(earliest.CreatedAt.Time) ||
+			(row.CreatedAt.Equal(earliest.CreatedAt.Time) && row.ExternalID < earliest.ExternalID) {
+			earliest = row
+		}
+	}
+	if earliest == nil {
+		return "", domain.ErrIdentityNotFound
+	}
+	return earliest.ExternalID, nil
+}
+
+// ImportIdentities создаёт или перепривязывает учётные записи разом:
+// при ошибке в любой строке не применяется ни одна.
+func (r *IdentityRepo) ImportIdentities(ctx context.Context, identities []domain.UserIdentity) (created, updated int, err error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, identity := range identities {
+		if _, ok := r.s.users[identity.UserID()]; !ok {
+			return 0, 0, domain.ErrUserNotFound
+		}
+	}
+	for _, identity := range identities {
+		key := identityKey{provider: identity.Provider(), externalID: identity.ExternalID()}
+		if row, ok := r.s.identities[key]; ok {
+			row.UserID = identity.UserID()
+			updated++
+			continue
+		}
+		r.s.identities[key] = newIdentityRow(&identity)
+		created++
+	}
+	return created, updated, nil
+}
+
+func newIdentityRow(identity *domain.UserIdentity) *identityRow {
+	return &identityRow{
+		Provider:   identity.Provider(),
+		ExternalID: identity.ExternalID(),
+		UserID:     identity.UserID(),
+		CreatedAt:  newTimestamp(identity.CreatedAt()),
+	}
+}
+
+func (s *Store) lookupIdentityUser(provider, externalID string) (*domain.User, error) {
+	identity, ok := s.identities[identityKey{provider: provider, externalID: externalID}]
+	if !ok {
+		return nil, domain.ErrIdentityNotFound
+	}
+	row := s.users[identity.UserID]
+	return domain.NewUser(row.ID, row.Username, row.IsActive)
+}
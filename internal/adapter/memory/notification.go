@@ -0,0 +1,139 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+const (
+	notificationPending = "PENDING"
+	notificationSent    = "SENT"
+	notificationFailed  = "FAILED"
+)
+
+// NotificationRepo — очередь уведомлений
+type NotificationRepo struct {
+	s *Store
+}
+
+func NewNotificationRepo(s *Store) *NotificationRepo {
+	return &NotificationRepo{s: s}
+}
+
+func (r *NotificationRepo) EnqueueNotifications(ctx context.Context, eventSequence int64, drafts []domain.NotificationDraft) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, d := range drafts {
+		if r.s.hasNotification(eventSequence, d) {
+			continue
+		}
+		eventID := eventSequence
+		r.s.insertNotification(&eventID, d)
+	}
+	return nil
+}
+
+func (s *Store) hasNotification(eventID int64, d domain.NotificationDraft) bool {
+	for _, n := range s.notifications {
+		if n.eventID != nil && *n.eventID == eventID && n.channel == string(d.Channel) && n.target == d.Target {
+			return true
+		}
+	}
+	return false
+}
+
+// insertNotification ставит уведомление в очередь; eventID == nil — уведомление не связано с событием
+func (s *Store) insertNotification(eventID *int64, d domain.NotificationDraft) {
+	now := time.Now().UTC()
+	s.notifications = append(s.notifications, &notificationRow{
+		id:            s.nextID("notifications"),
+		eventID:       eventID,
+		channel:       string(d.Channel),
+		target:        d.Target,
+		subject:       d.Subject,
+		body:          d.Body,
+		htmlBody:      d.HTMLBody,
+		status:        notificationPending,
+		nextAttemptAt: now,
+		createdAt:     now,
+	})
+}
+
+// ClaimDueNotifications захватывает уведомления, время которых пришло, сдвигая next_attempt_at на lease.
+func (r *NotificationRepo) ClaimDueNotifications(ctx context.Context, limit int, lease time.Duration) ([]domain.Notification, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := time.Now().UTC()
+	var due []*notificationRow
+	for _, n := range r.s.notifications {
+		if n.status == notificationPending && !n.nextAttemptAt.After(now) {
+			due = append(due, n)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool { return due[i].nextAttemptAt.Before(due[j].nextAttemptAt) })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	messages := make([]domain.Notification, 0, len(due))
+	for _, n := range due {
+		n.attempts++
+		n.nextAttemptAt = now.Add(lease)
+		messages = append(messages, domain.Notification{
+			ID:       n.id,
+			Attempts: n.attempts,
+			Channel:  domain.NotificationChannel(n.channel),
+			Target:   n.target,
+			Subject:  n.subject,
+			Body:     n.body,
+			HTMLBody: n.htmlBody,
+		})
+	}
+	return messages, nil
+}
+
+func (r *NotificationRepo) MarkNotificationSent(ctx context.Context, id int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if n := r.s.findNotification(id); n != nil {
+		now := time.Now().UTC()
+		n.status = notificationSent
+		n.lastError = ""
+		n.sentAt = &now
+	}
+	return nil
+}
+
+// MarkNotificationFailed записывает неудачную попытку и планирует следующую.
+// Если nextAttemptAt == nil, уведомление переводится в FAILED.
+func (r *NotificationRepo) MarkNotificationFailed(ctx context.Context, id int64, errMsg string, nextAttemptAt *time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	n := r.s.findNotification(id)
+	if n == nil {
+		return nil
+	}
+	n.lastError = errMsg
+	if nextAttemptAt == nil {
+		n.status = notificationFailed
+		return nil
+	}
+	n.nextAttemptAt = nextAttemptAt.UTC()
+	return nil
+}
+
+func (s *Store) findNotification(id int64) *notificationRow {
+	for _, n := range s.notifications {
+		if n.id == id {
+			return n
+		}
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+// OutboxRepo читает outbox для фонового relay.
+type OutboxRepo struct {
+	s *Store
+}
+
+func NewOutboxRepo(s *Store) *OutboxRepo {
+	return &OutboxRepo{s: s}
+}
+
+// ClaimDueEvents захватывает готовые к отправке события, сдвигая next_attempt_at на lease.
+func (r *OutboxRepo) ClaimDueEvents(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboxAttempt, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := time.Now()
+	var attempts []domain.OutboxAttempt
+	for _, row := range r.s.outbox {
+		if len(attempts) >= limit {
+			break
+		}
+		if row.PublishedAt != nil || row.NextAttemptAt.After(now) {
+			continue
+		}
+		row.Attempts++
+		row.NextAttemptAt = newTimestamp(now.Add(lease))
+		attempts = append(attempts, domain.OutboxAttempt{Event: row.toDomain(), Attempts: row.Attempts})
+	}
+	return attempts, nil
+}
+
+// MarkEventPublished отмечает событие опубликованным.
+func (r *OutboxRepo) MarkEventPublished(ctx context.Context, sequence int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if row := r.s.findEvent(sequence); row != nil {
+		now := time.Now()
+		row.PublishedAt = timestampPtr(&now)
+		row.LastError = nil
+	}
+	return nil
+}
+
+// MarkEventFailed записывает неудачную попытку публикации и откладывает событие до nextAttemptAt.
+func (r *OutboxRepo) MarkEventFailed(ctx context.Context, sequence int64, errMsg string, nextAttemptAt time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if row := r.s.findEvent(sequence); row != nil {
+		row.LastError = &errMsg
+		row.NextAttemptAt = newTimestamp(nextAttemptAt)
+	}
+	return nil
+}
+
+// Backlog возвращает число неопубликованных событий и время создания самого старого.
+func (r *OutboxRepo) Backlog(ctx context.Context) (int64, *time.Time, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var (
+		pending int64
+		oldest  *time.Time
+	)
+	for _, row := range r.s.outbox {
+		if row.PublishedAt != nil {
+			continue
+		}
+		pending++
+		if oldest == nil || row.CreatedAt.Before(*oldest) {
+			oldest = row.CreatedAt.timePtr()
+		}
+	}
+	return pending, oldest, nil
+}
+
+// GetEvent возвращает событие outbox по порядковому номеру.
+func (r *OutboxRepo) GetEvent(ctx context.Context, sequence int64) (*domain.Event, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	row := r.s.findEvent(sequence)
+	if row == nil {
+		return nil, errors.New("outbox event not found")
+	}
+	event := row.toDomain()
+	return &event, nil
+}
+
+// LatestSequence возвращает порядковый номер последнего события outbox или 0, если событий нет.
+func (r *OutboxRepo) LatestSequence(ctx context.Context) (int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	if len(r.s.outbox) == 0 {
+		return 0, nil
+	}
+	return r.s.outbox[len(r.s.outbox)-1].ID, nil
+}
+
+// ListUserEventsAfter возвращает события PR, касающиеся пользователя, с порядковым номером
+// больше afterSequence — для возобновления SSE-ленты по Last-Event-ID.
+func (r *OutboxRepo) ListUserEventsAfter(ctx context.Context, userID string, afterSequence int64, limit int) ([]domain.Event, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var events []domain.Event
+	for _, row := range r.s.outbox {
+		if len(events) >= limit {
+			break
+		}
+		if row.ID <= afterSequence || !concernsUser(row, userID) {
+			continue
+		}
+		events = append(events, row.toDomain())
+	}
+	return events, nil
+}
+
+// concernsUser отбирает события ленты пользователя по тем же правилам, что domain.UserEventsFromEvent
+func concernsUser(row *outboxRow, userID string) bool {
+	userEvents, err := domain.UserEventsFromEvent(row.toDomain())
+	if err != nil {
+		return false
+	}
+	for _, ue := range userEvents {
+		if ue.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,261 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+// PullRequestRepo хранит PullRequest в памяти с той же семантикой ошибок, что и PostgreSQL.
+type PullRequestRepo struct {
+	s *Store
+}
+
+func NewPullRequestRepo(s *Store) *PullRequestRepo {
+	return &PullRequestRepo{s: s}
+}
+
+// Save сохраняет новый PullRequest (только в статусе OPEN)
+// и вместе с ним пишет событие pull_request.created в outbox.
+func (r *PullRequestRepo) Save(ctx context.Context, pr *domain.PullRequest) error {
+	if pr.Status() != domain.PROpen {
+		return errors.New("only OPEN pull requests can be saved")
+	}
+
+	event, err := domain.NewPullRequestEvent(domain.EventPRCreated, pr, "", "")
+	if err != nil {
+		return err
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.pullRequests[pr.ID()]; ok {
+		return domain.ErrPRExists
+	}
+	if _, ok := r.s.users[pr.AuthorID()]; !ok {
+		return domain.ErrAuthorNotFound
+	}
+	for _, id := range pr.AssignedReviewers() {
+		if _, ok := r.s.users[id]; !ok {
+			return domain.ErrUserNotFound
+		}
+	}
+
+	r.s.pullRequests[pr.ID()] = &pullRequestRow{
+		ID:                pr.ID(),
+		Name:              pr.Name(),
+		AuthorID:          pr.AuthorID(),
+		Status:            string(pr.Status()),
+		AssignedReviewers: append([]string(nil), pr.AssignedReviewers()...),
+		CreatedAt:         newTimestamp(pr.CreatedAt()),
+		Version:           pr.Version(),
+	}
+	r.s.syncReviewAssignments(pr.ID(), nil, pr.AssignedReviewers(), pr.CreatedAt())
+	r.s.insertEvent(event)
+	return nil
+}
+
+// GetByID возвращает PullRequest по ID.
+func (r *PullRequestRepo) GetByID(ctx context.Context, id string) (*domain.PullRequest, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	row, ok := r.s.pullRequests[id]
+	if !ok {
+		return nil, domain.ErrPRNotFound
+	}
+	return row.toDomain()
+}
+
+// PRExists проверяет существование PR по ID.
+func (r *PullRequestRepo) PRExists(ctx context.Context, id string) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	_, ok := r.s.pullRequests[id]
+	return ok, nil
+}
+
+// UpdateReviewers обновляет список ревьюеров у существующего PR,
+// если его версия всё ещё равна expectedVersion. Иначе — ErrPRVersionConflict.
+// Вместе с изменением пишет событие pull_request.reviewer_reassigned в outbox.
+func (r *PullRequestRepo) UpdateReviewers(ctx context.Context, id string, reviewers []string, expectedVersion int) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	row, ok := r.s.pullRequests[id]
+	if !ok {
+		return domain.ErrPRNotFound
+	}
+	if row.Version != expectedVersion {
+		return domain.ErrPRVersionConflict
+	}
+	for _, reviewerID := range reviewers {
+		if _, ok := r.s.users[reviewerID]; !ok {
+			return domain.ErrUserNotFound
+		}
+	}
+
+	pr, err := domain.RestorePullRequest(id, row.Name, row.AuthorID, domain.PRStatus(row.Status), reviewers,
+		row.CreatedAt.Time, nil, expectedVersion+1)
+	if err != nil {
+		return err
+	}
+	oldReviewer, newReviewer := diffReviewers(row.AssignedReviewers, reviewers)
+	event, err := domain.NewPullRequestEvent(domain.EventReviewerReassigned, pr, oldReviewer, newReviewer)
+	if err != nil {
+		return err
+	}
+
+	oldReviewers := row.AssignedReviewers
+	row.AssignedReviewers = append([]string(nil), reviewers...)
+	row.Version++
+	r.s.syncReviewAssignments(id, oldReviewers, reviewers, time.Now().UTC())
+	r.s.insertEvent(event)
+	return nil
+}
+
+// Merge переводит PR в статус MERGED с указанным временем,
+// если его версия всё ещё равна expectedVersion.
+// Вместе с изменением пишет событие pull_request.merged в outbox.
+// Идемпотентен: если уже MERGED — не ошибка.
+func (r *PullRequestRepo) Merge(ctx context.Context, id string, mergedAt time.Time, expectedVersion int) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	row, ok := r.s.pullRequests[id]
+	if !ok {
+		return domain.ErrPRNotFound
+	}
+	switch domain.PRStatus(row.Status) {
+	case domain.PRMerged:
+		return nil
+	case domain.PRClosed:
+		return domain.ErrPRClosed
+	}
+	if row.Version != expectedVersion {
+		return domain.ErrPRVersionConflict
+	}
+
+	pr, err := domain.RestorePullRequest(id, row.Name, row.AuthorID, domain.PRMerged,
+		append([]string(nil), row.AssignedReviewers...), row.CreatedAt.Time, &mergedAt, row.Version+1)
+	if err != nil {
+		return err
+	}
+	event, err := domain.NewPullRequestEvent(domain.EventPRMerged, pr, "", "")
+	if err != nil {
+		return err
+	}
+
+	row.Status = string(domain.PRMerged)
+	row.MergedAt = timestampPtr(&mergedAt)
+	row.Version++
+	r.s.insertEvent(event)
+	return nil
+}
+
+// Close переводит открытый PR в статус CLOSED, если его версия всё ещё равна expectedVersion,
+// и снимает текущие назначения ревьюеров на момент closedAt: закрытый PR не ждёт ревью.
+// Вместе с изменением пишет событие pull_request.closed в outbox.
+// Идемпотентен: если уже CLOSED — не ошибка.
+func (r *PullRequestRepo) Close(ctx context.Context, id string, closedAt time.Time, expectedVersion int) error {
+	return r.transition(id, domain.PROpen, domain.PRClosed, closedAt, expectedVersion)
+}
+
+// Reopen возвращает закрытый PR в статус OPEN, если его версия всё ещё равна expectedVersion,
+// и заново назначает тех же ревьюеров с момента reopenedAt.
+// Вместе с изменением пишет событие pull_request.reopened в outbox.
+// Идемпотентен: если PR уже OPEN — не ошибка.
+func (r *PullRequestRepo) Reopen(ctx context.Context, id string, reopenedAt time.Time, expectedVersion int) error {
+	return r.transition(id, domain.PRClosed, domain.PROpen, reopenedAt, expectedVersion)
+}
+
+// transition переводит PR между статусами OPEN и CLOSED
+func (r *PullRequestRepo) transition(id string, from, to domain.PRStatus, at time.Time, expectedVersion int) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	row, ok := r.s.pullRequests[id]
+	if !ok {
+		return domain.ErrPRNotFound
+	}
+	switch domain.PRStatus(row.Status) {
+	case to:
+		return nil
+	case domain.PRMerged:
+		return domain.ErrPRAlreadyMerged
+	case from:
+	default:
+		return domain.ErrPRClosed
+	}
+	if row.Version != expectedVersion {
+		return domain.ErrPRVersionConflict
+	}
+
+	reviewers := append([]string(nil), row.AssignedReviewers...)
+	pr, err := domain.RestorePullRequest(id, row.Name, row.AuthorID, to, reviewers, row.CreatedAt.Time, nil, row.Version+1)
+	if err != nil {
+		return err
+	}
+	eventType := domain.EventPRReopened
+	oldReviewers, newReviewers := []string(nil), reviewers
+	if to == domain.PRClosed {
+		eventType = domain.EventPRClosed
+		oldReviewers, newReviewers = newReviewers, oldReviewers
+	}
+	event, err := domain.NewPullRequestEvent(eventType, pr, "", "")
+	if err != nil {
+		return err
+	}
+
+	row.Status = string(to)
+	row.Version++
+	r.s.syncReviewAssignments(id, oldReviewers, newReviewers, at)
+	r.s.insertEvent(event)
+	return nil
+}
+
+// GetByReviewer возвращает все PR, где reviewerID в assigned_reviewers.
+func (r *PullRequestRepo) GetByReviewer(ctx context.Context, reviewerID string) ([]domain.PullRequest, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var prs []domain.PullRequest
+	for _, row := range r.s.pullRequests {
+		if !containsString(row.AssignedReviewers, reviewerID) {
+			continue
+		}
+		pr, err := row.toDomain()
+		if err != nil {
+			return nil, err
+		}
+		prs = append(prs, *pr)
+	}
+	sort.Slice(prs, func(i, j int) bool {
+		if !prs[i].CreatedAt().Equal(prs[j].CreatedAt()) {
+			return prs[i].CreatedAt().Before(prs[j].CreatedAt())
+		}
+		return prs[i].ID() < prs[j].ID()
+	})
+	return prs, nil
+}
+
+// diffReviewers находит снятого и нового ревьюера при замене одного на другого.
+func diffReviewers(oldReviewers, newReviewers []string) (removed, added string) {
+	for _, o := range oldReviewers {
+		if !containsString(newReviewers, o) {
+			removed = o
+			break
+		}
+	}
+	for _, n := range newReviewers {
+		if !containsString(oldReviewers, n) {
+			added = n
+			break
+		}
+	}
+	return removed, added
+}
@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+// syncReviewAssignments отражает смену состава ревьюеров в назначениях:
+// снятым проставляет unassigned_at, новым создаёт назначение. Вызывается под s.mu.
+func (s *Store) syncReviewAssignments(prID string, oldReviewers, newReviewers []string, at time.Time) {
+	for _, id := range oldReviewers {
+		if containsString(newReviewers, id) {
+			continue
+		}
+		if a := s.currentAssignment(prID, id); a != nil {
+			a.UnassignedAt = timestampPtr(&at)
+		}
+	}
+	for _, id := range newReviewers {
+		if containsString(oldReviewers, id) || s.currentAssignment(prID, id) != nil {
+			continue
+		}
+		s.assignments = append(s.assignments, &reviewAssignmentRow{
+			ID:            s.nextID("review_assignments"),
+			PullRequestID: prID,
+			ReviewerID:    id,
+			AssignedAt:    newTimestamp(at),
+		})
+	}
+}
+
+// currentAssignment возвращает действующее (не снятое) назначение ревьюера на PR
+func (s *Store) currentAssignment(prID, reviewerID string) *reviewAssignmentRow {
+	for _, a := range s.assignments {
+		if a.PullRequestID == prID && a.ReviewerID == reviewerID && a.UnassignedAt == nil {
+			return a
+		}
+	}
+	return nil
+}
+
+// RecordReviewAction фиксирует первое действие ревьюера по текущему назначению.
+// Возвращает время первого действия и true, если оно записано этим вызовом;
+// для повторных действий — ранее записанное время и false.
+func (r *PullRequestRepo) RecordReviewAction(ctx context.Context, prID, reviewerID string, at time.Time) (time.Time, bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	a := r.s.currentAssignment(prID, reviewerID)
+	if a == nil {
+		return time.Time{}, false, domain.ErrReviewerNotAssigned
+	}
+	if a.FirstActionAt != nil {
+		return a.FirstActionAt.Time, false, nil
+	}
+	a.FirstActionAt = timestampPtr(&at)
+	return a.FirstActionAt.Time, true, nil
+}
@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+// ReviewPolicyRepo хранит правила команд для зависших PR
+type ReviewPolicyRepo struct {
+	s *Store
+}
+
+func NewReviewPolicyRepo(s *Store) *ReviewPolicyRepo {
+	return &ReviewPolicyRepo{s: s}
+}
+
+// SaveReviewPolicy создаёт или заменяет политику команды
+func (r *ReviewPolicyRepo) SaveReviewPolicy(ctx context.Context, p *domain.ReviewPolicy) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.teams[p.TeamName()]; !ok {
+		return domain.ErrTeamNotFound
+	}
+	row := &reviewPolicyRow{
+		TeamName:             p.TeamName(),
+		RemindAfterSeconds:   int64(p.RemindAfter() / time.Second),
+		EscalateAfterSeconds: int64(p.EscalateAfter() / time.Second),
+		Escalation:           string(p.Escalation()),
+		UpdatedAt:            newTimestamp(time.Now()),
+	}
+	if leadID := p.LeadID(); leadID != "" {
+		if _, ok := r.s.users[leadID]; !ok {
+			return domain.ErrUserNotFound
+		}
+		row.LeadUserID = &leadID
+	}
+	r.s.policies[p.TeamName()] = row
+	return nil
+}
+
+func (r *ReviewPolicyRepo) GetReviewPolicy(ctx context.Context, teamName string) (*domain.ReviewPolicy, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	row, ok := r.s.policies[teamName]
+	if !ok {
+		return nil, domain.ErrReviewPolicyNotFound
+	}
+	return row.toDomain(), nil
+}
+
+func (r *reviewPolicyRow) toDomain() *domain.ReviewPolicy {
+	leadID := ""
+	if r.LeadUserID != nil {
+		leadID = *r.LeadUserID
+	}
+	return domain.RestoreReviewPolicy(r.TeamName,
+		time.Duration(r.RemindAfterSeconds)*time.Second,
+		time.Duration(r.EscalateAfterSeconds)*time.Second,
+		domain.EscalationAction(r.Escalation), leadID)
+}
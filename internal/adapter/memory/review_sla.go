@@ -0,0 +1,168 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+// ReviewSLARepo хранит SLA команд и строит отчёты по назначениям ревьюеров
+type ReviewSLARepo struct {
+	s *Store
+}
+
+func NewReviewSLARepo(s *Store) *ReviewSLARepo {
+	return &ReviewSLARepo{s: s}
+}
+
+// SaveReviewSLA создаёт или заменяет SLA команды
+func (r *ReviewSLARepo) SaveReviewSLA(ctx context.Context, sla *domain.ReviewSLA) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.teams[sla.TeamName()]; !ok {
+		return domain.ErrTeamNotFound
+	}
+	r.s.slas[sla.TeamName()] = &reviewSLARow{
+		TeamName:           sla.TeamName(),
+		FirstReviewSeconds: int64(sla.FirstReviewWithin() / time.Second),
+		UpdatedAt:          newTimestamp(time.Now()),
+	}
+	return nil
+}
+
+// assignmentDeadline — назначение с командой автора PR и сроком первого действия по SLA команды
+type assignmentDeadline struct {
+	assignment *reviewAssignmentRow
+	pr         *pullRequestRow
+	team       string
+	dueAt      time.Time
+}
+
+// assignmentsWithDeadline отбирает назначения по match и считает их сроки;
+// defaultSLA действует для команд без своего SLA. Вызывается под s.mu.
+func (s *Store) assignmentsWithDeadline(defaultSLA time.Duration,
+	match func(a *reviewAssignmentRow, pr *pullRequestRow) bool) []assignmentDeadline {
+	var result []assignmentDeadline
+	for _, a := range s.assignments {
+		pr, ok := s.pullRequests[a.PullRequestID]
+		if !ok || !match(a, pr) {
+			continue
+		}
+		team := s.teamOf(pr.AuthorID)
+		sla := defaultSLA
+		if row, ok := s.slas[team]; ok {
+			sla = time.Duration(row.FirstReviewSeconds) * time.Second
+		}
+		result = append(result, assignmentDeadline{assignment: a, pr: pr, team: team, dueAt: a.AssignedAt.Add(sla)})
+	}
+	return result
+}
+
+// ListSLAViolations возвращает текущие назначения на OPEN PR без первого действия,
+// срок которых наступает до dueBefore, начиная с самых просроченных.
+// teamName == "" — по всем командам.
+func (r *ReviewSLARepo) ListSLAViolations(ctx context.Context, teamName string, now, dueBefore time.Time,
+	defaultSLA time.Duration) ([]domain.SLAViolation, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	deadlines := r.s.assignmentsWithDeadline(defaultSLA, func(a *reviewAssignmentRow, pr *pullRequestRow) bool {
+		return pr.Status == string(domain.PROpen) && a.FirstActionAt == nil && a.UnassignedAt == nil
+	})
+
+	var violations []domain.SLAViolation
+	for _, d := range deadlines {
+		if !d.dueAt.Before(dueBefore) || (teamName != "" && d.team != teamName) {
+			continue
+		}
+		v := domain.SLAViolation{
+			PullRequestID:   d.pr.ID,
+			PullRequestName: d.pr.Name,
+			Team:            d.team,
+			ReviewerID:      d.assignment.ReviewerID,
+			AssignedAt:      d.assignment.AssignedAt.Time,
+			DueAt:           d.dueAt,
+			Status:          domain.SLAAtRisk,
+		}
+		if !v.DueAt.After(now) {
+			v.Status = domain.SLABreached
+		}
+		violations = append(violations, v)
+	}
+	sort.Slice(violations, func(i, j int) bool {
+		a, b := violations[i], violations[j]
+		if !a.DueAt.Equal(b.DueAt) {
+			return a.DueAt.Before(b.DueAt)
+		}
+		if a.PullRequestID != b.PullRequestID {
+			return a.PullRequestID < b.PullRequestID
+		}
+		return a.ReviewerID < b.ReviewerID
+	})
+	return violations, nil
+}
+
+// SLAReport считает нарушения по командам и ревьюерам для назначений, сделанных в [from, to).
+// Назначение нарушено, если первое действие (а без него — снятие ревьюера, мерж PR или текущий момент)
+// наступило позже срока.
+func (r *ReviewSLARepo) SLAReport(ctx context.Context, teamName string, from, to, now time.Time,
+	defaultSLA time.Duration) (*domain.SLAReport, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	deadlines := r.s.assignmentsWithDeadline(defaultSLA, func(a *reviewAssignmentRow, _ *pullRequestRow) bool {
+		return !a.AssignedAt.Before(from) && a.AssignedAt.Before(to)
+	})
+
+	byTeam := make(map[string]*domain.SLABreachStats)
+	byReviewer := make(map[string]*domain.SLABreachStats)
+	add := func(stats map[string]*domain.SLABreachStats, key string, breached, inProgress bool) {
+		s, ok := stats[key]
+		if !ok {
+			s = &domain.SLABreachStats{Key: key}
+			stats[key] = s
+		}
+		s.Assignments++
+		if breached {
+			s.Breached++
+		}
+		if inProgress {
+			s.InProgress++
+		}
+	}
+	for _, d := range deadlines {
+		if teamName != "" && d.team != teamName {
+			continue
+		}
+		outcome := now
+		pending := true
+		for _, t := range []*timestamp{d.assignment.FirstActionAt, d.assignment.UnassignedAt, d.pr.MergedAt} {
+			if t != nil {
+				outcome = t.Time
+				pending = false
+				break
+			}
+		}
+		breached := outcome.After(d.dueAt)
+		inProgress := pending && d.dueAt.After(now)
+		add(byTeam, d.team, breached, inProgress)
+		add(byReviewer, d.assignment.ReviewerID, breached, inProgress)
+	}
+
+	return &domain.SLAReport{From: from, To: to, Teams: sortedBreachStats(byTeam), Reviewers: sortedBreachStats(byReviewer)}, nil
+}
+
+func sortedBreachStats(stats map[string]*domain.SLABreachStats) []domain.SLABreachStats {
+	if len(stats) == 0 {
+		return nil
+	}
+	result := make([]domain.SLABreachStats, 0, len(stats))
+	for _, s := range stats {
+		result = append(result, *s)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result
+}
@@ -0,0 +1,556 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+// snapshotTable — таблица снимка с теми же именем и порядком строк, что и в PostgreSQL.
+// rows возвращает строки таблицы, load добавляет строку в восстанавливаемое состояние
+// и проверяет ограничения схемы: первичные и внешние ключи, обязательные поля.
+type snapshotTable struct {
+	name string
+	rows func(s *Store) []any
+	load func(s *Store, data []byte) error
+}
+
+// snapshotTables перечислены в порядке внешних ключей: восстановление идёт в том же порядке
+var snapshotTables = []snapshotTable{
+	{name: "teams", rows: exportTeams, load: loadTeam},
+	{name: "users", rows: exportUsers, load: loadUser},
+	{name: "team_members", rows: exportTeamMembers, load: loadTeamMember},
+	{name: "user_identities", rows: exportIdentities, load: loadIdentity},
+	{name: "team_review_policies", rows: exportReviewPolicies, load: loadReviewPolicy},
+	{name: "team_review_slas", rows: exportReviewSLAs, load: loadReviewSLA},
+	{name: "pull_requests", rows: exportPullRequests, load: loadPullRequest},
+	{name: "review_assignments", rows: exportReviewAssignments, load: loadReviewAssignment},
+	{name: "stale_pr_actions", rows: exportStaleActions, load: loadStaleAction},
+	{name: "webhook_subscriptions", rows: exportWebhookSubscriptions, load: loadWebhookSubscription},
+	{name: "outbox", rows: exportOutbox, load: loadOutboxEvent},
+}
+
+type SnapshotRepo struct {
+	s *Store
+}
+
+func NewSnapshotRepo(s *Store) *SnapshotRepo {
+	return &SnapshotRepo{s: s}
+}
+
+// ExportSnapshot передаёт в emit все строки снимка. Строки копируются под блокировкой,
+// поэтому снимок согласован даже при параллельной записи, а emit вызывается уже без неё.
+func (r *SnapshotRepo) ExportSnapshot(ctx context.Context, emit func(row domain.SnapshotRow) error) error {
+	var rows []domain.SnapshotRow
+	r.s.mu.RLock()
+	for _, t := range snapshotTables {
+		for _, row := range t.rows(r.s) {
+			data, err := json.Marshal(row)
+			if err != nil {
+				r.s.mu.RUnlock()
+				return fmt.Errorf("export %s: %w", t.name, err)
+			}
+			rows = append(rows, domain.SnapshotRow{Table: t.name, Data: data})
+		}
+	}
+	r.s.mu.RUnlock()
+
+	for _, row := range rows {
+		if err := emit(row); err != nil {
+			return fmt.Errorf("export %s: %w", row.Table, err)
+		}
+	}
+	return nil
+}
+
+// RestoreSnapshot загружает строки из next (до io.EOF) в новое состояние и подменяет им текущее
+// только после успешной загрузки всех строк. Без force возвращает domain.ErrDatabaseNotEmpty,
+// если в хранилище уже есть данные; с force заменяет их, очищая очереди доставки и ключи идемпотентности.
+// Восстановленные события outbox помечаются опубликованными, чтобы не разослать их повторно.
+func (r *SnapshotRepo) RestoreSnapshot(ctx context.Context, force bool, next func() (*domain.SnapshotRow, error)) (map[string]int, error) {
+	if !force {
+		r.s.mu.RLock()
+		empty := r.s.snapshotTablesEmpty()
+		r.s.mu.RUnlock()
+		if !empty {
+			return nil, domain.ErrDatabaseNotEmpty
+		}
+	}
+
+	restored := NewStore(r.s.schemaVersion)
+	counts := make(map[string]int)
+	position := 0
+	for {
+		row, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		index := snapshotTableIndex(row.Table)
+		if index < 0 {
+			return nil, fmt.Errorf("%w: unknown table %q", domain.ErrInvalidSnapshot, row.Table)
+		}
+		if index < position {
+			return nil, fmt.Errorf("%w: table %q is out of order", domain.ErrInvalidSnapshot, row.Table)
+		}
+		position = index
+
+		if err := snapshotTables[index].load(restored, row.Data); err != nil {
+			return nil, fmt.Errorf("%w: %s row %d: %v", domain.ErrInvalidSnapshot, row.Table, counts[row.Table]+1, err)
+		}
+		counts[row.Table]++
+	}
+
+	if err := restored.checkCurrentAssignments(); err != nil {
+		return nil, err
+	}
+
+	now := newTimestamp(time.Now())
+	for _, event := range restored.outbox {
+		if event.PublishedAt == nil {
+			published := now
+			event.PublishedAt = &published
+		}
+	}
+	sort.Slice(restored.assignments, func(i, j int) bool { return restored.assignments[i].ID < restored.assignments[j].ID })
+	sort.Slice(restored.outbox, func(i, j int) bool { return restored.outbox[i].ID < restored.outbox[j].ID })
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	// Проверяем ещё раз: данные могли появиться, пока читался снимок
+	if !force && !r.s.snapshotTablesEmpty() {
+		return nil, domain.ErrDatabaseNotEmpty
+	}
+	r.s.teams = restored.teams
+	r.s.users = restored.users
+	r.s.members = restored.members
+	r.s.identities = restored.identities
+	r.s.policies = restored.policies
+	r.s.slas = restored.slas
+	r.s.pullRequests = restored.pullRequests
+	r.s.assignments = restored.assignments
+	r.s.staleActions = restored.staleActions
+	r.s.webhooks = restored.webhooks
+	r.s.outbox = restored.outbox
+	r.s.deliveries = nil
+	r.s.reviewRequests = nil
+	r.s.notifications = nil
+	r.s.digestItems = nil
+	// Сохранённые ответы ссылаются на прежние данные, поэтому повтор запроса должен выполниться заново
+	r.s.idempotency = make(map[idempotencyKey]*idempotencyRow)
+	// Последовательности очередей доставки продолжаются, чтобы ID не повторялись
+	for _, name := range []string{"review_assignments", "webhook_subscriptions", "outbox"} {
+		r.s.sequences[name] = restored.sequences[name]
+	}
+	return counts, nil
+}
+
+func (s *Store) snapshotTablesEmpty() bool {
+	for _, t := range snapshotTables {
+		if len(t.rows(s)) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// checkCurrentAssignments — аналог уникального индекса: у ревьюера не больше одного действующего назначения на PR
+func (s *Store) checkCurrentAssignments() error {
+	type key struct{ pr, reviewer string }
+	current := make(map[key]bool)
+	for _, a := range s.assignments {
+		if a.UnassignedAt != nil {
+			continue
+		}
+		k := key{pr: a.PullRequestID, reviewer: a.ReviewerID}
+		if current[k] {
+			return fmt.Errorf("%w: review_assignments: reviewer %q has two current assignments to %q",
+				domain.ErrInvalidSnapshot, a.ReviewerID, a.PullRequestID)
+		}
+		current[k] = true
+	}
+	return nil
+}
+
+func snapshotTableIndex(name string) int {
+	for i, t := range snapshotTables {
+		if t.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// hasSerialID проверяет, занят ли id в таблице из n строк. Экспорт упорядочивает строки по id,
+// поэтому обычно id больше последнего выданного и просматривать таблицу не нужно.
+func (s *Store) hasSerialID(table string, id int64, idAt func(i int) int64, n int) bool {
+	if id > s.sequences[table] {
+		return false
+	}
+	for i := 0; i < n; i++ {
+		if idAt(i) == id {
+			return true
+		}
+	}
+	return false
+}
+
+// trackSequence сдвигает последовательность таблицы за восстановленный id
+func (s *Store) trackSequence(table string, id int64) {
+	if id > s.sequences[table] {
+		s.sequences[table] = id
+	}
+}
+
+func exportTeams(s *Store) []any {
+	names := make([]string, 0, len(s.teams))
+	for name := range s.teams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	rows := make([]any, len(names))
+	for i, name := range names {
+		rows[i] = *s.teams[name]
+	}
+	return rows
+}
+
+func loadTeam(s *Store, data []byte) error {
+	var row teamRow
+	if err := json.Unmarshal(data, &row); err != nil {
+		return err
+	}
+	if row.Name == "" {
+		return errors.New("name is required")
+	}
+	if _, ok := s.teams[row.Name]; ok {
+		return fmt.Errorf("duplicate team %q", row.Name)
+	}
+	s.teams[row.Name] = &row
+	return nil
+}
+
+func exportUsers(s *Store) []any {
+	ids := make([]string, 0, len(s.users))
+	for id := range s.users {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	rows := make([]any, len(ids))
+	for i, id := range ids {
+		rows[i] = *s.users[id]
+	}
+	return rows
+}
+
+func loadUser(s *Store, data []byte) error {
+	var row userRow
+	if err := json.Unmarshal(data, &row); err != nil {
+		return err
+	}
+	if row.ID == "" || row.Username == "" {
+		return errors.New("id and username are required")
+	}
+	if _, ok := s.users[row.ID]; ok {
+		return fmt.Errorf("duplicate user %q", row.ID)
+	}
+	s.users[row.ID] = &row
+	return nil
+}
+
+func exportTeamMembers(s *Store) []any {
+	members := make([]teamMemberRow, 0, len(s.members))
+	for userID, team := range s.members {
+		members = append(members, teamMemberRow{TeamName: team, UserID: userID})
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if members[i].TeamName != members[j].TeamName {
+			return members[i].TeamName < members[j].TeamName
+		}
+		return members[i].UserID < members[j].UserID
+	})
+	rows := make([]any, len(members))
+	for i, m := range members {
+		rows[i] = m
+	}
+	return rows
+}
+
+func loadTeamMember(s *Store, data []byte) error {
+	var row teamMemberRow
+	if err := json.Unmarshal(data, &row); err != nil {
+		return err
+	}
+	if _, ok := s.teams[row.TeamName]; !ok {
+		return fmt.Errorf("unknown team %q", row.TeamName)
+	}
+	if _, ok := s.users[row.UserID]; !ok {
+		return fmt.Errorf("unknown user %q", row.UserID)
+	}
+	if team, ok := s.members[row.UserID]; ok {
+		return fmt.Errorf("user %q is already in team %q", row.UserID, team)
+	}
+	s.members[row.UserID] = row.TeamName
+	return nil
+}
+
+func exportIdentities(s *Store) []any {
+	identities := make([]*identityRow, 0, len(s.identities))
+	for _, row := range s.identities {
+		identities = append(identities, row)
+	}
+	sort.Slice(identities, func(i, j int) bool {
+		if identities[i].Provider != identities[j].Provider {
+			return identities[i].Provider < identities[j].Provider
+		}
+		return identities[i].ExternalID < identities[j].ExternalID
+	})
+	rows := make([]any, len(identities))
+	for i, row := range identities {
+		rows[i] = *row
+	}
+	return rows
+}
+
+func loadIdentity(s *Store, data []byte) error {
+	var row identityRow
+	if err := json.Unmarshal(data, &row); err != nil {
+		return err
+	}
+	if row.Provider == "" || row.ExternalID == "" {
+		return errors.New("provider and external_id are required")
+	}
+	if _, ok := s.users[row.UserID]; !ok {
+		return fmt.Errorf("unknown user %q", row.UserID)
+	}
+	key := identityKey{provider: row.Provider, externalID: row.ExternalID}
+	if _, ok := s.identities[key]; ok {
+		return fmt.Errorf("duplicate identity %s/%s", row.Provider, row.ExternalID)
+	}
+	s.identities[key] = &row
+	return nil
+}
+
+func exportReviewPolicies(s *Store) []any {
+	names := make([]string, 0, len(s.policies))
+	for name := range s.policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	rows := make([]any, len(names))
+	for i, name := range names {
+		rows[i] = *s.policies[name]
+	}
+	return rows
+}
+
+func loadReviewPolicy(s *Store, data []byte) error {
+	var row reviewPolicyRow
+	if err := json.Unmarshal(data, &row); err != nil {
+		return err
+	}
+	if _, ok := s.teams[row.TeamName]; !ok {
+		return fmt.Errorf("unknown team %q", row.TeamName)
+	}
+	if _, ok := s.policies[row.TeamName]; ok {
+		return fmt.Errorf("duplicate policy for team %q", row.TeamName)
+	}
+	if row.LeadUserID != nil {
+		if _, ok := s.users[*row.LeadUserID]; !ok {
+			return fmt.Errorf("unknown user %q", *row.LeadUserID)
+		}
+	}
+	s.policies[row.TeamName] = &row
+	return nil
+}
+
+func exportReviewSLAs(s *Store) []any {
+	names := make([]string, 0, len(s.slas))
+	for name := range s.slas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	rows := make([]any, len(names))
+	for i, name := range names {
+		rows[i] = *s.slas[name]
+	}
+	return rows
+}
+
+func loadReviewSLA(s *Store, data []byte) error {
+	var row reviewSLARow
+	if err := json.Unmarshal(data, &row); err != nil {
+		return err
+	}
+	if _, ok := s.teams[row.TeamName]; !ok {
+		return fmt.Errorf("unknown team %q", row.TeamName)
+	}
+	if _, ok := s.slas[row.TeamName]; ok {
+		return fmt.Errorf("duplicate SLA for team %q", row.TeamName)
+	}
+	if row.FirstReviewSeconds <= 0 {
+		return errors.New("first_review_seconds must be positive")
+	}
+	s.slas[row.TeamName] = &row
+	return nil
+}
+
+func exportPullRequests(s *Store) []any {
+	ids := make([]string, 0, len(s.pullRequests))
+	for id := range s.pullRequests {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	rows := make([]any, len(ids))
+	for i, id := range ids {
+		rows[i] = *s.pullRequests[id]
+	}
+	return rows
+}
+
+func loadPullRequest(s *Store, data []byte) error {
+	var row pullRequestRow
+	if err := json.Unmarshal(data, &row); err != nil {
+		return err
+	}
+	if row.ID == "" || row.Name == "" {
+		return errors.New("id and name are required")
+	}
+	if _, ok := s.pullRequests[row.ID]; ok {
+		return fmt.Errorf("duplicate pull request %q", row.ID)
+	}
+	if _, ok := s.users[row.AuthorID]; !ok {
+		return fmt.Errorf("unknown user %q", row.AuthorID)
+	}
+	if status := domain.PRStatus(row.Status); status != domain.PROpen && status != domain.PRMerged && status != domain.PRClosed {
+		return fmt.Errorf("unknown status %q", row.Status)
+	}
+	s.pullRequests[row.ID] = &row
+	return nil
+}
+
+func exportReviewAssignments(s *Store) []any {
+	rows := make([]any, len(s.assignments))
+	for i, row := range s.assignments {
+		rows[i] = *row
+	}
+	return rows
+}
+
+func loadReviewAssignment(s *Store, data []byte) error {
+	var row reviewAssignmentRow
+	if err := json.Unmarshal(data, &row); err != nil {
+		return err
+	}
+	if _, ok := s.pullRequests[row.PullRequestID]; !ok {
+		return fmt.Errorf("unknown pull request %q", row.PullRequestID)
+	}
+	if _, ok := s.users[row.ReviewerID]; !ok {
+		return fmt.Errorf("unknown user %q", row.ReviewerID)
+	}
+	if s.hasSerialID("review_assignments", row.ID, func(i int) int64 { return s.assignments[i].ID }, len(s.assignments)) {
+		return fmt.Errorf("duplicate id %d", row.ID)
+	}
+	s.assignments = append(s.assignments, &row)
+	s.trackSequence("review_assignments", row.ID)
+	return nil
+}
+
+func exportStaleActions(s *Store) []any {
+	actions := make([]*staleActionRow, 0, len(s.staleActions))
+	for _, row := range s.staleActions {
+		actions = append(actions, row)
+	}
+	sort.Slice(actions, func(i, j int) bool {
+		if actions[i].PullRequestID != actions[j].PullRequestID {
+			return actions[i].PullRequestID < actions[j].PullRequestID
+		}
+		return actions[i].Stage < actions[j].Stage
+	})
+	rows := make([]any, len(actions))
+	for i, row := range actions {
+		rows[i] = *row
+	}
+	return rows
+}
+
+func loadStaleAction(s *Store, data []byte) error {
+	var row staleActionRow
+	if err := json.Unmarshal(data, &row); err != nil {
+		return err
+	}
+	if _, ok := s.pullRequests[row.PullRequestID]; !ok {
+		return fmt.Errorf("unknown pull request %q", row.PullRequestID)
+	}
+	if stage := domain.StaleStage(row.Stage); stage != domain.StaleStageReminded && stage != domain.StaleStageEscalated {
+		return fmt.Errorf("unknown stage %q", row.Stage)
+	}
+	key := staleActionKey{pullRequestID: row.PullRequestID, stage: row.Stage}
+	if _, ok := s.staleActions[key]; ok {
+		return fmt.Errorf("duplicate stage %s for pull request %q", row.Stage, row.PullRequestID)
+	}
+	s.staleActions[key] = &row
+	return nil
+}
+
+func exportWebhookSubscriptions(s *Store) []any {
+	ids := make([]int64, 0, len(s.webhooks))
+	for id := range s.webhooks {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	rows := make([]any, len(ids))
+	for i, id := range ids {
+		rows[i] = *s.webhooks[id]
+	}
+	return rows
+}
+
+func loadWebhookSubscription(s *Store, data []byte) error {
+	var row webhookSubscriptionRow
+	if err := json.Unmarshal(data, &row); err != nil {
+		return err
+	}
+	if row.URL == "" || row.EventTypes == nil {
+		return errors.New("url and event_types are required")
+	}
+	if _, ok := s.webhooks[row.ID]; ok {
+		return fmt.Errorf("duplicate id %d", row.ID)
+	}
+	s.webhooks[row.ID] = &row
+	s.trackSequence("webhook_subscriptions", row.ID)
+	return nil
+}
+
+func exportOutbox(s *Store) []any {
+	rows := make([]any, len(s.outbox))
+	for i, row := range s.outbox {
+		rows[i] = *row
+	}
+	return rows
+}
+
+func loadOutboxEvent(s *Store, data []byte) error {
+	var row outboxRow
+	if err := json.Unmarshal(data, &row); err != nil {
+		return err
+	}
+	if row.EventType == "" || len(row.Payload) == 0 {
+		return errors.New("event_type and payload are required")
+	}
+	if s.hasSerialID("outbox", row.ID, func(i int) int64 { return s.outbox[i].ID }, len(s.outbox)) {
+		return fmt.Errorf("duplicate id %d", row.ID)
+	}
+	s.outbox = append(s.outbox, &row)
+	s.trackSequence("outbox", row.ID)
+	return nil
+}
@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+// StalePullRequestRepo находит долго открытые PR и фиксирует выполненные по ним этапы
+type StalePullRequestRepo struct {
+	s *Store
+}
+
+func NewStalePullRequestRepo(s *Store) *StalePullRequestRepo {
+	return &StalePullRequestRepo{s: s}
+}
+
+// ListStalePullRequests возвращает OPEN PR, которым на момент now пора напомнить или эскалировать,
+// начиная с самых старых. Пороги берутся из политики команды автора, а для команд без своей
+// политики — из defaults, поэтому в пачку limit не попадают PR, которым ещё рано.
+func (r *StalePullRequestRepo) ListStalePullRequests(ctx context.Context, now time.Time, defaults *domain.ReviewPolicy, limit int) ([]domain.StalePullRequest, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var result []domain.StalePullRequest
+	for _, row := range r.s.pullRequests {
+		if row.Status != string(domain.PROpen) {
+			continue
+		}
+		policy := r.s.stalePolicy(r.s.teamOf(row.AuthorID), defaults)
+		stage := r.s.staleStage(row.ID)
+		if policy.NextStage(now.Sub(row.CreatedAt.Time), stage) == domain.StaleStageNone {
+			continue
+		}
+		pr, err := domain.RestorePullRequest(row.ID, row.Name, row.AuthorID, domain.PRStatus(row.Status),
+			append([]string(nil), row.AssignedReviewers...), row.CreatedAt.Time, nil, row.Version)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, domain.StalePullRequest{PullRequest: pr, Policy: policy, Stage: stage})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i].PullRequest, result[j].PullRequest
+		if !a.CreatedAt().Equal(b.CreatedAt()) {
+			return a.CreatedAt().Before(b.CreatedAt())
+		}
+		return a.ID() < b.ID()
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+// RecordStaleStage отмечает этап для PR и вместе с ним пишет событие в outbox.
+// Возвращает false, если этап уже был выполнен.
+func (r *StalePullRequestRepo) RecordStaleStage(ctx context.Context, prID string, stage domain.StaleStage, event domain.Event) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.pullRequests[prID]; !ok {
+		return false, domain.ErrPRNotFound
+	}
+	key := staleActionKey{pullRequestID: prID, stage: string(stage)}
+	if _, ok := r.s.staleActions[key]; ok {
+		return false, nil
+	}
+	r.s.staleActions[key] = &staleActionRow{PullRequestID: prID, Stage: string(stage), CreatedAt: newTimestamp(time.Now())}
+	r.s.insertEvent(event)
+	return true, nil
+}
+
+func (s *Store) hasStaleStage(prID string, stage domain.StaleStage) bool {
+	_, ok := s.staleActions[staleActionKey{pullRequestID: prID, stage: string(stage)}]
+	return ok
+}
+
+// staleStage возвращает самый поздний выполненный этап PR
+func (s *Store) staleStage(prID string) domain.StaleStage {
+	for _, stage := range []domain.StaleStage{domain.StaleStageEscalated, domain.StaleStageReminded} {
+		if s.hasStaleStage(prID, stage) {
+			return stage
+		}
+	}
+	return domain.StaleStageNone
+}
+
+// stalePolicy возвращает политику команды или defaults, если своей у команды нет
+func (s *Store) stalePolicy(teamName string, defaults *domain.ReviewPolicy) *domain.ReviewPolicy {
+	if row, ok := s.policies[teamName]; ok {
+		return row.toDomain()
+	}
+	return defaults.ForTeam(teamName)
+}
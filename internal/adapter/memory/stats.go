@@ -0,0 +1,224 @@
+package memory
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+// StatsRepo строит статистику по PR и назначениям ревьюеров
+type StatsRepo struct {
+	s *Store
+}
+
+func NewStatsRepo(s *Store) *StatsRepo {
+	return &StatsRepo{s: s}
+}
+
+// authorInTeam — автор PR состоит в команде; пустое имя — все команды
+func (s *Store) authorInTeam(pr *pullRequestRow, teamName string) bool {
+	return teamName == "" || s.teamOf(pr.AuthorID) == teamName
+}
+
+// GetReviewerStats возвращает количество OPEN PR на каждого ревьюера.
+func (r *StatsRepo) GetReviewerStats(ctx context.Context, teamName string) (map[string]int, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	stats := make(map[string]int)
+	for _, pr := range r.s.pullRequests {
+		if pr.Status != string(domain.PROpen) || !r.s.authorInTeam(pr, teamName) {
+			continue
+		}
+		for _, reviewer := range pr.AssignedReviewers {
+			stats[reviewer]++
+		}
+	}
+	return stats, nil
+}
+
+// GetTimeToMerge считает медиану и 90-й перцентиль времени до мержа для PR, смерженных за период
+func (r *StatsRepo) GetTimeToMerge(ctx context.Context, f domain.StatsFilter) (domain.TimeToMerge, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var seconds []float64
+	for _, pr := range r.s.pullRequests {
+		if pr.MergedAt == nil || !inPeriod(pr.MergedAt.Time, f) || !r.s.authorInTeam(pr, f.TeamName) {
+			continue
+		}
+		seconds = append(seconds, pr.MergedAt.Sub(pr.CreatedAt.Time).Seconds())
+	}
+	sort.Float64s(seconds)
+	return domain.TimeToMerge{
+		Merged: len(seconds),
+		Median: secondsToDuration(percentileCont(seconds, 0.5)),
+		P90:    secondsToDuration(percentileCont(seconds, 0.9)),
+	}, nil
+}
+
+// GetThroughput возвращает число созданных и смерженных PR по периодам f.Bucket
+func (r *StatsRepo) GetThroughput(ctx context.Context, f domain.StatsFilter) ([]domain.ThroughputPoint, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	byPeriod := make(map[time.Time]*domain.ThroughputPoint)
+	point := func(t time.Time) *domain.ThroughputPoint {
+		period := truncateToBucket(t, f.Bucket)
+		p, ok := byPeriod[period]
+		if !ok {
+			p = &domain.ThroughputPoint{PeriodStart: period}
+			byPeriod[period] = p
+		}
+		return p
+	}
+	for _, pr := range r.s.pullRequests {
+		if !r.s.authorInTeam(pr, f.TeamName) {
+			continue
+		}
+		if inPeriod(pr.CreatedAt.Time, f) {
+			point(pr.CreatedAt.Time).Created++
+		}
+		if pr.MergedAt != nil && inPeriod(pr.MergedAt.Time, f) {
+			point(pr.MergedAt.Time).Merged++
+		}
+	}
+
+	points := make([]domain.ThroughputPoint, 0, len(byPeriod))
+	for _, p := range byPeriod {
+		points = append(points, *p)
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].PeriodStart.Before(points[j].PeriodStart) })
+	return points, nil
+}
+
+// GetReviewerLoad возвращает назначения, первые действия и замены каждого ревьюера за период
+func (r *StatsRepo) GetReviewerLoad(ctx context.Context, f domain.StatsFilter) ([]domain.ReviewerLoad, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	byReviewer := make(map[string]*domain.ReviewerLoad)
+	for _, a := range r.s.assignments {
+		assigned := inPeriod(a.AssignedAt.Time, f)
+		reviewed := a.FirstActionAt != nil && inPeriod(a.FirstActionAt.Time, f)
+		unassigned := a.UnassignedAt != nil && inPeriod(a.UnassignedAt.Time, f)
+		if !assigned && !reviewed && !unassigned {
+			continue
+		}
+		pr, ok := r.s.pullRequests[a.PullRequestID]
+		if !ok || !r.s.authorInTeam(pr, f.TeamName) {
+			continue
+		}
+
+		l, ok := byReviewer[a.ReviewerID]
+		if !ok {
+			l = &domain.ReviewerLoad{UserID: a.ReviewerID}
+			byReviewer[a.ReviewerID] = l
+		}
+		if assigned {
+			l.Assigned++
+		}
+		if reviewed {
+			l.Reviewed++
+		}
+		if unassigned {
+			l.ReassignedAway++
+		}
+	}
+
+	load := make([]domain.ReviewerLoad, 0, len(byReviewer))
+	for _, l := range byReviewer {
+		load = append(load, *l)
+	}
+	sort.Slice(load, func(i, j int) bool { return load[i].UserID < load[j].UserID })
+	return load, nil
+}
+
+// GetAuthorReviewerMatrix возвращает, сколько раз каждый ревьюер назначался на PR каждого автора за период
+func (r *StatsRepo) GetAuthorReviewerMatrix(ctx context.Context, f domain.StatsFilter) ([]domain.AuthorReviewerPair, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	type pairKey struct{ author, reviewer string }
+	counts := make(map[pairKey]int)
+	for _, a := range r.s.assignments {
+		if !inPeriod(a.AssignedAt.Time, f) {
+			continue
+		}
+		pr, ok := r.s.pullRequests[a.PullRequestID]
+		if !ok || !r.s.authorInTeam(pr, f.TeamName) {
+			continue
+		}
+		counts[pairKey{author: pr.AuthorID, reviewer: a.ReviewerID}]++
+	}
+
+	matrix := make([]domain.AuthorReviewerPair, 0, len(counts))
+	for k, n := range counts {
+		matrix = append(matrix, domain.AuthorReviewerPair{AuthorID: k.author, ReviewerID: k.reviewer, Count: n})
+	}
+	sort.Slice(matrix, func(i, j int) bool {
+		if matrix[i].AuthorID != matrix[j].AuthorID {
+			return matrix[i].AuthorID < matrix[j].AuthorID
+		}
+		return matrix[i].ReviewerID < matrix[j].ReviewerID
+	})
+	return matrix, nil
+}
+
+// GetReviewSnapshot возвращает текущее число OPEN PR, назначений по командам и замен ревьюеров.
+// Автор без команды попадает в команду с пустым именем.
+func (r *StatsRepo) GetReviewSnapshot(ctx context.Context) (*domain.ReviewSnapshot, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	snapshot := &domain.ReviewSnapshot{OpenAssignmentsByTeam: make(map[string]int)}
+	for _, pr := range r.s.pullRequests {
+		if pr.Status != string(domain.PROpen) {
+			continue
+		}
+		snapshot.OpenPullRequests++
+		snapshot.OpenAssignmentsByTeam[r.s.teamOf(pr.AuthorID)] += len(pr.AssignedReviewers)
+	}
+	for _, a := range r.s.assignments {
+		if a.UnassignedAt != nil {
+			snapshot.Reassignments++
+		}
+	}
+	return snapshot, nil
+}
+
+func inPeriod(t time.Time, f domain.StatsFilter) bool {
+	return !t.Before(f.From) && t.Before(f.To)
+}
+
+// truncateToBucket — аналог date_trunc: начало дня, недели (с понедельника) или месяца в UTC
+func truncateToBucket(t time.Time, bucket domain.StatsBucket) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch bucket {
+	case domain.BucketDay:
+		return day
+	case domain.BucketMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	}
+}
+
+// percentileCont — аналог percentile_cont: линейная интерполяция по отсортированным значениям
+func percentileCont(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	pos := p * float64(len(sorted)-1)
+	lower := int(math.Floor(pos))
+	upper := int(math.Ceil(pos))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(pos-float64(lower))
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second)).Round(time.Second)
+}
@@ -0,0 +1,381 @@
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+// Store — состояние хранилища в памяти. Все репозитории пакета работают с одним Store
+// под общим мьютексом, поэтому каждая операция атомарна, как транзакция в Postgres.
+// Строки повторяют таблицы схемы (и их имена колонок в JSON), чтобы снимки базы
+// переносились между хранилищами без преобразований.
+type Store struct {
+	mu sync.RWMutex
+
+	schemaVersion uint
+
+	teams        map[string]*teamRow
+	users        map[string]*userRow
+	members      map[string]string // user_id → team_name
+	identities   map[identityKey]*identityRow
+	policies     map[string]*reviewPolicyRow
+	slas         map[string]*reviewSLARow
+	pullRequests map[string]*pullRequestRow
+	assignments  []*reviewAssignmentRow
+	staleActions map[staleActionKey]*staleActionRow
+	webhooks     map[int64]*webhookSubscriptionRow
+	outbox       []*outboxRow
+
+	// Очереди доставки и ключи идемпотентности в снимок не входят
+	deliveries     []*webhookDeliveryRow
+	reviewRequests []*reviewRequestRow
+	notifications  []*notificationRow
+	digestItems    []*digestItemRow
+	idempotency    map[idempotencyKey]*idempotencyRow
+
+	// sequences — последние выданные значения BIGSERIAL по таблицам
+	sequences map[string]int64
+
+	listenersMu sync.Mutex
+	listeners   map[int]func(event domain.Event)
+	listenerSeq int
+}
+
+// NewStore создаёт пустое хранилище. schemaVersion — версия миграций, которой соответствует
+// модель данных: её возвращает MigrationVersion и проверяет восстановление снимка.
+func NewStore(schemaVersion uint) *Store {
+	s := &Store{schemaVersion: schemaVersion, listeners: make(map[int]func(event domain.Event))}
+	s.reset()
+	return s
+}
+
+// reset очищает все таблицы, кроме ключей идемпотентности
+func (s *Store) reset() {
+	s.teams = make(map[string]*teamRow)
+	s.users = make(map[string]*userRow)
+	s.members = make(map[string]string)
+	s.identities = make(map[identityKey]*identityRow)
+	s.policies = make(map[string]*reviewPolicyRow)
+	s.slas = make(map[string]*reviewSLARow)
+	s.pullRequests = make(map[string]*pullRequestRow)
+	s.assignments = nil
+	s.staleActions = make(map[staleActionKey]*staleActionRow)
+	s.webhooks = make(map[int64]*webhookSubscriptionRow)
+	s.outbox = nil
+	s.deliveries = nil
+	s.reviewRequests = nil
+	s.notifications = nil
+	s.digestItems = nil
+	if s.idempotency == nil {
+		s.idempotency = make(map[idempotencyKey]*idempotencyRow)
+	}
+	s.sequences = make(map[string]int64)
+}
+
+// nextID выдаёт очередное значение последовательности таблицы
+func (s *Store) nextID(table string) int64 {
+	s.sequences[table]++
+	return s.sequences[table]
+}
+
+// timestamp — время в формате колонки TIMESTAMP Postgres (UTC без зоны)
+type timestamp struct{ time.Time }
+
+const timestampLayout = "2006-01-02T15:04:05.999999"
+
+func newTimestamp(t time.Time) timestamp { return timestamp{t.UTC()} }
+
+func timestampPtr(t *time.Time) *timestamp {
+	if t == nil {
+		return nil
+	}
+	ts := newTimestamp(*t)
+	return &ts
+}
+
+func (t *timestamp) timePtr() *time.Time {
+	if t == nil {
+		return nil
+	}
+	v := t.Time
+	return &v
+}
+
+func (t timestamp) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.UTC().Format(timestampLayout))
+}
+
+// UnmarshalJSON принимает и формат Postgres без зоны, и RFC 3339
+func (t *timestamp) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	for _, layout := range []string{"2006-01-02T15:04:05", time.RFC3339Nano} {
+		if v, err := time.Parse(layout, s); err == nil {
+			t.Time = v.UTC()
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid timestamp %q", s)
+}
+
+type teamRow struct {
+	Name string `json:"name"`
+}
+
+type userRow struct {
+	ID          string  `json:"id"`
+	Username    string  `json:"username"`
+	IsActive    bool    `json:"is_active"`
+	Email       *string `json:"email"`
+	EmailDigest bool    `json:"email_digest"`
+}
+
+func (r *userRow) toDomain() (*domain.User, error) {
+	user, err := domain.NewUser(r.ID, r.Username, r.IsActive)
+	if err != nil {
+		return nil, err
+	}
+	if r.Email != nil {
+		if err := user.SetEmail(*r.Email); err != nil {
+			return nil, err
+		}
+	}
+	user.SetEmailDigest(r.EmailDigest)
+	return user, nil
+}
+
+type teamMemberRow struct {
+	TeamName string `json:"team_name"`
+	UserID   string `json:"user_id"`
+}
+
+type identityKey struct {
+	provider   string
+	externalID string
+}
+
+type identityRow struct {
+	Provider   string    `json:"provider"`
+	ExternalID string    `json:"external_id"`
+	UserID     string    `json:"user_id"`
+	CreatedAt  timestamp `json:"created_at"`
+}
+
+type reviewPolicyRow struct {
+	TeamName             string    `json:"team_name"`
+	RemindAfterSeconds   int64     `json:"remind_after_seconds"`
+	EscalateAfterSeconds int64     `json:"escalate_after_seconds"`
+	Escalation           string    `json:"escalation"`
+	LeadUserID           *string   `json:"lead_user_id"`
+	UpdatedAt            timestamp `json:"updated_at"`
+}
+
+type reviewSLARow struct {
+	TeamName           string    `json:"team_name"`
+	FirstReviewSeconds int64     `json:"first_review_seconds"`
+	UpdatedAt          timestamp `json:"updated_at"`
+}
+
+type pullRequestRow struct {
+	ID                string     `json:"id"`
+	Name              string     `json:"name"`
+	AuthorID          string     `json:"author_id"`
+	Status            string     `json:"status"`
+	AssignedReviewers []string   `json:"assigned_reviewers"`
+	CreatedAt         timestamp  `json:"created_at"`
+	MergedAt          *timestamp `json:"merged_at"`
+	Version           int        `json:"version"`
+}
+
+func (r *pullRequestRow) toDomain() (*domain.PullRequest, error) {
+	reviewers := append([]string(nil), r.AssignedReviewers...)
+	return domain.RestorePullRequest(r.ID, r.Name, r.AuthorID, domain.PRStatus(r.Status), reviewers,
+		r.CreatedAt.Time, r.MergedAt.timePtr(), r.Version)
+}
+
+type reviewAssignmentRow struct {
+	ID            int64      `json:"id"`
+	PullRequestID string     `json:"pull_request_id"`
+	ReviewerID    string     `json:"reviewer_id"`
+	AssignedAt    timestamp  `json:"assigned_at"`
+	FirstActionAt *timestamp `json:"first_action_at"`
+	UnassignedAt  *timestamp `json:"unassigned_at"`
+}
+
+type staleActionKey struct {
+	pullRequestID string
+	stage         string
+}
+
+type staleActionRow struct {
+	PullRequestID string    `json:"pull_request_id"`
+	Stage         string    `json:"stage"`
+	CreatedAt     timestamp `json:"created_at"`
+}
+
+type webhookSubscriptionRow struct {
+	ID         int64     `json:"id"`
+	URL        string    `json:"url"`
+	Secret     string    `json:"secret"`
+	EventTypes []string  `json:"event_types"`
+	IsActive   bool      `json:"is_active"`
+	CreatedAt  timestamp `json:"created_at"`
+}
+
+func (r *webhookSubscriptionRow) toDomain() *domain.WebhookSubscription {
+	types := make([]domain.EventType, len(r.EventTypes))
+	for i, t := range r.EventTypes {
+		types[i] = domain.EventType(t)
+	}
+	return domain.RestoreWebhookSubscription(r.ID, r.URL, r.Secret, types, r.IsActive, r.CreatedAt.Time)
+}
+
+type outboxRow struct {
+	ID            int64           `json:"id"`
+	EventType     string          `json:"event_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	Payload       json.RawMessage `json:"payload"`
+	CreatedAt     timestamp       `json:"created_at"`
+	PublishedAt   *timestamp      `json:"published_at"`
+	Attempts      int             `json:"attempts"`
+	NextAttemptAt timestamp       `json:"next_attempt_at"`
+	LastError     *string         `json:"last_error"`
+}
+
+func (r *outboxRow) toDomain() domain.Event {
+	return domain.Event{
+		Sequence:    r.ID,
+		Type:        domain.EventType(r.EventType),
+		AggregateID: r.AggregateID,
+		Payload:     append(json.RawMessage(nil), r.Payload...),
+		OccurredAt:  r.CreatedAt.Time,
+	}
+}
+
+type webhookDeliveryRow struct {
+	id             int64
+	subscriptionID int64
+	eventID        int64
+	status         domain.DeliveryStatus
+	attempts       int
+	nextAttemptAt  time.Time
+	lastStatusCode *int
+	lastError      string
+	createdAt      time.Time
+	deliveredAt    *time.Time
+}
+
+type reviewRequestRow struct {
+	id            int64
+	eventID       int64
+	pullRequestID string
+	action        string
+	reviewerIDs   []string
+	status        string
+	attempts      int
+	nextAttemptAt time.Time
+	lastError     string
+	createdAt     time.Time
+	completedAt   *time.Time
+}
+
+type notificationRow struct {
+	id            int64
+	eventID       *int64
+	channel       string
+	target        string
+	subject       string
+	body          string
+	htmlBody      string
+	status        string
+	attempts      int
+	nextAttemptAt time.Time
+	lastError     string
+	createdAt     time.Time
+	sentAt        *time.Time
+}
+
+type digestItemRow struct {
+	id        int64
+	userID    string
+	eventID   int64
+	summary   string
+	createdAt time.Time
+	sentAt    *time.Time
+}
+
+type idempotencyKey struct {
+	key   string
+	route string
+}
+
+type idempotencyRow struct {
+	requestHash  string
+	statusCode   *int
+	responseBody []byte
+	createdAt    time.Time
+	expiresAt    time.Time
+}
+
+// insertEvent добавляет доменное событие в outbox; вызывается под s.mu вместе с изменением состояния
+func (s *Store) insertEvent(event domain.Event) {
+	s.outbox = append(s.outbox, &outboxRow{
+		ID:            s.nextID("outbox"),
+		EventType:     string(event.Type),
+		AggregateID:   event.AggregateID,
+		Payload:       append(json.RawMessage(nil), event.Payload...),
+		CreatedAt:     newTimestamp(event.OccurredAt),
+		NextAttemptAt: newTimestamp(time.Now()),
+	})
+}
+
+// findEvent ищет событие outbox по номеру; события хранятся по возрастанию номера
+func (s *Store) findEvent(sequence int64) *outboxRow {
+	i := sort.Search(len(s.outbox), func(i int) bool { return s.outbox[i].ID >= sequence })
+	if i < len(s.outbox) && s.outbox[i].ID == sequence {
+		return s.outbox[i]
+	}
+	return nil
+}
+
+// teamOf возвращает команду пользователя или пустую строку
+func (s *Store) teamOf(userID string) string {
+	return s.members[userID]
+}
+
+// membersOf возвращает участников команды в порядке ID
+func (s *Store) membersOf(teamName string, onlyActive bool) ([]domain.User, error) {
+	var users []domain.User
+	for userID, team := range s.members {
+		if team != teamName {
+			continue
+		}
+		row := s.users[userID]
+		if onlyActive && !row.IsActive {
+			continue
+		}
+		user, err := domain.NewUser(row.ID, row.Username, row.IsActive)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, *user)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID() < users[j].ID() })
+	return users, nil
+}
+
+func containsString(slice []string, s string) bool {
+	for _, v := range slice {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,172 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+type TeamRepo struct {
+	s *Store
+}
+
+func NewTeamRepo(s *Store) *TeamRepo {
+	return &TeamRepo{s: s}
+}
+
+func (r *TeamRepo) CreateTeam(ctx context.Context, teamName string, members []domain.User) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.saveTeam(teamName, members)
+}
+
+// SaveTeam сохраняет команду, создаёт/обновляет участников и переносит их в команду
+// вместе с событиями смены активности
+func (r *TeamRepo) SaveTeam(ctx context.Context, team *domain.Team) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.saveTeam(team.Name(), team.Members())
+}
+
+// ImportTeams сохраняет все команды разом: при ошибке не применяется ни одна
+func (r *TeamRepo) ImportTeams(ctx context.Context, teams []domain.Team) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, team := range teams {
+		if err := r.s.validateMembers(team.Members()); err != nil {
+			return err
+		}
+	}
+	for _, team := range teams {
+		if err := r.s.saveTeam(team.Name(), team.Members()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListMemberships возвращает всех пользователей с их командами, включая не состоящих ни в одной
+func (r *TeamRepo) ListMemberships(ctx context.Context) ([]domain.Membership, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	memberships := make([]domain.Membership, 0, len(r.s.users))
+	for _, row := range r.s.users {
+		user, err := domain.NewUser(row.ID, row.Username, row.IsActive)
+		if err != nil {
+			return nil, err
+		}
+		memberships = append(memberships, domain.Membership{User: *user, TeamName: r.s.teamOf(row.ID)})
+	}
+	sort.Slice(memberships, func(i, j int) bool { return memberships[i].User.ID() < memberships[j].User.ID() })
+	return memberships, nil
+}
+
+// ReconcileTeams сохраняет команды из желаемого состояния и убирает из команд пользователей removed.
+// Активность removed сохраняется как передана: так выключаются пропавшие из файла.
+func (r *TeamRepo) ReconcileTeams(ctx context.Context, teams []domain.Team, removed []domain.User) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, team := range teams {
+		if err := r.s.validateMembers(team.Members()); err != nil {
+			return err
+		}
+	}
+	for _, u := range removed {
+		if _, ok := r.s.users[u.ID()]; !ok {
+			return domain.ErrUserNotFound
+		}
+	}
+
+	for _, team := range teams {
+		if err := r.s.saveTeam(team.Name(), team.Members()); err != nil {
+			return err
+		}
+	}
+	for _, u := range removed {
+		if err := r.s.updateUser(&u); err != nil {
+			return err
+		}
+		delete(r.s.members, u.ID())
+	}
+	return nil
+}
+
+// TeamExists проверяет наличие команды, в том числе оставшейся без участников
+func (r *TeamRepo) TeamExists(ctx context.Context, teamName string) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	_, ok := r.s.teams[teamName]
+	return ok, nil
+}
+
+func (r *TeamRepo) FindTeamByName(ctx context.Context, teamName string) (*domain.Team, error) {
+	return r.GetTeamByName(ctx, teamName)
+}
+
+func (r *TeamRepo) GetUserByID(ctx context.Context, id string) (*domain.User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	return r.s.getUserByID(id)
+}
+
+func (r *TeamRepo) CreateUser(ctx context.Context, u *domain.User) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.createUser(u)
+}
+
+func (r *TeamRepo) UpdateUser(ctx context.Context, u *domain.User) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.updateUser(u)
+}
+
+func (r *TeamRepo) GetTeamByName(ctx context.Context, teamName string) (*domain.Team, error) {
+	users, err := r.GetUsersInTeam(ctx, teamName, false)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewTeam(teamName, users)
+}
+
+func (r *TeamRepo) GetUsersInTeam(ctx context.Context, teamName string, onlyActive bool) ([]domain.User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	return r.s.usersInTeam(teamName, onlyActive)
+}
+
+// saveTeam создаёт команду, если её нет, обновляет или создаёт участников и переносит их в команду
+func (s *Store) saveTeam(teamName string, members []domain.User) error {
+	if err := s.validateMembers(members); err != nil {
+		return err
+	}
+	if _, ok := s.teams[teamName]; !ok {
+		s.teams[teamName] = &teamRow{Name: teamName}
+	}
+	for _, u := range members {
+		err := s.updateUser(&u)
+		if errors.Is(err, domain.ErrUserNotFound) {
+			err = s.createUser(&u)
+		}
+		if err != nil {
+			return err
+		}
+		s.members[u.ID()] = teamName
+	}
+	return nil
+}
+
+// validateMembers проверяет участников до изменения состояния, чтобы ошибка не оставила его наполовину применённым
+func (s *Store) validateMembers(members []domain.User) error {
+	for _, u := range members {
+		if u.ID() == "" || u.Username() == "" {
+			return errors.New("user id and username are required")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,623 @@
+package memory_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/adapter/memory"
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+
+	identityImportUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/identity/bulkImport"
+	identityCreateUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/identity/create"
+	identityDeleteUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/identity/delete"
+	identityListUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/identity/list"
+	identityLookupUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/identity/lookup"
+	integrationSyncUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/integration/syncPullRequest"
+	prCloseUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/pullrequest/close"
+	prCreateUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/pullrequest/create"
+	prGetUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/pullrequest/get"
+	prListSLAViolationsUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/pullrequest/listSLAViolations"
+	prMergeUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/pullrequest/merge"
+	prReassignUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/pullrequest/reassign"
+	prRecordReviewUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/pullrequest/recordReview"
+	prRemindStaleUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/pullrequest/remindStale"
+	prReopenUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/pullrequest/reopen"
+	snapshotExportUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/snapshot/export"
+	snapshotRestoreUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/snapshot/restore"
+	statsUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/stats/get"
+	slaReportUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/stats/slaReport"
+	teamImportUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/team/bulkImport"
+	teamCreateUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/team/create"
+	teamGetUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/team/get"
+	teamGetReviewPolicyUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/team/getReviewPolicy"
+	teamReconcileUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/team/reconcile"
+	teamSetReviewPolicyUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/team/setReviewPolicy"
+	teamSetReviewSLAUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/team/setReviewSLA"
+	userGetEventsUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/user/getEvents"
+	userGetReviewUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/user/getReview"
+	userSetActiveUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/user/setActive"
+	userSetEmailUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/user/setEmail"
+	webhookCreateUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/webhook/create"
+	webhookDeleteUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/webhook/delete"
+	webhookListUC "github.com/Skorpsrgvch/reviewer-service/internal/usecase/webhook/list"
+)
+
+// testReviewSLA — SLA первой реакции по умолчанию, как review_sla в конфиге
+const testReviewSLA = 4 * time.Hour
+
+// usecases — юзкейсы, собранные на одном хранилище в памяти так же, как в cmd/server
+type usecases struct {
+	createTeam      *teamCreateUC.Usecase
+	getTeam         *teamGetUC.Usecase
+	importTeams     *teamImportUC.Usecase
+	reconcileTeams  *teamReconcileUC.Usecase
+	setReviewPolicy *teamSetReviewPolicyUC.Usecase
+	getReviewPolicy *teamGetReviewPolicyUC.Usecase
+	setReviewSLA    *teamSetReviewSLAUC.Usecase
+
+	setActive *userSetActiveUC.Usecase
+	setEmail  *userSetEmailUC.Usecase
+	getReview *userGetReviewUC.Usecase
+	getEvents *userGetEventsUC.Usecase
+
+	createPR          *prCreateUC.Usecase
+	getPR             *prGetUC.Usecase
+	mergePR           *prMergeUC.Usecase
+	closePR           *prCloseUC.Usecase
+	reopenPR          *prReopenUC.Usecase
+	reassignPR        *prReassignUC.Usecase
+	recordReview      *prRecordReviewUC.Usecase
+	remindStale       *prRemindStaleUC.Usecase
+	listSLAViolations *prListSLAViolationsUC.Usecase
+
+	getStats     *statsUC.Usecase
+	getSLAReport *slaReportUC.Usecase
+
+	createIdentity   *identityCreateUC.Usecase
+	listIdentities   *identityListUC.Usecase
+	deleteIdentity   *identityDeleteUC.Usecase
+	lookupIdentity   *identityLookupUC.Usecase
+	importIdentities *identityImportUC.Usecase
+
+	syncPR *integrationSyncUC.Usecase
+
+	createWebhook *webhookCreateUC.Usecase
+	listWebhooks  *webhookListUC.Usecase
+	deleteWebhook *webhookDeleteUC.Usecase
+
+	exportSnapshot  *snapshotExportUC.Usecase
+	restoreSnapshot *snapshotRestoreUC.Usecase
+}
+
+// must возвращает юзкейс; конструкторы отказывают только при nil-зависимостях, то есть при ошибке в тесте
+func must[T any](uc T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return uc
+}
+
+func newUsecases(t *testing.T) *usecases {
+	t.Helper()
+	s := memory.NewStore(testSchemaVersion)
+	teamRepo := memory.NewTeamRepo(s)
+	userRepo := memory.NewUserRepo(s)
+	prRepo := memory.NewPullRequestRepo(s)
+	identityRepo := memory.NewIdentityRepo(s)
+	policyRepo := memory.NewReviewPolicyRepo(s)
+	slaRepo := memory.NewReviewSLARepo(s)
+	webhookRepo := memory.NewWebhookRepo(s)
+	snapshotRepo := memory.NewSnapshotRepo(s)
+	dbRepo := memory.NewDBRepo(s)
+
+	defaultPolicy, err := domain.NewReviewPolicy("*", 24*time.Hour, 48*time.Hour, domain.EscalationReassign, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u := &usecases{}
+	u.createTeam = must(teamCreateUC.NewUsecase(teamRepo))
+	u.getTeam = must(teamGetUC.NewUsecase(teamRepo))
+	u.importTeams = must(teamImportUC.NewUsecase(teamRepo, userRepo))
+	u.reconcileTeams = must(teamReconcileUC.NewUsecase(teamRepo))
+	u.setReviewPolicy = must(teamSetReviewPolicyUC.NewUsecase(teamRepo, userRepo, policyRepo))
+	u.getReviewPolicy = must(teamGetReviewPolicyUC.NewUsecase(policyRepo, teamRepo, defaultPolicy))
+	u.setReviewSLA = must(teamSetReviewSLAUC.NewUsecase(teamRepo, slaRepo))
+
+	u.setActive = must(userSetActiveUC.NewUsecase(userRepo, userRepo))
+	u.setEmail = must(userSetEmailUC.NewUsecase(userRepo, userRepo))
+	u.getReview = must(userGetReviewUC.NewUsecase(prRepo, userRepo))
+	u.getEvents = must(userGetEventsUC.NewUsecase(memory.NewOutboxRepo(s), userRepo))
+
+	u.createPR = must(prCreateUC.NewUsecase(prRepo, userRepo, teamRepo, 2))
+	u.getPR = must(prGetUC.NewUsecase(prRepo))
+	u.mergePR = must(prMergeUC.NewUsecase(prRepo, prRepo))
+	u.closePR = must(prCloseUC.NewUsecase(prRepo, prRepo))
+	u.reopenPR = must(prReopenUC.NewUsecase(prRepo, prRepo))
+	u.reassignPR = must(prReassignUC.NewUsecase(prRepo, userRepo, teamRepo))
+	u.recordReview = must(prRecordReviewUC.NewUsecase(prRepo, prRepo))
+	u.remindStale = must(prRemindStaleUC.NewUsecase(memory.NewStalePullRequestRepo(s), prRepo, u.reassignPR, defaultPolicy))
+	u.listSLAViolations = must(prListSLAViolationsUC.NewUsecase(slaRepo, testReviewSLA))
+
+	u.getStats = must(statsUC.NewUsecase(memory.NewStatsRepo(s)))
+	u.getSLAReport = must(slaReportUC.NewUsecase(slaRepo, testReviewSLA))
+
+	u.createIdentity = must(identityCreateUC.NewUsecase(identityRepo, userRepo))
+	u.listIdentities = must(identityListUC.NewUsecase(identityRepo))
+	u.deleteIdentity = must(identityDeleteUC.NewUsecase(identityRepo))
+	u.lookupIdentity = must(identityLookupUC.NewUsecase(identityRepo))
+	u.importIdentities = must(identityImportUC.NewUsecase(identityRepo, userRepo))
+
+	u.syncPR = must(integrationSyncUC.NewUsecase(userRepo, identityRepo, u.createPR, u.mergePR, u.closePR,
+		u.reopenPR, u.reassignPR, u.recordReview))
+
+	u.createWebhook = must(webhookCreateUC.NewUsecase(webhookRepo))
+	u.listWebhooks = must(webhookListUC.NewUsecase(webhookRepo))
+	u.deleteWebhook = must(webhookDeleteUC.NewUsecase(webhookRepo))
+
+	u.exportSnapshot = must(snapshotExportUC.NewUsecase(snapshotRepo, dbRepo))
+	u.restoreSnapshot = must(snapshotRestoreUC.NewUsecase(snapshotRepo, dbRepo))
+	return u
+}
+
+// seedTeam создаёт команду из активных пользователей через юзкейс создания команды
+func (u *usecases) seedTeam(t *testing.T, teamName string, userIDs ...string) {
+	t.Helper()
+	members := make([]domain.User, 0, len(userIDs))
+	for _, id := range userIDs {
+		user, err := domain.NewUser(id, "name-"+id, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		members = append(members, *user)
+	}
+	if err := u.createTeam.Execute(context.Background(), teamCreateUC.Input{TeamName: teamName, Members: members}); err != nil {
+		t.Fatalf("create team %s: %v", teamName, err)
+	}
+}
+
+func (u *usecases) seedPR(t *testing.T, id, authorID string) *domain.PullRequest {
+	t.Helper()
+	pr, err := u.createPR.Execute(context.Background(), prCreateUC.Input{PullRequestID: id, PullRequestName: "PR " + id, AuthorID: authorID})
+	if err != nil {
+		t.Fatalf("create PR %s: %v", id, err)
+	}
+	return pr
+}
+
+func wantErr(t *testing.T, op string, err, want error) {
+	t.Helper()
+	if !errors.Is(err, want) {
+		t.Errorf("%s: error = %v, want %v", op, err, want)
+	}
+}
+
+func TestUsecaseTeams(t *testing.T) {
+	u := newUsecases(t)
+	ctx := context.Background()
+	u.seedTeam(t, "backend", "u1", "u2")
+
+	team, err := u.getTeam.Execute(ctx, teamGetUC.Input{TeamName: "backend"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(team.Members()) != 2 {
+		t.Errorf("members = %+v, want u1 and u2", team.Members())
+	}
+	_, err = u.getTeam.Execute(ctx, teamGetUC.Input{TeamName: "ghost"})
+	wantErr(t, "get missing team", err, domain.ErrTeamNotFound)
+
+	imported, err := u.importTeams.Execute(ctx, teamImportUC.Input{
+		Data:   strings.NewReader("team_name,user_id,username\nbackend,u1,name-u1\nplatform,u2,name-u2\nplatform,u3,name-u3\n"),
+		Format: teamImportUC.FormatCSV,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(imported.TeamsCreated, []string{"platform"}) {
+		t.Errorf("teams created = %v, want [platform]", imported.TeamsCreated)
+	}
+	if teamName := userTeam(t, u, "u2"); teamName != "platform" {
+		t.Errorf("u2 team after import = %q, want platform", teamName)
+	}
+
+	// Синхронизация: u3 нет в файле, он исключается из команды и выключается
+	desired := "team_name,user_id,username\nbackend,u1,name-u1\nbackend,u2,name-u2\n"
+	plan, err := u.reconcileTeams.Execute(ctx, teamReconcileUC.Input{Data: strings.NewReader(desired), Format: teamImportUC.FormatCSV, DeactivateMissing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Applied || plan.PlanHash == "" {
+		t.Fatalf("plan = %+v, want an unapplied plan with a hash", plan)
+	}
+	applied, err := u.reconcileTeams.Execute(ctx, teamReconcileUC.Input{Data: strings.NewReader(desired), Format: teamImportUC.FormatCSV,
+		DeactivateMissing: true, Apply: true, PlanHash: plan.PlanHash})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !applied.Applied || !reflect.DeepEqual(applied.TeamsEmptied, []string{"platform"}) {
+		t.Errorf("applied = %+v, want platform emptied", applied)
+	}
+	if teamName := userTeam(t, u, "u3"); teamName != "" {
+		t.Errorf("u3 team after reconcile = %q, want none", teamName)
+	}
+	_, err = u.reconcileTeams.Execute(ctx, teamReconcileUC.Input{Data: strings.NewReader(desired), Format: teamImportUC.FormatCSV,
+		Apply: true, PlanHash: "stale"})
+	wantErr(t, "apply stale plan", err, domain.ErrReconcilePlanStale)
+}
+
+// userTeam возвращает команду пользователя через юзкейс смены активности
+func userTeam(t *testing.T, u *usecases, userID string) string {
+	t.Helper()
+	out, err := u.setActive.Execute(context.Background(), userSetActiveUC.Input{UserID: userID, IsActive: true})
+	if err != nil {
+		t.Fatalf("set active %s: %v", userID, err)
+	}
+	return out.TeamName
+}
+
+func TestUsecasePullRequestLifecycle(t *testing.T) {
+	u := newUsecases(t)
+	ctx := context.Background()
+	u.seedTeam(t, "backend", "u1", "u2", "u3", "u4")
+
+	pr := u.seedPR(t, "pr-1", "u1")
+	if len(pr.AssignedReviewers()) != 2 || slices.Contains(pr.AssignedReviewers(), "u1") {
+		t.Fatalf("reviewers = %v, want two reviewers other than the author", pr.AssignedReviewers())
+	}
+	_, err := u.createPR.Execute(ctx, prCreateUC.Input{PullRequestID: "pr-1", PullRequestName: "again", AuthorID: "u1"})
+	wantErr(t, "create existing PR", err, domain.ErrPRExists)
+	_, err = u.createPR.Execute(ctx, prCreateUC.Input{PullRequestID: "pr-x", PullRequestName: "x", AuthorID: "ghost"})
+	wantErr(t, "create PR by unknown author", err, domain.ErrAuthorNotFound)
+
+	reviewer := pr.AssignedReviewers()[0]
+	review, err := u.getReview.Execute(ctx, userGetReviewUC.Input{UserID: reviewer})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(review.PullRequests) != 1 || review.PullRequests[0].ID() != "pr-1" {
+		t.Errorf("review of %s = %+v, want pr-1", reviewer, review.PullRequests)
+	}
+
+	first, err := u.recordReview.Execute(ctx, prRecordReviewUC.Input{PullRequestID: "pr-1", ReviewerID: reviewer})
+	if err != nil || !first.First {
+		t.Fatalf("first review = %+v, %v, want first", first, err)
+	}
+	again, err := u.recordReview.Execute(ctx, prRecordReviewUC.Input{PullRequestID: "pr-1", ReviewerID: reviewer})
+	if err != nil || again.First || !again.FirstActionAt.Equal(first.FirstActionAt) {
+		t.Errorf("repeated review = %+v, %v, want the first action time kept", again, err)
+	}
+	_, err = u.recordReview.Execute(ctx, prRecordReviewUC.Input{PullRequestID: "pr-1", ReviewerID: "u1"})
+	wantErr(t, "review by the author", err, domain.ErrReviewerNotAssigned)
+
+	reassigned, newReviewer, err := u.reassignPR.Execute(ctx, prReassignUC.Input{PullRequestID: "pr-1", OldReviewerID: reviewer})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newReviewer == "u1" || newReviewer == reviewer || !slices.Contains(reassigned.AssignedReviewers(), newReviewer) {
+		t.Errorf("reassign %s -> %s, reviewers %v", reviewer, newReviewer, reassigned.AssignedReviewers())
+	}
+	stale := pr.Version()
+	_, _, err = u.reassignPR.Execute(ctx, prReassignUC.Input{PullRequestID: "pr-1", OldReviewerID: newReviewer, ExpectedVersion: &stale})
+	wantErr(t, "reassign with a stale version", err, domain.ErrPRVersionMismatch)
+
+	merged, err := u.mergePR.Execute(ctx, prMergeUC.Input{PullRequestID: "pr-1"})
+	if err != nil || merged.Status() != domain.PRMerged || merged.MergedAt() == nil {
+		t.Fatalf("merge = %+v, %v", merged, err)
+	}
+	if again, err := u.mergePR.Execute(ctx, prMergeUC.Input{PullRequestID: "pr-1"}); err != nil || again.Status() != domain.PRMerged {
+		t.Errorf("repeated merge = %+v, %v, want an idempotent merge", again, err)
+	}
+	_, _, err = u.reassignPR.Execute(ctx, prReassignUC.Input{PullRequestID: "pr-1", OldReviewerID: newReviewer})
+	wantErr(t, "reassign merged PR", err, domain.ErrPRAlreadyMerged)
+	_, err = u.closePR.Execute(ctx, prCloseUC.Input{PullRequestID: "pr-1"})
+	wantErr(t, "close merged PR", err, domain.ErrPRAlreadyMerged)
+
+	got, err := u.getPR.Execute(ctx, prGetUC.Input{PullRequestID: "pr-1"})
+	if err != nil || got.Status() != domain.PRMerged {
+		t.Errorf("get = %+v, %v, want MERGED", got, err)
+	}
+	_, err = u.getPR.Execute(ctx, prGetUC.Input{PullRequestID: "ghost"})
+	wantErr(t, "get missing PR", err, domain.ErrPRNotFound)
+}
+
+func TestUsecaseCloseAndReopen(t *testing.T) {
+	u := newUsecases(t)
+	ctx := context.Background()
+	u.seedTeam(t, "backend", "u1", "u2", "u3")
+	pr := u.seedPR(t, "pr-1", "u1")
+
+	closed, err := u.closePR.Execute(ctx, prCloseUC.Input{PullRequestID: "pr-1"})
+	if err != nil || closed.Status() != domain.PRClosed {
+		t.Fatalf("close = %+v, %v", closed, err)
+	}
+	_, err = u.mergePR.Execute(ctx, prMergeUC.Input{PullRequestID: "pr-1"})
+	wantErr(t, "merge closed PR", err, domain.ErrPRClosed)
+	_, err = u.recordReview.Execute(ctx, prRecordReviewUC.Input{PullRequestID: "pr-1", ReviewerID: pr.AssignedReviewers()[0]})
+	wantErr(t, "review closed PR", err, domain.ErrPRClosed)
+	review, err := u.getReview.Execute(ctx, userGetReviewUC.Input{UserID: pr.AssignedReviewers()[0]})
+	if err != nil || len(review.PullRequests) != 1 || review.PullRequests[0].Status() != domain.PRClosed {
+		t.Errorf("review after close = %+v, %v, want the closed pr-1", review, err)
+	}
+
+	reopened, err := u.reopenPR.Execute(ctx, prReopenUC.Input{PullRequestID: "pr-1"})
+	if err != nil || !reopened.Reopened || reopened.PullRequest.Status() != domain.PROpen {
+		t.Fatalf("reopen = %+v, %v", reopened, err)
+	}
+	if !reflect.DeepEqual(reopened.PullRequest.AssignedReviewers(), pr.AssignedReviewers()) {
+		t.Errorf("reviewers after reopen = %v, want %v", reopened.PullRequest.AssignedReviewers(), pr.AssignedReviewers())
+	}
+	if again, err := u.reopenPR.Execute(ctx, prReopenUC.Input{PullRequestID: "pr-1"}); err != nil || again.Reopened {
+		t.Errorf("reopen of an open PR = %+v, %v, want a no-op", again, err)
+	}
+
+	events, err := u.getEvents.Execute(ctx, userGetEventsUC.Input{UserID: pr.AssignedReviewers()[0], Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kinds []domain.UserEventKind
+	for _, e := range events.Events {
+		kinds = append(kinds, e.Kind)
+	}
+	want := []domain.UserEventKind{domain.UserEventAssigned, domain.UserEventClosed, domain.UserEventReopened}
+	if !reflect.DeepEqual(kinds, want) {
+		t.Errorf("reviewer events = %v, want %v", kinds, want)
+	}
+}
+
+func TestUsecaseInactiveUsersAreNotAssigned(t *testing.T) {
+	u := newUsecases(t)
+	ctx := context.Background()
+	u.seedTeam(t, "backend", "u1", "u2", "u3", "u4")
+
+	for _, id := range []string{"u2", "u3"} {
+		if _, err := u.setActive.Execute(ctx, userSetActiveUC.Input{UserID: id, IsActive: false}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pr := u.seedPR(t, "pr-1", "u1")
+	if !reflect.DeepEqual(pr.AssignedReviewers(), []string{"u4"}) {
+		t.Errorf("reviewers = %v, want only the active u4", pr.AssignedReviewers())
+	}
+	_, _, err := u.reassignPR.Execute(ctx, prReassignUC.Input{PullRequestID: "pr-1", OldReviewerID: "u4"})
+	wantErr(t, "reassign without candidates", err, domain.ErrNoActiveReviewers)
+
+	user, err := u.setEmail.Execute(ctx, userSetEmailUC.Input{UserID: "u1", Email: "u1@example.com", Digest: true})
+	if err != nil || user.Email() != "u1@example.com" {
+		t.Errorf("set email = %+v, %v", user, err)
+	}
+	_, err = u.setEmail.Execute(ctx, userSetEmailUC.Input{UserID: "u1", Email: "not-an-email"})
+	wantErr(t, "set invalid email", err, domain.ErrInvalidEmail)
+}
+
+func TestUsecaseStats(t *testing.T) {
+	u := newUsecases(t)
+	ctx := context.Background()
+	u.seedTeam(t, "backend", "u1", "u2", "u3")
+	open := u.seedPR(t, "pr-1", "u1")
+	u.seedPR(t, "pr-2", "u1")
+	if _, err := u.mergePR.Execute(ctx, prMergeUC.Input{PullRequestID: "pr-2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := u.getStats.Execute(ctx, statsUC.Input{TeamName: "backend", Now: time.Now().UTC().Add(time.Minute)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantOpen := make(map[string]int)
+	for _, id := range open.AssignedReviewers() {
+		wantOpen[id] = 1
+	}
+	if !reflect.DeepEqual(stats.OpenAssignments, wantOpen) {
+		t.Errorf("open assignments = %v, want %v", stats.OpenAssignments, wantOpen)
+	}
+	if stats.TimeToMerge.Merged != 1 {
+		t.Errorf("time to merge = %+v, want one merged PR", stats.TimeToMerge)
+	}
+	_, err = u.getStats.Execute(ctx, statsUC.Input{TeamName: "backend", From: time.Now().UTC(), To: time.Now().UTC().Add(-time.Hour)})
+	wantErr(t, "stats for an inverted period", err, domain.ErrInvalidPeriod)
+}
+
+func TestUsecaseStalePolicies(t *testing.T) {
+	u := newUsecases(t)
+	ctx := context.Background()
+	u.seedTeam(t, "backend", "u1", "u2", "u3", "u4")
+
+	got, err := u.getReviewPolicy.Execute(ctx, teamGetReviewPolicyUC.Input{TeamName: "backend"})
+	if err != nil || !got.IsDefault {
+		t.Fatalf("policy before set = %+v, %v, want the default", got, err)
+	}
+	_, err = u.setReviewPolicy.Execute(ctx, teamSetReviewPolicyUC.Input{TeamName: "backend", RemindAfter: time.Hour,
+		EscalateAfter: 2 * time.Hour, Escalation: domain.EscalationNotifyLead, LeadID: "ghost"})
+	wantErr(t, "policy with unknown lead", err, domain.ErrUserNotFound)
+	if _, err := u.setReviewPolicy.Execute(ctx, teamSetReviewPolicyUC.Input{TeamName: "backend", RemindAfter: time.Hour,
+		EscalateAfter: 2 * time.Hour, Escalation: domain.EscalationReassign}); err != nil {
+		t.Fatal(err)
+	}
+	got, err = u.getReviewPolicy.Execute(ctx, teamGetReviewPolicyUC.Input{TeamName: "backend"})
+	if err != nil || got.IsDefault || got.Policy.Escalation() != domain.EscalationReassign {
+		t.Fatalf("policy after set = %+v, %v", got, err)
+	}
+
+	pr := u.seedPR(t, "pr-1", "u1")
+	now := time.Now().UTC()
+
+	reminded, err := u.remindStale.Execute(ctx, prRemindStaleUC.Input{Now: now.Add(90 * time.Minute)})
+	if err != nil || reminded.Reminded != 1 || reminded.Escalated != 0 {
+		t.Fatalf("remind = %+v, %v, want one reminder", reminded, err)
+	}
+	if again, err := u.remindStale.Execute(ctx, prRemindStaleUC.Input{Now: now.Add(100 * time.Minute)}); err != nil || again.Reminded != 0 {
+		t.Errorf("repeated remind = %+v, %v, want no second reminder", again, err)
+	}
+
+	escalated, err := u.remindStale.Execute(ctx, prRemindStaleUC.Input{Now: now.Add(3 * time.Hour)})
+	if err != nil || escalated.Escalated != 1 || escalated.Reassigned != 2 {
+		t.Fatalf("escalate = %+v, %v, want both reviewers replaced", escalated, err)
+	}
+	after, err := u.getPR.Execute(ctx, prGetUC.Input{PullRequestID: "pr-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Замены идут по очереди, поэтому снятый первым ревьюер может заменить второго
+	if after.Version() != pr.Version()+2 || slices.Contains(after.AssignedReviewers(), "u1") ||
+		reflect.DeepEqual(after.AssignedReviewers(), pr.AssignedReviewers()) {
+		t.Errorf("after escalation = %+v, want two replacements of %v", after, pr.AssignedReviewers())
+	}
+}
+
+func TestUsecaseReviewSLA(t *testing.T) {
+	u := newUsecases(t)
+	ctx := context.Background()
+	u.seedTeam(t, "backend", "u1", "u2", "u3")
+
+	_, err := u.setReviewSLA.Execute(ctx, teamSetReviewSLAUC.Input{TeamName: "ghost", FirstReviewWithin: time.Hour})
+	wantErr(t, "SLA for missing team", err, domain.ErrTeamNotFound)
+	_, err = u.setReviewSLA.Execute(ctx, teamSetReviewSLAUC.Input{TeamName: "backend"})
+	wantErr(t, "zero SLA", err, domain.ErrInvalidReviewSLA)
+	if _, err := u.setReviewSLA.Execute(ctx, teamSetReviewSLAUC.Input{TeamName: "backend", FirstReviewWithin: time.Hour}); err != nil {
+		t.Fatal(err)
+	}
+
+	pr := u.seedPR(t, "pr-1", "u1")
+	reviewed := pr.AssignedReviewers()[0]
+	if _, err := u.recordReview.Execute(ctx, prRecordReviewUC.Input{PullRequestID: "pr-1", ReviewerID: reviewed}); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now().UTC().Add(2 * time.Hour)
+	violations, err := u.listSLAViolations.Execute(ctx, prListSLAViolationsUC.Input{TeamName: "backend", Now: now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(violations) != 1 || violations[0].ReviewerID != pr.AssignedReviewers()[1] {
+		t.Errorf("violations = %+v, want only the reviewer without a reaction", violations)
+	}
+
+	report, err := u.getSLAReport.Execute(ctx, slaReportUC.Input{TeamName: "backend", Now: now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []domain.SLABreachStats{{Key: "backend", Assignments: 2, Breached: 1}}
+	if !reflect.DeepEqual(report.Teams, want) {
+		t.Errorf("report teams = %+v, want %+v", report.Teams, want)
+	}
+}
+
+func TestUsecaseIdentitiesAndSync(t *testing.T) {
+	u := newUsecases(t)
+	ctx := context.Background()
+	u.seedTeam(t, "backend", "u1", "u2", "u3")
+
+	if _, err := u.createIdentity.Execute(ctx, identityCreateUC.Input{Provider: "gitlab", ExternalID: "101", UserID: "u1"}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := u.createIdentity.Execute(ctx, identityCreateUC.Input{Provider: "gitlab", ExternalID: "101", UserID: "u2"})
+	wantErr(t, "create duplicate identity", err, domain.ErrIdentityExists)
+	_, err = u.createIdentity.Execute(ctx, identityCreateUC.Input{Provider: "gitlab", ExternalID: "999", UserID: "ghost"})
+	wantErr(t, "identity of unknown user", err, domain.ErrUserNotFound)
+
+	imported, err := u.importIdentities.Execute(ctx, identityImportUC.Input{CSV: strings.NewReader("provider,external_id,user_id\ngithub,octo-u2,u2\n")})
+	if err != nil || imported.Created != 1 {
+		t.Fatalf("import = %+v, %v, want one identity", imported, err)
+	}
+	user, err := u.lookupIdentity.Execute(ctx, identityLookupUC.Input{Provider: "github", ExternalID: "octo-u2"})
+	if err != nil || user.ID() != "u2" {
+		t.Errorf("lookup = %+v, %v, want u2", user, err)
+	}
+	list, err := u.listIdentities.Execute(ctx, identityListUC.Input{UserID: "u1"})
+	if err != nil || len(list) != 1 || list[0].ExternalID() != "101" {
+		t.Errorf("identities of u1 = %+v, %v", list, err)
+	}
+
+	// Автор GitLab без логина сопоставляется по ID учётной записи, ревью — по логину GitHub
+	event := domain.VCSPullRequestEvent{Provider: domain.VCSGitLab, Action: domain.VCSActionOpened, Repository: "acme/api",
+		Number: 7, Title: "Add API", AuthorExternalID: "101"}
+	out, err := u.syncPR.Execute(ctx, event)
+	if err != nil || out.Result != integrationSyncUC.ResultCreated {
+		t.Fatalf("sync opened = %+v, %v", out, err)
+	}
+	pr, err := u.getPR.Execute(ctx, prGetUC.Input{PullRequestID: out.PullRequestID})
+	if err != nil || pr.AuthorID() != "u1" {
+		t.Fatalf("synced PR = %+v, %v, want author u1", pr, err)
+	}
+	event.Action = domain.VCSActionMerged
+	if out, err := u.syncPR.Execute(ctx, event); err != nil || out.Result != integrationSyncUC.ResultMerged {
+		t.Errorf("sync merged = %+v, %v", out, err)
+	}
+
+	if err := u.deleteIdentity.Execute(ctx, identityDeleteUC.Input{Provider: "gitlab", ExternalID: "101"}); err != nil {
+		t.Fatal(err)
+	}
+	err = u.deleteIdentity.Execute(ctx, identityDeleteUC.Input{Provider: "gitlab", ExternalID: "101"})
+	wantErr(t, "delete missing identity", err, domain.ErrIdentityNotFound)
+	_, err = u.lookupIdentity.Execute(ctx, identityLookupUC.Input{Provider: "gitlab", ExternalID: "101"})
+	wantErr(t, "lookup deleted identity", err, domain.ErrIdentityNotFound)
+}
+
+func TestUsecaseWebhooks(t *testing.T) {
+	u := newUsecases(t)
+	ctx := context.Background()
+
+	sub, err := u.createWebhook.Execute(ctx, webhookCreateUC.Input{URL: "https://example.com/hook", Secret: "s3cret",
+		EventTypes: []domain.EventType{domain.EventPRMerged}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = u.createWebhook.Execute(ctx, webhookCreateUC.Input{URL: "ftp://example.com", Secret: "s3cret"})
+	wantErr(t, "create invalid webhook", err, domain.ErrInvalidWebhook)
+
+	subs, err := u.listWebhooks.Execute(ctx)
+	if err != nil || len(subs) != 1 || subs[0].ID() != sub.ID() {
+		t.Errorf("webhooks = %+v, %v, want the created subscription", subs, err)
+	}
+	if err := u.deleteWebhook.Execute(ctx, webhookDeleteUC.Input{SubscriptionID: sub.ID()}); err != nil {
+		t.Fatal(err)
+	}
+	err = u.deleteWebhook.Execute(ctx, webhookDeleteUC.Input{SubscriptionID: sub.ID()})
+	wantErr(t, "delete missing webhook", err, domain.ErrWebhookNotFound)
+}
+
+func TestUsecaseSnapshotRoundTrip(t *testing.T) {
+	src := newUsecases(t)
+	ctx := context.Background()
+	src.seedTeam(t, "backend", "u1", "u2", "u3")
+	pr := src.seedPR(t, "pr-1", "u1")
+	src.seedPR(t, "pr-2", "u1")
+	if _, err := src.closePR.Execute(ctx, prCloseUC.Input{PullRequestID: "pr-2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	exported, err := src.exportSnapshot.Execute(ctx, snapshotExportUC.Input{W: &buf, Now: time.Now().UTC()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dst := newUsecases(t)
+	dst.seedTeam(t, "other", "x1")
+	_, err = dst.restoreSnapshot.Execute(ctx, snapshotRestoreUC.Input{R: bytes.NewReader(buf.Bytes())})
+	wantErr(t, "restore into non-empty store", err, domain.ErrDatabaseNotEmpty)
+	restored, err := dst.restoreSnapshot.Execute(ctx, snapshotRestoreUC.Input{R: bytes.NewReader(buf.Bytes()), Force: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.Rows != exported.Rows {
+		t.Errorf("restored %d rows, exported %d", restored.Rows, exported.Rows)
+	}
+
+	got, err := dst.getPR.Execute(ctx, prGetUC.Input{PullRequestID: "pr-1"})
+	if err != nil || !reflect.DeepEqual(got.AssignedReviewers(), pr.AssignedReviewers()) {
+		t.Errorf("restored pr-1 = %+v, %v, want reviewers %v", got, err, pr.AssignedReviewers())
+	}
+	if closed, err := dst.getPR.Execute(ctx, prGetUC.Input{PullRequestID: "pr-2"}); err != nil || closed.Status() != domain.PRClosed {
+		t.Errorf("restored pr-2 = %+v, %v, want CLOSED", closed, err)
+	}
+	_, err = dst.getTeam.Execute(ctx, teamGetUC.Input{TeamName: "other"})
+	wantErr(t, "team overwritten by restore", err, domain.ErrTeamNotFound)
+}
@@ -0,0 +1,149 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+type UserRepo struct {
+	s *Store
+}
+
+func NewUserRepo(s *Store) *UserRepo {
+	return &UserRepo{s: s}
+}
+
+func (r *UserRepo) GetUserByID(ctx context.Context, id string) (*domain.User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	return r.s.getUserByID(id)
+}
+
+// UpdateUserEmail сохраняет настройки почтовых уведомлений пользователя
+func (r *UserRepo) UpdateUserEmail(ctx context.Context, u *domain.User) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	row, ok := r.s.users[u.ID()]
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+	row.Email = nil
+	if u.Email() != "" {
+		email := u.Email()
+		row.Email = &email
+	}
+	row.EmailDigest = u.EmailDigest()
+	return nil
+}
+
+func (r *UserRepo) CreateUser(ctx context.Context, u *domain.User) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.createUser(u)
+}
+
+// UpdateUser обновляет пользователя. Если изменилась активность,
+// вместе с изменением пишет user.activated / user.deactivated в outbox.
+func (r *UserRepo) UpdateUser(ctx context.Context, u *domain.User) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.updateUser(u)
+}
+
+func (r *UserRepo) GetUsersInTeam(ctx context.Context, teamName string, onlyActive bool) ([]domain.User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	return r.s.usersInTeam(teamName, onlyActive)
+}
+
+func (r *UserRepo) GetTeamByUser(ctx context.Context, userID string) (string, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	teamName, ok := r.s.members[userID]
+	if !ok {
+		return "", domain.ErrUserNotFound
+	}
+	return teamName, nil
+}
+
+// ResolveUser находит пользователя по логину VCS: сначала по привязкам учётных записей,
+// затем по совпадению с id или username без учёта регистра.
+func (r *UserRepo) ResolveUser(ctx context.Context, provider domain.VCSProvider, login string) (*domain.User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	user, err := r.s.lookupIdentityUser(string(provider), login)
+	if !errors.Is(err, domain.ErrIdentityNotFound) {
+		return user, err
+	}
+
+	if row, ok := r.s.users[login]; ok {
+		return domain.NewUser(row.ID, row.Username, row.IsActive)
+	}
+	var found *userRow
+	for _, row := range r.s.users {
+		if strings.EqualFold(row.Username, login) && (found == nil || row.ID < found.ID) {
+			found = row
+		}
+	}
+	if found == nil {
+		return nil, domain.ErrUserNotFound
+	}
+	return domain.NewUser(found.ID, found.Username, found.IsActive)
+}
+
+// getUserByID загружает пользователя вместе с настройками почтовых уведомлений
+func (s *Store) getUserByID(id string) (*domain.User, error) {
+	row, ok := s.users[id]
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+	return row.toDomain()
+}
+
+func (s *Store) createUser(u *domain.User) error {
+	if _, ok := s.users[u.ID()]; ok {
+		return fmt.Errorf("user %q already exists", u.ID())
+	}
+	s.users[u.ID()] = &userRow{ID: u.ID(), Username: u.Username(), IsActive: u.IsActive()}
+	return nil
+}
+
+// updateUser обновляет пользователя и записывает событие смены активности
+func (s *Store) updateUser(u *domain.User) error {
+	row, ok := s.users[u.ID()]
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+	wasActive := row.IsActive
+	row.Username = u.Username()
+	row.IsActive = u.IsActive()
+
+	if wasActive == u.IsActive() {
+		return nil
+	}
+	event, err := domain.NewUserActivityEvent(u)
+	if err != nil {
+		return err
+	}
+	s.insertEvent(event)
+	return nil
+}
+
+// usersInTeam возвращает участников команды или domain.ErrTeamNotFound, если их нет
+func (s *Store) usersInTeam(teamName string, onlyActive bool) ([]domain.User, error) {
+	users, err := s.membersOf(teamName, onlyActive)
+	if err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		return nil, domain.ErrTeamNotFound
+	}
+	return users, nil
+}
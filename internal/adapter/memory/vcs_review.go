@@ -0,0 +1,143 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+const (
+	reviewRequestPending = "PENDING"
+	reviewRequestDone    = "DONE"
+	reviewRequestFailed  = "FAILED"
+)
+
+// VCSReviewRequestRepo — журнал запросов ревью в VCS
+type VCSReviewRequestRepo struct {
+	s *Store
+}
+
+func NewVCSReviewRequestRepo(s *Store) *VCSReviewRequestRepo {
+	return &VCSReviewRequestRepo{s: s}
+}
+
+func (r *VCSReviewRequestRepo) EnqueueReviewRequests(ctx context.Context, eventSequence int64, requests []domain.VCSReviewRequestDraft) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := time.Now().UTC()
+	for _, req := range requests {
+		if r.s.hasReviewRequest(eventSequence, string(req.Action)) {
+			continue
+		}
+		r.s.reviewRequests = append(r.s.reviewRequests, &reviewRequestRow{
+			id:            r.s.nextID("vcs_review_requests"),
+			eventID:       eventSequence,
+			pullRequestID: req.PullRequestID,
+			action:        string(req.Action),
+			reviewerIDs:   append([]string(nil), req.ReviewerIDs...),
+			status:        reviewRequestPending,
+			nextAttemptAt: now,
+			createdAt:     now,
+		})
+	}
+	return nil
+}
+
+func (s *Store) hasReviewRequest(eventID int64, action string) bool {
+	for _, req := range s.reviewRequests {
+		if req.eventID == eventID && req.action == action {
+			return true
+		}
+	}
+	return false
+}
+
+// ClaimDueReviewRequests захватывает запросы, время которых пришло, сдвигая next_attempt_at на lease.
+// Снятие ревьюера отправляется раньше запроса нового из того же события.
+func (r *VCSReviewRequestRepo) ClaimDueReviewRequests(ctx context.Context, limit int, lease time.Duration) ([]domain.VCSReviewRequest, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := time.Now().UTC()
+	var due []*reviewRequestRow
+	for _, req := range r.s.reviewRequests {
+		if req.status == reviewRequestPending && !req.nextAttemptAt.After(now) {
+			due = append(due, req)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		if !due[i].nextAttemptAt.Equal(due[j].nextAttemptAt) {
+			return due[i].nextAttemptAt.Before(due[j].nextAttemptAt)
+		}
+		return due[i].eventID < due[j].eventID
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		if due[i].eventID != due[j].eventID {
+			return due[i].eventID < due[j].eventID
+		}
+		return due[i].action == string(domain.VCSReviewActionRemove) && due[j].action != string(domain.VCSReviewActionRemove)
+	})
+
+	requests := make([]domain.VCSReviewRequest, 0, len(due))
+	for _, req := range due {
+		req.attempts++
+		req.nextAttemptAt = now.Add(lease)
+		requests = append(requests, domain.VCSReviewRequest{
+			ID:            req.id,
+			Attempts:      req.attempts,
+			PullRequestID: req.pullRequestID,
+			Action:        domain.VCSReviewAction(req.action),
+			ReviewerIDs:   append([]string(nil), req.reviewerIDs...),
+		})
+	}
+	return requests, nil
+}
+
+func (r *VCSReviewRequestRepo) MarkReviewRequestDone(ctx context.Context, id int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if req := r.s.findReviewRequest(id); req != nil {
+		now := time.Now().UTC()
+		req.status = reviewRequestDone
+		req.lastError = ""
+		req.completedAt = &now
+	}
+	return nil
+}
+
+// MarkReviewRequestFailed записывает неудачную попытку и планирует следующую.
+// Если nextAttemptAt == nil, запрос переводится в FAILED.
+func (r *VCSReviewRequestRepo) MarkReviewRequestFailed(ctx context.Context, id int64, errMsg string, nextAttemptAt *time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	req := r.s.findReviewRequest(id)
+	if req == nil {
+		return nil
+	}
+	req.lastError = errMsg
+	if nextAttemptAt == nil {
+		now := time.Now().UTC()
+		req.status = reviewRequestFailed
+		req.completedAt = &now
+		return nil
+	}
+	req.nextAttemptAt = nextAttemptAt.UTC()
+	return nil
+}
+
+func (s *Store) findReviewRequest(id int64) *reviewRequestRow {
+	for _, req := range s.reviewRequests {
+		if req.id == id {
+			return req
+		}
+	}
+	return nil
+}
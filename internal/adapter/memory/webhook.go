@@ -0,0 +1,237 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+// WebhookRepo хранит подписки на вебхуки и журнал доставок.
+type WebhookRepo struct {
+	s *Store
+}
+
+func NewWebhookRepo(s *Store) *WebhookRepo {
+	return &WebhookRepo{s: s}
+}
+
+// CreateSubscription сохраняет подписку и возвращает её с присвоенным ID.
+func (r *WebhookRepo) CreateSubscription(ctx context.Context, sub *domain.WebhookSubscription) (*domain.WebhookSubscription, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	types := make([]string, 0, len(sub.EventTypes()))
+	for _, t := range sub.EventTypes() {
+		types = append(types, string(t))
+	}
+	row := &webhookSubscriptionRow{
+		ID:         r.s.nextID("webhook_subscriptions"),
+		URL:        sub.URL(),
+		Secret:     sub.Secret(),
+		EventTypes: types,
+		IsActive:   sub.IsActive(),
+		CreatedAt:  newTimestamp(sub.CreatedAt()),
+	}
+	r.s.webhooks[row.ID] = row
+	return domain.RestoreWebhookSubscription(row.ID, sub.URL(), sub.Secret(), sub.EventTypes(), sub.IsActive(), sub.CreatedAt()), nil
+}
+
+// ListSubscriptions возвращает все подписки.
+func (r *WebhookRepo) ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var subs []domain.WebhookSubscription
+	for _, row := range r.s.webhooks {
+		subs = append(subs, *row.toDomain())
+	}
+	sort.Slice(subs, func(i, j int) bool { return subs[i].ID() < subs[j].ID() })
+	return subs, nil
+}
+
+// DeleteSubscription удаляет подписку вместе с её журналом доставок.
+func (r *WebhookRepo) DeleteSubscription(ctx context.Context, id int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.webhooks[id]; !ok {
+		return domain.ErrWebhookNotFound
+	}
+	delete(r.s.webhooks, id)
+
+	deliveries := r.s.deliveries[:0]
+	for _, d := range r.s.deliveries {
+		if d.subscriptionID != id {
+			deliveries = append(deliveries, d)
+		}
+	}
+	r.s.deliveries = deliveries
+	return nil
+}
+
+// ListDeliveries возвращает последние доставки по подписке.
+func (r *WebhookRepo) ListDeliveries(ctx context.Context, subscriptionID int64, limit int) ([]domain.WebhookDelivery, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	if _, ok := r.s.webhooks[subscriptionID]; !ok {
+		return nil, domain.ErrWebhookNotFound
+	}
+
+	var deliveries []domain.WebhookDelivery
+	for i := len(r.s.deliveries) - 1; i >= 0 && len(deliveries) < limit; i-- {
+		d := r.s.deliveries[i]
+		if d.subscriptionID != subscriptionID {
+			continue
+		}
+		event := r.s.findEvent(d.eventID)
+		if event == nil {
+			continue
+		}
+		var code *int
+		if d.lastStatusCode != nil {
+			c := *d.lastStatusCode
+			code = &c
+		}
+		deliveries = append(deliveries, domain.WebhookDelivery{
+			ID:             d.id,
+			SubscriptionID: d.subscriptionID,
+			EventSequence:  d.eventID,
+			EventType:      domain.EventType(event.EventType),
+			Status:         d.status,
+			Attempts:       d.attempts,
+			NextAttemptAt:  d.nextAttemptAt,
+			LastStatusCode: code,
+			LastError:      d.lastError,
+			CreatedAt:      d.createdAt,
+			DeliveredAt:    d.deliveredAt,
+		})
+	}
+	return deliveries, nil
+}
+
+// EnqueueDeliveries создаёт доставки события для активных подписок на его тип.
+// Повторный вызов для того же события дубликатов не создаёт.
+func (r *WebhookRepo) EnqueueDeliveries(ctx context.Context, eventSequence int64, eventType domain.EventType) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	ids := make([]int64, 0, len(r.s.webhooks))
+	for id, sub := range r.s.webhooks {
+		if sub.IsActive && containsString(sub.EventTypes, string(eventType)) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	now := time.Now().UTC()
+	for _, subID := range ids {
+		if r.s.hasDelivery(subID, eventSequence) {
+			continue
+		}
+		r.s.deliveries = append(r.s.deliveries, &webhookDeliveryRow{
+			id:             r.s.nextID("webhook_deliveries"),
+			subscriptionID: subID,
+			eventID:        eventSequence,
+			status:         domain.DeliveryPending,
+			nextAttemptAt:  now,
+			createdAt:      now,
+		})
+	}
+	return nil
+}
+
+func (s *Store) hasDelivery(subscriptionID, eventID int64) bool {
+	for _, d := range s.deliveries {
+		if d.subscriptionID == subscriptionID && d.eventID == eventID {
+			return true
+		}
+	}
+	return false
+}
+
+// ClaimDueDeliveries захватывает доставки, время которых пришло, сдвигая next_attempt_at на lease.
+func (r *WebhookRepo) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]domain.WebhookAttempt, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := time.Now().UTC()
+	var due []*webhookDeliveryRow
+	for _, d := range r.s.deliveries {
+		if d.status == domain.DeliveryPending && !d.nextAttemptAt.After(now) {
+			due = append(due, d)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool { return due[i].nextAttemptAt.Before(due[j].nextAttemptAt) })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	deliveries := make([]domain.WebhookAttempt, 0, len(due))
+	for _, d := range due {
+		sub, event := r.s.webhooks[d.subscriptionID], r.s.findEvent(d.eventID)
+		if sub == nil || event == nil {
+			continue
+		}
+		d.attempts++
+		d.nextAttemptAt = now.Add(lease)
+		deliveries = append(deliveries, domain.WebhookAttempt{
+			ID:       d.id,
+			Attempts: d.attempts,
+			URL:      sub.URL,
+			Secret:   sub.Secret,
+			Event:    event.toDomain(),
+		})
+	}
+	return deliveries, nil
+}
+
+// MarkDelivered отмечает успешную доставку.
+func (r *WebhookRepo) MarkDelivered(ctx context.Context, id int64, statusCode int) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if d := r.s.findDelivery(id); d != nil {
+		now := time.Now().UTC()
+		d.status = domain.DeliveryDelivered
+		d.lastStatusCode = &statusCode
+		d.lastError = ""
+		d.deliveredAt = &now
+	}
+	return nil
+}
+
+// MarkFailed записывает неудачную попытку и планирует следующую.
+// Если nextAttemptAt == nil, доставка переводится в FAILED.
+func (r *WebhookRepo) MarkFailed(ctx context.Context, id int64, statusCode *int, errMsg string, nextAttemptAt *time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	d := r.s.findDelivery(id)
+	if d == nil {
+		return nil
+	}
+	d.lastStatusCode = nil
+	if statusCode != nil {
+		code := *statusCode
+		d.lastStatusCode = &code
+	}
+	d.lastError = errMsg
+	if nextAttemptAt == nil {
+		d.status = domain.DeliveryFailed
+		return nil
+	}
+	d.nextAttemptAt = nextAttemptAt.UTC()
+	return nil
+}
+
+func (s *Store) findDelivery(id int64) *webhookDeliveryRow {
+	for _, d := range s.deliveries {
+		if d.id == id {
+			return d
+		}
+	}
+	return nil
+}
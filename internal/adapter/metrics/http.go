@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	common "github.com/Skorpsrgvch/reviewer-service/internal/adapter/http/common"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// unmatchedRoute — метка для запросов без маршрута, чтобы произвольные пути не раздували кардинальность
+const unmatchedRoute = "unmatched"
+
+// HTTPMetrics считает запросы, их длительность и ошибки NO_CANDIDATE по маршрутам
+type HTTPMetrics struct {
+	requests    *prometheus.CounterVec
+	duration    *prometheus.HistogramVec
+	noCandidate *prometheus.CounterVec
+}
+
+func NewHTTPMetrics(reg prometheus.Registerer) (*HTTPMetrics, error) {
+	m := &HTTPMetrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "HTTP requests by method, route and status.",
+		}, []string{"method", "route", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency by method, route and status.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		noCandidate: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "reviewer_no_candidate_errors_total",
+			Help: "Requests rejected with NO_CANDIDATE: no active reviewer in the team.",
+		}, []string{"route"}),
+	}
+	for _, c := range []prometheus.Collector{m.requests, m.duration, m.noCandidate} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// Middleware замеряет запрос после обработки. Маршрут берётся из шаблона (/pullRequest/get),
+// а не из фактического пути.
+func (m *HTTPMetrics) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		status := strconv.Itoa(c.Writer.Status())
+		m.requests.WithLabelValues(c.Request.Method, route, status).Inc()
+		m.duration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+		if c.GetString(common.ErrorCodeKey) == "NO_CANDIDATE" {
+			m.noCandidate.WithLabelValues(route).Inc()
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// OutboxStatsReader возвращает счётчики relay outbox
+type OutboxStatsReader interface {
+	Stats() domain.OutboxStats
+}
+
+// OutboxCollector отдаёт счётчики relay этой реплики и отставание outbox
+type OutboxCollector struct {
+	reader OutboxStatsReader
+
+	published *prometheus.Desc
+	failed    *prometheus.Desc
+	pending   *prometheus.Desc
+	lag       *prometheus.Desc
+}
+
+func NewOutboxCollector(reader OutboxStatsReader) *OutboxCollector {
+	return &OutboxCollector{
+		reader: reader,
+		published: prometheus.NewDesc("reviewer_outbox_published_total",
+			"Outbox events published by this replica.", nil, nil),
+		failed: prometheus.NewDesc("reviewer_outbox_failed_total",
+			"Failed outbox publish attempts by this replica.", nil, nil),
+		pending: prometheus.NewDesc("reviewer_outbox_pending",
+			"Unpublished outbox events.", nil, nil),
+		lag: prometheus.NewDesc("reviewer_outbox_lag_seconds",
+			"Age of the oldest unpublished outbox event.", nil, nil),
+	}
+}
+
+func (c *OutboxCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.published
+	ch <- c.failed
+	ch <- c.pending
+	ch <- c.lag
+}
+
+func (c *OutboxCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.reader.Stats()
+	ch <- prometheus.MustNewConstMetric(c.published, prometheus.CounterValue, float64(stats.Published))
+	ch <- prometheus.MustNewConstMetric(c.failed, prometheus.CounterValue, float64(stats.Failed))
+	ch <- prometheus.MustNewConstMetric(c.pending, prometheus.GaugeValue, float64(stats.Pending))
+	ch <- prometheus.MustNewConstMetric(c.lag, prometheus.GaugeValue, stats.Lag.Seconds())
+}
@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// SnapshotReader возвращает текущее состояние ревью
+type SnapshotReader interface {
+	GetReviewSnapshot(ctx context.Context) (*domain.ReviewSnapshot, error)
+}
+
+// ReviewCollector читает доменные метрики из БД при каждом сборе.
+// Значения общие для всех реплик, поэтому при агрегации стоит брать max, а не sum.
+type ReviewCollector struct {
+	reader  SnapshotReader
+	timeout time.Duration
+
+	openPullRequests *prometheus.Desc
+	openAssignments  *prometheus.Desc
+	reassignments    *prometheus.Desc
+}
+
+func NewReviewCollector(reader SnapshotReader, timeout time.Duration) *ReviewCollector {
+	return &ReviewCollector{
+		reader:  reader,
+		timeout: timeout,
+		openPullRequests: prometheus.NewDesc("reviewer_open_pull_requests",
+			"Pull requests in OPEN status.", nil, nil),
+		openAssignments: prometheus.NewDesc("reviewer_open_assignments",
+			"Reviewer assignments on OPEN pull requests by author's team.", []string{"team"}, nil),
+		reassignments: prometheus.NewDesc("reviewer_reassignments_total",
+			"Reviewers replaced on pull requests.", nil, nil),
+	}
+}
+
+func (c *ReviewCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.openPullRequests
+	ch <- c.openAssignments
+	ch <- c.reassignments
+}
+
+func (c *ReviewCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	snapshot, err := c.reader.GetReviewSnapshot(ctx)
+	if err != nil {
+		slog.Error("metrics: failed to read review snapshot", "error", err)
+		ch <- prometheus.NewInvalidMetric(c.openPullRequests, err)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.openPullRequests, prometheus.GaugeValue, float64(snapshot.OpenPullRequests))
+	for team, count := range snapshot.OpenAssignmentsByTeam {
+		ch <- prometheus.MustNewConstMetric(c.openAssignments, prometheus.GaugeValue, float64(count), team)
+	}
+	ch <- prometheus.MustNewConstMetric(c.reassignments, prometheus.CounterValue, float64(snapshot.Reassignments))
+}
@@ -0,0 +1,83 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/dispatch"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/logger"
+)
+
+// Sender отправляет уведомление в свой канал
+type Sender interface {
+	Send(ctx context.Context, msg domain.Notification) error
+}
+
+// Enqueuer ставит уведомления в очередь
+type Enqueuer interface {
+	// EnqueueNotifications идемпотентен: повторный вызов для того же события не создаёт дубликатов
+	EnqueueNotifications(ctx context.Context, eventSequence int64, drafts []domain.NotificationDraft) error
+}
+
+// Store — очередь уведомлений
+type Store interface {
+	ClaimDueNotifications(ctx context.Context, limit int, lease time.Duration) ([]domain.Notification, error)
+	MarkNotificationSent(ctx context.Context, id int64) error
+	// MarkNotificationFailed записывает неудачную попытку. nextAttemptAt == nil — попытки исчерпаны
+	MarkNotificationFailed(ctx context.Context, id int64, errMsg string, nextAttemptAt *time.Time) error
+}
+
+// DefaultConfig возвращает параметры по умолчанию
+func DefaultConfig() dispatch.Config {
+	return dispatch.Config{
+		PollInterval: 2 * time.Second,
+		BatchSize:    50,
+		MaxAttempts:  6,
+		BaseBackoff:  10 * time.Second,
+		MaxBackoff:   30 * time.Minute,
+		Timeout:      10 * time.Second,
+	}
+}
+
+// Dispatcher отправляет уведомления из очереди через Sender своего канала
+type Dispatcher struct {
+	store   Store
+	senders map[domain.NotificationChannel]Sender
+	cfg     dispatch.Config
+}
+
+func NewDispatcher(store Store, senders map[domain.NotificationChannel]Sender, cfg dispatch.Config) *Dispatcher {
+	return &Dispatcher{store: store, senders: senders, cfg: cfg}
+}
+
+// Run отправляет уведомления до отмены контекста
+func (d *Dispatcher) Run(ctx context.Context) {
+	dispatch.Run(ctx, d.cfg, "notifications", d.store.ClaimDueNotifications, d.send)
+}
+
+func (d *Dispatcher) send(ctx context.Context, msg domain.Notification) {
+	err := d.deliver(ctx, msg)
+	if err == nil {
+		if err := d.store.MarkNotificationSent(ctx, msg.ID); err != nil {
+			logger.FromContext(ctx).Error("failed to mark notification as sent", "notification_id", msg.ID, "error", err)
+		}
+		return
+	}
+
+	next := d.cfg.NextAttempt(msg.Attempts, err)
+	if err := d.store.MarkNotificationFailed(ctx, msg.ID, err.Error(), next); err != nil {
+		logger.FromContext(ctx).Error("failed to record notification failure", "notification_id", msg.ID, "error", err)
+	}
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, msg domain.Notification) error {
+	sender, ok := d.senders[msg.Channel]
+	if !ok {
+		return dispatch.Permanent(fmt.Errorf("channel %q is not configured", msg.Channel))
+	}
+	ctx, cancel := context.WithTimeout(ctx, d.cfg.Timeout)
+	defer cancel()
+	return sender.Send(ctx, msg)
+}
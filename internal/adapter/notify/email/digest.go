@@ -0,0 +1,80 @@
+package email
+
+import (
+	"context"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/adapter/notify"
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/logger"
+)
+
+// DigestStore хранит события, отложенные до дайджеста
+type DigestStore interface {
+	// AddDigestItem идемпотентен по (userID, eventSequence)
+	AddDigestItem(ctx context.Context, userID string, eventSequence int64, summary string) error
+	// FlushDigests в одной транзакции превращает накопленные события каждого пользователя
+	// в письмо через render, ставит его в очередь уведомлений и помечает события отправленными
+	FlushDigests(ctx context.Context, render func(batch domain.DigestBatch) (domain.NotificationDraft, error)) (int, error)
+}
+
+// DigestJob раз в сутки в заданный час (UTC) рассылает дайджесты
+type DigestJob struct {
+	store     DigestStore
+	templates *Templates
+	hour      int
+}
+
+func NewDigestJob(store DigestStore, templates *Templates, hour int) *DigestJob {
+	return &DigestJob{store: store, templates: templates, hour: hour}
+}
+
+// Run рассылает дайджесты до отмены контекста
+func (j *DigestJob) Run(ctx context.Context) {
+	for {
+		timer := time.NewTimer(time.Until(j.nextRun(time.Now().UTC())))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		n, err := j.store.FlushDigests(ctx, j.render)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.FromContext(ctx).Error("failed to send email digests", "error", err)
+			}
+			continue
+		}
+		if n > 0 {
+			logger.FromContext(ctx).Info("queued email digests", "count", n)
+		}
+	}
+}
+
+func (j *DigestJob) render(batch domain.DigestBatch) (domain.NotificationDraft, error) {
+	rendered, err := j.templates.RenderDigest(DigestData{
+		Recipient: notify.Person{ID: batch.UserID, Name: batch.Username, Handle: batch.Email},
+		Items:     batch.Items,
+	})
+	if err != nil {
+		return domain.NotificationDraft{}, err
+	}
+	return domain.NotificationDraft{
+		Channel:  domain.NotificationEmail,
+		Target:   batch.Email,
+		Subject:  rendered.Subject,
+		Body:     rendered.Text,
+		HTMLBody: rendered.HTML,
+	}, nil
+}
+
+// nextRun — ближайший момент hour:00 UTC после now
+func (j *DigestJob) nextRun(now time.Time) time.Time {
+	next := time.Date(now.Year(), now.Month(), now.Day(), j.hour, 0, 0, 0, time.UTC)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
@@ -0,0 +1,196 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/dispatch"
+)
+
+type TLSMode string
+
+const (
+	// TLSStartTLS — STARTTLS (порт 587); если сервер его не предлагает, письмо не отправляется,
+	// чтобы пароль и содержимое не ушли открытым текстом
+	TLSStartTLS TLSMode = "starttls"
+	// TLSImplicit — TLS с самого подключения (порт 465)
+	TLSImplicit TLSMode = "tls"
+	// TLSNone — без шифрования; для локальных SMTP-заглушек вроде MailHog
+	TLSNone TLSMode = "none"
+)
+
+// SMTPConfig — параметры SMTP-сервера
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	TLS      TLSMode
+}
+
+// Sender отправляет письма по SMTP: multipart/alternative с текстовой и HTML-версией
+type Sender struct {
+	cfg SMTPConfig
+}
+
+func NewSender(cfg SMTPConfig) (*Sender, error) {
+	if cfg.Host == "" || cfg.Port == 0 || cfg.From == "" {
+		return nil, errors.New("smtp host, port and from are required")
+	}
+	switch cfg.TLS {
+	case "":
+		cfg.TLS = TLSStartTLS
+	case TLSStartTLS, TLSImplicit, TLSNone:
+	default:
+		return nil, fmt.Errorf("unknown smtp tls mode %q", cfg.TLS)
+	}
+	return &Sender{cfg: cfg}, nil
+}
+
+func (s *Sender) Send(ctx context.Context, msg domain.Notification) error {
+	body, err := s.compose(msg)
+	if err != nil {
+		return dispatch.Permanent(err)
+	}
+
+	addr := net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.cfg.Port))
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	if s.cfg.TLS == TLSImplicit {
+		conn = tls.Client(conn, &tls.Config{ServerName: s.cfg.Host})
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if s.cfg.TLS == TLSStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return dispatch.Permanent(errors.New("smtp server does not offer STARTTLS"))
+		}
+		if err := client.StartTLS(&tls.Config{ServerName: s.cfg.Host}); err != nil {
+			return err
+		}
+	}
+	if s.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)); err != nil {
+			return authError(err)
+		}
+	}
+	if err := client.Mail(s.cfg.From); err != nil {
+		return classify(err)
+	}
+	if err := client.Rcpt(msg.Target); err != nil {
+		return classify(err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return classify(err)
+	}
+	if _, err := w.Write(body); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return classify(err)
+	}
+	return client.Quit()
+}
+
+// authError помечает ошибку аутентификации как неповторяемую: неверные учётные данные (535)
+// и отказ клиента передавать пароль без TLS не исправятся сами. Повторяется только временный сбой (4xx).
+func authError(err error) error {
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) && protoErr.Code < 500 {
+		return err
+	}
+	return dispatch.Permanent(err)
+}
+
+// classify помечает постоянные отказы SMTP (коды 5xx) как неповторяемые
+func classify(err error) error {
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) && protoErr.Code >= 500 {
+		return dispatch.Permanent(err)
+	}
+	return err
+}
+
+func (s *Sender) compose(msg domain.Notification) ([]byte, error) {
+	boundary, err := randomBoundary()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	header := func(k, v string) { fmt.Fprintf(&buf, "%s: %s\r\n", k, v) }
+	header("From", s.cfg.From)
+	header("To", msg.Target)
+	header("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+
+	if msg.HTMLBody == "" {
+		header("Content-Type", "text/plain; charset=utf-8")
+		header("Content-Transfer-Encoding", "quoted-printable")
+		buf.WriteString("\r\n")
+		if err := writeQuotedPrintable(&buf, msg.Body); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	header("Content-Type", fmt.Sprintf("multipart/alternative; boundary=%q", boundary))
+	buf.WriteString("\r\n")
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", msg.Body},
+		{"text/html; charset=utf-8", msg.HTMLBody},
+	} {
+		fmt.Fprintf(&buf, "--%s\r\n", boundary)
+		fmt.Fprintf(&buf, "Content-Type: %s\r\nContent-Transfer-Encoding: quoted-printable\r\n\r\n", part.contentType)
+		if err := writeQuotedPrintable(&buf, part.body); err != nil {
+			return nil, err
+		}
+		buf.WriteString("\r\n")
+	}
+	fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+	return buf.Bytes(), nil
+}
+
+func writeQuotedPrintable(buf *bytes.Buffer, text string) error {
+	w := quotedprintable.NewWriter(buf)
+	if _, err := w.Write([]byte(text)); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+func randomBoundary() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
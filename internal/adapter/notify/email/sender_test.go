@@ -0,0 +1,180 @@
+package email
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/dispatch"
+)
+
+// fakeSMTP — SMTP-заглушка на локальном порту: объявляет extensions в ответ на EHLO,
+// на AUTH отвечает authReply и складывает полученные письма в messages
+type fakeSMTP struct {
+	extensions []string
+	authReply  string
+	messages   chan string
+}
+
+// start принимает одно подключение и возвращает порт
+func (f *fakeSMTP) start(t *testing.T) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	f.messages = make(chan string, 1)
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		f.serve(conn)
+	}()
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+func (f *fakeSMTP) serve(conn net.Conn) {
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+	r := bufio.NewReader(conn)
+	reply := func(lines ...string) {
+		for _, line := range lines {
+			_, _ = conn.Write([]byte(line + "\r\n"))
+		}
+	}
+
+	reply("220 fake ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.Fields(line + " ")[0])
+		switch command {
+		case "EHLO":
+			lines := []string{"250-fake"}
+			for _, ext := range f.extensions {
+				lines = append(lines, "250-"+ext)
+			}
+			reply(append(lines, "250 8BITMIME")...)
+		case "AUTH":
+			reply(f.authReply)
+		case "MAIL", "RCPT", "RSET", "NOOP":
+			reply("250 OK")
+		case "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(l)
+			}
+			f.messages <- data.String()
+			reply("250 queued")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+func newTestSender(t *testing.T, port int, tlsMode TLSMode, username string) *Sender {
+	t.Helper()
+	sender, err := NewSender(SMTPConfig{
+		Host:     "127.0.0.1",
+		Port:     port,
+		Username: username,
+		Password: "secret",
+		From:     "reviewer@example.com",
+		TLS:      tlsMode,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sender
+}
+
+func testNotification() domain.Notification {
+	return domain.Notification{
+		Target:   "alice@example.com",
+		Subject:  "Review requested",
+		Body:     "Please review pr-1",
+		HTMLBody: "<p>Please review pr-1</p>",
+	}
+}
+
+func sendWithTimeout(sender *Sender) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return sender.Send(ctx, testNotification())
+}
+
+func TestSenderDeliversMessage(t *testing.T) {
+	server := &fakeSMTP{extensions: []string{"AUTH PLAIN"}, authReply: "235 2.7.0 accepted"}
+	port := server.start(t)
+
+	if err := sendWithTimeout(newTestSender(t, port, TLSNone, "bot")); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	select {
+	case data := <-server.messages:
+		for _, want := range []string{"To: alice@example.com", "Subject: Review requested", "multipart/alternative", "<p>Please review pr-1</p>"} {
+			if !strings.Contains(data, want) {
+				t.Errorf("message has no %q:\n%s", want, data)
+			}
+		}
+	default:
+		t.Fatal("server received no message")
+	}
+}
+
+func TestSenderRequiresStartTLS(t *testing.T) {
+	server := &fakeSMTP{}
+	port := server.start(t)
+
+	err := sendWithTimeout(newTestSender(t, port, TLSStartTLS, ""))
+	if err == nil || !dispatch.IsPermanent(err) {
+		t.Errorf("Send without STARTTLS = %v, want a permanent error", err)
+	}
+	if len(server.messages) != 0 {
+		t.Error("message must not be sent without STARTTLS")
+	}
+}
+
+func TestSenderClassifiesAuthErrors(t *testing.T) {
+	tests := []struct {
+		reply     string
+		permanent bool
+	}{
+		{"535 5.7.8 authentication credentials invalid", true},
+		{"454 4.7.0 temporary authentication failure", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.reply[:3], func(t *testing.T) {
+			server := &fakeSMTP{extensions: []string{"AUTH PLAIN"}, authReply: tt.reply}
+			port := server.start(t)
+
+			err := sendWithTimeout(newTestSender(t, port, TLSNone, "bot"))
+			if err == nil {
+				t.Fatal("Send: want an error for rejected auth")
+			}
+			if got := dispatch.IsPermanent(err); got != tt.permanent {
+				t.Errorf("permanent = %v, want %v (%v)", got, tt.permanent, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package email
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/adapter/notify"
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+// IdentityProvider — провайдер user_identities для Handle; у писем отдельного хэндла нет
+const IdentityProvider = "email"
+
+// Sink — outbox-sink, который пишет ревьюерам о назначении, переназначении и зависших PR,
+// а лиду команды — об эскалации.
+// Пользователям без адреса письма не отправляются, подписанным на дайджест — откладываются до него.
+type Sink struct {
+	enqueuer   notify.Enqueuer
+	digests    DigestStore
+	users      notify.UserDirectory
+	identities notify.IdentityResolver
+	templates  *Templates
+}
+
+func NewSink(enqueuer notify.Enqueuer, digests DigestStore, users notify.UserDirectory,
+	identities notify.IdentityResolver, templates *Templates) *Sink {
+	return &Sink{
+		enqueuer:   enqueuer,
+		digests:    digests,
+		users:      users,
+		identities: identities,
+		templates:  templates,
+	}
+}
+
+func (s *Sink) Name() string { return "email" }
+
+func (s *Sink) Publish(ctx context.Context, event domain.Event) error {
+	if event.Type == domain.EventPRMerged {
+		return nil
+	}
+	msg, err := notify.BuildPullRequestMessage(ctx, event, s.users, s.identities, IdentityProvider)
+	if err != nil || msg == nil {
+		return err
+	}
+
+	var recipients []notify.Person
+	switch msg.Kind {
+	case notify.KindReassigned:
+		recipients = []notify.Person{*msg.NewReviewer}
+	case notify.KindEscalated:
+		// При эскалации переназначением новые ревьюеры получат письма о переназначении
+		if msg.Lead == nil {
+			return nil
+		}
+		recipients = []notify.Person{*msg.Lead}
+	default:
+		recipients = msg.Reviewers
+	}
+
+	var drafts []domain.NotificationDraft
+	for _, recipient := range recipients {
+		user, err := s.users.GetUserByID(ctx, recipient.ID)
+		if err != nil {
+			if errors.Is(err, domain.ErrUserNotFound) {
+				continue
+			}
+			return err
+		}
+		if user.Email() == "" {
+			continue
+		}
+
+		data := Data{PullRequestMessage: msg, Recipient: recipient}
+		if user.EmailDigest() {
+			line, err := s.templates.RenderDigestLine(data)
+			if err != nil {
+				return err
+			}
+			if err := s.digests.AddDigestItem(ctx, user.ID(), event.Sequence, line); err != nil {
+				return err
+			}
+			continue
+		}
+
+		rendered, err := s.templates.Render(data)
+		if err != nil {
+			return err
+		}
+		drafts = append(drafts, domain.NotificationDraft{
+			Channel:  domain.NotificationEmail,
+			Target:   user.Email(),
+			Subject:  rendered.Subject,
+			Body:     rendered.Text,
+			HTMLBody: rendered.HTML,
+		})
+	}
+
+	if len(drafts) == 0 {
+		return nil
+	}
+	return s.enqueuer.EnqueueNotifications(ctx, event.Sequence, drafts)
+}
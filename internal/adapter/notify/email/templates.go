@@ -0,0 +1,138 @@
+package email
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/adapter/notify"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+// digestLine — строка события в дайджесте
+const digestLine = `{{if eq .Kind "reassigned"}}Вы заменили {{.OldReviewer.Name}} в` +
+	`{{else if eq .Kind "reminder"}}Ждёт ревью {{.OpenForText}}:` +
+	`{{else if eq .Kind "escalated"}}Без ревью {{.OpenForText}}:` +
+	`{{else}}Назначены ревьюером{{end}} «{{.PullRequestName}}» ({{.PullRequestID}}) от {{.Author.Name}}`
+
+// Data — данные шаблонов письма о событии PR
+type Data struct {
+	*notify.PullRequestMessage
+	Recipient notify.Person
+}
+
+// DigestData — данные шаблонов дайджеста
+type DigestData struct {
+	Recipient notify.Person
+	Items     []string
+}
+
+// Rendered — готовое письмо
+type Rendered struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+type templateSet struct {
+	subject *texttemplate.Template
+	text    *texttemplate.Template
+	html    *htmltemplate.Template
+}
+
+// Templates — шаблоны писем: тема и текст на text/template, HTML-версия на html/template
+// (экранирует имена и названия PR). Лежат в templates/<вид>.{subject,txt,html}.tmpl.
+type Templates struct {
+	sets map[string]templateSet
+	line *texttemplate.Template
+}
+
+func LoadTemplates() (*Templates, error) {
+	funcs := map[string]any{"names": names}
+
+	t := &Templates{sets: make(map[string]templateSet)}
+	for _, kind := range []string{
+		string(notify.KindAssigned), string(notify.KindReassigned),
+		string(notify.KindReminder), string(notify.KindEscalated), "digest",
+	} {
+		subject, err := texttemplate.New("subject").Funcs(funcs).ParseFS(templateFS, "templates/"+kind+".subject.tmpl")
+		if err != nil {
+			return nil, fmt.Errorf("email template %s subject: %w", kind, err)
+		}
+		text, err := texttemplate.New("text").Funcs(funcs).ParseFS(templateFS, "templates/"+kind+".txt.tmpl")
+		if err != nil {
+			return nil, fmt.Errorf("email template %s text: %w", kind, err)
+		}
+		html, err := htmltemplate.New("html").Funcs(funcs).ParseFS(templateFS, "templates/"+kind+".html.tmpl")
+		if err != nil {
+			return nil, fmt.Errorf("email template %s html: %w", kind, err)
+		}
+		t.sets[kind] = templateSet{
+			subject: subject.Lookup(kind + ".subject.tmpl"),
+			text:    text.Lookup(kind + ".txt.tmpl"),
+			html:    html.Lookup(kind + ".html.tmpl"),
+		}
+	}
+
+	line, err := texttemplate.New("line").Parse(digestLine)
+	if err != nil {
+		return nil, err
+	}
+	t.line = line
+	return t, nil
+}
+
+// Render формирует письмо о событии PR
+func (t *Templates) Render(data Data) (*Rendered, error) {
+	return t.render(string(data.Kind), data)
+}
+
+// RenderDigest формирует письмо-дайджест
+func (t *Templates) RenderDigest(data DigestData) (*Rendered, error) {
+	return t.render("digest", data)
+}
+
+// RenderDigestLine формирует строку события для дайджеста
+func (t *Templates) RenderDigestLine(data Data) (string, error) {
+	var buf bytes.Buffer
+	if err := t.line.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func (t *Templates) render(kind string, data any) (*Rendered, error) {
+	set, ok := t.sets[kind]
+	if !ok {
+		return nil, fmt.Errorf("no email template for %s", kind)
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := set.subject.Execute(&subject, data); err != nil {
+		return nil, err
+	}
+	if err := set.text.Execute(&text, data); err != nil {
+		return nil, err
+	}
+	if err := set.html.Execute(&html, data); err != nil {
+		return nil, err
+	}
+	return &Rendered{
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
+
+func names(people []notify.Person) string {
+	parts := make([]string, 0, len(people))
+	for _, p := range people {
+		parts = append(parts, p.Name)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,160 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+type Kind string
+
+const (
+	KindAssigned   Kind = "assigned"
+	KindReassigned Kind = "reassigned"
+	KindMerged     Kind = "merged"
+	KindReminder   Kind = "reminder"
+	KindEscalated  Kind = "escalated"
+)
+
+// UserDirectory — сведения о пользователях для текста уведомлений
+type UserDirectory interface {
+	GetUserByID(ctx context.Context, id string) (*domain.User, error)
+	GetTeamByUser(ctx context.Context, userID string) (string, error)
+}
+
+// IdentityResolver возвращает учётную запись пользователя в канале (Slack ID, email)
+type IdentityResolver interface {
+	ExternalIDForUser(ctx context.Context, provider, userID string) (string, error)
+}
+
+// Person — участник PR в шаблоне уведомления
+type Person struct {
+	ID   string
+	Name string
+	// Handle — учётная запись в канале уведомлений; пустая, если не привязана
+	Handle string
+}
+
+// PullRequestMessage — данные для шаблонов уведомлений о PR
+type PullRequestMessage struct {
+	Kind            Kind
+	PullRequestID   string
+	PullRequestName string
+	Team            string
+	Author          Person
+	Reviewers       []Person
+	// OldReviewer и NewReviewer заполнены только для KindReassigned
+	OldReviewer *Person
+	NewReviewer *Person
+	// OpenFor и Escalation заполнены для KindReminder и KindEscalated
+	OpenFor    time.Duration
+	Escalation domain.EscalationAction
+	// Lead — лид команды, заполнен только для эскалации notify_lead
+	Lead *Person
+}
+
+// OpenForText возвращает возраст PR для текста уведомления: «5 ч» или «3 д 4 ч»
+func (m *PullRequestMessage) OpenForText() string {
+	hours := int(m.OpenFor / time.Hour)
+	if hours < 24 {
+		return fmt.Sprintf("%d ч", hours)
+	}
+	if hours%24 == 0 {
+		return fmt.Sprintf("%d д", hours/24)
+	}
+	return fmt.Sprintf("%d д %d ч", hours/24, hours%24)
+}
+
+// BuildPullRequestMessage собирает данные уведомления из события PR.
+// handleProvider — провайдер в user_identities, из которого берётся Handle (например, "slack").
+// Для событий, о которых не уведомляют, возвращает nil.
+func BuildPullRequestMessage(ctx context.Context, event domain.Event, users UserDirectory, identities IdentityResolver, handleProvider string) (*PullRequestMessage, error) {
+	var kind Kind
+	switch event.Type {
+	case domain.EventPRCreated:
+		kind = KindAssigned
+	case domain.EventReviewerReassigned:
+		kind = KindReassigned
+	case domain.EventPRMerged:
+		kind = KindMerged
+	case domain.EventPRReviewReminder:
+		kind = KindReminder
+	case domain.EventPREscalated:
+		kind = KindEscalated
+	default:
+		return nil, nil
+	}
+
+	var p domain.PullRequestEventPayload
+	if err := json.Unmarshal(event.Payload, &p); err != nil {
+		return nil, err
+	}
+
+	person := func(userID string) (Person, error) {
+		result := Person{ID: userID, Name: userID}
+		user, err := users.GetUserByID(ctx, userID)
+		switch {
+		case err == nil:
+			result.Name = user.Username()
+		case !errors.Is(err, domain.ErrUserNotFound):
+			return result, err
+		}
+		handle, err := identities.ExternalIDForUser(ctx, handleProvider, userID)
+		switch {
+		case err == nil:
+			result.Handle = handle
+		case !errors.Is(err, domain.ErrIdentityNotFound):
+			return result, err
+		}
+		return result, nil
+	}
+
+	team, err := users.GetTeamByUser(ctx, p.AuthorID)
+	if err != nil && !errors.Is(err, domain.ErrUserNotFound) {
+		return nil, err
+	}
+
+	msg := &PullRequestMessage{
+		Kind:            kind,
+		PullRequestID:   p.PullRequestID,
+		PullRequestName: p.PullRequestName,
+		Team:            team,
+	}
+	if msg.Author, err = person(p.AuthorID); err != nil {
+		return nil, err
+	}
+	for _, id := range p.AssignedReviewers {
+		r, err := person(id)
+		if err != nil {
+			return nil, err
+		}
+		msg.Reviewers = append(msg.Reviewers, r)
+	}
+	if kind == KindReassigned {
+		oldReviewer, err := person(p.OldReviewerID)
+		if err != nil {
+			return nil, err
+		}
+		newReviewer, err := person(p.NewReviewerID)
+		if err != nil {
+			return nil, err
+		}
+		msg.OldReviewer, msg.NewReviewer = &oldReviewer, &newReviewer
+	}
+	if kind == KindReminder || kind == KindEscalated {
+		msg.OpenFor = time.Duration(p.OpenSeconds) * time.Second
+		msg.Escalation = p.Escalation
+	}
+	if kind == KindEscalated && p.Escalation == domain.EscalationNotifyLead && p.LeadID != "" {
+		lead, err := person(p.LeadID)
+		if err != nil {
+			return nil, err
+		}
+		msg.Lead = &lead
+	}
+	return msg, nil
+}
@@ -0,0 +1,56 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/dispatch"
+)
+
+// Sender отправляет сообщения во входящие вебхуки Slack (и совместимые: Mattermost, Rocket.Chat)
+type Sender struct {
+	client *http.Client
+}
+
+func NewSender(client *http.Client) *Sender {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Sender{client: client}
+}
+
+func (s *Sender) Send(ctx context.Context, msg domain.Notification) error {
+	body, err := json.Marshal(map[string]string{"text": msg.Body})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, msg.Target, bytes.NewReader(body))
+	if err != nil {
+		return dispatch.Permanent(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	err = fmt.Errorf("slack webhook: status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	// Удалённый вебхук или архивный канал (404/410, 400/403) не восстановятся сами
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+		return dispatch.Permanent(err)
+	}
+	return err
+}
@@ -0,0 +1,57 @@
+package slack
+
+import (
+	"context"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/adapter/notify"
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+// IdentityProvider — провайдер в user_identities, где хранятся Slack member ID пользователей
+const IdentityProvider = "slack"
+
+// Sink — outbox-sink, который ставит в очередь сообщения в Slack-канал команды автора PR.
+// Отправка выполняется notify.Dispatcher асинхронно и не влияет на время ответа API.
+type Sink struct {
+	enqueuer   notify.Enqueuer
+	users      notify.UserDirectory
+	identities notify.IdentityResolver
+	templates  *Templates
+	// webhooks — URL входящего вебхука Slack по имени команды
+	webhooks map[string]string
+}
+
+func NewSink(enqueuer notify.Enqueuer, users notify.UserDirectory, identities notify.IdentityResolver,
+	templates *Templates, webhooks map[string]string) *Sink {
+	return &Sink{
+		enqueuer:   enqueuer,
+		users:      users,
+		identities: identities,
+		templates:  templates,
+		webhooks:   webhooks,
+	}
+}
+
+func (s *Sink) Name() string { return "slack" }
+
+func (s *Sink) Publish(ctx context.Context, event domain.Event) error {
+	msg, err := notify.BuildPullRequestMessage(ctx, event, s.users, s.identities, IdentityProvider)
+	if err != nil || msg == nil {
+		return err
+	}
+
+	url, ok := s.webhooks[msg.Team]
+	if !ok {
+		return nil
+	}
+
+	text, err := s.templates.Render(msg)
+	if err != nil {
+		return err
+	}
+	return s.enqueuer.EnqueueNotifications(ctx, event.Sequence, []domain.NotificationDraft{{
+		Channel: domain.NotificationSlack,
+		Target:  url,
+		Body:    text,
+	}})
+}
@@ -0,0 +1,96 @@
+package slack
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/adapter/notify"
+)
+
+const (
+	defaultAssigned = `:eyes: {{mentions .Reviewers}}, вас назначили ревьюерами PR *{{.PullRequestName}}* ` +
+		"(`{{.PullRequestID}}`) от {{mention .Author}}"
+	defaultReassigned = `:arrows_counterclockwise: PR *{{.PullRequestName}}* (` + "`{{.PullRequestID}}`" + `): ` +
+		`ревьюер {{mention .OldReviewer}} заменён на {{mention .NewReviewer}}. Автор: {{mention .Author}}`
+	defaultMerged = `:white_check_mark: PR *{{.PullRequestName}}* (` + "`{{.PullRequestID}}`" + `) от {{mention .Author}} смержен. ` +
+		`Спасибо за ревью, {{mentions .Reviewers}}!`
+	defaultReminder = `:hourglass: {{mentions .Reviewers}}, PR *{{.PullRequestName}}* (` + "`{{.PullRequestID}}`" + `) ` +
+		`от {{mention .Author}} ждёт ревью уже {{.OpenForText}}`
+	defaultEscalated = `:rotating_light: PR *{{.PullRequestName}}* (` + "`{{.PullRequestID}}`" + `) от {{mention .Author}} ` +
+		`без ревью уже {{.OpenForText}}. ` +
+		`{{if .Lead}}{{mention .Lead}}, нужна помощь: ревьюеры {{mentions .Reviewers}}` +
+		`{{else}}Ревьюеры переназначены: {{mentions .Reviewers}}{{end}}`
+)
+
+// defaultTemplates — встроенные шаблоны по виду уведомления
+var defaultTemplates = map[notify.Kind]string{
+	notify.KindAssigned:   defaultAssigned,
+	notify.KindReassigned: defaultReassigned,
+	notify.KindMerged:     defaultMerged,
+	notify.KindReminder:   defaultReminder,
+	notify.KindEscalated:  defaultEscalated,
+}
+
+// Templates — шаблоны сообщений Slack (text/template над notify.PullRequestMessage).
+// Доступны функции mention (упоминание <@ID> или имя) и mentions (список через запятую).
+type Templates struct {
+	byKind map[notify.Kind]*template.Template
+}
+
+// DefaultTemplates возвращает встроенные шаблоны
+func DefaultTemplates() *Templates {
+	t, err := ParseTemplates(nil)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// ParseTemplates разбирает пользовательские шаблоны по виду уведомления;
+// для видов без шаблона (или с пустой строкой) используется встроенный
+func ParseTemplates(overrides map[notify.Kind]string) (*Templates, error) {
+	funcs := template.FuncMap{"mention": mention, "mentions": mentions}
+
+	t := &Templates{byKind: make(map[notify.Kind]*template.Template, len(defaultTemplates))}
+	for kind, text := range defaultTemplates {
+		if override := overrides[kind]; override != "" {
+			text = override
+		}
+		tmpl, err := template.New(string(kind)).Funcs(funcs).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("slack template %s: %w", kind, err)
+		}
+		t.byKind[kind] = tmpl
+	}
+	return t, nil
+}
+
+// Render формирует текст сообщения
+func (t *Templates) Render(msg *notify.PullRequestMessage) (string, error) {
+	tmpl, ok := t.byKind[msg.Kind]
+	if !ok {
+		return "", fmt.Errorf("no slack template for %s", msg.Kind)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, msg); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func mention(p notify.Person) string {
+	if p.Handle != "" {
+		return "<@" + p.Handle + ">"
+	}
+	return p.Name
+}
+
+func mentions(people []notify.Person) string {
+	parts := make([]string, 0, len(people))
+	for _, p := range people {
+		parts = append(parts, mention(p))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,27 @@
+package outbox
+
+import (
+	"context"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/logger"
+)
+
+// LogSink пишет события в лог. Удобен для отладки и локальной разработки.
+type LogSink struct{}
+
+func NewLogSink() *LogSink {
+	return &LogSink{}
+}
+
+func (s *LogSink) Name() string { return "log" }
+
+func (s *LogSink) Publish(ctx context.Context, event domain.Event) error {
+	logger.FromContext(ctx).Info("outbox event",
+		"sequence", event.Sequence,
+		"type", event.Type,
+		"aggregate_id", event.AggregateID,
+		"payload", event.Payload,
+	)
+	return nil
+}
@@ -0,0 +1,183 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/dispatch"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/logger"
+)
+
+// Sink получает события из outbox. Доставка «как минимум один раз»:
+// при ошибке любого sink событие будет отправлено повторно во все sink,
+// поэтому Publish должен быть идемпотентным по event.Sequence.
+type Sink interface {
+	Name() string
+	Publish(ctx context.Context, event domain.Event) error
+}
+
+// Store — хранилище outbox
+type Store interface {
+	// ClaimDueEvents захватывает до limit готовых к отправке событий на время lease и сразу
+	// фиксирует захват: другие реплики их не возьмут, а если процесс упадёт, события
+	// вернутся в очередь по истечении аренды.
+	ClaimDueEvents(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboxAttempt, error)
+	MarkEventPublished(ctx context.Context, sequence int64) error
+	// MarkEventFailed записывает неудачную попытку и откладывает событие до nextAttemptAt
+	MarkEventFailed(ctx context.Context, sequence int64, errMsg string, nextAttemptAt time.Time) error
+	// Backlog возвращает число неопубликованных событий и время создания самого старого
+	Backlog(ctx context.Context) (pending int64, oldest *time.Time, err error)
+}
+
+// Config — параметры relay
+type Config struct {
+	PollInterval time.Duration
+	BatchSize    int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	// Timeout — ограничение на публикацию одного события во все sink
+	Timeout time.Duration
+}
+
+// DefaultConfig возвращает параметры по умолчанию
+func DefaultConfig() Config {
+	return Config{
+		PollInterval: time.Second,
+		BatchSize:    100,
+		BaseBackoff:  5 * time.Second,
+		MaxBackoff:   10 * time.Minute,
+		Timeout:      10 * time.Second,
+	}
+}
+
+// Relay — фоновый процесс, публикующий события outbox во все sink
+type Relay struct {
+	store Store
+	sinks []Sink
+	cfg   Config
+	// backoff — задержка перед повторной публикацией
+	backoff dispatch.Backoff
+
+	published atomic.Int64
+	failed    atomic.Int64
+	pending   atomic.Int64
+	lagMillis atomic.Int64
+}
+
+func NewRelay(store Store, cfg Config, sinks ...Sink) (*Relay, error) {
+	if store == nil {
+		return nil, errors.New("store is required")
+	}
+	if len(sinks) == 0 {
+		return nil, errors.New("at least one sink is required")
+	}
+	return &Relay{
+		store:   store,
+		sinks:   sinks,
+		cfg:     cfg,
+		backoff: dispatch.Backoff{Base: cfg.BaseBackoff, Max: cfg.MaxBackoff},
+	}, nil
+}
+
+// Run публикует события до отмены контекста
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Пока есть полные пачки — разбираем без ожидания
+		for ctx.Err() == nil {
+			if r.runOnce(ctx) < r.cfg.BatchSize {
+				break
+			}
+		}
+		r.updateBacklog(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce обрабатывает одну пачку и возвращает число захваченных событий
+func (r *Relay) runOnce(ctx context.Context) int {
+	attempts, err := r.store.ClaimDueEvents(ctx, r.cfg.BatchSize, dispatch.Lease(r.cfg.Timeout, r.cfg.BatchSize))
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.FromContext(ctx).Error("failed to claim outbox events", "error", err)
+		}
+		return 0
+	}
+	for _, attempt := range attempts {
+		if ctx.Err() != nil {
+			break
+		}
+		r.process(ctx, attempt)
+	}
+	return len(attempts)
+}
+
+func (r *Relay) process(ctx context.Context, attempt domain.OutboxAttempt) {
+	err := r.publish(ctx, attempt.Event)
+	if err == nil {
+		if err := r.store.MarkEventPublished(ctx, attempt.Event.Sequence); err != nil {
+			logger.FromContext(ctx).Error("failed to mark outbox event as published", "sequence", attempt.Event.Sequence, "error", err)
+			return
+		}
+		r.published.Add(1)
+		return
+	}
+
+	r.failed.Add(1)
+	logger.FromContext(ctx).Warn("failed to publish outbox event", "sequence", attempt.Event.Sequence, "attempts", attempt.Attempts, "error", err)
+	next := time.Now().UTC().Add(r.backoff.Delay(attempt.Attempts))
+	if err := r.store.MarkEventFailed(ctx, attempt.Event.Sequence, err.Error(), next); err != nil {
+		logger.FromContext(ctx).Error("failed to record outbox event failure", "sequence", attempt.Event.Sequence, "error", err)
+	}
+}
+
+// publish отправляет событие во все sink
+func (r *Relay) publish(ctx context.Context, event domain.Event) error {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.Timeout)
+	defer cancel()
+
+	var errs []error
+	for _, sink := range r.sinks {
+		if err := sink.Publish(ctx, event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sink.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *Relay) updateBacklog(ctx context.Context) {
+	pending, oldest, err := r.store.Backlog(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.FromContext(ctx).Error("failed to read outbox backlog", "error", err)
+		}
+		return
+	}
+	r.pending.Store(pending)
+	if oldest == nil {
+		r.lagMillis.Store(0)
+		return
+	}
+	r.lagMillis.Store(time.Since(*oldest).Milliseconds())
+}
+
+// Stats возвращает текущие метрики relay
+func (r *Relay) Stats() domain.OutboxStats {
+	return domain.OutboxStats{
+		Published: r.published.Load(),
+		Failed:    r.failed.Load(),
+		Pending:   r.pending.Load(),
+		Lag:       time.Duration(r.lagMillis.Load()) * time.Millisecond,
+	}
+}
@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/logger"
+)
+
+// AdvisoryLocker выполняет задачу только на одной реплике, удерживая
+// сессионный advisory lock Postgres на время выполнения
+type AdvisoryLocker struct {
+	db *sql.DB
+}
+
+func NewAdvisoryLocker(db *sql.DB) *AdvisoryLocker {
+	return &AdvisoryLocker{db: db}
+}
+
+// RunExclusive пытается взять блокировку key и выполнить fn.
+// Если блокировку держит другая реплика, возвращает false без ожидания.
+// Блокировка привязана к соединению, поэтому fn выполняется, пока соединение удерживается из пула;
+// при падении процесса Postgres снимает её вместе с сессией.
+func (l *AdvisoryLocker) RunExclusive(ctx context.Context, key int64, fn func(ctx context.Context) error) (bool, error) {
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&locked); err != nil {
+		return false, err
+	}
+	if !locked {
+		return false, nil
+	}
+	defer func() {
+		// Контекст задачи мог быть отменён — снимаем блокировку независимо от него
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", key); err != nil {
+			logger.FromContext(ctx).Error("advisory lock: unlock failed", "key", key, "error", err)
+			// Не возвращаем в пул соединение, которое может всё ещё держать блокировку
+			_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+	}()
+
+	return true, fn(ctx)
+}
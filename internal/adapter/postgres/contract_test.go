@@ -0,0 +1,108 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/adapter/postgres"
+	"github.com/Skorpsrgvch/reviewer-service/internal/adapter/storetest"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/db"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+)
+
+// testDBURLEnv — переменная с URL тестовой базы Postgres; без неё набор пропускается
+const testDBURLEnv = "TEST_DB_URL"
+
+// openTempSchema создаёт в тестовой базе отдельную схему, применяет к ней миграции
+// и открывает подключение с search_path на эту схему. Схема удаляется после теста.
+func openTempSchema(t *testing.T) storetest.Repos {
+	t.Helper()
+	dsn := os.Getenv(testDBURLEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testDBURLEnv)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	admin, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	schema := fmt.Sprintf("storetest_%d", time.Now().UnixNano())
+	if _, err := admin.ExecContext(ctx, "CREATE SCHEMA "+schema); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		if _, err := admin.Exec("DROP SCHEMA " + schema + " CASCADE"); err != nil {
+			t.Errorf("drop schema: %v", err)
+		}
+	})
+
+	schemaDSN, err := withSearchPath(dsn, schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := migrate.New("file://../../../migrations", schemaDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Up(); err != nil {
+		t.Fatalf("migrations: %v", err)
+	}
+	if srcErr, dbErr := m.Close(); srcErr != nil || dbErr != nil {
+		t.Fatal(srcErr, dbErr)
+	}
+
+	conn, err := db.NewPostgresDB(ctx, schemaDSN, db.PoolConfig{MaxOpenConns: 4, MaxIdleConns: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return storetest.Repos{
+		Teams:        postgres.NewTeamRepo(conn),
+		Users:        postgres.NewUserRepo(conn),
+		PullRequests: postgres.NewPullRequestRepo(conn),
+		Stats:        postgres.NewStatsRepo(conn),
+		Identities:   postgres.NewIdentityRepo(conn),
+		Policies:     postgres.NewReviewPolicyRepo(conn),
+		StalePRs:     postgres.NewStalePullRequestRepo(conn),
+		SLAs:         postgres.NewReviewSLARepo(conn),
+		Idempotency:  postgres.NewIdempotencyRepo(conn),
+		Outbox:       postgres.NewOutboxRepo(conn),
+		Snapshots:    postgres.NewSnapshotRepo(conn),
+	}
+}
+
+// withSearchPath добавляет к URL подключения search_path: его понимают и lib/pq, и мигратор
+func withSearchPath(dsn, schema string) (string, error) {
+	if !strings.HasPrefix(dsn, "postgres://") && !strings.HasPrefix(dsn, "postgresql://") {
+		return "", fmt.Errorf("%s must be a postgres:// URL", testDBURLEnv)
+	}
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set("search_path", schema)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+func TestContract(t *testing.T) {
+	if os.Getenv(testDBURLEnv) == "" {
+		t.Skipf("%s is not set", testDBURLEnv)
+	}
+	storetest.Run(t, openTempSchema)
+}
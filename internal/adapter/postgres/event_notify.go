@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/logger"
+	"github.com/lib/pq"
+)
+
+// eventsChannel — канал LISTEN/NOTIFY, по которому реплики узнают об опубликованных событиях
+const eventsChannel = "outbox_events"
+
+// EventNotifySink — outbox-sink, который оповещает все реплики о событии через pg_notify.
+// Нужен, чтобы SSE-клиенты получали события независимо от того, какая реплика их опубликовала.
+type EventNotifySink struct {
+	db *sql.DB
+}
+
+func NewEventNotifySink(db *sql.DB) *EventNotifySink {
+	return &EventNotifySink{db: db}
+}
+
+func (s *EventNotifySink) Name() string { return "notify" }
+
+func (s *EventNotifySink) Publish(ctx context.Context, event domain.Event) error {
+	_, err := s.db.ExecContext(ctx, "SELECT pg_notify($1, $2)", eventsChannel, strconv.FormatInt(event.Sequence, 10))
+	return err
+}
+
+// EventListener слушает оповещения о событиях и загружает их из outbox.
+type EventListener struct {
+	dsn    string
+	outbox *OutboxRepo
+}
+
+func NewEventListener(dsn string, outbox *OutboxRepo) *EventListener {
+	return &EventListener{dsn: dsn, outbox: outbox}
+}
+
+// Run вызывает handle для каждого оповещённого события до отмены контекста.
+// При разрыве соединения переподключается; пропущенные события клиенты догоняют через Last-Event-ID.
+func (l *EventListener) Run(ctx context.Context, handle func(event domain.Event)) {
+	listener := pq.NewListener(l.dsn, 10*time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil && ctx.Err() == nil {
+			logger.FromContext(ctx).Warn("event listener connection error", "error", err)
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(eventsChannel); err != nil {
+		logger.FromContext(ctx).Error("event listener: failed to listen", "channel", eventsChannel, "error", err)
+		return
+	}
+
+	ping := time.NewTicker(90 * time.Second)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ping.C:
+			go func() { _ = listener.Ping() }()
+		case n := <-listener.Notify:
+			if n == nil {
+				// Переподключились — оповещения за время разрыва потеряны
+				continue
+			}
+			seq, err := strconv.ParseInt(n.Extra, 10, 64)
+			if err != nil {
+				continue
+			}
+			event, err := l.outbox.GetEvent(ctx, seq)
+			if err != nil {
+				if ctx.Err() == nil {
+					logger.FromContext(ctx).Error("event listener: failed to load event", "sequence", seq, "error", err)
+				}
+				continue
+			}
+			handle(*event)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+// IdempotencyRepo хранит ответы на запросы с заголовком Idempotency-Key.
+type IdempotencyRepo struct {
+	db *sql.DB
+}
+
+func NewIdempotencyRepo(db *sql.DB) *IdempotencyRepo {
+	return &IdempotencyRepo{db: db}
+}
+
+// maxReserveAttempts ограничивает повторы Reserve, когда занятый ключ освобождают между вставкой и чтением
+const maxReserveAttempts = 3
+
+// Reserve занимает ключ для маршрута. Просроченная запись перезаписывается.
+// Если ключ уже занят живой записью — возвращает её, иначе nil.
+func (r *IdempotencyRepo) Reserve(ctx context.Context, key, route, requestHash string, ttl time.Duration) (*domain.IdempotencyRecord, error) {
+	for attempt := 1; ; attempt++ {
+		reserved, err := r.insertKey(ctx, key, route, requestHash, ttl)
+		if err != nil || reserved {
+			return nil, err
+		}
+
+		// Ключ занят — читаем сохранённую запись. Если её успели освободить (Release после 5xx
+		// или паники), ключ снова свободен, и его нужно занять заново
+		record, err := r.getRecord(ctx, key, route)
+		if errors.Is(err, sql.ErrNoRows) && attempt < maxReserveAttempts {
+			continue
+		}
+		return record, err
+	}
+}
+
+// insertKey занимает свободный или просроченный ключ; false — ключ занят живой записью
+func (r *IdempotencyRepo) insertKey(ctx context.Context, key, route, requestHash string, ttl time.Duration) (bool, error) {
+	var reserved string
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO idempotency_keys (key, route, request_hash, created_at, expires_at)
+		VALUES ($1, $2, $3, NOW(), NOW() + make_interval(secs => $4))
+		ON CONFLICT (key, route) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash,
+		    status_code = NULL,
+		    response_body = NULL,
+		    created_at = EXCLUDED.created_at,
+		    expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at < NOW()
+		RETURNING key
+	`, key, route, requestHash, ttl.Seconds()).Scan(&reserved)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *IdempotencyRepo) getRecord(ctx context.Context, key, route string) (*domain.IdempotencyRecord, error) {
+	var (
+		hash       string
+		statusCode sql.NullInt64
+		body       []byte
+	)
+	err := r.db.QueryRowContext(ctx,
+		"SELECT request_hash, status_code, response_body FROM idempotency_keys WHERE key = $1 AND route = $2",
+		key, route,
+	).Scan(&hash, &statusCode, &body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.IdempotencyRecord{
+		RequestHash: hash,
+		StatusCode:  int(statusCode.Int64),
+		Body:        body,
+		Completed:   statusCode.Valid,
+	}, nil
+}
+
+// Complete сохраняет ответ для ранее зарезервированного ключа.
+func (r *IdempotencyRepo) Complete(ctx context.Context, key, route string, statusCode int, body []byte) error {
+	_, err := r.db.ExecContext(ctx,
+		"UPDATE idempotency_keys SET status_code = $1, response_body = $2 WHERE key = $3 AND route = $4",
+		statusCode, body, key, route,
+	)
+	return err
+}
+
+// Release освобождает ключ, чтобы повторный запрос выполнился заново.
+func (r *IdempotencyRepo) Release(ctx context.Context, key, route string) error {
+	_, err := r.db.ExecContext(ctx,
+		"DELETE FROM idempotency_keys WHERE key = $1 AND route = $2 AND status_code IS NULL",
+		key, route,
+	)
+	return err
+}
+
+// DeleteExpired удаляет просроченные ключи и возвращает их количество.
+func (r *IdempotencyRepo) DeleteExpired(ctx context.Context) (int64, error) {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE expires_at < NOW()")
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
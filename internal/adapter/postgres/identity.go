@@ -0,0 +1,166 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/lib/pq"
+)
+
+// pgForeignKeyViolation — код ошибки Postgres при нарушении внешнего ключа
+const pgForeignKeyViolation = "23503"
+
+// IdentityRepo хранит привязки внешних учётных записей к пользователям
+type IdentityRepo struct {
+	db *sql.DB
+}
+
+func NewIdentityRepo(db *sql.DB) *IdentityRepo {
+	return &IdentityRepo{db: db}
+}
+
+func (r *IdentityRepo) CreateIdentity(ctx context.Context, identity *domain.UserIdentity) error {
+	res, err := r.db.ExecContext(ctx, `
+		INSERT INTO user_identities (provider, external_id, user_id, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (provider, external_id) DO NOTHING
+	`, identity.Provider(), identity.ExternalID(), identity.UserID(), identity.CreatedAt())
+	if err != nil {
+		return mapIdentityError(err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrIdentityExists
+	}
+	return nil
+}
+
+// ListIdentities возвращает привязки с необязательными фильтрами по пользователю и провайдеру
+func (r *IdentityRepo) ListIdentities(ctx context.Context, userID, provider string) ([]domain.UserIdentity, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT provider, external_id, user_id, created_at
+		FROM user_identities
+		WHERE ($1 = '' OR user_id = $1) AND ($2 = '' OR provider = $2)
+		ORDER BY provider, external_id
+	`, userID, provider)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var identities []domain.UserIdentity
+	for rows.Next() {
+		var (
+			p, externalID, uid string
+			createdAt          time.Time
+		)
+		if err := rows.Scan(&p, &externalID, &uid, &createdAt); err != nil {
+			return nil, err
+		}
+		identities = append(identities, *domain.RestoreUserIdentity(p, externalID, uid, createdAt))
+	}
+	return identities, rows.Err()
+}
+
+func (r *IdentityRepo) DeleteIdentity(ctx context.Context, provider, externalID string) error {
+	res, err := r.db.ExecContext(ctx,
+		"DELETE FROM user_identities WHERE provider = $1 AND external_id = $2",
+		provider, externalID,
+	)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrIdentityNotFound
+	}
+	return nil
+}
+
+// LookupUser возвращает пользователя, привязанного к учётной записи провайдера
+func (r *IdentityRepo) LookupUser(ctx context.Context, provider, externalID string) (*domain.User, error) {
+	return lookupIdentityUser(ctx, r.db, provider, externalID)
+}
+
+// ExternalIDForUser возвращает учётную запись пользователя у провайдера (самую раннюю, если их несколько)
+func (r *IdentityRepo) ExternalIDForUser(ctx context.Context, provider, userID string) (string, error) {
+	var externalID string
+	err := r.db.QueryRowContext(ctx, `
+		SELECT external_id FROM user_identities
+		WHERE provider = $1 AND user_id = $2
+		ORDER BY created_at, external_id
+		LIMIT 1
+	`, provider, userID).Scan(&externalID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", domain.ErrIdentityNotFound
+		}
+		return "", err
+	}
+	return externalID, nil
+}
+
+// ImportIdentities создаёт или перепривязывает учётные записи одной транзакцией:
+// при ошибке в любой строке не применяется ни одна.
+func (r *IdentityRepo) ImportIdentities(ctx context.Context, identities []domain.UserIdentity) (created, updated int, err error) {
+	err = withTx(ctx, r.db, func(tx *sql.Tx) error {
+		for _, identity := range identities {
+			var inserted bool
+			// xmax = 0 только у только что вставленной строки
+			err := tx.QueryRowContext(ctx, `
+				INSERT INTO user_identities (provider, external_id, user_id, created_at)
+				VALUES ($1, $2, $3, $4)
+				ON CONFLICT (provider, external_id) DO UPDATE SET user_id = EXCLUDED.user_id
+				RETURNING xmax = 0
+			`, identity.Provider(), identity.ExternalID(), identity.UserID(), identity.CreatedAt()).Scan(&inserted)
+			if err != nil {
+				return mapIdentityError(err)
+			}
+			if inserted {
+				created++
+			} else {
+				updated++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return created, updated, nil
+}
+
+func lookupIdentityUser(ctx context.Context, q querier, provider, externalID string) (*domain.User, error) {
+	var id, username string
+	var isActive bool
+	err := q.QueryRowContext(ctx, `
+		SELECT u.id, u.username, u.is_active
+		FROM users u
+		JOIN user_identities i ON i.user_id = u.id
+		WHERE i.provider = $1 AND i.external_id = $2
+	`, provider, externalID).Scan(&id, &username, &isActive)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrIdentityNotFound
+		}
+		return nil, err
+	}
+	return domain.NewUser(id, username, isActive)
+}
+
+func mapIdentityError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == pgForeignKeyViolation {
+		return domain.ErrUserNotFound
+	}
+	return err
+}
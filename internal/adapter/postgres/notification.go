@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+// NotificationRepo — очередь уведомлений
+type NotificationRepo struct {
+	db *sql.DB
+}
+
+func NewNotificationRepo(db *sql.DB) *NotificationRepo {
+	return &NotificationRepo{db: db}
+}
+
+func (r *NotificationRepo) EnqueueNotifications(ctx context.Context, eventSequence int64, drafts []domain.NotificationDraft) error {
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		for _, d := range drafts {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO notifications (event_id, channel, target, subject, body, html_body, status, next_attempt_at, created_at)
+				VALUES ($1, $2, $3, $4, $5, $6, 'PENDING', NOW(), NOW())
+				ON CONFLICT (event_id, channel, target) DO NOTHING
+			`, eventSequence, string(d.Channel), d.Target, d.Subject, d.Body, d.HTMLBody)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ClaimDueNotifications захватывает уведомления, время которых пришло, сдвигая next_attempt_at на lease.
+func (r *NotificationRepo) ClaimDueNotifications(ctx context.Context, limit int, lease time.Duration) ([]domain.Notification, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		UPDATE notifications
+		SET attempts = attempts + 1,
+		    next_attempt_at = NOW() + make_interval(secs => $2)
+		WHERE id IN (
+		    SELECT id FROM notifications
+		    WHERE status = 'PENDING' AND next_attempt_at <= NOW()
+		    ORDER BY next_attempt_at
+		    LIMIT $1
+		    FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, attempts, channel, target, subject, body, html_body
+	`, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []domain.Notification
+	for rows.Next() {
+		var (
+			m       domain.Notification
+			channel string
+		)
+		if err := rows.Scan(&m.ID, &m.Attempts, &channel, &m.Target, &m.Subject, &m.Body, &m.HTMLBody); err != nil {
+			return nil, err
+		}
+		m.Channel = domain.NotificationChannel(channel)
+		messages = append(messages, m)
+	}
+	return messages, rows.Err()
+}
+
+func (r *NotificationRepo) MarkNotificationSent(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx,
+		"UPDATE notifications SET status = 'SENT', last_error = NULL, sent_at = NOW() WHERE id = $1",
+		id,
+	)
+	return err
+}
+
+// MarkNotificationFailed записывает неудачную попытку и планирует следующую.
+// Если nextAttemptAt == nil, уведомление переводится в FAILED.
+func (r *NotificationRepo) MarkNotificationFailed(ctx context.Context, id int64, errMsg string, nextAttemptAt *time.Time) error {
+	if nextAttemptAt == nil {
+		_, err := r.db.ExecContext(ctx,
+			"UPDATE notifications SET status = 'FAILED', last_error = $1 WHERE id = $2",
+			errMsg, id,
+		)
+		return err
+	}
+
+	_, err := r.db.ExecContext(ctx,
+		"UPDATE notifications SET last_error = $1, next_attempt_at = $2 WHERE id = $3",
+		errMsg, *nextAttemptAt, id,
+	)
+	return err
+}
@@ -20,33 +20,48 @@ func NewPullRequestRepo(db *sql.DB) *PullRequestRepo {
 	return &PullRequestRepo{db: db}
 }
 
-// Save сохраняет новый PullRequest (только в статусе OPEN).
+// Save сохраняет новый PullRequest (только в статусе OPEN)
+// и в той же транзакции пишет событие pull_request.created в outbox.
 func (r *PullRequestRepo) Save(ctx context.Context, pr *domain.PullRequest) error {
 	// Валидация: можно сохранять только OPEN-запросы
 	if pr.Status() != domain.PROpen {
 		return errors.New("only OPEN pull requests can be saved")
 	}
 
+	event, err := domain.NewPullRequestEvent(domain.EventPRCreated, pr, "", "")
+	if err != nil {
+		return err
+	}
+
 	query := `
-		INSERT INTO pull_requests (id, name, author_id, status, assigned_reviewers, created_at, merged_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO pull_requests (id, name, author_id, status, assigned_reviewers, created_at, merged_at, version)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 	`
-	_, err := r.db.ExecContext(ctx, query,
-		pr.ID(),
-		pr.Name(),
-		pr.AuthorID(),
-		string(pr.Status()),
-		pq.Array(pr.AssignedReviewers()),
-		pr.CreatedAt(),
-		nil, // merged_at = NULL для OPEN
-	)
-	return err
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, query,
+			pr.ID(),
+			pr.Name(),
+			pr.AuthorID(),
+			string(pr.Status()),
+			pq.Array(pr.AssignedReviewers()),
+			pr.CreatedAt(),
+			nil, // merged_at = NULL для OPEN
+			pr.Version(),
+		)
+		if err != nil {
+			return err
+		}
+		if err := syncReviewAssignments(ctx, tx, pr.ID(), nil, pr.AssignedReviewers(), pr.CreatedAt()); err != nil {
+			return err
+		}
+		return insertEvent(ctx, tx, event)
+	})
 }
 
 // GetByID возвращает PullRequest по ID.
 func (r *PullRequestRepo) GetByID(ctx context.Context, id string) (*domain.PullRequest, error) {
 	query := `
-		SELECT id, name, author_id, status, assigned_reviewers, created_at, merged_at
+		SELECT id, name, author_id, status, assigned_reviewers, created_at, merged_at, version
 		FROM pull_requests
 		WHERE id = $1
 	`
@@ -57,9 +72,10 @@ func (r *PullRequestRepo) GetByID(ctx context.Context, id string) (*domain.PullR
 		reviewers                        pq.StringArray
 		createdAt                        time.Time
 		mergedAt                         *time.Time
+		version                          int
 	)
 
-	if err := row.Scan(&idStr, &name, &authorID, &statusStr, &reviewers, &createdAt, &mergedAt); err != nil {
+	if err := row.Scan(&idStr, &name, &authorID, &statusStr, &reviewers, &createdAt, &mergedAt, &version); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, domain.ErrPRNotFound
 		}
@@ -69,7 +85,7 @@ func (r *PullRequestRepo) GetByID(ctx context.Context, id string) (*domain.PullR
 	status := domain.PRStatus(statusStr)
 	assignedReviewers := []string(reviewers)
 
-	return domain.RestorePullRequest(idStr, name, authorID, status, assignedReviewers, createdAt, mergedAt)
+	return domain.RestorePullRequest(idStr, name, authorID, status, assignedReviewers, createdAt, mergedAt, version)
 }
 
 // PRExists проверяет существование PR по ID.
@@ -82,45 +98,255 @@ func (r *PullRequestRepo) PRExists(ctx context.Context, id string) (bool, error)
 	return exists, nil
 }
 
-// UpdateReviewers обновляет список ревьюеров у существующего PR.
-func (r *PullRequestRepo) UpdateReviewers(ctx context.Context, id string, reviewers []string) error {
-	_, err := r.db.ExecContext(ctx,
-		"UPDATE pull_requests SET assigned_reviewers = $1 WHERE id = $2",
-		pq.Array(reviewers), id,
-	)
-	return err
+// UpdateReviewers обновляет список ревьюеров у существующего PR,
+// если его версия всё ещё равна expectedVersion. Иначе — ErrPRVersionConflict.
+// В той же транзакции пишет событие pull_request.reviewer_reassigned в outbox.
+func (r *PullRequestRepo) UpdateReviewers(ctx context.Context, id string, reviewers []string, expectedVersion int) error {
+	notUpdated := false
+	err := withTx(ctx, r.db, func(tx *sql.Tx) error {
+		var (
+			name, authorID, statusStr string
+			oldReviewers              pq.StringArray
+			createdAt                 time.Time
+		)
+		err := tx.QueryRowContext(ctx, `
+			SELECT name, author_id, status, assigned_reviewers, created_at
+			FROM pull_requests
+			WHERE id = $1 AND version = $2
+			FOR UPDATE
+		`, id, expectedVersion).Scan(&name, &authorID, &statusStr, &oldReviewers, &createdAt)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				notUpdated = true
+				return nil
+			}
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx,
+			"UPDATE pull_requests SET assigned_reviewers = $1, version = version + 1 WHERE id = $2",
+			pq.Array(reviewers), id,
+		)
+		if err != nil {
+			return err
+		}
+		if err := syncReviewAssignments(ctx, tx, id, oldReviewers, reviewers, time.Now().UTC()); err != nil {
+			return err
+		}
+
+		pr, err := domain.RestorePullRequest(id, name, authorID, domain.PRStatus(statusStr), reviewers, createdAt, nil, expectedVersion+1)
+		if err != nil {
+			return err
+		}
+		oldReviewer, newReviewer := diffReviewers(oldReviewers, reviewers)
+		event, err := domain.NewPullRequestEvent(domain.EventReviewerReassigned, pr, oldReviewer, newReviewer)
+		if err != nil {
+			return err
+		}
+		return insertEvent(ctx, tx, event)
+	})
+	if err != nil {
+		return err
+	}
+	if notUpdated {
+		return r.versionConflictOrNotFound(ctx, id)
+	}
+	return nil
 }
 
-// Merge переводит PR в статус MERGED с указанным временем.
+// Merge переводит PR в статус MERGED с указанным временем,
+// если его версия всё ещё равна expectedVersion.
+// В той же транзакции пишет событие pull_request.merged в outbox.
 // Идемпотентен: если уже MERGED — не ошибка.
-func (r *PullRequestRepo) Merge(ctx context.Context, id string, mergedAt time.Time) error {
-	res, err := r.db.ExecContext(ctx,
-		"UPDATE pull_requests SET status = 'MERGED', merged_at = $1 WHERE id = $2 AND status = 'OPEN'",
-		mergedAt, id,
-	)
+func (r *PullRequestRepo) Merge(ctx context.Context, id string, mergedAt time.Time, expectedVersion int) error {
+	notUpdated := false
+	err := withTx(ctx, r.db, func(tx *sql.Tx) error {
+		var (
+			name, authorID string
+			reviewers      pq.StringArray
+			createdAt      time.Time
+			version        int
+		)
+		err := tx.QueryRowContext(ctx, `
+			UPDATE pull_requests
+			SET status = 'MERGED', merged_at = $1, version = version + 1
+			WHERE id = $2 AND status = 'OPEN' AND version = $3
+			RETURNING name, author_id, assigned_reviewers, created_at, version
+		`, mergedAt, id, expectedVersion).Scan(&name, &authorID, &reviewers, &createdAt, &version)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				notUpdated = true
+				return nil
+			}
+			return err
+		}
+
+		pr, err := domain.RestorePullRequest(id, name, authorID, domain.PRMerged, reviewers, createdAt, &mergedAt, version)
+		if err != nil {
+			return err
+		}
+		event, err := domain.NewPullRequestEvent(domain.EventPRMerged, pr, "", "")
+		if err != nil {
+			return err
+		}
+		return insertEvent(ctx, tx, event)
+	})
 	if err != nil {
 		return err
 	}
 
-	rowsAffected, _ := res.RowsAffected()
-	if rowsAffected == 0 {
-		// Не обновилось → либо не существует, либо уже MERGED
-		exists, err := r.PRExists(ctx, id)
+	if notUpdated {
+		// Не обновилось → либо не существует, либо уже MERGED, либо версия изменилась
+		var status string
+		err := r.db.QueryRowContext(ctx, "SELECT status FROM pull_requests WHERE id = $1", id).Scan(&status)
 		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return domain.ErrPRNotFound
+			}
 			return err
 		}
-		if !exists {
-			return domain.ErrPRNotFound
+		switch domain.PRStatus(status) {
+		case domain.PROpen:
+			return domain.ErrPRVersionConflict
+		case domain.PRClosed:
+			return domain.ErrPRClosed
 		}
-		// Если существует — значит, уже MERGED → OK
+		// Если уже MERGED → OK
 	}
 	return nil
 }
 
+// Close переводит открытый PR в статус CLOSED, если его версия всё ещё равна expectedVersion,
+// и снимает текущие назначения ревьюеров на момент closedAt: закрытый PR не ждёт ревью.
+// В той же транзакции пишет событие pull_request.closed в outbox.
+// Идемпотентен: если уже CLOSED — не ошибка.
+func (r *PullRequestRepo) Close(ctx context.Context, id string, closedAt time.Time, expectedVersion int) error {
+	return r.transition(ctx, id, domain.PROpen, domain.PRClosed, closedAt, expectedVersion)
+}
+
+// Reopen возвращает закрытый PR в статус OPEN, если его версия всё ещё равна expectedVersion,
+// и заново назначает тех же ревьюеров с момента reopenedAt.
+// В той же транзакции пишет событие pull_request.reopened в outbox.
+// Идемпотентен: если PR уже OPEN — не ошибка.
+func (r *PullRequestRepo) Reopen(ctx context.Context, id string, reopenedAt time.Time, expectedVersion int) error {
+	return r.transition(ctx, id, domain.PRClosed, domain.PROpen, reopenedAt, expectedVersion)
+}
+
+// transition переводит PR между статусами OPEN и CLOSED
+func (r *PullRequestRepo) transition(ctx context.Context, id string, from, to domain.PRStatus, at time.Time, expectedVersion int) error {
+	notUpdated := false
+	err := withTx(ctx, r.db, func(tx *sql.Tx) error {
+		var (
+			name, authorID string
+			reviewers      pq.StringArray
+			createdAt      time.Time
+			version        int
+		)
+		err := tx.QueryRowContext(ctx, `
+			UPDATE pull_requests
+			SET status = $1, version = version + 1
+			WHERE id = $2 AND status = $3 AND version = $4
+			RETURNING name, author_id, assigned_reviewers, created_at, version
+		`, string(to), id, string(from), expectedVersion).Scan(&name, &authorID, &reviewers, &createdAt, &version)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				notUpdated = true
+				return nil
+			}
+			return err
+		}
+
+		eventType := domain.EventPRReopened
+		oldReviewers, newReviewers := []string(nil), []string(reviewers)
+		if to == domain.PRClosed {
+			eventType = domain.EventPRClosed
+			oldReviewers, newReviewers = newReviewers, oldReviewers
+		}
+		if err := syncReviewAssignments(ctx, tx, id, oldReviewers, newReviewers, at); err != nil {
+			return err
+		}
+
+		pr, err := domain.RestorePullRequest(id, name, authorID, to, reviewers, createdAt, nil, version)
+		if err != nil {
+			return err
+		}
+		event, err := domain.NewPullRequestEvent(eventType, pr, "", "")
+		if err != nil {
+			return err
+		}
+		return insertEvent(ctx, tx, event)
+	})
+	if err != nil || !notUpdated {
+		return err
+	}
+
+	var status string
+	err = r.db.QueryRowContext(ctx, "SELECT status FROM pull_requests WHERE id = $1", id).Scan(&status)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrPRNotFound
+		}
+		return err
+	}
+	return transitionError(domain.PRStatus(status), from, to)
+}
+
+// transitionError объясняет, почему PR в статусе status не перешёл из from в to
+func transitionError(status, from, to domain.PRStatus) error {
+	switch status {
+	case to:
+		return nil
+	case from:
+		return domain.ErrPRVersionConflict
+	case domain.PRMerged:
+		return domain.ErrPRAlreadyMerged
+	default:
+		return domain.ErrPRClosed
+	}
+}
+
+// diffReviewers находит снятого и нового ревьюера при замене одного на другого.
+func diffReviewers(oldReviewers, newReviewers []string) (removed, added string) {
+	for _, o := range oldReviewers {
+		if !containsString(newReviewers, o) {
+			removed = o
+			break
+		}
+	}
+	for _, n := range newReviewers {
+		if !containsString(oldReviewers, n) {
+			added = n
+			break
+		}
+	}
+	return removed, added
+}
+
+func containsString(slice []string, s string) bool {
+	for _, v := range slice {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// versionConflictOrNotFound различает отсутствие PR и конфликт версий после неудачного UPDATE.
+func (r *PullRequestRepo) versionConflictOrNotFound(ctx context.Context, id string) error {
+	exists, err := r.PRExists(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return domain.ErrPRNotFound
+	}
+	return domain.ErrPRVersionConflict
+}
+
 // GetByReviewer возвращает все PR, где reviewerID в assigned_reviewers.
 func (r *PullRequestRepo) GetByReviewer(ctx context.Context, reviewerID string) ([]domain.PullRequest, error) {
 	rows, err := r.db.QueryContext(ctx,
-		`SELECT id, name, author_id, status, assigned_reviewers, created_at, merged_at
+		`SELECT id, name, author_id, status, assigned_reviewers, created_at, merged_at, version
 		 FROM pull_requests
 		 WHERE $1 = ANY(assigned_reviewers)`,
 		reviewerID,
@@ -136,15 +362,16 @@ func (r *PullRequestRepo) GetByReviewer(ctx context.Context, reviewerID string)
 		var reviewers pq.StringArray
 		var createdAt time.Time
 		var mergedAt *time.Time
+		var version int
 
-		if err := rows.Scan(&id, &name, &authorID, &statusStr, &reviewers, &createdAt, &mergedAt); err != nil {
+		if err := rows.Scan(&id, &name, &authorID, &statusStr, &reviewers, &createdAt, &mergedAt, &version); err != nil {
 			return nil, err
 		}
 
 		status := domain.PRStatus(statusStr)
 		assigned := []string(reviewers)
 
-		pr, err := domain.RestorePullRequest(id, name, authorID, status, assigned, createdAt, mergedAt)
+		pr, err := domain.RestorePullRequest(id, name, authorID, status, assigned, createdAt, mergedAt, version)
 		if err != nil {
 			return nil, err
 		}
@@ -152,31 +379,3 @@ func (r *PullRequestRepo) GetByReviewer(ctx context.Context, reviewerID string)
 	}
 	return prs, nil
 }
-
-// GetReviewerStats возвращает количество OPEN PR на каждого ревьюера.
-func (r *PullRequestRepo) GetReviewerStats(ctx context.Context) (map[string]int, error) {
-	rows, err := r.db.QueryContext(ctx, `
-		SELECT reviewer, COUNT(*)
-		FROM (
-			SELECT unnest(assigned_reviewers) AS reviewer
-			FROM pull_requests
-			WHERE status = 'OPEN'
-		) AS active_reviewers
-		GROUP BY reviewer
-	`)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	stats := make(map[string]int)
-	for rows.Next() {
-		var reviewer string
-		var count int
-		if err := rows.Scan(&reviewer, &count); err != nil {
-			return nil, err
-		}
-		stats[reviewer] = count
-	}
-	return stats, nil
-}
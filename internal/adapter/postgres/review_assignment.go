@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+// syncReviewAssignments отражает смену состава ревьюеров в review_assignments:
+// снятым проставляет unassigned_at, новым создаёт назначение. Вызывается в транзакции,
+// которая меняет assigned_reviewers.
+func syncReviewAssignments(ctx context.Context, tx *sql.Tx, prID string, oldReviewers, newReviewers []string, at time.Time) error {
+	for _, id := range oldReviewers {
+		if containsString(newReviewers, id) {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE review_assignments SET unassigned_at = $3
+			WHERE pull_request_id = $1 AND reviewer_id = $2 AND unassigned_at IS NULL
+		`, prID, id, at); err != nil {
+			return err
+		}
+	}
+	for _, id := range newReviewers {
+		if containsString(oldReviewers, id) {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO review_assignments (pull_request_id, reviewer_id, assigned_at)
+			VALUES ($1, $2, $3)
+			ON CONFLICT DO NOTHING
+		`, prID, id, at); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RecordReviewAction фиксирует первое действие ревьюера по текущему назначению.
+// Возвращает время первого действия и true, если оно записано этим вызовом;
+// для повторных действий — ранее записанное время и false.
+func (r *PullRequestRepo) RecordReviewAction(ctx context.Context, prID, reviewerID string, at time.Time) (time.Time, bool, error) {
+	var firstActionAt time.Time
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE review_assignments SET first_action_at = $3
+		WHERE pull_request_id = $1 AND reviewer_id = $2 AND unassigned_at IS NULL AND first_action_at IS NULL
+		RETURNING first_action_at
+	`, prID, reviewerID, at).Scan(&firstActionAt)
+	if err == nil {
+		return firstActionAt, true, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, false, err
+	}
+
+	err = r.db.QueryRowContext(ctx, `
+		SELECT first_action_at FROM review_assignments
+		WHERE pull_request_id = $1 AND reviewer_id = $2 AND unassigned_at IS NULL
+	`, prID, reviewerID).Scan(&firstActionAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, false, domain.ErrReviewerNotAssigned
+	}
+	return firstActionAt, false, err
+}
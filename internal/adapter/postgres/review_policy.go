@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+// ReviewPolicyRepo хранит правила команд для зависших PR
+type ReviewPolicyRepo struct {
+	db *sql.DB
+}
+
+func NewReviewPolicyRepo(db *sql.DB) *ReviewPolicyRepo {
+	return &ReviewPolicyRepo{db: db}
+}
+
+// SaveReviewPolicy создаёт или заменяет политику команды
+func (r *ReviewPolicyRepo) SaveReviewPolicy(ctx context.Context, p *domain.ReviewPolicy) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO team_review_policies
+			(team_name, remind_after_seconds, escalate_after_seconds, escalation, lead_user_id, updated_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6)
+		ON CONFLICT (team_name) DO UPDATE SET
+			remind_after_seconds = EXCLUDED.remind_after_seconds,
+			escalate_after_seconds = EXCLUDED.escalate_after_seconds,
+			escalation = EXCLUDED.escalation,
+			lead_user_id = EXCLUDED.lead_user_id,
+			updated_at = EXCLUDED.updated_at
+	`, p.TeamName(), int64(p.RemindAfter()/time.Second), int64(p.EscalateAfter()/time.Second),
+		string(p.Escalation()), p.LeadID(), time.Now().UTC())
+	return err
+}
+
+func (r *ReviewPolicyRepo) GetReviewPolicy(ctx context.Context, teamName string) (*domain.ReviewPolicy, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT team_name, remind_after_seconds, escalate_after_seconds, escalation, COALESCE(lead_user_id, '')
+		FROM team_review_policies
+		WHERE team_name = $1
+	`, teamName)
+	p, err := scanReviewPolicy(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrReviewPolicyNotFound
+	}
+	return p, err
+}
+
+func scanReviewPolicy(row interface{ Scan(dest ...any) error }) (*domain.ReviewPolicy, error) {
+	var (
+		teamName, escalation, leadID   string
+		remindSeconds, escalateSeconds int64
+	)
+	if err := row.Scan(&teamName, &remindSeconds, &escalateSeconds, &escalation, &leadID); err != nil {
+		return nil, err
+	}
+	return domain.RestoreReviewPolicy(teamName,
+		time.Duration(remindSeconds)*time.Second,
+		time.Duration(escalateSeconds)*time.Second,
+		domain.EscalationAction(escalation), leadID), nil
+}
@@ -0,0 +1,198 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/lib/pq"
+)
+
+// snapshotTable — таблица снимка. Очереди доставки (вебхуки, уведомления, запросы ревью в VCS)
+// и ключи идемпотентности в снимок не входят: это состояние конкретного окружения.
+type snapshotTable struct {
+	name    string
+	orderBy string
+	// serial — у таблицы BIGSERIAL id, после восстановления сдвигаем последовательность
+	serial bool
+}
+
+// snapshotTables перечислены в порядке внешних ключей: восстановление идёт в том же порядке
+var snapshotTables = []snapshotTable{
+	{name: "teams", orderBy: "name"},
+	{name: "users", orderBy: "id"},
+	{name: "team_members", orderBy: "team_name, user_id"},
+	{name: "user_identities", orderBy: "provider, external_id"},
+	{name: "team_review_policies", orderBy: "team_name"},
+	{name: "team_review_slas", orderBy: "team_name"},
+	{name: "pull_requests", orderBy: "id"},
+	{name: "review_assignments", orderBy: "id", serial: true},
+	{name: "stale_pr_actions", orderBy: "pull_request_id, stage"},
+	{name: "webhook_subscriptions", orderBy: "id", serial: true},
+	{name: "outbox", orderBy: "id", serial: true},
+}
+
+type SnapshotRepo struct {
+	db *sql.DB
+}
+
+func NewSnapshotRepo(db *sql.DB) *SnapshotRepo {
+	return &SnapshotRepo{db: db}
+}
+
+// ExportSnapshot передаёт в emit все строки снимка. Чтение идёт в одной транзакции
+// REPEATABLE READ, поэтому снимок согласован даже при параллельной записи.
+func (r *SnapshotRepo) ExportSnapshot(ctx context.Context, emit func(row domain.SnapshotRow) error) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, t := range snapshotTables {
+		if err := exportTable(ctx, tx, t, emit); err != nil {
+			return fmt.Errorf("export %s: %w", t.name, err)
+		}
+	}
+	return tx.Commit()
+}
+
+func exportTable(ctx context.Context, tx *sql.Tx, t snapshotTable, emit func(row domain.SnapshotRow) error) error {
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf("SELECT row_to_json(t)::text FROM %s t ORDER BY %s", t.name, t.orderBy))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return err
+		}
+		if err := emit(domain.SnapshotRow{Table: t.name, Data: json.RawMessage(data)}); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// RestoreSnapshot загружает строки из next (до io.EOF) в одной транзакции. Без force
+// возвращает domain.ErrDatabaseNotEmpty, если в базе уже есть данные; с force сначала их удаляет
+// вместе с очередями доставки и ключами идемпотентности.
+// Восстановленные события outbox помечаются опубликованными, чтобы не разослать их повторно.
+func (r *SnapshotRepo) RestoreSnapshot(ctx context.Context, force bool, next func() (*domain.SnapshotRow, error)) (map[string]int, error) {
+	counts := make(map[string]int)
+	err := withTx(ctx, r.db, func(tx *sql.Tx) error {
+		if force {
+			if err := truncateSnapshotTables(ctx, tx); err != nil {
+				return err
+			}
+		} else {
+			empty, err := snapshotTablesEmpty(ctx, tx)
+			if err != nil {
+				return err
+			}
+			if !empty {
+				return domain.ErrDatabaseNotEmpty
+			}
+		}
+
+		position := 0
+		for {
+			row, err := next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return err
+			}
+
+			index := snapshotTableIndex(row.Table)
+			if index < 0 {
+				return fmt.Errorf("%w: unknown table %q", domain.ErrInvalidSnapshot, row.Table)
+			}
+			if index < position {
+				return fmt.Errorf("%w: table %q is out of order", domain.ErrInvalidSnapshot, row.Table)
+			}
+			position = index
+
+			query := fmt.Sprintf("INSERT INTO %[1]s SELECT * FROM json_populate_record(NULL::%[1]s, $1::json)", row.Table)
+			if _, err := tx.ExecContext(ctx, query, string(row.Data)); err != nil {
+				return mapSnapshotError(row.Table, counts[row.Table]+1, err)
+			}
+			counts[row.Table]++
+		}
+
+		if _, err := tx.ExecContext(ctx, "UPDATE outbox SET published_at = NOW() WHERE published_at IS NULL"); err != nil {
+			return err
+		}
+		if err := indexOutboxUsers(ctx, tx); err != nil {
+			return err
+		}
+		for _, t := range snapshotTables {
+			if !t.serial {
+				continue
+			}
+			query := fmt.Sprintf(
+				"SELECT setval(pg_get_serial_sequence('%[1]s', 'id'), COALESCE((SELECT MAX(id) FROM %[1]s), 0) + 1, false)",
+				t.name,
+			)
+			if _, err := tx.ExecContext(ctx, query); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// truncateSnapshotTables очищает таблицы снимка и ключи идемпотентности: сохранённые ответы
+// ссылаются на прежние данные. CASCADE заодно очищает очереди доставки, ссылающиеся на таблицы снимка.
+func truncateSnapshotTables(ctx context.Context, tx *sql.Tx) error {
+	names := make([]string, 0, len(snapshotTables)+1)
+	for _, t := range snapshotTables {
+		names = append(names, t.name)
+	}
+	names = append(names, "idempotency_keys")
+	_, err := tx.ExecContext(ctx, "TRUNCATE "+strings.Join(names, ", ")+" CASCADE")
+	return err
+}
+
+func snapshotTablesEmpty(ctx context.Context, tx *sql.Tx) (bool, error) {
+	for _, t := range snapshotTables {
+		var exists bool
+		if err := tx.QueryRowContext(ctx, fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s)", t.name)).Scan(&exists); err != nil {
+			return false, err
+		}
+		if exists {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func snapshotTableIndex(name string) int {
+	for i, t := range snapshotTables {
+		if t.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// mapSnapshotError превращает ошибки данных (классы 22 и 23: формат, ограничения) в domain.ErrInvalidSnapshot
+func mapSnapshotError(table string, row int, err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && (pqErr.Code.Class() == "22" || pqErr.Code.Class() == "23") {
+		return fmt.Errorf("%w: %s row %d: %s", domain.ErrInvalidSnapshot, table, row, pqErr.Message)
+	}
+	return err
+}
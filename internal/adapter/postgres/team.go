@@ -17,76 +17,114 @@ func NewTeamRepo(db *sql.DB) *TeamRepo {
 }
 
 func (r *TeamRepo) CreateTeam(ctx context.Context, teamName string, members []domain.User) error {
-	// 1. Создаём команду
-	_, err := r.db.ExecContext(ctx, "INSERT INTO teams (name) VALUES ($1) ON CONFLICT (name) DO NOTHING", teamName)
-	if err != nil {
-		return err
-	}
+	return r.saveTeam(ctx, teamName, members)
+}
 
-	// 2. Обрабатываем пользователей
-	for _, u := range members {
-		// Проверяем существование
-		_, err := r.GetUserByID(ctx, u.ID())
-		if err == nil {
-			// Обновляем
-			if err := r.UpdateUser(ctx, &u); err != nil {
-				return err
-			}
-		} else if errors.Is(err, domain.ErrUserNotFound) {
-			// Создаём
-			if err := r.CreateUser(ctx, &u); err != nil {
+// SaveTeam сохраняет команду, создаёт/обновляет участников и переносит их в команду.
+// Всё выполняется в одной транзакции вместе с событиями смены активности.
+func (r *TeamRepo) SaveTeam(ctx context.Context, team *domain.Team) error {
+	return r.saveTeam(ctx, team.Name(), team.Members())
+}
+
+func (r *TeamRepo) saveTeam(ctx context.Context, teamName string, members []domain.User) error {
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		return saveTeamTx(ctx, tx, teamName, members)
+	})
+}
+
+// ImportTeams сохраняет все команды в одной транзакции: при ошибке не применяется ни одна
+func (r *TeamRepo) ImportTeams(ctx context.Context, teams []domain.Team) error {
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		for _, team := range teams {
+			if err := saveTeamTx(ctx, tx, team.Name(), team.Members()); err != nil {
 				return err
 			}
-		} else {
-			return err
 		}
+		return nil
+	})
+}
 
-		// 3. Переназначаем команду
-		_, err = r.db.ExecContext(ctx, "DELETE FROM team_members WHERE user_id = $1", u.ID())
-		if err != nil {
-			return err
+// ListMemberships возвращает всех пользователей с их командами, включая не состоящих ни в одной
+func (r *TeamRepo) ListMemberships(ctx context.Context) ([]domain.Membership, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT u.id, u.username, u.is_active, COALESCE(tm.team_name, '')
+		FROM users u
+		LEFT JOIN team_members tm ON tm.user_id = u.id
+		ORDER BY u.id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var memberships []domain.Membership
+	for rows.Next() {
+		var id, username, teamName string
+		var isActive bool
+		if err := rows.Scan(&id, &username, &isActive, &teamName); err != nil {
+			return nil, err
 		}
-		_, err = r.db.ExecContext(ctx, "INSERT INTO team_members (team_name, user_id) VALUES ($1, $2)", teamName, u.ID())
+		user, err := domain.NewUser(id, username, isActive)
 		if err != nil {
-			return err
+			return nil, err
 		}
+		memberships = append(memberships, domain.Membership{User: *user, TeamName: teamName})
 	}
+	return memberships, rows.Err()
+}
 
-	return nil
+// ReconcileTeams в одной транзакции сохраняет команды из желаемого состояния и убирает из команд
+// пользователей removed. Активность removed сохраняется как передана: так выключаются пропавшие из файла.
+func (r *TeamRepo) ReconcileTeams(ctx context.Context, teams []domain.Team, removed []domain.User) error {
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		for _, team := range teams {
+			if err := saveTeamTx(ctx, tx, team.Name(), team.Members()); err != nil {
+				return err
+			}
+		}
+		for _, u := range removed {
+			if err := updateUserTx(ctx, tx, &u); err != nil {
+				return err
+			}
+			if _, err := tx.ExecContext(ctx, "DELETE FROM team_members WHERE user_id = $1", u.ID()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
-func (r *TeamRepo) SaveTeam(ctx context.Context, team *domain.Team) error {
-	teamName := team.Name()
-	members := team.Members()
+// TeamExists проверяет наличие команды, в том числе оставшейся без участников
+func (r *TeamRepo) TeamExists(ctx context.Context, teamName string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM teams WHERE name = $1)", teamName).Scan(&exists)
+	return exists, err
+}
 
-	// Вставляем команду
-	_, err := r.db.ExecContext(ctx, "INSERT INTO teams (name) VALUES ($1) ON CONFLICT (name) DO NOTHING", teamName)
+func saveTeamTx(ctx context.Context, tx *sql.Tx, teamName string, members []domain.User) error {
+	// 1. Создаём команду
+	_, err := tx.ExecContext(ctx, "INSERT INTO teams (name) VALUES ($1) ON CONFLICT (name) DO NOTHING", teamName)
 	if err != nil {
 		return err
 	}
 
-	// Обрабатываем пользователей
+	// 2. Обрабатываем пользователей
 	for _, u := range members {
-		// Проверяем существование
-		_, err := r.GetUserByID(ctx, u.ID())
-		if err == nil {
-			if err := r.UpdateUser(ctx, &u); err != nil {
-				return err
-			}
-		} else if errors.Is(err, domain.ErrUserNotFound) {
-			if err := r.CreateUser(ctx, &u); err != nil {
-				return err
-			}
-		} else {
+		err := updateUserTx(ctx, tx, &u)
+		if errors.Is(err, domain.ErrUserNotFound) {
+			// Создаём
+			err = createUser(ctx, tx, &u)
+		}
+		if err != nil {
 			return err
 		}
 
-		// Обновляем team_members
-		_, err = r.db.ExecContext(ctx, "DELETE FROM team_members WHERE user_id = $1", u.ID())
+		// 3. Переназначаем команду
+		_, err = tx.ExecContext(ctx, "DELETE FROM team_members WHERE user_id = $1", u.ID())
 		if err != nil {
 			return err
 		}
-		_, err = r.db.ExecContext(ctx, "INSERT INTO team_members (team_name, user_id) VALUES ($1, $2)", teamName, u.ID())
+		_, err = tx.ExecContext(ctx, "INSERT INTO team_members (team_name, user_id) VALUES ($1, $2)", teamName, u.ID())
 		if err != nil {
 			return err
 		}
@@ -130,30 +168,17 @@ func (r *TeamRepo) FindTeamByName(ctx context.Context, teamName string) (*domain
 }
 
 func (r *TeamRepo) GetUserByID(ctx context.Context, id string) (*domain.User, error) {
-	// дублирование — временно, пока нет общего репозитория
-	query := `SELECT id, username, is_active FROM users WHERE id = $1`
-	row := r.db.QueryRowContext(ctx, query, id)
-	var idStr, username string
-	var isActive bool
-	if err := row.Scan(&idStr, &username, &isActive); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, domain.ErrUserNotFound
-		}
-		return nil, err
-	}
-	return domain.NewUser(idStr, username, isActive)
+	return getUserByID(ctx, r.db, id)
 }
 
 func (r *TeamRepo) CreateUser(ctx context.Context, u *domain.User) error {
-	query := `INSERT INTO users (id, username, is_active) VALUES ($1, $2, $3)`
-	_, err := r.db.ExecContext(ctx, query, u.ID(), u.Username(), u.IsActive())
-	return err
+	return createUser(ctx, r.db, u)
 }
 
 func (r *TeamRepo) UpdateUser(ctx context.Context, u *domain.User) error {
-	query := `UPDATE users SET username = $1, is_active = $2 WHERE id = $3`
-	_, err := r.db.ExecContext(ctx, query, u.Username(), u.IsActive(), u.ID())
-	return err
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		return updateUserTx(ctx, tx, u)
+	})
 }
 
 func (r *TeamRepo) GetTeamByName(ctx context.Context, teamName string) (*domain.Team, error) {
@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/lib/pq"
+)
+
+// VCSReviewRequestRepo — журнал запросов ревью в VCS
+type VCSReviewRequestRepo struct {
+	db *sql.DB
+}
+
+func NewVCSReviewRequestRepo(db *sql.DB) *VCSReviewRequestRepo {
+	return &VCSReviewRequestRepo{db: db}
+}
+
+func (r *VCSReviewRequestRepo) EnqueueReviewRequests(ctx context.Context, eventSequence int64, requests []domain.VCSReviewRequestDraft) error {
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		for _, req := range requests {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO vcs_review_requests (event_id, pull_request_id, action, reviewer_ids, status, next_attempt_at, created_at)
+				VALUES ($1, $2, $3, $4, 'PENDING', NOW(), NOW())
+				ON CONFLICT (event_id, action) DO NOTHING
+			`, eventSequence, req.PullRequestID, string(req.Action), pq.Array(req.ReviewerIDs))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ClaimDueReviewRequests захватывает запросы, время которых пришло, сдвигая next_attempt_at на lease.
+// Снятие ревьюера отправляется раньше запроса нового из того же события.
+func (r *VCSReviewRequestRepo) ClaimDueReviewRequests(ctx context.Context, limit int, lease time.Duration) ([]domain.VCSReviewRequest, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		UPDATE vcs_review_requests
+		SET attempts = attempts + 1,
+		    next_attempt_at = NOW() + make_interval(secs => $2)
+		WHERE id IN (
+		    SELECT id FROM vcs_review_requests
+		    WHERE status = 'PENDING' AND next_attempt_at <= NOW()
+		    ORDER BY next_attempt_at, event_id
+		    LIMIT $1
+		    FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, attempts, pull_request_id, action, reviewer_ids, event_id
+	`, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type claimed struct {
+		req     domain.VCSReviewRequest
+		eventID int64
+	}
+	var batch []claimed
+	for rows.Next() {
+		var (
+			c      claimed
+			action string
+		)
+		if err := rows.Scan(&c.req.ID, &c.req.Attempts, &c.req.PullRequestID, &action, pq.Array(&c.req.ReviewerIDs), &c.eventID); err != nil {
+			return nil, err
+		}
+		c.req.Action = domain.VCSReviewAction(action)
+		batch = append(batch, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// RETURNING не сохраняет порядок подзапроса
+	sort.Slice(batch, func(i, j int) bool {
+		if batch[i].eventID != batch[j].eventID {
+			return batch[i].eventID < batch[j].eventID
+		}
+		return batch[i].req.Action == domain.VCSReviewActionRemove && batch[j].req.Action != domain.VCSReviewActionRemove
+	})
+	requests := make([]domain.VCSReviewRequest, 0, len(batch))
+	for _, c := range batch {
+		requests = append(requests, c.req)
+	}
+	return requests, nil
+}
+
+func (r *VCSReviewRequestRepo) MarkReviewRequestDone(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE vcs_review_requests
+		SET status = 'DONE', last_error = NULL, completed_at = NOW()
+		WHERE id = $1
+	`, id)
+	return err
+}
+
+// MarkReviewRequestFailed записывает неудачную попытку и планирует следующую.
+// Если nextAttemptAt == nil, запрос переводится в FAILED.
+func (r *VCSReviewRequestRepo) MarkReviewRequestFailed(ctx context.Context, id int64, errMsg string, nextAttemptAt *time.Time) error {
+	if nextAttemptAt == nil {
+		_, err := r.db.ExecContext(ctx, `
+			UPDATE vcs_review_requests
+			SET status = 'FAILED', last_error = $1, completed_at = NOW()
+			WHERE id = $2
+		`, errMsg, id)
+		return err
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE vcs_review_requests
+		SET last_error = $1, next_attempt_at = $2
+		WHERE id = $3
+	`, errMsg, *nextAttemptAt, id)
+	return err
+}
@@ -0,0 +1,224 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/lib/pq"
+)
+
+// WebhookRepo хранит подписки на вебхуки и журнал доставок.
+type WebhookRepo struct {
+	db *sql.DB
+}
+
+func NewWebhookRepo(db *sql.DB) *WebhookRepo {
+	return &WebhookRepo{db: db}
+}
+
+// CreateSubscription сохраняет подписку и возвращает её с присвоенным ID.
+func (r *WebhookRepo) CreateSubscription(ctx context.Context, sub *domain.WebhookSubscription) (*domain.WebhookSubscription, error) {
+	var id int64
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO webhook_subscriptions (url, secret, event_types, is_active, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, sub.URL(), sub.Secret(), pq.Array(eventTypesToStrings(sub.EventTypes())), sub.IsActive(), sub.CreatedAt()).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	return domain.RestoreWebhookSubscription(id, sub.URL(), sub.Secret(), sub.EventTypes(), sub.IsActive(), sub.CreatedAt()), nil
+}
+
+// ListSubscriptions возвращает все подписки.
+func (r *WebhookRepo) ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, url, secret, event_types, is_active, created_at
+		FROM webhook_subscriptions
+		ORDER BY id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subs []domain.WebhookSubscription
+	for rows.Next() {
+		var (
+			id          int64
+			url, secret string
+			types       pq.StringArray
+			isActive    bool
+			createdAt   time.Time
+		)
+		if err := rows.Scan(&id, &url, &secret, &types, &isActive, &createdAt); err != nil {
+			return nil, err
+		}
+		subs = append(subs, *domain.RestoreWebhookSubscription(id, url, secret, stringsToEventTypes(types), isActive, createdAt))
+	}
+	return subs, rows.Err()
+}
+
+// DeleteSubscription удаляет подписку вместе с её журналом доставок.
+func (r *WebhookRepo) DeleteSubscription(ctx context.Context, id int64) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM webhook_subscriptions WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := res.RowsAffected()
+	if rowsAffected == 0 {
+		return domain.ErrWebhookNotFound
+	}
+	return nil
+}
+
+// ListDeliveries возвращает последние доставки по подписке.
+func (r *WebhookRepo) ListDeliveries(ctx context.Context, subscriptionID int64, limit int) ([]domain.WebhookDelivery, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM webhook_subscriptions WHERE id = $1)", subscriptionID).Scan(&exists)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, domain.ErrWebhookNotFound
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT d.id, d.subscription_id, d.event_id, o.event_type, d.status, d.attempts,
+		       d.next_attempt_at, d.last_status_code, d.last_error, d.created_at, d.delivered_at
+		FROM webhook_deliveries d
+		JOIN outbox o ON o.id = d.event_id
+		WHERE d.subscription_id = $1
+		ORDER BY d.id DESC
+		LIMIT $2
+	`, subscriptionID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []domain.WebhookDelivery
+	for rows.Next() {
+		var (
+			d                 domain.WebhookDelivery
+			eventType, status string
+			lastStatusCode    sql.NullInt64
+			lastError         sql.NullString
+		)
+		if err := rows.Scan(&d.ID, &d.SubscriptionID, &d.EventSequence, &eventType, &status, &d.Attempts,
+			&d.NextAttemptAt, &lastStatusCode, &lastError, &d.CreatedAt, &d.DeliveredAt); err != nil {
+			return nil, err
+		}
+		d.EventType = domain.EventType(eventType)
+		d.Status = domain.DeliveryStatus(status)
+		if lastStatusCode.Valid {
+			code := int(lastStatusCode.Int64)
+			d.LastStatusCode = &code
+		}
+		d.LastError = lastError.String
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// EnqueueDeliveries создаёт доставки события для активных подписок на его тип.
+// Повторный вызов для того же события дубликатов не создаёт.
+func (r *WebhookRepo) EnqueueDeliveries(ctx context.Context, eventSequence int64, eventType domain.EventType) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO webhook_deliveries (subscription_id, event_id, status, next_attempt_at, created_at)
+		SELECT id, $1, 'PENDING', NOW(), NOW()
+		FROM webhook_subscriptions
+		WHERE is_active AND $2 = ANY(event_types)
+		ON CONFLICT (subscription_id, event_id) DO NOTHING
+	`, eventSequence, string(eventType))
+	return err
+}
+
+// ClaimDueDeliveries захватывает доставки, время которых пришло, сдвигая next_attempt_at на lease.
+func (r *WebhookRepo) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]domain.WebhookAttempt, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		UPDATE webhook_deliveries d
+		SET attempts = d.attempts + 1,
+		    next_attempt_at = NOW() + make_interval(secs => $2)
+		FROM webhook_subscriptions s, outbox o
+		WHERE d.subscription_id = s.id
+		  AND d.event_id = o.id
+		  AND d.id IN (
+		      SELECT id FROM webhook_deliveries
+		      WHERE status = 'PENDING' AND next_attempt_at <= NOW()
+		      ORDER BY next_attempt_at
+		      LIMIT $1
+		      FOR UPDATE SKIP LOCKED
+		  )
+		RETURNING d.id, d.attempts, s.url, s.secret, o.id, o.event_type, o.aggregate_id, o.payload, o.created_at
+	`, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []domain.WebhookAttempt
+	for rows.Next() {
+		var (
+			d         domain.WebhookAttempt
+			eventType string
+			payload   []byte
+		)
+		if err := rows.Scan(&d.ID, &d.Attempts, &d.URL, &d.Secret,
+			&d.Event.Sequence, &eventType, &d.Event.AggregateID, &payload, &d.Event.OccurredAt); err != nil {
+			return nil, err
+		}
+		d.Event.Type = domain.EventType(eventType)
+		d.Event.Payload = payload
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// MarkDelivered отмечает успешную доставку.
+func (r *WebhookRepo) MarkDelivered(ctx context.Context, id int64, statusCode int) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE webhook_deliveries
+		SET status = 'DELIVERED', last_status_code = $1, last_error = NULL, delivered_at = NOW()
+		WHERE id = $2
+	`, statusCode, id)
+	return err
+}
+
+// MarkFailed записывает неудачную попытку и планирует следующую.
+// Если nextAttemptAt == nil, доставка переводится в FAILED.
+func (r *WebhookRepo) MarkFailed(ctx context.Context, id int64, statusCode *int, errMsg string, nextAttemptAt *time.Time) error {
+	if nextAttemptAt == nil {
+		_, err := r.db.ExecContext(ctx, `
+			UPDATE webhook_deliveries
+			SET status = 'FAILED', last_status_code = $1, last_error = $2
+			WHERE id = $3
+		`, statusCode, errMsg, id)
+		return err
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE webhook_deliveries
+		SET last_status_code = $1, last_error = $2, next_attempt_at = $3
+		WHERE id = $4
+	`, statusCode, errMsg, *nextAttemptAt, id)
+	return err
+}
+
+func eventTypesToStrings(types []domain.EventType) []string {
+	out := make([]string, len(types))
+	for i, t := range types {
+		out[i] = string(t)
+	}
+	return out
+}
+
+func stringsToEventTypes(values []string) []domain.EventType {
+	out := make([]domain.EventType, len(values))
+	for i, v := range values {
+		out[i] = domain.EventType(v)
+	}
+	return out
+}
@@ -0,0 +1,52 @@
+package scheduler
+
+import (
+	"context"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/logger"
+	"time"
+)
+
+// Locker выполняет функцию, только если удалось взять распределённую блокировку key
+type Locker interface {
+	RunExclusive(ctx context.Context, key int64, fn func(ctx context.Context) error) (bool, error)
+}
+
+// Job — периодическая задача, которая на каждом шаге выполняется только на одной реплике
+type Job struct {
+	Name     string
+	Interval time.Duration
+	LockKey  int64
+	Run      func(ctx context.Context) error
+}
+
+// Scheduler запускает периодические задачи внутри процесса сервера
+type Scheduler struct {
+	locker Locker
+}
+
+func New(locker Locker) *Scheduler {
+	return &Scheduler{locker: locker}
+}
+
+// Run выполняет job каждые job.Interval до отмены ctx. Первый запуск — сразу.
+// Если задачу в этот момент выполняет другая реплика, шаг пропускается.
+func (s *Scheduler) Run(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		ran, err := s.locker.RunExclusive(ctx, job.LockKey, job.Run)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			logger.FromContext(ctx).Error("scheduler: job failed", "job", job.Name, "error", err)
+		case err == nil && !ran:
+			logger.FromContext(ctx).Info("scheduler: job is running on another replica, skipping", "job", job.Name)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
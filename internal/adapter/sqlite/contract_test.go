@@ -0,0 +1,62 @@
+//go:build cgo
+
+package sqlite_test
+
+import (
+	"context"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/adapter/sqlite"
+	"github.com/Skorpsrgvch/reviewer-service/internal/adapter/storetest"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/db"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite3"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+)
+
+// openTempDB создаёт файл SQLite во временном каталоге теста и применяет к нему миграции
+func openTempDB(t *testing.T) storetest.Repos {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "reviewer.db")
+
+	m, err := migrate.New("file://../../../migrations/sqlite", "sqlite3://"+path+"?_foreign_keys=off")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Up(); err != nil {
+		t.Fatalf("migrations: %v", err)
+	}
+	if srcErr, dbErr := m.Close(); srcErr != nil || dbErr != nil {
+		t.Fatal(srcErr, dbErr)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := db.NewSQLiteDB(ctx, path, url.Values{}, db.PoolConfig{MaxOpenConns: 4, MaxIdleConns: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return storetest.Repos{
+		Teams:        sqlite.NewTeamRepo(conn),
+		Users:        sqlite.NewUserRepo(conn),
+		PullRequests: sqlite.NewPullRequestRepo(conn),
+		Stats:        sqlite.NewStatsRepo(conn),
+		Identities:   sqlite.NewIdentityRepo(conn),
+		Policies:     sqlite.NewReviewPolicyRepo(conn),
+		StalePRs:     sqlite.NewStalePullRequestRepo(conn),
+		SLAs:         sqlite.NewReviewSLARepo(conn),
+		Idempotency:  sqlite.NewIdempotencyRepo(conn),
+		Outbox:       sqlite.NewOutboxRepo(conn),
+		Snapshots:    sqlite.NewSnapshotRepo(conn),
+	}
+}
+
+func TestContract(t *testing.T) {
+	storetest.Run(t, openTempDB)
+}
@@ -0,0 +1,108 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+// EmailDigestRepo хранит события, отложенные до ежедневного дайджеста
+type EmailDigestRepo struct {
+	db *sql.DB
+}
+
+func NewEmailDigestRepo(db *sql.DB) *EmailDigestRepo {
+	return &EmailDigestRepo{db: db}
+}
+
+func (r *EmailDigestRepo) AddDigestItem(ctx context.Context, userID string, eventSequence int64, summary string) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO email_digest_items (user_id, event_id, summary, created_at)
+		VALUES (?1, ?2, ?3, ?4)
+		ON CONFLICT (user_id, event_id) DO NOTHING
+	`, userID, eventSequence, summary, timestamp(time.Now()))
+	return err
+}
+
+// FlushDigests собирает накопленные события в одной транзакции с блокировкой записи,
+// поэтому одновременный запуск рассылки не отправит дайджест дважды.
+// Пользователи, сменившие адрес на пустой, пропускаются, их события помечаются отправленными.
+func (r *EmailDigestRepo) FlushDigests(ctx context.Context, render func(batch domain.DigestBatch) (domain.NotificationDraft, error)) (int, error) {
+	sent := 0
+	err := withTx(ctx, r.db, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, `
+			SELECT i.id, i.user_id, u.username, COALESCE(u.email, ''), i.summary
+			FROM email_digest_items i
+			JOIN users u ON u.id = i.user_id
+			WHERE i.sent_at IS NULL
+			ORDER BY i.user_id, i.id
+		`)
+		if err != nil {
+			return err
+		}
+
+		var (
+			batches []domain.DigestBatch
+			ids     []int64
+		)
+		for rows.Next() {
+			var (
+				id                        int64
+				userID, username, address string
+				summary                   string
+			)
+			if err := rows.Scan(&id, &userID, &username, &address, &summary); err != nil {
+				rows.Close()
+				return err
+			}
+			ids = append(ids, id)
+			if n := len(batches); n == 0 || batches[n-1].UserID != userID {
+				batches = append(batches, domain.DigestBatch{UserID: userID, Username: username, Email: address})
+			}
+			batches[len(batches)-1].Items = append(batches[len(batches)-1].Items, summary)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		now := timestamp(time.Now())
+		for _, batch := range batches {
+			if batch.Email == "" {
+				continue
+			}
+			draft, err := render(batch)
+			if err != nil {
+				return err
+			}
+			_, err = tx.ExecContext(ctx, `
+				INSERT INTO notifications (channel, target, subject, body, html_body, status, next_attempt_at, created_at)
+				VALUES (?1, ?2, ?3, ?4, ?5, 'PENDING', ?6, ?6)
+			`, string(draft.Channel), draft.Target, draft.Subject, draft.Body, draft.HTMLBody, now)
+			if err != nil {
+				return err
+			}
+			sent++
+		}
+
+		if len(ids) == 0 {
+			return nil
+		}
+		idsJSON, err := json.Marshal(ids)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx,
+			"UPDATE email_digest_items SET sent_at = ?1 WHERE id IN (SELECT value FROM json_each(?2))",
+			now, string(idsJSON),
+		)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return sent, nil
+}
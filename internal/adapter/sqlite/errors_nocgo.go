@@ -0,0 +1,10 @@
+//go:build !cgo
+
+package sqlite
+
+// Без cgo драйвер go-sqlite3 — заглушка, которая не открывает базу, поэтому ошибок SQLite не бывает.
+// Пакет при этом собирается, и бинарник с CGO_ENABLED=0 работает с другими хранилищами.
+
+func isForeignKeyViolation(error) bool { return false }
+
+func isDataError(error) bool { return false }
@@ -0,0 +1,55 @@
+package sqlite
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+// EventNotifySink — outbox-sink, который передаёт опубликованные события слушателям этого процесса.
+// С файлом SQLite работает один процесс, поэтому аналог LISTEN/NOTIFY между репликами не нужен.
+type EventNotifySink struct {
+	mu        sync.Mutex
+	listeners map[int]func(event domain.Event)
+	seq       int
+}
+
+func NewEventNotifySink() *EventNotifySink {
+	return &EventNotifySink{listeners: make(map[int]func(event domain.Event))}
+}
+
+func (n *EventNotifySink) Name() string { return "notify" }
+
+func (n *EventNotifySink) Publish(ctx context.Context, event domain.Event) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, handle := range n.listeners {
+		handle(event)
+	}
+	return nil
+}
+
+// EventListener получает события, опубликованные через свой EventNotifySink.
+type EventListener struct {
+	sink *EventNotifySink
+}
+
+func NewEventListener(sink *EventNotifySink) *EventListener {
+	return &EventListener{sink: sink}
+}
+
+// Run вызывает handle для каждого опубликованного события до отмены контекста.
+func (l *EventListener) Run(ctx context.Context, handle func(event domain.Event)) {
+	l.sink.mu.Lock()
+	l.sink.seq++
+	id := l.sink.seq
+	l.sink.listeners[id] = handle
+	l.sink.mu.Unlock()
+
+	<-ctx.Done()
+
+	l.sink.mu.Lock()
+	delete(l.sink.listeners, id)
+	l.sink.mu.Unlock()
+}
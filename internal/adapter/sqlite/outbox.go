@@ -0,0 +1,245 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+// insertEvent записывает доменное событие в outbox в рамках переданной транзакции.
+// Так событие не теряется и не публикуется, если бизнес-изменение откатилось.
+func insertEvent(ctx context.Context, tx *sql.Tx, event domain.Event) error {
+	err := tx.QueryRowContext(ctx, `
+		INSERT INTO outbox (event_type, aggregate_id, payload, created_at, next_attempt_at)
+		VALUES (?1, ?2, ?3, ?4, ?5)
+		RETURNING id
+	`, string(event.Type), event.AggregateID, string(event.Payload), timestamp(event.OccurredAt), timestamp(time.Now())).Scan(&event.Sequence)
+	if err != nil {
+		return err
+	}
+	return insertEventUsers(ctx, tx, event)
+}
+
+// indexOutboxUsers заполняет outbox_users по всем событиям outbox: после восстановления снимка
+// получатели не записаны. Получателей считает domain.UserEventsFromEvent, как и при записи события,
+// поэтому правила ленты пользователя не расходятся.
+func indexOutboxUsers(ctx context.Context, tx *sql.Tx) error {
+	const batchSize = 1000
+	var after int64
+	for {
+		// Партия читается целиком до вставок: в одной транзакции нельзя писать при открытом курсоре
+		events, err := outboxEventsAfter(ctx, tx, after, batchSize)
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			if err := insertEventUsers(ctx, tx, event); err != nil {
+				return err
+			}
+		}
+		if len(events) < batchSize {
+			return nil
+		}
+		after = events[len(events)-1].Sequence
+	}
+}
+
+func outboxEventsAfter(ctx context.Context, tx *sql.Tx, after int64, limit int) ([]domain.Event, error) {
+	rows, err := tx.QueryContext(ctx,
+		"SELECT id, event_type, aggregate_id, payload FROM outbox WHERE id > ?1 ORDER BY id LIMIT ?2",
+		after, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []domain.Event
+	for rows.Next() {
+		var (
+			event     domain.Event
+			eventType string
+			payload   string
+		)
+		if err := rows.Scan(&event.Sequence, &eventType, &event.AggregateID, &payload); err != nil {
+			return nil, err
+		}
+		event.Type = domain.EventType(eventType)
+		event.Payload = []byte(payload)
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// insertEventUsers записывает получателей события в outbox_users, по которой читается лента пользователя
+func insertEventUsers(ctx context.Context, tx *sql.Tx, event domain.Event) error {
+	userEvents, err := domain.UserEventsFromEvent(event)
+	if err != nil {
+		return err
+	}
+	for _, ue := range userEvents {
+		if ue.UserID == "" {
+			continue
+		}
+		_, err := tx.ExecContext(ctx,
+			"INSERT INTO outbox_users (user_id, event_id) VALUES (?1, ?2) ON CONFLICT DO NOTHING",
+			ue.UserID, event.Sequence,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// OutboxRepo читает outbox для фонового relay.
+type OutboxRepo struct {
+	db *sql.DB
+}
+
+func NewOutboxRepo(db *sql.DB) *OutboxRepo {
+	return &OutboxRepo{db: db}
+}
+
+// ClaimDueEvents захватывает готовые к отправке события, сдвигая next_attempt_at на lease.
+// Захват фиксируется сразу: sink пишут в базу через другие соединения, а SQLite допускает
+// одного писателя. Если процесс упадёт, события вернутся в очередь по истечении аренды.
+func (r *OutboxRepo) ClaimDueEvents(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboxAttempt, error) {
+	now := time.Now()
+	rows, err := r.db.QueryContext(ctx, `
+		UPDATE outbox
+		SET attempts = attempts + 1, next_attempt_at = ?3
+		WHERE id IN (
+		    SELECT id FROM outbox
+		    WHERE published_at IS NULL AND next_attempt_at <= ?2
+		    ORDER BY id
+		    LIMIT ?1
+		)
+		RETURNING id, event_type, aggregate_id, payload, created_at, attempts
+	`, limit, timestamp(now), timestamp(now.Add(lease)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attempts []domain.OutboxAttempt
+	for rows.Next() {
+		var (
+			a         domain.OutboxAttempt
+			eventType string
+			payload   string
+		)
+		if err := rows.Scan(&a.Event.Sequence, &eventType, &a.Event.AggregateID, &payload, &a.Event.OccurredAt, &a.Attempts); err != nil {
+			return nil, err
+		}
+		a.Event.Type = domain.EventType(eventType)
+		a.Event.Payload = []byte(payload)
+		attempts = append(attempts, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	// RETURNING не сохраняет порядок подзапроса, а события публикуются в порядке записи
+	sort.Slice(attempts, func(i, j int) bool { return attempts[i].Event.Sequence < attempts[j].Event.Sequence })
+	return attempts, nil
+}
+
+// MarkEventPublished отмечает событие опубликованным.
+func (r *OutboxRepo) MarkEventPublished(ctx context.Context, sequence int64) error {
+	_, err := r.db.ExecContext(ctx,
+		"UPDATE outbox SET published_at = ?1, last_error = NULL WHERE id = ?2",
+		timestamp(time.Now()), sequence,
+	)
+	return err
+}
+
+// MarkEventFailed записывает неудачную попытку публикации и откладывает событие до nextAttemptAt.
+func (r *OutboxRepo) MarkEventFailed(ctx context.Context, sequence int64, errMsg string, nextAttemptAt time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		"UPDATE outbox SET last_error = ?1, next_attempt_at = ?2 WHERE id = ?3",
+		errMsg, timestamp(nextAttemptAt), sequence,
+	)
+	return err
+}
+
+// Backlog возвращает число неопубликованных событий и время создания самого старого.
+func (r *OutboxRepo) Backlog(ctx context.Context) (int64, *time.Time, error) {
+	var (
+		pending int64
+		oldest  dbTime
+	)
+	err := r.db.QueryRowContext(ctx,
+		"SELECT COUNT(*), MIN(created_at) FROM outbox WHERE published_at IS NULL",
+	).Scan(&pending, &oldest)
+	if err != nil {
+		return 0, nil, err
+	}
+	return pending, oldest.ptr(), nil
+}
+
+// GetEvent возвращает событие outbox по порядковому номеру.
+func (r *OutboxRepo) GetEvent(ctx context.Context, sequence int64) (*domain.Event, error) {
+	var (
+		event     domain.Event
+		eventType string
+		payload   string
+	)
+	err := r.db.QueryRowContext(ctx,
+		"SELECT id, event_type, aggregate_id, payload, created_at FROM outbox WHERE id = ?1",
+		sequence,
+	).Scan(&event.Sequence, &eventType, &event.AggregateID, &payload, &event.OccurredAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("outbox event not found")
+		}
+		return nil, err
+	}
+	event.Type = domain.EventType(eventType)
+	event.Payload = []byte(payload)
+	return &event, nil
+}
+
+// LatestSequence возвращает порядковый номер последнего события outbox или 0, если событий нет.
+func (r *OutboxRepo) LatestSequence(ctx context.Context) (int64, error) {
+	var sequence int64
+	err := r.db.QueryRowContext(ctx, "SELECT COALESCE(MAX(id), 0) FROM outbox").Scan(&sequence)
+	return sequence, err
+}
+
+// ListUserEventsAfter возвращает события PR, касающиеся пользователя, с порядковым номером
+// больше afterSequence — для возобновления SSE-ленты по Last-Event-ID. Событие попадает в outbox
+// вместе с бизнес-изменением, поэтому ждать его публикации relay не нужно.
+func (r *OutboxRepo) ListUserEventsAfter(ctx context.Context, userID string, afterSequence int64, limit int) ([]domain.Event, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT o.id, o.event_type, o.aggregate_id, o.payload, o.created_at
+		FROM outbox_users u
+		JOIN outbox o ON o.id = u.event_id
+		WHERE u.user_id = ?1 AND u.event_id > ?2
+		ORDER BY u.event_id
+		LIMIT ?3
+	`, userID, afterSequence, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []domain.Event
+	for rows.Next() {
+		var (
+			event     domain.Event
+			eventType string
+			payload   string
+		)
+		if err := rows.Scan(&event.Sequence, &eventType, &event.AggregateID, &payload, &event.OccurredAt); err != nil {
+			return nil, err
+		}
+		event.Type = domain.EventType(eventType)
+		event.Payload = []byte(payload)
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
@@ -0,0 +1,417 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+// assignedReviewers — ревьюеры PR pr в порядке назначения JSON-массивом: замена колонки
+// assigned_reviewers TEXT[] из схемы Postgres
+const assignedReviewers = `(SELECT json_group_array(r.reviewer_id ORDER BY r.position)
+	FROM pull_request_reviewers r WHERE r.pull_request_id = pr.id)`
+
+// PullRequestRepo реализует работу с PullRequest в SQLite.
+// Следует принципу: «один репозиторий — один агрегат».
+type PullRequestRepo struct {
+	db *sql.DB
+}
+
+func NewPullRequestRepo(db *sql.DB) *PullRequestRepo {
+	return &PullRequestRepo{db: db}
+}
+
+// Save сохраняет новый PullRequest (только в статусе OPEN)
+// и в той же транзакции пишет событие pull_request.created в outbox.
+func (r *PullRequestRepo) Save(ctx context.Context, pr *domain.PullRequest) error {
+	// Валидация: можно сохранять только OPEN-запросы
+	if pr.Status() != domain.PROpen {
+		return errors.New("only OPEN pull requests can be saved")
+	}
+
+	event, err := domain.NewPullRequestEvent(domain.EventPRCreated, pr, "", "")
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO pull_requests (id, name, author_id, status, created_at, merged_at, version)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7)
+	`
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, query,
+			pr.ID(),
+			pr.Name(),
+			pr.AuthorID(),
+			string(pr.Status()),
+			timestamp(pr.CreatedAt()),
+			nil, // merged_at = NULL для OPEN
+			pr.Version(),
+		)
+		if err != nil {
+			return err
+		}
+		if err := saveReviewers(ctx, tx, pr.ID(), pr.AssignedReviewers()); err != nil {
+			return err
+		}
+		if err := syncReviewAssignments(ctx, tx, pr.ID(), nil, pr.AssignedReviewers(), pr.CreatedAt()); err != nil {
+			return err
+		}
+		return insertEvent(ctx, tx, event)
+	})
+}
+
+// GetByID возвращает PullRequest по ID.
+func (r *PullRequestRepo) GetByID(ctx context.Context, id string) (*domain.PullRequest, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT pr.id, pr.name, pr.author_id, pr.status, `+assignedReviewers+`, pr.created_at, pr.merged_at, pr.version
+		FROM pull_requests pr
+		WHERE pr.id = ?1
+	`, id)
+	pr, err := scanPullRequest(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrPRNotFound
+	}
+	return pr, err
+}
+
+// PRExists проверяет существование PR по ID.
+func (r *PullRequestRepo) PRExists(ctx context.Context, id string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM pull_requests WHERE id = ?1)", id).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// UpdateReviewers обновляет список ревьюеров у существующего PR,
+// если его версия всё ещё равна expectedVersion. Иначе — ErrPRVersionConflict.
+// В той же транзакции пишет событие pull_request.reviewer_reassigned в outbox.
+func (r *PullRequestRepo) UpdateReviewers(ctx context.Context, id string, reviewers []string, expectedVersion int) error {
+	notUpdated := false
+	err := withTx(ctx, r.db, func(tx *sql.Tx) error {
+		var (
+			name, authorID, statusStr string
+			createdAt                 time.Time
+		)
+		err := tx.QueryRowContext(ctx, `
+			UPDATE pull_requests SET version = version + 1
+			WHERE id = ?1 AND version = ?2
+			RETURNING name, author_id, status, created_at
+		`, id, expectedVersion).Scan(&name, &authorID, &statusStr, &createdAt)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				notUpdated = true
+				return nil
+			}
+			return err
+		}
+
+		oldReviewers, err := loadReviewers(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if err := saveReviewers(ctx, tx, id, reviewers); err != nil {
+			return err
+		}
+		if err := syncReviewAssignments(ctx, tx, id, oldReviewers, reviewers, time.Now().UTC()); err != nil {
+			return err
+		}
+
+		pr, err := domain.RestorePullRequest(id, name, authorID, domain.PRStatus(statusStr), reviewers, createdAt, nil, expectedVersion+1)
+		if err != nil {
+			return err
+		}
+		oldReviewer, newReviewer := diffReviewers(oldReviewers, reviewers)
+		event, err := domain.NewPullRequestEvent(domain.EventReviewerReassigned, pr, oldReviewer, newReviewer)
+		if err != nil {
+			return err
+		}
+		return insertEvent(ctx, tx, event)
+	})
+	if err != nil {
+		return err
+	}
+	if notUpdated {
+		return r.versionConflictOrNotFound(ctx, id)
+	}
+	return nil
+}
+
+// Merge переводит PR в статус MERGED с указанным временем,
+// если его версия всё ещё равна expectedVersion.
+// В той же транзакции пишет событие pull_request.merged в outbox.
+// Идемпотентен: если уже MERGED — не ошибка.
+func (r *PullRequestRepo) Merge(ctx context.Context, id string, mergedAt time.Time, expectedVersion int) error {
+	notUpdated := false
+	err := withTx(ctx, r.db, func(tx *sql.Tx) error {
+		var (
+			name, authorID string
+			createdAt      time.Time
+			version        int
+		)
+		err := tx.QueryRowContext(ctx, `
+			UPDATE pull_requests
+			SET status = 'MERGED', merged_at = ?1, version = version + 1
+			WHERE id = ?2 AND status = 'OPEN' AND version = ?3
+			RETURNING name, author_id, created_at, version
+		`, timestamp(mergedAt), id, expectedVersion).Scan(&name, &authorID, &createdAt, &version)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				notUpdated = true
+				return nil
+			}
+			return err
+		}
+
+		reviewers, err := loadReviewers(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		pr, err := domain.RestorePullRequest(id, name, authorID, domain.PRMerged, reviewers, createdAt, &mergedAt, version)
+		if err != nil {
+			return err
+		}
+		event, err := domain.NewPullRequestEvent(domain.EventPRMerged, pr, "", "")
+		if err != nil {
+			return err
+		}
+		return insertEvent(ctx, tx, event)
+	})
+	if err != nil {
+		return err
+	}
+
+	if notUpdated {
+		// Не обновилось → либо не существует, либо уже MERGED, либо версия изменилась
+		var status string
+		err := r.db.QueryRowContext(ctx, "SELECT status FROM pull_requests WHERE id = ?1", id).Scan(&status)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return domain.ErrPRNotFound
+			}
+			return err
+		}
+		switch domain.PRStatus(status) {
+		case domain.PROpen:
+			return domain.ErrPRVersionConflict
+		case domain.PRClosed:
+			return domain.ErrPRClosed
+		}
+		// Если уже MERGED → OK
+	}
+	return nil
+}
+
+// Close переводит открытый PR в статус CLOSED, если его версия всё ещё равна expectedVersion,
+// и снимает текущие назначения ревьюеров на момент closedAt: закрытый PR не ждёт ревью.
+// В той же транзакции пишет событие pull_request.closed в outbox.
+// Идемпотентен: если уже CLOSED — не ошибка.
+func (r *PullRequestRepo) Close(ctx context.Context, id string, closedAt time.Time, expectedVersion int) error {
+	return r.transition(ctx, id, domain.PROpen, domain.PRClosed, closedAt, expectedVersion)
+}
+
+// Reopen возвращает закрытый PR в статус OPEN, если его версия всё ещё равна expectedVersion,
+// и заново назначает тех же ревьюеров с момента reopenedAt.
+// В той же транзакции пишет событие pull_request.reopened в outbox.
+// Идемпотентен: если PR уже OPEN — не ошибка.
+func (r *PullRequestRepo) Reopen(ctx context.Context, id string, reopenedAt time.Time, expectedVersion int) error {
+	return r.transition(ctx, id, domain.PRClosed, domain.PROpen, reopenedAt, expectedVersion)
+}
+
+// transition переводит PR между статусами OPEN и CLOSED
+func (r *PullRequestRepo) transition(ctx context.Context, id string, from, to domain.PRStatus, at time.Time, expectedVersion int) error {
+	notUpdated := false
+	err := withTx(ctx, r.db, func(tx *sql.Tx) error {
+		var (
+			name, authorID string
+			createdAt      time.Time
+			version        int
+		)
+		err := tx.QueryRowContext(ctx, `
+			UPDATE pull_requests
+			SET status = ?1, version = version + 1
+			WHERE id = ?2 AND status = ?3 AND version = ?4
+			RETURNING name, author_id, created_at, version
+		`, string(to), id, string(from), expectedVersion).Scan(&name, &authorID, &createdAt, &version)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				notUpdated = true
+				return nil
+			}
+			return err
+		}
+
+		reviewers, err := loadReviewers(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		eventType := domain.EventPRReopened
+		oldReviewers, newReviewers := []string(nil), reviewers
+		if to == domain.PRClosed {
+			eventType = domain.EventPRClosed
+			oldReviewers, newReviewers = newReviewers, oldReviewers
+		}
+		if err := syncReviewAssignments(ctx, tx, id, oldReviewers, newReviewers, at); err != nil {
+			return err
+		}
+
+		pr, err := domain.RestorePullRequest(id, name, authorID, to, reviewers, createdAt, nil, version)
+		if err != nil {
+			return err
+		}
+		event, err := domain.NewPullRequestEvent(eventType, pr, "", "")
+		if err != nil {
+			return err
+		}
+		return insertEvent(ctx, tx, event)
+	})
+	if err != nil || !notUpdated {
+		return err
+	}
+
+	var status string
+	err = r.db.QueryRowContext(ctx, "SELECT status FROM pull_requests WHERE id = ?1", id).Scan(&status)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrPRNotFound
+		}
+		return err
+	}
+	return transitionError(domain.PRStatus(status), from, to)
+}
+
+// transitionError объясняет, почему PR в статусе status не перешёл из from в to
+func transitionError(status, from, to domain.PRStatus) error {
+	switch status {
+	case to:
+		return nil
+	case from:
+		return domain.ErrPRVersionConflict
+	case domain.PRMerged:
+		return domain.ErrPRAlreadyMerged
+	default:
+		return domain.ErrPRClosed
+	}
+}
+
+// diffReviewers находит снятого и нового ревьюера при замене одного на другого.
+func diffReviewers(oldReviewers, newReviewers []string) (removed, added string) {
+	for _, o := range oldReviewers {
+		if !containsString(newReviewers, o) {
+			removed = o
+			break
+		}
+	}
+	for _, n := range newReviewers {
+		if !containsString(oldReviewers, n) {
+			added = n
+			break
+		}
+	}
+	return removed, added
+}
+
+func containsString(slice []string, s string) bool {
+	for _, v := range slice {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// versionConflictOrNotFound различает отсутствие PR и конфликт версий после неудачного UPDATE.
+func (r *PullRequestRepo) versionConflictOrNotFound(ctx context.Context, id string) error {
+	exists, err := r.PRExists(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return domain.ErrPRNotFound
+	}
+	return domain.ErrPRVersionConflict
+}
+
+// GetByReviewer возвращает все PR, где назначен reviewerID.
+func (r *PullRequestRepo) GetByReviewer(ctx context.Context, reviewerID string) ([]domain.PullRequest, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT pr.id, pr.name, pr.author_id, pr.status, `+assignedReviewers+`, pr.created_at, pr.merged_at, pr.version
+		FROM pull_requests pr
+		WHERE pr.id IN (SELECT pull_request_id FROM pull_request_reviewers WHERE reviewer_id = ?1)
+	`, reviewerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prs []domain.PullRequest
+	for rows.Next() {
+		pr, err := scanPullRequest(rows)
+		if err != nil {
+			return nil, err
+		}
+		prs = append(prs, *pr)
+	}
+	return prs, rows.Err()
+}
+
+func scanPullRequest(row interface{ Scan(dest ...any) error }) (*domain.PullRequest, error) {
+	var (
+		id, name, authorID, status, reviewersJSON string
+		createdAt                                 time.Time
+		mergedAt                                  *time.Time
+		version                                   int
+	)
+	if err := row.Scan(&id, &name, &authorID, &status, &reviewersJSON, &createdAt, &mergedAt, &version); err != nil {
+		return nil, err
+	}
+	var reviewers []string
+	if err := json.Unmarshal([]byte(reviewersJSON), &reviewers); err != nil {
+		return nil, err
+	}
+	return domain.RestorePullRequest(id, name, authorID, domain.PRStatus(status), reviewers, createdAt, mergedAt, version)
+}
+
+// loadReviewers возвращает ревьюеров PR в порядке назначения
+func loadReviewers(ctx context.Context, q querier, prID string) ([]string, error) {
+	rows, err := q.QueryContext(ctx,
+		"SELECT reviewer_id FROM pull_request_reviewers WHERE pull_request_id = ?1 ORDER BY position",
+		prID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reviewers []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		reviewers = append(reviewers, id)
+	}
+	return reviewers, rows.Err()
+}
+
+// saveReviewers заменяет состав ревьюеров PR, сохраняя порядок
+func saveReviewers(ctx context.Context, tx *sql.Tx, prID string, reviewers []string) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM pull_request_reviewers WHERE pull_request_id = ?1", prID); err != nil {
+		return err
+	}
+	for i, id := range reviewers {
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO pull_request_reviewers (pull_request_id, reviewer_id, position) VALUES (?1, ?2, ?3)",
+			prID, id, i,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+// assignmentsWithDeadline — назначения с командой автора PR и сроком первого действия по SLA команды.
+// ?1 — SLA по умолчанию в секундах для команд без своего SLA. Интервалов в SQLite нет, поэтому
+// время сравнивается в секундах Unix (due_epoch), а точный срок due_at считается в Go.
+const assignmentsWithDeadline = `
+	WITH assignments AS (
+		SELECT ra.pull_request_id, pr.name AS pull_request_name, pr.status, pr.merged_at,
+		       ra.reviewer_id, ra.assigned_at, ra.first_action_at, ra.unassigned_at,
+		       COALESCE((SELECT tm.team_name FROM team_members tm WHERE tm.user_id = pr.author_id LIMIT 1), '') AS team_name
+		FROM review_assignments ra
+		JOIN pull_requests pr ON pr.id = ra.pull_request_id
+		WHERE %s
+	), deadlines AS (
+		SELECT a.*, COALESCE(s.first_review_seconds, ?1) AS sla_seconds,
+		       unixepoch(a.assigned_at, 'subsec') + COALESCE(s.first_review_seconds, ?1) AS due_epoch
+		FROM assignments a
+		LEFT JOIN team_review_slas s ON s.team_name = a.team_name
+	)
+`
+
+// ReviewSLARepo хранит SLA команд и строит отчёты по назначениям ревьюеров
+type ReviewSLARepo struct {
+	db *sql.DB
+}
+
+func NewReviewSLARepo(db *sql.DB) *ReviewSLARepo {
+	return &ReviewSLARepo{db: db}
+}
+
+// SaveReviewSLA создаёт или заменяет SLA команды
+func (r *ReviewSLARepo) SaveReviewSLA(ctx context.Context, sla *domain.ReviewSLA) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO team_review_slas (team_name, first_review_seconds, updated_at)
+		VALUES (?1, ?2, ?3)
+		ON CONFLICT (team_name) DO UPDATE SET
+			first_review_seconds = excluded.first_review_seconds,
+			updated_at = excluded.updated_at
+	`, sla.TeamName(), int64(sla.FirstReviewWithin()/time.Second), timestamp(time.Now()))
+	return err
+}
+
+// ListSLAViolations возвращает текущие назначения на OPEN PR без первого действия,
+// срок которых наступает до dueBefore, начиная с самых просроченных.
+// teamName == "" — по всем командам.
+func (r *ReviewSLARepo) ListSLAViolations(ctx context.Context, teamName string, now, dueBefore time.Time,
+	defaultSLA time.Duration) ([]domain.SLAViolation, error) {
+	query := fmt.Sprintf(assignmentsWithDeadline,
+		"pr.status = 'OPEN' AND ra.first_action_at IS NULL AND ra.unassigned_at IS NULL") + `
+		SELECT pull_request_id, pull_request_name, team_name, reviewer_id, assigned_at, sla_seconds
+		FROM deadlines
+		WHERE due_epoch < unixepoch(?2, 'subsec') AND (?3 = '' OR team_name = ?3)
+		ORDER BY due_epoch, pull_request_id, reviewer_id
+	`
+	rows, err := r.db.QueryContext(ctx, query, int64(defaultSLA/time.Second), timestamp(dueBefore), teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var violations []domain.SLAViolation
+	for rows.Next() {
+		var (
+			v          domain.SLAViolation
+			assignedAt dbTime
+			slaSeconds int64
+		)
+		if err := rows.Scan(&v.PullRequestID, &v.PullRequestName, &v.Team, &v.ReviewerID, &assignedAt, &slaSeconds); err != nil {
+			return nil, err
+		}
+		v.AssignedAt = assignedAt.Time
+		v.DueAt = v.AssignedAt.Add(time.Duration(slaSeconds) * time.Second)
+		v.Status = domain.SLAAtRisk
+		if !v.DueAt.After(now) {
+			v.Status = domain.SLABreached
+		}
+		violations = append(violations, v)
+	}
+	return violations, rows.Err()
+}
+
+// SLAReport считает нарушения по командам и ревьюерам для назначений, сделанных в [from, to).
+// Назначение нарушено, если первое действие (а без него — снятие ревьюера, мерж PR или текущий момент)
+// наступило позже срока.
+func (r *ReviewSLARepo) SLAReport(ctx context.Context, teamName string, from, to, now time.Time,
+	defaultSLA time.Duration) (*domain.SLAReport, error) {
+	query := fmt.Sprintf(assignmentsWithDeadline, "ra.assigned_at >= ?2 AND ra.assigned_at < ?3") + `
+		, outcomes AS (
+			SELECT team_name, reviewer_id,
+			       unixepoch(COALESCE(first_action_at, unassigned_at, merged_at, ?4), 'subsec') > due_epoch AS breached,
+			       first_action_at IS NULL AND unassigned_at IS NULL AND merged_at IS NULL
+			           AND due_epoch > unixepoch(?4, 'subsec') AS in_progress
+			FROM deadlines
+			WHERE ?5 = '' OR team_name = ?5
+		)
+		SELECT 1 AS by_team, team_name AS key,
+		       COUNT(*), COUNT(*) FILTER (WHERE breached), COUNT(*) FILTER (WHERE in_progress)
+		FROM outcomes
+		GROUP BY team_name
+		UNION ALL
+		SELECT 0, reviewer_id,
+		       COUNT(*), COUNT(*) FILTER (WHERE breached), COUNT(*) FILTER (WHERE in_progress)
+		FROM outcomes
+		GROUP BY reviewer_id
+		ORDER BY by_team DESC, key
+	`
+	rows, err := r.db.QueryContext(ctx, query, int64(defaultSLA/time.Second),
+		timestamp(from), timestamp(to), timestamp(now), teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	report := &domain.SLAReport{From: from, To: to}
+	for rows.Next() {
+		var (
+			byTeam bool
+			stats  domain.SLABreachStats
+		)
+		if err := rows.Scan(&byTeam, &stats.Key, &stats.Assignments, &stats.Breached, &stats.InProgress); err != nil {
+			return nil, err
+		}
+		if byTeam {
+			report.Teams = append(report.Teams, stats)
+		} else {
+			report.Reviewers = append(report.Reviewers, stats)
+		}
+	}
+	return report, rows.Err()
+}
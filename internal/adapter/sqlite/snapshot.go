@@ -0,0 +1,429 @@
+package sqlite
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+// columnKind — тип колонки в JSON снимка
+type columnKind int
+
+const (
+	columnText columnKind = iota
+	columnInt
+	columnBool
+	columnTime
+	// columnJSON — JSON, хранящийся текстом (payload, event_types)
+	columnJSON
+	// columnReviewers — assigned_reviewers из Postgres: строки таблицы pull_request_reviewers
+	columnReviewers
+)
+
+type snapshotColumn struct {
+	name string
+	kind columnKind
+}
+
+// snapshotTable — таблица снимка. Имена, колонки и порядок строк те же, что в PostgreSQL,
+// поэтому снимки переносятся между хранилищами. Очереди доставки (вебхуки, уведомления,
+// запросы ревью в VCS) и ключи идемпотентности в снимок не входят: это состояние конкретного окружения.
+type snapshotTable struct {
+	name    string
+	orderBy string
+	columns []snapshotColumn
+	// serial — у таблицы AUTOINCREMENT id, после восстановления сдвигаем sqlite_sequence
+	serial bool
+}
+
+// snapshotTables перечислены в порядке внешних ключей: восстановление идёт в том же порядке
+var snapshotTables = []snapshotTable{
+	{name: "teams", orderBy: "name", columns: []snapshotColumn{{"name", columnText}}},
+	{name: "users", orderBy: "id", columns: []snapshotColumn{
+		{"id", columnText}, {"username", columnText}, {"is_active", columnBool},
+		{"email", columnText}, {"email_digest", columnBool},
+	}},
+	{name: "team_members", orderBy: "team_name, user_id", columns: []snapshotColumn{
+		{"team_name", columnText}, {"user_id", columnText},
+	}},
+	{name: "user_identities", orderBy: "provider, external_id", columns: []snapshotColumn{
+		{"provider", columnText}, {"external_id", columnText}, {"user_id", columnText}, {"created_at", columnTime},
+	}},
+	{name: "team_review_policies", orderBy: "team_name", columns: []snapshotColumn{
+		{"team_name", columnText}, {"remind_after_seconds", columnInt}, {"escalate_after_seconds", columnInt},
+		{"escalation", columnText}, {"lead_user_id", columnText}, {"updated_at", columnTime},
+	}},
+	{name: "team_review_slas", orderBy: "team_name", columns: []snapshotColumn{
+		{"team_name", columnText}, {"first_review_seconds", columnInt}, {"updated_at", columnTime},
+	}},
+	{name: "pull_requests", orderBy: "id", columns: []snapshotColumn{
+		{"id", columnText}, {"name", columnText}, {"author_id", columnText}, {"status", columnText},
+		{"assigned_reviewers", columnReviewers}, {"created_at", columnTime}, {"merged_at", columnTime},
+		{"version", columnInt},
+	}},
+	{name: "review_assignments", orderBy: "id", serial: true, columns: []snapshotColumn{
+		{"id", columnInt}, {"pull_request_id", columnText}, {"reviewer_id", columnText},
+		{"assigned_at", columnTime}, {"first_action_at", columnTime}, {"unassigned_at", columnTime},
+	}},
+	{name: "stale_pr_actions", orderBy: "pull_request_id, stage", columns: []snapshotColumn{
+		{"pull_request_id", columnText}, {"stage", columnText}, {"created_at", columnTime},
+	}},
+	{name: "webhook_subscriptions", orderBy: "id", serial: true, columns: []snapshotColumn{
+		{"id", columnInt}, {"url", columnText}, {"secret", columnText}, {"event_types", columnJSON},
+		{"is_active", columnBool}, {"created_at", columnTime},
+	}},
+	{name: "outbox", orderBy: "id", serial: true, columns: []snapshotColumn{
+		{"id", columnInt}, {"event_type", columnText}, {"aggregate_id", columnText}, {"payload", columnJSON},
+		{"created_at", columnTime}, {"published_at", columnTime}, {"attempts", columnInt},
+		{"next_attempt_at", columnTime}, {"last_error", columnText},
+	}},
+}
+
+// snapshotQueueTables — очереди доставки и ключи идемпотентности, которые в Postgres очищает
+// тот же TRUNCATE, что и таблицы снимка: сохранённые ответы ссылаются на прежние данные
+var snapshotQueueTables = []string{"webhook_deliveries", "vcs_review_requests", "notifications", "email_digest_items", "idempotency_keys"}
+
+// snapshotTimeLayout — формат времени row_to_json в Postgres
+const snapshotTimeLayout = "2006-01-02T15:04:05.999999"
+
+type SnapshotRepo struct {
+	db *sql.DB
+}
+
+func NewSnapshotRepo(db *sql.DB) *SnapshotRepo {
+	return &SnapshotRepo{db: db}
+}
+
+// ExportSnapshot передаёт в emit все строки снимка. Строки читаются в одной транзакции,
+// поэтому снимок согласован даже при параллельной записи, а emit вызывается уже после неё:
+// транзакция держит единственную блокировку записи, и медленный клиент не должен её задерживать.
+func (r *SnapshotRepo) ExportSnapshot(ctx context.Context, emit func(row domain.SnapshotRow) error) error {
+	var rows []domain.SnapshotRow
+	err := withTx(ctx, r.db, func(tx *sql.Tx) error {
+		for _, t := range snapshotTables {
+			tableRows, err := exportTable(ctx, tx, t)
+			if err != nil {
+				return fmt.Errorf("export %s: %w", t.name, err)
+			}
+			rows = append(rows, tableRows...)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		if err := emit(row); err != nil {
+			return fmt.Errorf("export %s: %w", row.Table, err)
+		}
+	}
+	return nil
+}
+
+func exportTable(ctx context.Context, tx *sql.Tx, t snapshotTable) ([]domain.SnapshotRow, error) {
+	columns := make([]string, len(t.columns))
+	for i, c := range t.columns {
+		columns[i] = c.name
+		if c.kind == columnReviewers {
+			columns[i] = `(SELECT json_group_array(rv.reviewer_id ORDER BY rv.position)
+				FROM pull_request_reviewers rv WHERE rv.pull_request_id = t.id)`
+		}
+	}
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf("SELECT %s FROM %s t ORDER BY %s",
+		strings.Join(columns, ", "), t.name, t.orderBy))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []domain.SnapshotRow
+	for rows.Next() {
+		values := make([]any, len(t.columns))
+		for i, c := range t.columns {
+			switch c.kind {
+			case columnInt:
+				values[i] = new(sql.NullInt64)
+			case columnBool:
+				values[i] = new(sql.NullBool)
+			case columnTime:
+				values[i] = new(dbTime)
+			default:
+				values[i] = new(sql.NullString)
+			}
+		}
+		if err := rows.Scan(values...); err != nil {
+			return nil, err
+		}
+		data, err := encodeSnapshotRow(t.columns, values)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, domain.SnapshotRow{Table: t.name, Data: data})
+	}
+	return result, rows.Err()
+}
+
+// encodeSnapshotRow собирает JSON-объект строки с колонками в порядке схемы, как row_to_json
+func encodeSnapshotRow(columns []snapshotColumn, values []any) (json.RawMessage, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, c := range columns {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(c.name)
+		buf.Write(name)
+		buf.WriteByte(':')
+
+		var value any
+		switch v := values[i].(type) {
+		case *sql.NullInt64:
+			if v.Valid {
+				value = v.Int64
+			}
+		case *sql.NullBool:
+			if v.Valid {
+				value = v.Bool
+			}
+		case *dbTime:
+			if v.Valid {
+				value = v.Time.Format(snapshotTimeLayout)
+			}
+		case *sql.NullString:
+			if v.Valid {
+				value = v.String
+				if c.kind == columnJSON || c.kind == columnReviewers {
+					value = json.RawMessage(v.String)
+				}
+			}
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(data)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// RestoreSnapshot загружает строки из next (до io.EOF) в одной транзакции. Без force
+// возвращает domain.ErrDatabaseNotEmpty, если в базе уже есть данные; с force сначала их удаляет
+// вместе с очередями доставки и ключами идемпотентности.
+// Восстановленные события outbox помечаются опубликованными, чтобы не разослать их повторно.
+func (r *SnapshotRepo) RestoreSnapshot(ctx context.Context, force bool, next func() (*domain.SnapshotRow, error)) (map[string]int, error) {
+	counts := make(map[string]int)
+	err := withTx(ctx, r.db, func(tx *sql.Tx) error {
+		if force {
+			if err := deleteSnapshotTables(ctx, tx); err != nil {
+				return err
+			}
+		} else {
+			empty, err := snapshotTablesEmpty(ctx, tx)
+			if err != nil {
+				return err
+			}
+			if !empty {
+				return domain.ErrDatabaseNotEmpty
+			}
+		}
+
+		position := 0
+		for {
+			row, err := next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return err
+			}
+
+			index := snapshotTableIndex(row.Table)
+			if index < 0 {
+				return fmt.Errorf("%w: unknown table %q", domain.ErrInvalidSnapshot, row.Table)
+			}
+			if index < position {
+				return fmt.Errorf("%w: table %q is out of order", domain.ErrInvalidSnapshot, row.Table)
+			}
+			position = index
+
+			if err := restoreRow(ctx, tx, snapshotTables[index], row.Data); err != nil {
+				return mapSnapshotError(row.Table, counts[row.Table]+1, err)
+			}
+			counts[row.Table]++
+		}
+
+		if _, err := tx.ExecContext(ctx, "UPDATE outbox SET published_at = ?1 WHERE published_at IS NULL", timestamp(time.Now())); err != nil {
+			return err
+		}
+		if err := indexOutboxUsers(ctx, tx); err != nil {
+			return err
+		}
+		for _, t := range snapshotTables {
+			if !t.serial {
+				continue
+			}
+			query := fmt.Sprintf(
+				"UPDATE sqlite_sequence SET seq = (SELECT COALESCE(MAX(id), 0) FROM %[1]s) WHERE name = '%[1]s'",
+				t.name,
+			)
+			if _, err := tx.ExecContext(ctx, query); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// snapshotFormatError — значение в снимке не соответствует типу колонки
+type snapshotFormatError struct {
+	column string
+	err    error
+}
+
+func (e *snapshotFormatError) Error() string {
+	return fmt.Sprintf("column %s: %v", e.column, e.err)
+}
+
+// restoreRow вставляет строку снимка. Как и json_populate_record, отсутствующие колонки
+// считаются NULL, а лишние ключи игнорируются.
+func restoreRow(ctx context.Context, tx *sql.Tx, t snapshotTable, data json.RawMessage) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return &snapshotFormatError{column: "*", err: err}
+	}
+
+	var (
+		columns      []string
+		placeholders []string
+		args         []any
+		reviewers    []string
+	)
+	for _, c := range t.columns {
+		raw, ok := fields[c.name]
+		if c.kind == columnReviewers {
+			if ok {
+				if err := json.Unmarshal(raw, &reviewers); err != nil {
+					return &snapshotFormatError{column: c.name, err: err}
+				}
+			}
+			continue
+		}
+		value, err := decodeSnapshotValue(c.kind, raw, ok)
+		if err != nil {
+			return &snapshotFormatError{column: c.name, err: err}
+		}
+		columns = append(columns, c.name)
+		args = append(args, value)
+		placeholders = append(placeholders, fmt.Sprintf("?%d", len(args)))
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		t.name, strings.Join(columns, ", "), strings.Join(placeholders, ", "))
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+	if len(reviewers) == 0 {
+		return nil
+	}
+	var prID string
+	if err := json.Unmarshal(fields["id"], &prID); err != nil {
+		return &snapshotFormatError{column: "id", err: err}
+	}
+	return saveReviewers(ctx, tx, prID, reviewers)
+}
+
+func decodeSnapshotValue(kind columnKind, raw json.RawMessage, present bool) (any, error) {
+	if !present || string(raw) == "null" {
+		return nil, nil
+	}
+	switch kind {
+	case columnInt:
+		var v int64
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case columnBool:
+		var v bool
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case columnTime:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, err
+		}
+		for _, layout := range []string{"2006-01-02T15:04:05", time.RFC3339Nano} {
+			if v, err := time.Parse(layout, s); err == nil {
+				return timestamp(v), nil
+			}
+		}
+		return nil, fmt.Errorf("invalid timestamp %q", s)
+	case columnJSON:
+		return string(raw), nil
+	default:
+		var v string
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	}
+}
+
+// deleteSnapshotTables очищает очереди доставки, ключи идемпотентности и таблицы снимка в обратном порядке внешних ключей
+func deleteSnapshotTables(ctx context.Context, tx *sql.Tx) error {
+	for _, name := range snapshotQueueTables {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM "+name); err != nil {
+			return err
+		}
+	}
+	for i := len(snapshotTables) - 1; i >= 0; i-- {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM "+snapshotTables[i].name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func snapshotTablesEmpty(ctx context.Context, tx *sql.Tx) (bool, error) {
+	for _, t := range snapshotTables {
+		var exists bool
+		if err := tx.QueryRowContext(ctx, fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s)", t.name)).Scan(&exists); err != nil {
+			return false, err
+		}
+		if exists {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func snapshotTableIndex(name string) int {
+	for i, t := range snapshotTables {
+		if t.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// mapSnapshotError превращает ошибки данных (формат значений, ограничения) в domain.ErrInvalidSnapshot
+func mapSnapshotError(table string, row int, err error) error {
+	var formatErr *snapshotFormatError
+	if errors.As(err, &formatErr) {
+		return fmt.Errorf("%w: %s row %d: %s", domain.ErrInvalidSnapshot, table, row, formatErr.Error())
+	}
+	if isDataError(err) {
+		return fmt.Errorf("%w: %s row %d: %s", domain.ErrInvalidSnapshot, table, row, err.Error())
+	}
+	return err
+}
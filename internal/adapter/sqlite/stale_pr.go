@@ -0,0 +1,112 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+// StalePullRequestRepo находит долго открытые PR и фиксирует выполненные по ним этапы
+type StalePullRequestRepo struct {
+	db *sql.DB
+}
+
+func NewStalePullRequestRepo(db *sql.DB) *StalePullRequestRepo {
+	return &StalePullRequestRepo{db: db}
+}
+
+// ListStalePullRequests возвращает OPEN PR, которым на момент now пора напомнить или эскалировать,
+// начиная с самых старых. Пороги берутся из политики команды автора, а для команд без своей
+// политики — из defaults, поэтому в пачку limit не попадают PR, которым ещё рано.
+func (r *StalePullRequestRepo) ListStalePullRequests(ctx context.Context, now time.Time, defaults *domain.ReviewPolicy, limit int) ([]domain.StalePullRequest, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		WITH candidates AS (
+			SELECT pr.id, pr.name, pr.author_id, pr.status, `+assignedReviewers+` AS assigned_reviewers, pr.created_at, pr.version,
+			       COALESCE((SELECT tm.team_name FROM team_members tm WHERE tm.user_id = pr.author_id LIMIT 1), '') AS team_name,
+			       CASE
+			           WHEN EXISTS (SELECT 1 FROM stale_pr_actions a WHERE a.pull_request_id = pr.id AND a.stage = 'ESCALATED') THEN 'ESCALATED'
+			           WHEN EXISTS (SELECT 1 FROM stale_pr_actions a WHERE a.pull_request_id = pr.id AND a.stage = 'REMINDED') THEN 'REMINDED'
+			           ELSE ''
+			       END AS stage
+			FROM pull_requests pr
+			WHERE pr.status = 'OPEN'
+		), policies AS (
+			SELECT c.*,
+			       COALESCE(p.remind_after_seconds, ?2) AS remind_after_seconds,
+			       COALESCE(p.escalate_after_seconds, ?3) AS escalate_after_seconds,
+			       COALESCE(p.escalation, ?4) AS escalation,
+			       CASE WHEN p.team_name IS NULL THEN ?5 ELSE COALESCE(p.lead_user_id, '') END AS lead_user_id
+			FROM candidates c
+			LEFT JOIN team_review_policies p ON p.team_name = c.team_name
+		)
+		SELECT id, name, author_id, status, assigned_reviewers, created_at, version, team_name, stage,
+		       remind_after_seconds, escalate_after_seconds, escalation, lead_user_id
+		FROM policies
+		WHERE stage <> 'ESCALATED'
+		  AND (unixepoch(created_at, 'subsec') + escalate_after_seconds <= unixepoch(?1, 'subsec')
+		       OR (stage = '' AND unixepoch(created_at, 'subsec') + remind_after_seconds <= unixepoch(?1, 'subsec')))
+		ORDER BY created_at
+		LIMIT ?6
+	`, timestamp(now), int64(defaults.RemindAfter()/time.Second), int64(defaults.EscalateAfter()/time.Second),
+		string(defaults.Escalation()), defaults.LeadID(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []domain.StalePullRequest
+	for rows.Next() {
+		var (
+			id, name, authorID, status, team, stage string
+			escalation, leadID                      string
+			reviewersJSON                           string
+			reviewers                               []string
+			createdAt                               time.Time
+			version                                 int
+			remindSeconds, escalateSeconds          int64
+		)
+		if err := rows.Scan(&id, &name, &authorID, &status, &reviewersJSON, &createdAt, &version, &team, &stage,
+			&remindSeconds, &escalateSeconds, &escalation, &leadID); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(reviewersJSON), &reviewers); err != nil {
+			return nil, err
+		}
+		pr, err := domain.RestorePullRequest(id, name, authorID, domain.PRStatus(status), reviewers, createdAt, nil, version)
+		if err != nil {
+			return nil, err
+		}
+		policy := domain.RestoreReviewPolicy(team,
+			time.Duration(remindSeconds)*time.Second,
+			time.Duration(escalateSeconds)*time.Second,
+			domain.EscalationAction(escalation), leadID)
+		result = append(result, domain.StalePullRequest{PullRequest: pr, Policy: policy, Stage: domain.StaleStage(stage)})
+	}
+	return result, rows.Err()
+}
+
+// RecordStaleStage отмечает этап для PR и в той же транзакции пишет событие в outbox.
+// Возвращает false, если этап уже был выполнен.
+func (r *StalePullRequestRepo) RecordStaleStage(ctx context.Context, prID string, stage domain.StaleStage, event domain.Event) (bool, error) {
+	recorded := false
+	err := withTx(ctx, r.db, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO stale_pr_actions (pull_request_id, stage, created_at)
+			VALUES (?1, ?2, ?3)
+			ON CONFLICT (pull_request_id, stage) DO NOTHING
+		`, prID, string(stage), timestamp(time.Now()))
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		if err != nil || n == 0 {
+			return err
+		}
+		recorded = true
+		return insertEvent(ctx, tx, event)
+	})
+	return recorded, err
+}
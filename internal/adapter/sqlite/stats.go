@@ -0,0 +1,227 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+// authorInTeam — условие «автор PR состоит в команде ?1»; пустое имя — все команды
+const authorInTeam = `(?1 = '' OR pr.author_id IN (SELECT tm.user_id FROM team_members tm WHERE tm.team_name = ?1))`
+
+// percentileCont — аналог percentile_cont(?N) по CTE ranked (seconds, next, i, n):
+// линейная интерполяция между соседними значениями, отсортированными по возрастанию
+const percentileCont = `(SELECT seconds + (COALESCE(next, seconds) - seconds) * (?%[1]d * (n - 1) - i)
+	FROM ranked WHERE i = CAST(?%[1]d * (n - 1) AS INTEGER))`
+
+// StatsRepo строит статистику по PR и назначениям ревьюеров
+type StatsRepo struct {
+	db *sql.DB
+}
+
+func NewStatsRepo(db *sql.DB) *StatsRepo {
+	return &StatsRepo{db: db}
+}
+
+// GetReviewerStats возвращает количество OPEN PR на каждого ревьюера.
+func (r *StatsRepo) GetReviewerStats(ctx context.Context, teamName string) (map[string]int, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT rv.reviewer_id, COUNT(*)
+		FROM pull_request_reviewers rv
+		JOIN pull_requests pr ON pr.id = rv.pull_request_id
+		WHERE pr.status = 'OPEN' AND `+authorInTeam+`
+		GROUP BY rv.reviewer_id
+	`, teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make(map[string]int)
+	for rows.Next() {
+		var reviewer string
+		var count int
+		if err := rows.Scan(&reviewer, &count); err != nil {
+			return nil, err
+		}
+		stats[reviewer] = count
+	}
+	return stats, rows.Err()
+}
+
+// GetTimeToMerge считает медиану и 90-й перцентиль времени до мержа для PR, смерженных за период
+func (r *StatsRepo) GetTimeToMerge(ctx context.Context, f domain.StatsFilter) (domain.TimeToMerge, error) {
+	var (
+		result      domain.TimeToMerge
+		median, p90 sql.NullFloat64
+	)
+	err := r.db.QueryRowContext(ctx, `
+		WITH durations AS (
+			SELECT unixepoch(pr.merged_at, 'subsec') - unixepoch(pr.created_at, 'subsec') AS seconds
+			FROM pull_requests pr
+			WHERE pr.merged_at >= ?2 AND pr.merged_at < ?3 AND `+authorInTeam+`
+		), ranked AS (
+			SELECT seconds,
+			       LEAD(seconds) OVER (ORDER BY seconds) AS next,
+			       ROW_NUMBER() OVER (ORDER BY seconds) - 1 AS i,
+			       COUNT(*) OVER () AS n
+			FROM durations
+		)
+		SELECT (SELECT COUNT(*) FROM durations), `+fmt.Sprintf(percentileCont, 4)+`, `+fmt.Sprintf(percentileCont, 5),
+		f.TeamName, timestamp(f.From), timestamp(f.To), 0.5, 0.9,
+	).Scan(&result.Merged, &median, &p90)
+	if err != nil {
+		return result, err
+	}
+	result.Median = secondsToDuration(median.Float64)
+	result.P90 = secondsToDuration(p90.Float64)
+	return result, nil
+}
+
+// GetThroughput возвращает число созданных и смерженных PR по периодам f.Bucket
+func (r *StatsRepo) GetThroughput(ctx context.Context, f domain.StatsFilter) ([]domain.ThroughputPoint, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT period, SUM(created), SUM(merged)
+		FROM (
+			SELECT `+dateTrunc("pr.created_at")+` AS period, 1 AS created, 0 AS merged
+			FROM pull_requests pr
+			WHERE pr.created_at >= ?2 AND pr.created_at < ?3 AND `+authorInTeam+`
+			UNION ALL
+			SELECT `+dateTrunc("pr.merged_at")+`, 0, 1
+			FROM pull_requests pr
+			WHERE pr.merged_at >= ?2 AND pr.merged_at < ?3 AND `+authorInTeam+`
+		) AS events
+		GROUP BY period
+		ORDER BY period
+	`, f.TeamName, timestamp(f.From), timestamp(f.To), string(f.Bucket))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var points []domain.ThroughputPoint
+	for rows.Next() {
+		var (
+			p      domain.ThroughputPoint
+			period string
+		)
+		if err := rows.Scan(&period, &p.Created, &p.Merged); err != nil {
+			return nil, err
+		}
+		if p.PeriodStart, err = time.Parse(time.DateOnly, period); err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+	return points, rows.Err()
+}
+
+// dateTrunc — аналог date_trunc(?4, column) в UTC; неделя начинается с понедельника
+func dateTrunc(column string) string {
+	return fmt.Sprintf(`CASE ?4
+		WHEN 'day' THEN date(%[1]s)
+		WHEN 'month' THEN date(%[1]s, 'start of month')
+		ELSE date(%[1]s, '-6 days', 'weekday 1')
+	END`, column)
+}
+
+// GetReviewerLoad возвращает назначения, первые действия и замены каждого ревьюера за период
+func (r *StatsRepo) GetReviewerLoad(ctx context.Context, f domain.StatsFilter) ([]domain.ReviewerLoad, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT ra.reviewer_id,
+		       COUNT(*) FILTER (WHERE ra.assigned_at >= ?2 AND ra.assigned_at < ?3),
+		       COUNT(*) FILTER (WHERE ra.first_action_at >= ?2 AND ra.first_action_at < ?3),
+		       COUNT(*) FILTER (WHERE ra.unassigned_at >= ?2 AND ra.unassigned_at < ?3)
+		FROM review_assignments ra
+		JOIN pull_requests pr ON pr.id = ra.pull_request_id
+		WHERE ((ra.assigned_at >= ?2 AND ra.assigned_at < ?3)
+		    OR (ra.first_action_at >= ?2 AND ra.first_action_at < ?3)
+		    OR (ra.unassigned_at >= ?2 AND ra.unassigned_at < ?3))
+		  AND `+authorInTeam+`
+		GROUP BY ra.reviewer_id
+		ORDER BY ra.reviewer_id
+	`, f.TeamName, timestamp(f.From), timestamp(f.To))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var load []domain.ReviewerLoad
+	for rows.Next() {
+		var l domain.ReviewerLoad
+		if err := rows.Scan(&l.UserID, &l.Assigned, &l.Reviewed, &l.ReassignedAway); err != nil {
+			return nil, err
+		}
+		load = append(load, l)
+	}
+	return load, rows.Err()
+}
+
+// GetAuthorReviewerMatrix возвращает, сколько раз каждый ревьюер назначался на PR каждого автора за период
+func (r *StatsRepo) GetAuthorReviewerMatrix(ctx context.Context, f domain.StatsFilter) ([]domain.AuthorReviewerPair, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT pr.author_id, ra.reviewer_id, COUNT(*)
+		FROM review_assignments ra
+		JOIN pull_requests pr ON pr.id = ra.pull_request_id
+		WHERE ra.assigned_at >= ?2 AND ra.assigned_at < ?3 AND `+authorInTeam+`
+		GROUP BY pr.author_id, ra.reviewer_id
+		ORDER BY pr.author_id, ra.reviewer_id
+	`, f.TeamName, timestamp(f.From), timestamp(f.To))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var matrix []domain.AuthorReviewerPair
+	for rows.Next() {
+		var p domain.AuthorReviewerPair
+		if err := rows.Scan(&p.AuthorID, &p.ReviewerID, &p.Count); err != nil {
+			return nil, err
+		}
+		matrix = append(matrix, p)
+	}
+	return matrix, rows.Err()
+}
+
+// GetReviewSnapshot возвращает текущее число OPEN PR, назначений по командам и замен ревьюеров.
+// Автор без команды попадает в команду с пустым именем.
+func (r *StatsRepo) GetReviewSnapshot(ctx context.Context) (*domain.ReviewSnapshot, error) {
+	snapshot := &domain.ReviewSnapshot{OpenAssignmentsByTeam: make(map[string]int)}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT (SELECT COUNT(*) FROM pull_requests WHERE status = 'OPEN'),
+		       (SELECT COUNT(*) FROM review_assignments WHERE unassigned_at IS NOT NULL)
+	`).Scan(&snapshot.OpenPullRequests, &snapshot.Reassignments)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT COALESCE((SELECT tm.team_name FROM team_members tm WHERE tm.user_id = pr.author_id LIMIT 1), ''),
+		       COUNT(rv.reviewer_id)
+		FROM pull_requests pr
+		LEFT JOIN pull_request_reviewers rv ON rv.pull_request_id = pr.id
+		WHERE pr.status = 'OPEN'
+		GROUP BY 1
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var team string
+		var count int
+		if err := rows.Scan(&team, &count); err != nil {
+			return nil, err
+		}
+		snapshot.OpenAssignmentsByTeam[team] = count
+	}
+	return snapshot, rows.Err()
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second)).Round(time.Second)
+}
@@ -0,0 +1,232 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+type TeamRepo struct {
+	db *sql.DB
+}
+
+func NewTeamRepo(db *sql.DB) *TeamRepo {
+	return &TeamRepo{db: db}
+}
+
+func (r *TeamRepo) CreateTeam(ctx context.Context, teamName string, members []domain.User) error {
+	return r.saveTeam(ctx, teamName, members)
+}
+
+// SaveTeam сохраняет команду, создаёт/обновляет участников и переносит их в команду.
+// Всё выполняется в одной транзакции вместе с событиями смены активности.
+func (r *TeamRepo) SaveTeam(ctx context.Context, team *domain.Team) error {
+	return r.saveTeam(ctx, team.Name(), team.Members())
+}
+
+func (r *TeamRepo) saveTeam(ctx context.Context, teamName string, members []domain.User) error {
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		return saveTeamTx(ctx, tx, teamName, members)
+	})
+}
+
+// ImportTeams сохраняет все команды в одной транзакции: при ошибке не применяется ни одна
+func (r *TeamRepo) ImportTeams(ctx context.Context, teams []domain.Team) error {
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		for _, team := range teams {
+			if err := saveTeamTx(ctx, tx, team.Name(), team.Members()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListMemberships возвращает всех пользователей с их командами, включая не состоящих ни в одной
+func (r *TeamRepo) ListMemberships(ctx context.Context) ([]domain.Membership, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT u.id, u.username, u.is_active, COALESCE(tm.team_name, '')
+		FROM users u
+		LEFT JOIN team_members tm ON tm.user_id = u.id
+		ORDER BY u.id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var memberships []domain.Membership
+	for rows.Next() {
+		var id, username, teamName string
+		var isActive bool
+		if err := rows.Scan(&id, &username, &isActive, &teamName); err != nil {
+			return nil, err
+		}
+		user, err := domain.NewUser(id, username, isActive)
+		if err != nil {
+			return nil, err
+		}
+		memberships = append(memberships, domain.Membership{User: *user, TeamName: teamName})
+	}
+	return memberships, rows.Err()
+}
+
+// ReconcileTeams в одной транзакции сохраняет команды из желаемого состояния и убирает из команд
+// пользователей removed. Активность removed сохраняется как передана: так выключаются пропавшие из файла.
+func (r *TeamRepo) ReconcileTeams(ctx context.Context, teams []domain.Team, removed []domain.User) error {
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		for _, team := range teams {
+			if err := saveTeamTx(ctx, tx, team.Name(), team.Members()); err != nil {
+				return err
+			}
+		}
+		for _, u := range removed {
+			if err := updateUserTx(ctx, tx, &u); err != nil {
+				return err
+			}
+			if _, err := tx.ExecContext(ctx, "DELETE FROM team_members WHERE user_id = ?1", u.ID()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// TeamExists проверяет наличие команды, в том числе оставшейся без участников
+func (r *TeamRepo) TeamExists(ctx context.Context, teamName string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM teams WHERE name = ?1)", teamName).Scan(&exists)
+	return exists, err
+}
+
+func saveTeamTx(ctx context.Context, tx *sql.Tx, teamName string, members []domain.User) error {
+	// 1. Создаём команду
+	_, err := tx.ExecContext(ctx, "INSERT INTO teams (name) VALUES (?1) ON CONFLICT (name) DO NOTHING", teamName)
+	if err != nil {
+		return err
+	}
+
+	// 2. Обрабатываем пользователей
+	for _, u := range members {
+		err := updateUserTx(ctx, tx, &u)
+		if errors.Is(err, domain.ErrUserNotFound) {
+			// Создаём
+			err = createUser(ctx, tx, &u)
+		}
+		if err != nil {
+			return err
+		}
+
+		// 3. Переназначаем команду
+		_, err = tx.ExecContext(ctx, "DELETE FROM team_members WHERE user_id = ?1", u.ID())
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, "INSERT INTO team_members (team_name, user_id) VALUES (?1, ?2)", teamName, u.ID())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *TeamRepo) FindTeamByName(ctx context.Context, teamName string) (*domain.Team, error) {
+	query := `
+		SELECT u.id, u.username, u.is_active
+		FROM users u
+		JOIN team_members tm ON u.id = tm.user_id
+		WHERE tm.team_name = ?1
+	`
+	rows, err := r.db.QueryContext(ctx, query, teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []domain.User
+	for rows.Next() {
+		var id, username string
+		var isActive bool
+		if err := rows.Scan(&id, &username, &isActive); err != nil {
+			return nil, err
+		}
+		user, err := domain.NewUser(id, username, isActive)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, *user)
+	}
+
+	if len(users) == 0 {
+		return nil, domain.ErrTeamNotFound
+	}
+
+	return domain.NewTeam(teamName, users)
+}
+
+func (r *TeamRepo) GetUserByID(ctx context.Context, id string) (*domain.User, error) {
+	return getUserByID(ctx, r.db, id)
+}
+
+func (r *TeamRepo) CreateUser(ctx context.Context, u *domain.User) error {
+	return createUser(ctx, r.db, u)
+}
+
+func (r *TeamRepo) UpdateUser(ctx context.Context, u *domain.User) error {
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		return updateUserTx(ctx, tx, u)
+	})
+}
+
+func (r *TeamRepo) GetTeamByName(ctx context.Context, teamName string) (*domain.Team, error) {
+	users, err := r.GetUsersInTeam(ctx, teamName, false)
+	if err != nil {
+		return nil, err
+	}
+
+	team, err := domain.NewTeam(teamName, users)
+	if err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+func (r *TeamRepo) GetUsersInTeam(ctx context.Context, teamName string, onlyActive bool) ([]domain.User, error) {
+	query := `
+		SELECT u.id, u.username, u.is_active
+		FROM users u
+		JOIN team_members tm ON u.id = tm.user_id
+		WHERE tm.team_name = ?1
+	`
+	args := []interface{}{teamName}
+	if onlyActive {
+		query += " AND u.is_active = true"
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []domain.User
+	for rows.Next() {
+		var id, username string
+		var isActive bool
+		if err := rows.Scan(&id, &username, &isActive); err != nil {
+			return nil, err
+		}
+		user, err := domain.NewUser(id, username, isActive)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, *user)
+	}
+	if len(users) == 0 {
+		return nil, domain.ErrTeamNotFound
+	}
+	return users, nil
+}
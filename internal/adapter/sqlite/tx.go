@@ -0,0 +1,86 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// querier — общее подмножество *sql.DB и *sql.Tx, чтобы хелперы работали в транзакции и без неё.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// withTx выполняет fn в транзакции: commit при успехе, rollback при ошибке.
+// Соединения открываются с _txlock=immediate, поэтому транзакция сразу берёт блокировку записи
+// и транзакции выполняются по очереди — это замена SELECT ... FOR UPDATE.
+func withTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// timestampLayout — формат колонок TIMESTAMP: UTC с микросекундами фиксированной ширины,
+// поэтому сравнение строк в SQL совпадает с хронологическим. Время передаётся в запросы
+// только через timestamp: драйвер записал бы time.Time со смещением зоны.
+const timestampLayout = "2006-01-02 15:04:05.000000"
+
+func timestamp(t time.Time) string {
+	return t.UTC().Format(timestampLayout)
+}
+
+func nullTimestamp(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return timestamp(*t)
+}
+
+// dbTime — приёмник Scan для времени. Колонки с объявленным типом TIMESTAMP драйвер отдаёт
+// как time.Time, а вычисляемые (MIN, COALESCE, колонки CTE) — строкой; dbTime принимает оба.
+type dbTime struct {
+	Time  time.Time
+	Valid bool
+}
+
+func (t *dbTime) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*t = dbTime{}
+		return nil
+	case time.Time:
+		*t = dbTime{Time: v.UTC(), Valid: true}
+		return nil
+	case string:
+		return t.parse(v)
+	case []byte:
+		return t.parse(string(v))
+	default:
+		return fmt.Errorf("unsupported timestamp type %T", src)
+	}
+}
+
+func (t *dbTime) parse(s string) error {
+	v, err := time.ParseInLocation(timestampLayout, s, time.UTC)
+	if err != nil {
+		return err
+	}
+	*t = dbTime{Time: v, Valid: true}
+	return nil
+}
+
+func (t dbTime) ptr() *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
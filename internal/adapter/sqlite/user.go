@@ -0,0 +1,199 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+// Реализует интерфейс из usecase
+type UserRepo struct {
+	db *sql.DB
+}
+
+func NewUserRepo(db *sql.DB) *UserRepo {
+	return &UserRepo{db: db}
+}
+
+func (r *UserRepo) GetUserByID(ctx context.Context, id string) (*domain.User, error) {
+	return getUserByID(ctx, r.db, id)
+}
+
+// UpdateUserEmail сохраняет настройки почтовых уведомлений пользователя
+func (r *UserRepo) UpdateUserEmail(ctx context.Context, u *domain.User) error {
+	var email *string
+	if u.Email() != "" {
+		e := u.Email()
+		email = &e
+	}
+	res, err := r.db.ExecContext(ctx,
+		"UPDATE users SET email = ?1, email_digest = ?2 WHERE id = ?3",
+		email, u.EmailDigest(), u.ID(),
+	)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *UserRepo) CreateUser(ctx context.Context, u *domain.User) error {
+	return createUser(ctx, r.db, u)
+}
+
+// UpdateUser обновляет пользователя. Если изменилась активность,
+// в той же транзакции пишет user.activated / user.deactivated в outbox.
+func (r *UserRepo) UpdateUser(ctx context.Context, u *domain.User) error {
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		return updateUserTx(ctx, tx, u)
+	})
+}
+
+// GetUsersInTeam возвращает domain.User
+func (r *UserRepo) GetUsersInTeam(ctx context.Context, teamName string, onlyActive bool) ([]domain.User, error) {
+	query := `
+		SELECT u.id, u.username, u.is_active
+		FROM users u
+		JOIN team_members tm ON u.id = tm.user_id
+		WHERE tm.team_name = ?1
+	`
+	args := []interface{}{teamName}
+	if onlyActive {
+		query += " AND u.is_active = true"
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []domain.User
+	for rows.Next() {
+		var id, username string
+		var isActive bool
+		if err := rows.Scan(&id, &username, &isActive); err != nil {
+			return nil, err
+		}
+		user, err := domain.NewUser(id, username, isActive)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, *user)
+	}
+
+	if len(users) == 0 {
+		return nil, domain.ErrTeamNotFound
+	}
+	return users, nil
+}
+
+func (r *UserRepo) GetTeamByUser(ctx context.Context, userID string) (string, error) {
+	var teamName string
+	err := r.db.QueryRowContext(ctx,
+		"SELECT team_name FROM team_members WHERE user_id = ?1",
+		userID,
+	).Scan(&teamName)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", domain.ErrUserNotFound
+		}
+		return "", err
+	}
+	return teamName, nil
+}
+
+// updateUserTx обновляет пользователя внутри транзакции и записывает событие смены активности.
+func updateUserTx(ctx context.Context, tx *sql.Tx, u *domain.User) error {
+	var wasActive bool
+	err := tx.QueryRowContext(ctx, "SELECT is_active FROM users WHERE id = ?1", u.ID()).Scan(&wasActive)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrUserNotFound
+		}
+		return err
+	}
+
+	query := `UPDATE users SET username = ?1, is_active = ?2 WHERE id = ?3`
+	if _, err := tx.ExecContext(ctx, query, u.Username(), u.IsActive(), u.ID()); err != nil {
+		return err
+	}
+
+	if wasActive == u.IsActive() {
+		return nil
+	}
+	event, err := domain.NewUserActivityEvent(u)
+	if err != nil {
+		return err
+	}
+	return insertEvent(ctx, tx, event)
+}
+
+func createUser(ctx context.Context, q querier, u *domain.User) error {
+	query := `INSERT INTO users (id, username, is_active) VALUES (?1, ?2, ?3)`
+	_, err := q.ExecContext(ctx, query, u.ID(), u.Username(), u.IsActive())
+	return err
+}
+
+// ResolveUser находит пользователя по логину VCS: сначала по таблице user_identities,
+// затем по совпадению с id или username без учёта регистра.
+func (r *UserRepo) ResolveUser(ctx context.Context, provider domain.VCSProvider, login string) (*domain.User, error) {
+	user, err := lookupIdentityUser(ctx, r.db, string(provider), login)
+	if !errors.Is(err, domain.ErrIdentityNotFound) {
+		return user, err
+	}
+
+	var id, username string
+	var isActive bool
+	err = r.db.QueryRowContext(ctx, `
+		SELECT id, username, is_active
+		FROM users
+		WHERE id = ?1 OR lower(username) = lower(?1)
+		ORDER BY (id = ?1) DESC, id
+		LIMIT 1
+	`, login).Scan(&id, &username, &isActive)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return domain.NewUser(id, username, isActive)
+}
+
+// getUserByID загружает пользователя вместе с настройками почтовых уведомлений
+func getUserByID(ctx context.Context, q querier, id string) (*domain.User, error) {
+	var (
+		idStr, username, email string
+		isActive, emailDigest  bool
+	)
+	err := q.QueryRowContext(ctx,
+		"SELECT id, username, is_active, COALESCE(email, ''), email_digest FROM users WHERE id = ?1",
+		id,
+	).Scan(&idStr, &username, &isActive, &email, &emailDigest)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	// domain.User создаётся через конструктор
+	user, err := domain.NewUser(idStr, username, isActive)
+	if err != nil {
+		return nil, err // не должно происходить, но на всякий случай
+	}
+	if err := user.SetEmail(email); err != nil {
+		return nil, err
+	}
+	user.SetEmailDigest(emailDigest)
+	return user, nil
+}
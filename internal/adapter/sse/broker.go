@@ -0,0 +1,125 @@
+package sse
+
+import (
+	"errors"
+	"log/slog"
+	"sync"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+// subscriptionBuffer — сколько событий может ждать медленного клиента.
+// При переполнении подписка закрывается: клиент переподключится с Last-Event-ID.
+const subscriptionBuffer = 64
+
+var ErrBrokerClosed = errors.New("event broker is closed")
+
+// Subscription — живая подписка одного клиента на ленту пользователя
+type Subscription struct {
+	userID string
+	events chan domain.UserEvent
+	done   chan struct{}
+	once   sync.Once
+}
+
+// Events — канал событий пользователя
+func (s *Subscription) Events() <-chan domain.UserEvent {
+	return s.events
+}
+
+// Done закрывается, когда брокер завершил подписку (остановка сервера или переполнение буфера)
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+func (s *Subscription) stop() {
+	s.once.Do(func() { close(s.done) })
+}
+
+// Broker раздаёт события пользователей подключённым SSE-клиентам этого процесса
+type Broker struct {
+	mu     sync.Mutex
+	subs   map[string]map[*Subscription]struct{}
+	closed bool
+}
+
+func NewBroker() *Broker {
+	return &Broker{subs: make(map[string]map[*Subscription]struct{})}
+}
+
+// Subscribe подписывает клиента на события пользователя
+func (b *Broker) Subscribe(userID string) (*Subscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil, ErrBrokerClosed
+	}
+
+	sub := &Subscription{
+		userID: userID,
+		events: make(chan domain.UserEvent, subscriptionBuffer),
+		done:   make(chan struct{}),
+	}
+	if b.subs[userID] == nil {
+		b.subs[userID] = make(map[*Subscription]struct{})
+	}
+	b.subs[userID][sub] = struct{}{}
+	return sub, nil
+}
+
+// Unsubscribe удаляет подписку (клиент отключился)
+func (b *Broker) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.remove(sub)
+	sub.stop()
+}
+
+// Publish раскладывает доменное событие по лентам пользователей и отправляет подписчикам
+func (b *Broker) Publish(event domain.Event) {
+	userEvents, err := domain.UserEventsFromEvent(event)
+	if err != nil {
+		slog.Error("SSE: failed to decode event", "sequence", event.Sequence, "error", err)
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, ue := range userEvents {
+		for sub := range b.subs[ue.UserID] {
+			select {
+			case sub.events <- ue:
+			default:
+				// Клиент не успевает читать — отключаем, он догонит через Last-Event-ID
+				b.remove(sub)
+				sub.stop()
+			}
+		}
+	}
+}
+
+// Close завершает все подписки и запрещает новые. Вызывается при остановке сервера,
+// чтобы открытые SSE-соединения не блокировали srv.Shutdown.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for userID, subs := range b.subs {
+		for sub := range subs {
+			sub.stop()
+		}
+		delete(b.subs, userID)
+	}
+}
+
+func (b *Broker) remove(sub *Subscription) {
+	subs := b.subs[sub.userID]
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(b.subs, sub.userID)
+	}
+}
@@ -0,0 +1,718 @@
+// Package storetest — общий контрактный набор тестов для хранилищ. Postgres задаёт эталон:
+// остальные адаптеры должны возвращать те же доменные ошибки и те же результаты запросов,
+// иначе юзкейсы ведут себя по-разному в зависимости от db.url. На Postgres набор запускается,
+// если задан TEST_DB_URL.
+package storetest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+// Репозитории, которые проверяет набор; методы совпадают с адаптерами хранилищ
+type (
+	TeamRepository interface {
+		SaveTeam(ctx context.Context, team *domain.Team) error
+		TeamExists(ctx context.Context, teamName string) (bool, error)
+		FindTeamByName(ctx context.Context, teamName string) (*domain.Team, error)
+		GetTeamByName(ctx context.Context, teamName string) (*domain.Team, error)
+	}
+
+	UserRepository interface {
+		GetUserByID(ctx context.Context, id string) (*domain.User, error)
+		UpdateUser(ctx context.Context, u *domain.User) error
+		UpdateUserEmail(ctx context.Context, u *domain.User) error
+		GetUsersInTeam(ctx context.Context, teamName string, onlyActive bool) ([]domain.User, error)
+		GetTeamByUser(ctx context.Context, userID string) (string, error)
+		ResolveUser(ctx context.Context, provider domain.VCSProvider, login string) (*domain.User, error)
+	}
+
+	PullRequestRepository interface {
+		Save(ctx context.Context, pr *domain.PullRequest) error
+		GetByID(ctx context.Context, id string) (*domain.PullRequest, error)
+		PRExists(ctx context.Context, id string) (bool, error)
+		UpdateReviewers(ctx context.Context, id string, reviewers []string, expectedVersion int) error
+		Merge(ctx context.Context, id string, mergedAt time.Time, expectedVersion int) error
+		Close(ctx context.Context, id string, closedAt time.Time, expectedVersion int) error
+		Reopen(ctx context.Context, id string, reopenedAt time.Time, expectedVersion int) error
+		GetByReviewer(ctx context.Context, reviewerID string) ([]domain.PullRequest, error)
+		RecordReviewAction(ctx context.Context, prID, reviewerID string, at time.Time) (time.Time, bool, error)
+	}
+
+	StatsRepository interface {
+		GetReviewerStats(ctx context.Context, teamName string) (map[string]int, error)
+		GetReviewerLoad(ctx context.Context, f domain.StatsFilter) ([]domain.ReviewerLoad, error)
+		GetReviewSnapshot(ctx context.Context) (*domain.ReviewSnapshot, error)
+	}
+
+	IdentityRepository interface {
+		CreateIdentity(ctx context.Context, identity *domain.UserIdentity) error
+		DeleteIdentity(ctx context.Context, provider, externalID string) error
+		LookupUser(ctx context.Context, provider, externalID string) (*domain.User, error)
+	}
+
+	ReviewPolicyRepository interface {
+		SaveReviewPolicy(ctx context.Context, p *domain.ReviewPolicy) error
+		GetReviewPolicy(ctx context.Context, teamName string) (*domain.ReviewPolicy, error)
+	}
+
+	StalePullRequestRepository interface {
+		ListStalePullRequests(ctx context.Context, now time.Time, defaults *domain.ReviewPolicy, limit int) ([]domain.StalePullRequest, error)
+		RecordStaleStage(ctx context.Context, prID string, stage domain.StaleStage, event domain.Event) (bool, error)
+	}
+
+	ReviewSLARepository interface {
+		SaveReviewSLA(ctx context.Context, sla *domain.ReviewSLA) error
+		ListSLAViolations(ctx context.Context, teamName string, now, dueBefore time.Time, defaultSLA time.Duration) ([]domain.SLAViolation, error)
+		SLAReport(ctx context.Context, teamName string, from, to, now time.Time, defaultSLA time.Duration) (*domain.SLAReport, error)
+	}
+
+	IdempotencyRepository interface {
+		domain.IdempotencyStore
+	}
+
+	OutboxRepository interface {
+		ListUserEventsAfter(ctx context.Context, userID string, afterSequence int64, limit int) ([]domain.Event, error)
+	}
+
+	SnapshotRepository interface {
+		ExportSnapshot(ctx context.Context, emit func(row domain.SnapshotRow) error) error
+		RestoreSnapshot(ctx context.Context, force bool, next func() (*domain.SnapshotRow, error)) (map[string]int, error)
+	}
+)
+
+// Repos — репозитории одного пустого хранилища
+type Repos struct {
+	Teams        TeamRepository
+	Users        UserRepository
+	PullRequests PullRequestRepository
+	Stats        StatsRepository
+	Identities   IdentityRepository
+	Policies     ReviewPolicyRepository
+	StalePRs     StalePullRequestRepository
+	SLAs         ReviewSLARepository
+	Idempotency  IdempotencyRepository
+	Outbox       OutboxRepository
+	Snapshots    SnapshotRepository
+}
+
+// Run прогоняет набор. open вызывается для каждой проверки и должен возвращать новое пустое хранилище.
+func Run(t *testing.T, open func(t *testing.T) Repos) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, open func(t *testing.T) Repos)
+	}{
+		{"UserNotFound", testUserNotFound},
+		{"TeamNotFound", testTeamNotFound},
+		{"PRNotFound", testPRNotFound},
+		{"VersionConflict", testVersionConflict},
+		{"StatusTransitions", testStatusTransitions},
+		{"Identities", testIdentities},
+		{"ReviewPolicies", testReviewPolicies},
+		{"Reassign", testReassign},
+		{"ReviewAction", testReviewAction},
+		{"ReviewerStats", testReviewerStats},
+		{"ReviewSnapshot", testReviewSnapshot},
+		{"SLA", testSLA},
+		{"StalePullRequests", testStalePullRequests},
+		{"SnapshotRestore", testSnapshotRestore},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) { tt.run(t, open) })
+	}
+}
+
+// base — момент, от которого отсчитывается время в проверках: в прошлом и без долей секунды,
+// чтобы переживать колонки TIMESTAMP любого хранилища без потери точности
+var base = time.Now().UTC().Add(-30 * 24 * time.Hour).Truncate(time.Second)
+
+func user(t *testing.T, id string, active bool) domain.User {
+	t.Helper()
+	u, err := domain.NewUser(id, "name-"+id, active)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return *u
+}
+
+// seedTeam создаёт команду из активных пользователей с указанными ID
+func seedTeam(t *testing.T, r Repos, teamName string, userIDs ...string) {
+	t.Helper()
+	members := make([]domain.User, 0, len(userIDs))
+	for _, id := range userIDs {
+		members = append(members, user(t, id, true))
+	}
+	team, err := domain.NewTeam(teamName, members)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Teams.SaveTeam(context.Background(), team); err != nil {
+		t.Fatalf("SaveTeam(%s): %v", teamName, err)
+	}
+}
+
+// seedPR сохраняет OPEN PR, созданный в createdAt
+func seedPR(t *testing.T, r Repos, id, authorID string, createdAt time.Time, reviewers ...string) *domain.PullRequest {
+	t.Helper()
+	pr, err := domain.RestorePullRequest(id, "PR "+id, authorID, domain.PROpen, reviewers, createdAt, nil, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.PullRequests.Save(context.Background(), pr); err != nil {
+		t.Fatalf("Save(%s): %v", id, err)
+	}
+	return pr
+}
+
+func getPR(t *testing.T, r Repos, id string) *domain.PullRequest {
+	t.Helper()
+	pr, err := r.PullRequests.GetByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("GetByID(%s): %v", id, err)
+	}
+	return pr
+}
+
+func wantErr(t *testing.T, op string, err, want error) {
+	t.Helper()
+	if !errors.Is(err, want) {
+		t.Errorf("%s: error = %v, want %v", op, err, want)
+	}
+}
+
+func testUserNotFound(t *testing.T, open func(t *testing.T) Repos) {
+	r := open(t)
+	ctx := context.Background()
+	ghost := user(t, "ghost", true)
+
+	_, err := r.Users.GetUserByID(ctx, "ghost")
+	wantErr(t, "GetUserByID", err, domain.ErrUserNotFound)
+	wantErr(t, "UpdateUser", r.Users.UpdateUser(ctx, &ghost), domain.ErrUserNotFound)
+	wantErr(t, "UpdateUserEmail", r.Users.UpdateUserEmail(ctx, &ghost), domain.ErrUserNotFound)
+	_, err = r.Users.GetTeamByUser(ctx, "ghost")
+	wantErr(t, "GetTeamByUser", err, domain.ErrUserNotFound)
+	_, err = r.Users.ResolveUser(ctx, domain.VCSGitHub, "ghost")
+	wantErr(t, "ResolveUser", err, domain.ErrUserNotFound)
+}
+
+func testTeamNotFound(t *testing.T, open func(t *testing.T) Repos) {
+	r := open(t)
+	ctx := context.Background()
+
+	exists, err := r.Teams.TeamExists(ctx, "nobody")
+	if err != nil || exists {
+		t.Errorf("TeamExists = %v, %v, want false", exists, err)
+	}
+	_, err = r.Teams.FindTeamByName(ctx, "nobody")
+	wantErr(t, "FindTeamByName", err, domain.ErrTeamNotFound)
+	_, err = r.Teams.GetTeamByName(ctx, "nobody")
+	wantErr(t, "GetTeamByName", err, domain.ErrTeamNotFound)
+	_, err = r.Users.GetUsersInTeam(ctx, "nobody", false)
+	wantErr(t, "GetUsersInTeam", err, domain.ErrTeamNotFound)
+
+	// Команда, где никто не активен, для выбора ревьюеров пуста
+	team, err := domain.NewTeam("idle", []domain.User{user(t, "i1", false)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Teams.SaveTeam(ctx, team); err != nil {
+		t.Fatal(err)
+	}
+	_, err = r.Users.GetUsersInTeam(ctx, "idle", true)
+	wantErr(t, "GetUsersInTeam(onlyActive)", err, domain.ErrTeamNotFound)
+	members, err := r.Users.GetUsersInTeam(ctx, "idle", false)
+	if err != nil || len(members) != 1 {
+		t.Errorf("GetUsersInTeam(idle) = %v, %v, want one member", members, err)
+	}
+}
+
+func testPRNotFound(t *testing.T, open func(t *testing.T) Repos) {
+	r := open(t)
+	ctx := context.Background()
+	seedTeam(t, r, "backend", "u1", "u2")
+
+	_, err := r.PullRequests.GetByID(ctx, "missing")
+	wantErr(t, "GetByID", err, domain.ErrPRNotFound)
+	exists, err := r.PullRequests.PRExists(ctx, "missing")
+	if err != nil || exists {
+		t.Errorf("PRExists = %v, %v, want false", exists, err)
+	}
+	wantErr(t, "UpdateReviewers", r.PullRequests.UpdateReviewers(ctx, "missing", []string{"u2"}, 1), domain.ErrPRNotFound)
+	wantErr(t, "Merge", r.PullRequests.Merge(ctx, "missing", base, 1), domain.ErrPRNotFound)
+	wantErr(t, "Close", r.PullRequests.Close(ctx, "missing", base, 1), domain.ErrPRNotFound)
+	wantErr(t, "Reopen", r.PullRequests.Reopen(ctx, "missing", base, 1), domain.ErrPRNotFound)
+
+	seedPR(t, r, "pr-1", "u1", base, "u2")
+	dup, err := domain.RestorePullRequest("pr-1", "again", "u1", domain.PROpen, []string{"u2"}, base, nil, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.PullRequests.Save(ctx, dup); err == nil {
+		t.Error("Save of an existing PR succeeded")
+	}
+}
+
+func testVersionConflict(t *testing.T, open func(t *testing.T) Repos) {
+	r := open(t)
+	ctx := context.Background()
+	seedTeam(t, r, "backend", "u1", "u2", "u3")
+	seedPR(t, r, "pr-1", "u1", base, "u2")
+
+	if err := r.PullRequests.UpdateReviewers(ctx, "pr-1", []string{"u3"}, 1); err != nil {
+		t.Fatalf("UpdateReviewers: %v", err)
+	}
+	if got := getPR(t, r, "pr-1").Version(); got != 2 {
+		t.Fatalf("version after reassign = %d, want 2", got)
+	}
+
+	// Все изменения со старой версией отклоняются, PR не меняется
+	wantErr(t, "UpdateReviewers", r.PullRequests.UpdateReviewers(ctx, "pr-1", []string{"u2"}, 1), domain.ErrPRVersionConflict)
+	wantErr(t, "Merge", r.PullRequests.Merge(ctx, "pr-1", base, 1), domain.ErrPRVersionConflict)
+	wantErr(t, "Close", r.PullRequests.Close(ctx, "pr-1", base, 1), domain.ErrPRVersionConflict)
+	pr := getPR(t, r, "pr-1")
+	if pr.Status() != domain.PROpen || pr.Version() != 2 || !reflect.DeepEqual(pr.AssignedReviewers(), []string{"u3"}) {
+		t.Errorf("PR after conflicts = %s v%d %v, want OPEN v2 [u3]", pr.Status(), pr.Version(), pr.AssignedReviewers())
+	}
+}
+
+func testStatusTransitions(t *testing.T, open func(t *testing.T) Repos) {
+	r := open(t)
+	ctx := context.Background()
+	seedTeam(t, r, "backend", "u1", "u2")
+	seedPR(t, r, "pr-1", "u1", base, "u2")
+	seedPR(t, r, "pr-2", "u1", base, "u2")
+
+	// Закрытие и переоткрытие идемпотентны, мерж закрытого PR запрещён
+	if err := r.PullRequests.Close(ctx, "pr-1", base.Add(time.Hour), 1); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := r.PullRequests.Close(ctx, "pr-1", base.Add(time.Hour), 1); err != nil {
+		t.Errorf("repeated Close: %v", err)
+	}
+	wantErr(t, "Merge(closed)", r.PullRequests.Merge(ctx, "pr-1", base.Add(time.Hour), 2), domain.ErrPRClosed)
+	if err := r.PullRequests.Reopen(ctx, "pr-1", base.Add(2*time.Hour), 2); err != nil {
+		t.Fatalf("Reopen: %v", err)
+	}
+	if err := r.PullRequests.Reopen(ctx, "pr-1", base.Add(2*time.Hour), 2); err != nil {
+		t.Errorf("repeated Reopen: %v", err)
+	}
+	if pr := getPR(t, r, "pr-1"); pr.Status() != domain.PROpen || pr.Version() != 3 {
+		t.Errorf("reopened PR = %s v%d, want OPEN v3", pr.Status(), pr.Version())
+	}
+
+	// Мерж идемпотентен, смерженный PR нельзя ни закрыть, ни переоткрыть
+	mergedAt := base.Add(3 * time.Hour)
+	if err := r.PullRequests.Merge(ctx, "pr-2", mergedAt, 1); err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if err := r.PullRequests.Merge(ctx, "pr-2", mergedAt, 1); err != nil {
+		t.Errorf("repeated Merge: %v", err)
+	}
+	wantErr(t, "Close(merged)", r.PullRequests.Close(ctx, "pr-2", mergedAt, 2), domain.ErrPRAlreadyMerged)
+	wantErr(t, "Reopen(merged)", r.PullRequests.Reopen(ctx, "pr-2", mergedAt, 2), domain.ErrPRAlreadyMerged)
+	pr := getPR(t, r, "pr-2")
+	if pr.Status() != domain.PRMerged || pr.MergedAt() == nil || !pr.MergedAt().Equal(mergedAt) {
+		t.Errorf("merged PR = %s merged at %v, want MERGED at %v", pr.Status(), pr.MergedAt(), mergedAt)
+	}
+}
+
+func testIdentities(t *testing.T, open func(t *testing.T) Repos) {
+	r := open(t)
+	ctx := context.Background()
+	seedTeam(t, r, "backend", "u1")
+
+	identity := domain.RestoreUserIdentity("github", "octocat", "u1", base)
+	if err := r.Identities.CreateIdentity(ctx, identity); err != nil {
+		t.Fatalf("CreateIdentity: %v", err)
+	}
+	wantErr(t, "CreateIdentity(duplicate)", r.Identities.CreateIdentity(ctx, identity), domain.ErrIdentityExists)
+	wantErr(t, "CreateIdentity(unknown user)",
+		r.Identities.CreateIdentity(ctx, domain.RestoreUserIdentity("github", "ghost", "ghost", base)), domain.ErrUserNotFound)
+
+	u, err := r.Identities.LookupUser(ctx, "github", "octocat")
+	if err != nil || u.ID() != "u1" {
+		t.Errorf("LookupUser = %v, %v, want u1", u, err)
+	}
+	u, err = r.Users.ResolveUser(ctx, domain.VCSGitHub, "octocat")
+	if err != nil || u.ID() != "u1" {
+		t.Errorf("ResolveUser = %v, %v, want u1", u, err)
+	}
+
+	if err := r.Identities.DeleteIdentity(ctx, "github", "octocat"); err != nil {
+		t.Fatalf("DeleteIdentity: %v", err)
+	}
+	wantErr(t, "DeleteIdentity(missing)", r.Identities.DeleteIdentity(ctx, "github", "octocat"), domain.ErrIdentityNotFound)
+	_, err = r.Identities.LookupUser(ctx, "github", "octocat")
+	wantErr(t, "LookupUser(missing)", err, domain.ErrIdentityNotFound)
+}
+
+func testReviewPolicies(t *testing.T, open func(t *testing.T) Repos) {
+	r := open(t)
+	ctx := context.Background()
+	seedTeam(t, r, "backend", "u1")
+
+	_, err := r.Policies.GetReviewPolicy(ctx, "backend")
+	wantErr(t, "GetReviewPolicy", err, domain.ErrReviewPolicyNotFound)
+
+	policy := domain.RestoreReviewPolicy("backend", time.Hour, 2*time.Hour, domain.EscalationNotifyLead, "u1")
+	if err := r.Policies.SaveReviewPolicy(ctx, policy); err != nil {
+		t.Fatalf("SaveReviewPolicy: %v", err)
+	}
+	got, err := r.Policies.GetReviewPolicy(ctx, "backend")
+	if err != nil || !reflect.DeepEqual(got, policy) {
+		t.Errorf("GetReviewPolicy = %+v, %v, want %+v", got, err, policy)
+	}
+}
+
+func testReassign(t *testing.T, open func(t *testing.T) Repos) {
+	r := open(t)
+	ctx := context.Background()
+	seedTeam(t, r, "backend", "u1", "u2", "u3", "u4")
+	seedPR(t, r, "pr-1", "u1", base, "u2", "u3")
+
+	if err := r.PullRequests.UpdateReviewers(ctx, "pr-1", []string{"u4", "u3"}, 1); err != nil {
+		t.Fatalf("UpdateReviewers: %v", err)
+	}
+	// Порядок ревьюеров сохраняется: новый встаёт на место заменённого
+	if got := getPR(t, r, "pr-1").AssignedReviewers(); !reflect.DeepEqual(got, []string{"u4", "u3"}) {
+		t.Errorf("reviewers = %v, want [u4 u3]", got)
+	}
+
+	for reviewer, want := range map[string][]string{"u2": nil, "u3": {"pr-1"}, "u4": {"pr-1"}} {
+		prs, err := r.PullRequests.GetByReviewer(ctx, reviewer)
+		if err != nil {
+			t.Fatalf("GetByReviewer(%s): %v", reviewer, err)
+		}
+		if got := prIDs(prs); !reflect.DeepEqual(got, want) {
+			t.Errorf("GetByReviewer(%s) = %v, want %v", reviewer, got, want)
+		}
+	}
+}
+
+func testReviewAction(t *testing.T, open func(t *testing.T) Repos) {
+	r := open(t)
+	ctx := context.Background()
+	seedTeam(t, r, "backend", "u1", "u2", "u3")
+	seedPR(t, r, "pr-1", "u1", base, "u2")
+
+	first := base.Add(time.Hour)
+	at, recorded, err := r.PullRequests.RecordReviewAction(ctx, "pr-1", "u2", first)
+	if err != nil || !recorded || !at.Equal(first) {
+		t.Errorf("RecordReviewAction = %v, %v, %v, want %v, true", at, recorded, err, first)
+	}
+	at, recorded, err = r.PullRequests.RecordReviewAction(ctx, "pr-1", "u2", first.Add(time.Hour))
+	if err != nil || recorded || !at.Equal(first) {
+		t.Errorf("repeated RecordReviewAction = %v, %v, %v, want %v, false", at, recorded, err, first)
+	}
+	_, _, err = r.PullRequests.RecordReviewAction(ctx, "pr-1", "u3", first)
+	wantErr(t, "RecordReviewAction(not assigned)", err, domain.ErrReviewerNotAssigned)
+}
+
+func testReviewerStats(t *testing.T, open func(t *testing.T) Repos) {
+	r := open(t)
+	ctx := context.Background()
+	seedTeam(t, r, "backend", "b1", "b2", "b3")
+	seedTeam(t, r, "frontend", "f1", "f2")
+	seedPR(t, r, "pr-1", "b1", base, "b2", "b3")
+	seedPR(t, r, "pr-2", "b1", base, "b2")
+	seedPR(t, r, "pr-3", "f1", base, "f2", "b2")
+	seedPR(t, r, "pr-4", "f1", base, "f2")
+	if err := r.PullRequests.Merge(ctx, "pr-4", base.Add(time.Hour), 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// Считаются только OPEN PR; фильтр — по команде автора
+	stats, err := r.Stats.GetReviewerStats(ctx, "")
+	if want := map[string]int{"b2": 3, "b3": 1, "f2": 1}; err != nil || !reflect.DeepEqual(stats, want) {
+		t.Errorf("GetReviewerStats(all) = %v, %v, want %v", stats, err, want)
+	}
+	stats, err = r.Stats.GetReviewerStats(ctx, "backend")
+	if want := map[string]int{"b2": 2, "b3": 1}; err != nil || !reflect.DeepEqual(stats, want) {
+		t.Errorf("GetReviewerStats(backend) = %v, %v, want %v", stats, err, want)
+	}
+
+	// Замена ревьюера и первое действие попадают в нагрузку за период
+	if err := r.PullRequests.UpdateReviewers(ctx, "pr-2", []string{"b3"}, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := r.PullRequests.RecordReviewAction(ctx, "pr-1", "b2", base.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	load, err := r.Stats.GetReviewerLoad(ctx, domain.StatsFilter{TeamName: "backend", From: base, To: time.Now().UTC().Add(time.Hour)})
+	want := []domain.ReviewerLoad{
+		{UserID: "b2", Assigned: 2, Reviewed: 1, ReassignedAway: 1},
+		{UserID: "b3", Assigned: 2},
+	}
+	if err != nil || !reflect.DeepEqual(load, want) {
+		t.Errorf("GetReviewerLoad = %+v, %v, want %+v", load, err, want)
+	}
+}
+
+func testReviewSnapshot(t *testing.T, open func(t *testing.T) Repos) {
+	r := open(t)
+	ctx := context.Background()
+	seedTeam(t, r, "backend", "b1", "b2", "b3")
+	seedTeam(t, r, "frontend", "f1", "f2")
+	seedPR(t, r, "pr-1", "b1", base, "b2", "b3")
+	seedPR(t, r, "pr-2", "f1", base, "f2")
+	seedPR(t, r, "pr-3", "f1", base, "f2")
+	if err := r.PullRequests.UpdateReviewers(ctx, "pr-1", []string{"f1", "b3"}, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.PullRequests.Merge(ctx, "pr-3", base.Add(time.Hour), 1); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot, err := r.Stats.GetReviewSnapshot(ctx)
+	want := &domain.ReviewSnapshot{
+		OpenPullRequests:      2,
+		OpenAssignmentsByTeam: map[string]int{"backend": 2, "frontend": 1},
+		Reassignments:         1,
+	}
+	if err != nil || !reflect.DeepEqual(snapshot, want) {
+		t.Errorf("GetReviewSnapshot = %+v, %v, want %+v", snapshot, err, want)
+	}
+}
+
+func testSLA(t *testing.T, open func(t *testing.T) Repos) {
+	r := open(t)
+	ctx := context.Background()
+	seedTeam(t, r, "backend", "b1", "b2", "b3")
+	seedTeam(t, r, "frontend", "f1", "f2", "f3")
+	sla, err := domain.NewReviewSLA("frontend", 4*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.SLAs.SaveReviewSLA(ctx, sla); err != nil {
+		t.Fatalf("SaveReviewSLA: %v", err)
+	}
+
+	now := base.Add(3 * time.Hour)
+	seedPR(t, r, "pr-b", "b1", base, "b2", "b3")
+	seedPR(t, r, "pr-f", "f1", base, "f2", "f3")
+	if _, _, err := r.PullRequests.RecordReviewAction(ctx, "pr-b", "b3", base.Add(30*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := r.PullRequests.RecordReviewAction(ctx, "pr-f", "f3", base.Add(30*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	// У backend SLA по умолчанию (2 часа) уже истёк; у frontend своё SLA истекает через час
+	violations, err := r.SLAs.ListSLAViolations(ctx, "", now, now.Add(2*time.Hour), 2*time.Hour)
+	if err != nil {
+		t.Fatalf("ListSLAViolations: %v", err)
+	}
+	wantViolations := []domain.SLAViolation{
+		{PullRequestID: "pr-b", PullRequestName: "PR pr-b", Team: "backend", ReviewerID: "b2",
+			AssignedAt: base, DueAt: base.Add(2 * time.Hour), Status: domain.SLABreached},
+		{PullRequestID: "pr-f", PullRequestName: "PR pr-f", Team: "frontend", ReviewerID: "f2",
+			AssignedAt: base, DueAt: base.Add(4 * time.Hour), Status: domain.SLAAtRisk},
+	}
+	if len(violations) != len(wantViolations) {
+		t.Fatalf("ListSLAViolations = %+v, want %+v", violations, wantViolations)
+	}
+	for i := range violations {
+		got, want := violations[i], wantViolations[i]
+		if !got.AssignedAt.Equal(want.AssignedAt) || !got.DueAt.Equal(want.DueAt) {
+			t.Errorf("violation %d times = %v..%v, want %v..%v", i, got.AssignedAt, got.DueAt, want.AssignedAt, want.DueAt)
+		}
+		got.AssignedAt, got.DueAt, want.AssignedAt, want.DueAt = time.Time{}, time.Time{}, time.Time{}, time.Time{}
+		if got != want {
+			t.Errorf("violation %d = %+v, want %+v", i, got, want)
+		}
+	}
+	violations, err = r.SLAs.ListSLAViolations(ctx, "frontend", now, now, 2*time.Hour)
+	if err != nil || len(violations) != 0 {
+		t.Errorf("ListSLAViolations(frontend, due now) = %+v, %v, want none", violations, err)
+	}
+
+	report, err := r.SLAs.SLAReport(ctx, "", base, base.Add(time.Hour), now, 2*time.Hour)
+	if err != nil {
+		t.Fatalf("SLAReport: %v", err)
+	}
+	wantTeams := []domain.SLABreachStats{
+		{Key: "backend", Assignments: 2, Breached: 1},
+		{Key: "frontend", Assignments: 2, InProgress: 1},
+	}
+	wantReviewers := []domain.SLABreachStats{
+		{Key: "b2", Assignments: 1, Breached: 1},
+		{Key: "b3", Assignments: 1},
+		{Key: "f2", Assignments: 1, InProgress: 1},
+		{Key: "f3", Assignments: 1},
+	}
+	if !reflect.DeepEqual(report.Teams, wantTeams) || !reflect.DeepEqual(report.Reviewers, wantReviewers) {
+		t.Errorf("SLAReport = %+v / %+v, want %+v / %+v", report.Teams, report.Reviewers, wantTeams, wantReviewers)
+	}
+}
+
+func testStalePullRequests(t *testing.T, open func(t *testing.T) Repos) {
+	r := open(t)
+	ctx := context.Background()
+	seedTeam(t, r, "backend", "b1", "b2")
+	seedTeam(t, r, "frontend", "f1", "f2")
+	policy := domain.RestoreReviewPolicy("frontend", time.Hour, 2*time.Hour, domain.EscalationNotifyLead, "f2")
+	if err := r.Policies.SaveReviewPolicy(ctx, policy); err != nil {
+		t.Fatal(err)
+	}
+	defaults := domain.RestoreReviewPolicy("*", 24*time.Hour, 72*time.Hour, domain.EscalationReassign, "")
+
+	now := base.Add(100 * time.Hour)
+	seedPR(t, r, "b-escalate", "b1", now.Add(-80*time.Hour), "b2")
+	seedPR(t, r, "b-remind", "b1", now.Add(-30*time.Hour), "b2")
+	seedPR(t, r, "b-early", "b1", now.Add(-90*time.Minute), "b2")
+	seedPR(t, r, "f-remind", "f1", now.Add(-90*time.Minute), "f2")
+	seedPR(t, r, "f-reminded", "f1", now.Add(-100*time.Minute), "f2")
+	seedPR(t, r, "f-escalated", "f1", now.Add(-3*time.Hour), "f2")
+	record := func(id string, stage domain.StaleStage) {
+		t.Helper()
+		event, err := domain.NewStalePullRequestEvent(stage, getPR(t, r, id), time.Hour, "", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if recorded, err := r.StalePRs.RecordStaleStage(ctx, id, stage, event); err != nil || !recorded {
+			t.Fatalf("RecordStaleStage(%s, %s) = %v, %v", id, stage, recorded, err)
+		}
+	}
+	record("f-reminded", domain.StaleStageReminded)
+	record("f-escalated", domain.StaleStageEscalated)
+
+	stale, err := r.StalePRs.ListStalePullRequests(ctx, now, defaults, 10)
+	if err != nil {
+		t.Fatalf("ListStalePullRequests: %v", err)
+	}
+	var got []string
+	for _, s := range stale {
+		got = append(got, s.PullRequest.ID()+"/"+s.Policy.TeamName()+"/"+string(s.Policy.NextStage(now.Sub(s.PullRequest.CreatedAt()), s.Stage)))
+	}
+	want := []string{"b-escalate/backend/ESCALATED", "b-remind/backend/REMINDED", "f-remind/frontend/REMINDED"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ListStalePullRequests = %v, want %v", got, want)
+	}
+	for _, s := range stale {
+		if s.PullRequest.ID() == "f-remind" && !reflect.DeepEqual(s.Policy, policy) {
+			t.Errorf("policy of f-remind = %+v, want %+v", s.Policy, policy)
+		}
+	}
+
+	stale, err = r.StalePRs.ListStalePullRequests(ctx, now, defaults, 1)
+	if err != nil || len(stale) != 1 || stale[0].PullRequest.ID() != "b-escalate" {
+		t.Errorf("ListStalePullRequests(limit 1) = %v, %v, want the oldest PR", stale, err)
+	}
+}
+
+func testSnapshotRestore(t *testing.T, open func(t *testing.T) Repos) {
+	src := open(t)
+	ctx := context.Background()
+	seedTeam(t, src, "backend", "u1", "u2", "u3")
+	seedPR(t, src, "pr-1", "u1", base, "u2")
+	seedPR(t, src, "pr-2", "u1", base, "u3")
+	if err := src.PullRequests.UpdateReviewers(ctx, "pr-1", []string{"u3"}, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.PullRequests.Merge(ctx, "pr-2", base.Add(time.Hour), 1); err != nil {
+		t.Fatal(err)
+	}
+	seedPR(t, src, "pr-3", "u1", base)
+	if err := src.PullRequests.Close(ctx, "pr-3", base.Add(time.Hour), 1); err != nil {
+		t.Fatal(err)
+	}
+
+	var rows []domain.SnapshotRow
+	if err := src.Snapshots.ExportSnapshot(ctx, func(row domain.SnapshotRow) error {
+		rows = append(rows, row)
+		return nil
+	}); err != nil {
+		t.Fatalf("ExportSnapshot: %v", err)
+	}
+	reader := func() func() (*domain.SnapshotRow, error) {
+		i := 0
+		return func() (*domain.SnapshotRow, error) {
+			if i == len(rows) {
+				return nil, io.EOF
+			}
+			i++
+			return &rows[i-1], nil
+		}
+	}
+
+	dst := open(t)
+	counts, err := dst.Snapshots.RestoreSnapshot(ctx, false, reader())
+	if err != nil {
+		t.Fatalf("RestoreSnapshot: %v", err)
+	}
+	if counts["pull_requests"] != 3 || counts["users"] != 3 {
+		t.Errorf("restored counts = %v, want 3 pull_requests and 3 users", counts)
+	}
+	for _, id := range []string{"pr-1", "pr-2"} {
+		want, got := getPR(t, src, id), getPR(t, dst, id)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("restored %s = %+v, want %+v", id, got, want)
+		}
+	}
+	prs, err := dst.PullRequests.GetByReviewer(ctx, "u3")
+	if got := prIDs(prs); err != nil || !reflect.DeepEqual(got, []string{"pr-1", "pr-2"}) {
+		t.Errorf("restored GetByReviewer(u3) = %v, %v, want [pr-1 pr-2]", got, err)
+	}
+	// Назначения восстанавливаются вместе с историей замен
+	load, err := dst.Stats.GetReviewerLoad(ctx, domain.StatsFilter{From: base, To: time.Now().UTC().Add(time.Hour)})
+	want := []domain.ReviewerLoad{{UserID: "u2", Assigned: 1, ReassignedAway: 1}, {UserID: "u3", Assigned: 2}}
+	if err != nil || !reflect.DeepEqual(load, want) {
+		t.Errorf("restored GetReviewerLoad = %+v, %v, want %+v", load, err, want)
+	}
+	// Ленты пользователей восстанавливаются по всем типам событий, включая закрытие PR
+	for _, userID := range []string{"u1", "u2", "u3"} {
+		want, got := userEventTypes(t, src, userID), userEventTypes(t, dst, userID)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("restored events of %s = %v, want %v", userID, got, want)
+		}
+	}
+	if got := userEventTypes(t, dst, "u1"); !reflect.DeepEqual(got, []domain.EventType{domain.EventPRMerged, domain.EventPRClosed}) {
+		t.Errorf("restored events of u1 = %v, want merged and closed", got)
+	}
+
+	// В непустое хранилище снимок загружается только с force, и тогда ключи идемпотентности сбрасываются
+	_, err = dst.Snapshots.RestoreSnapshot(ctx, false, reader())
+	wantErr(t, "RestoreSnapshot(not empty)", err, domain.ErrDatabaseNotEmpty)
+	if _, err := dst.Idempotency.Reserve(ctx, "key", "/route", "hash", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dst.Snapshots.RestoreSnapshot(ctx, true, reader()); err != nil {
+		t.Fatalf("RestoreSnapshot(force): %v", err)
+	}
+	record, err := dst.Idempotency.Reserve(ctx, "key", "/route", "hash", time.Hour)
+	if err != nil || record != nil {
+		t.Errorf("Reserve after forced restore = %+v, %v, want a free key", record, err)
+	}
+	if got := getPR(t, dst, "pr-1"); !reflect.DeepEqual(got.AssignedReviewers(), []string{"u3"}) {
+		t.Errorf("pr-1 reviewers after forced restore = %v, want [u3]", got.AssignedReviewers())
+	}
+}
+
+func userEventTypes(t *testing.T, r Repos, userID string) []domain.EventType {
+	t.Helper()
+	events, err := r.Outbox.ListUserEventsAfter(context.Background(), userID, 0, 100)
+	if err != nil {
+		t.Fatalf("ListUserEventsAfter(%s): %v", userID, err)
+	}
+	var types []domain.EventType
+	for _, e := range events {
+		types = append(types, e.Type)
+	}
+	return types
+}
+
+func prIDs(prs []domain.PullRequest) []string {
+	var ids []string
+	for _, pr := range prs {
+		ids = append(ids, pr.ID())
+	}
+	sort.Strings(ids)
+	return ids
+}
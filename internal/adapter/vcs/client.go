@@ -0,0 +1,11 @@
+package vcs
+
+import "context"
+
+// Client — порт к API системы контроля версий для синхронизации ревьюеров на реальном PR
+type Client interface {
+	// RequestReviewers запрашивает ревью у пользователей с указанными логинами
+	RequestReviewers(ctx context.Context, repository string, number int, logins []string) error
+	// RemoveReviewer отзывает запрос ревью
+	RemoveReviewer(ctx context.Context, repository string, number int, login string) error
+}
@@ -0,0 +1,113 @@
+package vcs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/dispatch"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/logger"
+)
+
+// Store — журнал запросов ревью
+type Store interface {
+	// ClaimDueReviewRequests захватывает запросы, время которых пришло, на время lease
+	ClaimDueReviewRequests(ctx context.Context, limit int, lease time.Duration) ([]domain.VCSReviewRequest, error)
+	MarkReviewRequestDone(ctx context.Context, id int64) error
+	// MarkReviewRequestFailed записывает неудачную попытку. nextAttemptAt == nil — попытки исчерпаны
+	MarkReviewRequestFailed(ctx context.Context, id int64, errMsg string, nextAttemptAt *time.Time) error
+}
+
+// IdentityResolver возвращает логин пользователя во внешней системе
+type IdentityResolver interface {
+	ExternalIDForUser(ctx context.Context, provider, userID string) (string, error)
+}
+
+// DefaultConfig возвращает параметры по умолчанию
+func DefaultConfig() dispatch.Config {
+	return dispatch.Config{
+		PollInterval: 2 * time.Second,
+		BatchSize:    50,
+		MaxAttempts:  8,
+		BaseBackoff:  10 * time.Second,
+		MaxBackoff:   time.Hour,
+		Timeout:      10 * time.Second,
+	}
+}
+
+// Dispatcher отправляет запланированные запросы ревью в VCS с повторами по экспоненциальной задержке
+type Dispatcher struct {
+	store      Store
+	identities IdentityResolver
+	clients    map[domain.VCSProvider]Client
+	cfg        dispatch.Config
+}
+
+func NewDispatcher(store Store, identities IdentityResolver, clients map[domain.VCSProvider]Client, cfg dispatch.Config) *Dispatcher {
+	return &Dispatcher{store: store, identities: identities, clients: clients, cfg: cfg}
+}
+
+// Run отправляет запросы до отмены контекста
+func (d *Dispatcher) Run(ctx context.Context) {
+	dispatch.Run(ctx, d.cfg, "VCS review requests", d.store.ClaimDueReviewRequests, d.process)
+}
+
+func (d *Dispatcher) process(ctx context.Context, req domain.VCSReviewRequest) {
+	err := d.send(ctx, req)
+	if err == nil {
+		if err := d.store.MarkReviewRequestDone(ctx, req.ID); err != nil {
+			logger.FromContext(ctx).Error("failed to mark VCS review request as done", "request_id", req.ID, "error", err)
+		}
+		return
+	}
+
+	next := d.cfg.NextAttempt(req.Attempts, err)
+	if next == nil {
+		logger.FromContext(ctx).Warn("VCS review request failed permanently", "request_id", req.ID, "pull_request_id", req.PullRequestID, "error", err)
+	}
+	if err := d.store.MarkReviewRequestFailed(ctx, req.ID, err.Error(), next); err != nil {
+		logger.FromContext(ctx).Error("failed to record VCS review request failure", "request_id", req.ID, "error", err)
+	}
+}
+
+func (d *Dispatcher) send(ctx context.Context, req domain.VCSReviewRequest) error {
+	provider, repository, number, ok := domain.ParseVCSPullRequestID(req.PullRequestID)
+	if !ok {
+		return dispatch.Permanent(fmt.Errorf("pull request %q is not linked to a VCS", req.PullRequestID))
+	}
+	client, ok := d.clients[provider]
+	if !ok {
+		return dispatch.Permanent(fmt.Errorf("no client configured for %s", provider))
+	}
+
+	logins := make([]string, 0, len(req.ReviewerIDs))
+	for _, userID := range req.ReviewerIDs {
+		login, err := d.identities.ExternalIDForUser(ctx, string(provider), userID)
+		if err != nil {
+			if errors.Is(err, domain.ErrIdentityNotFound) {
+				return dispatch.Permanent(fmt.Errorf("user %q has no %s identity", userID, provider))
+			}
+			return err
+		}
+		logins = append(logins, login)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, d.cfg.Timeout)
+	defer cancel()
+
+	switch req.Action {
+	case domain.VCSReviewActionRequest:
+		return client.RequestReviewers(ctx, repository, number, logins)
+	case domain.VCSReviewActionRemove:
+		for _, login := range logins {
+			if err := client.RemoveReviewer(ctx, repository, number, login); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return dispatch.Permanent(fmt.Errorf("unknown action %q", req.Action))
+	}
+}
@@ -0,0 +1,85 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Skorpsrgvch/reviewer-service/pkg/dispatch"
+)
+
+// DefaultAPIURL — адрес GitHub REST API; для GitHub Enterprise или тестового сервера задаётся свой
+const DefaultAPIURL = "https://api.github.com"
+
+// Client — реализация vcs.Client поверх GitHub REST API
+type Client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+func NewClient(baseURL, token string, httpClient *http.Client) *Client {
+	if baseURL == "" {
+		baseURL = DefaultAPIURL
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{baseURL: strings.TrimRight(baseURL, "/"), token: token, http: httpClient}
+}
+
+type reviewersRequest struct {
+	Reviewers []string `json:"reviewers"`
+}
+
+func (c *Client) RequestReviewers(ctx context.Context, repository string, number int, logins []string) error {
+	return c.do(ctx, http.MethodPost, c.reviewersURL(repository, number), reviewersRequest{Reviewers: logins})
+}
+
+func (c *Client) RemoveReviewer(ctx context.Context, repository string, number int, login string) error {
+	return c.do(ctx, http.MethodDelete, c.reviewersURL(repository, number), reviewersRequest{Reviewers: []string{login}})
+}
+
+func (c *Client) reviewersURL(repository string, number int) string {
+	return fmt.Sprintf("%s/repos/%s/pulls/%d/requested_reviewers", c.baseURL, repository, number)
+}
+
+func (c *Client) do(ctx context.Context, method, url string, body any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	err = fmt.Errorf("github %s %s: status %d: %s", method, url, resp.StatusCode, strings.TrimSpace(string(respBody)))
+	// 403 и 429 — обычно rate limit, 5xx — сбой GitHub: повторяем. Остальные 4xx повторять бесполезно
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+		resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return dispatch.Permanent(err)
+	}
+	return err
+}
@@ -0,0 +1,95 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/Skorpsrgvch/reviewer-service/pkg/dispatch"
+)
+
+// recordedRequest — запрос, который получил тестовый сервер GitHub
+type recordedRequest struct {
+	method    string
+	path      string
+	auth      string
+	reviewers []string
+}
+
+// newFakeGitHub поднимает сервер, который отвечает status и записывает запросы
+func newFakeGitHub(t *testing.T, status int) (*Client, *[]recordedRequest) {
+	t.Helper()
+	var requests []recordedRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body reviewersRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode request body: %v", err)
+		}
+		requests = append(requests, recordedRequest{
+			method:    r.Method,
+			path:      r.URL.Path,
+			auth:      r.Header.Get("Authorization"),
+			reviewers: body.Reviewers,
+		})
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"message":"fake"}`))
+	}))
+	t.Cleanup(srv.Close)
+	return NewClient(srv.URL+"/", "tok", srv.Client()), &requests
+}
+
+func TestClientSendsReviewerRequests(t *testing.T) {
+	client, requests := newFakeGitHub(t, http.StatusCreated)
+	ctx := context.Background()
+
+	if err := client.RequestReviewers(ctx, "acme/api", 7, []string{"alice", "bob"}); err != nil {
+		t.Fatalf("RequestReviewers: %v", err)
+	}
+	if err := client.RemoveReviewer(ctx, "acme/api", 7, "alice"); err != nil {
+		t.Fatalf("RemoveReviewer: %v", err)
+	}
+
+	want := []recordedRequest{
+		{method: http.MethodPost, path: "/repos/acme/api/pulls/7/requested_reviewers", auth: "Bearer tok", reviewers: []string{"alice", "bob"}},
+		{method: http.MethodDelete, path: "/repos/acme/api/pulls/7/requested_reviewers", auth: "Bearer tok", reviewers: []string{"alice"}},
+	}
+	if !reflect.DeepEqual(*requests, want) {
+		t.Errorf("requests = %+v, want %+v", *requests, want)
+	}
+}
+
+func TestClientClassifiesErrors(t *testing.T) {
+	tests := []struct {
+		status    int
+		permanent bool
+	}{
+		{http.StatusInternalServerError, false},
+		{http.StatusBadGateway, false},
+		{http.StatusTooManyRequests, false},
+		{http.StatusForbidden, false},
+		{http.StatusNotFound, true},
+		{http.StatusUnprocessableEntity, true},
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			client, _ := newFakeGitHub(t, tt.status)
+			ctx := context.Background()
+
+			for name, call := range map[string]func() error{
+				"RequestReviewers": func() error { return client.RequestReviewers(ctx, "acme/api", 7, []string{"alice"}) },
+				"RemoveReviewer":   func() error { return client.RemoveReviewer(ctx, "acme/api", 7, "alice") },
+			} {
+				err := call()
+				if err == nil {
+					t.Fatalf("%s: want an error for status %d", name, tt.status)
+				}
+				if got := dispatch.IsPermanent(err); got != tt.permanent {
+					t.Errorf("%s: permanent = %v, want %v (%v)", name, got, tt.permanent, err)
+				}
+			}
+		})
+	}
+}
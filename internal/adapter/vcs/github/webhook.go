@@ -0,0 +1,132 @@
+package github
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+const (
+	SignatureHeader = "X-Hub-Signature-256"
+	EventHeader     = "X-GitHub-Event"
+)
+
+// WebhookParser проверяет подпись GitHub-вебхука и разбирает события pull_request и pull_request_review
+type WebhookParser struct {
+	secret []byte
+}
+
+func NewWebhookParser(secret string) *WebhookParser {
+	return &WebhookParser{secret: []byte(secret)}
+}
+
+type pullRequestPayload struct {
+	Action      string `json:"action"`
+	Number      int    `json:"number"`
+	PullRequest struct {
+		Number int    `json:"number"`
+		Title  string `json:"title"`
+		Draft  bool   `json:"draft"`
+		Merged bool   `json:"merged"`
+		User   struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	// Review есть только в событиях pull_request_review
+	Review struct {
+		User struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	} `json:"review"`
+}
+
+// Parse возвращает событие PR или nil, если событие не влияет на ревью
+// (ping, черновики, правки описания и т.п.)
+func (p *WebhookParser) Parse(header http.Header, body []byte) (*domain.VCSPullRequestEvent, error) {
+	if !p.verify(header.Get(SignatureHeader), body) {
+		return nil, domain.ErrInvalidVCSSignature
+	}
+	eventName := header.Get(EventHeader)
+	if eventName != "pull_request" && eventName != "pull_request_review" {
+		return nil, nil
+	}
+
+	var payload pullRequestPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidVCSPayload, err)
+	}
+	// В pull_request_review номер есть только внутри pull_request
+	if payload.Number == 0 {
+		payload.Number = payload.PullRequest.Number
+	}
+	if payload.Repository.FullName == "" || payload.Number == 0 || payload.PullRequest.User.Login == "" {
+		return nil, fmt.Errorf("%w: repository, number and author are required", domain.ErrInvalidVCSPayload)
+	}
+
+	if eventName == "pull_request_review" {
+		if payload.Action != "submitted" || payload.Review.User.Login == "" {
+			return nil, nil
+		}
+		return &domain.VCSPullRequestEvent{
+			Provider:      domain.VCSGitHub,
+			Action:        domain.VCSActionReviewed,
+			Repository:    payload.Repository.FullName,
+			Number:        payload.Number,
+			Title:         payload.PullRequest.Title,
+			AuthorLogin:   payload.PullRequest.User.Login,
+			ReviewerLogin: payload.Review.User.Login,
+		}, nil
+	}
+
+	var action domain.VCSAction
+	switch payload.Action {
+	case "opened", "reopened":
+		// Черновик пойдёт на ревью по событию ready_for_review
+		if payload.PullRequest.Draft {
+			return nil, nil
+		}
+		action = domain.VCSActionOpened
+	case "ready_for_review":
+		action = domain.VCSActionOpened
+	case "closed":
+		action = domain.VCSActionClosed
+		if payload.PullRequest.Merged {
+			action = domain.VCSActionMerged
+		}
+	default:
+		return nil, nil
+	}
+
+	return &domain.VCSPullRequestEvent{
+		Provider:    domain.VCSGitHub,
+		Action:      action,
+		Repository:  payload.Repository.FullName,
+		Number:      payload.Number,
+		Title:       payload.PullRequest.Title,
+		AuthorLogin: payload.PullRequest.User.Login,
+	}, nil
+}
+
+// verify сравнивает подпись "sha256=<hex>" с HMAC-SHA256 тела
+func (p *WebhookParser) verify(signature string, body []byte) bool {
+	got, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return false
+	}
+	gotMAC, err := hex.DecodeString(got)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write(body)
+	return hmac.Equal(gotMAC, mac.Sum(nil))
+}
@@ -0,0 +1,120 @@
+package github
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+const testSecret = "s3cr3t"
+
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
+
+func TestWebhookParserFixtures(t *testing.T) {
+	tests := []struct {
+		fixture string
+		event   string
+		want    *domain.VCSPullRequestEvent
+	}{
+		{
+			fixture: "pull_request_opened.json",
+			event:   "pull_request",
+			want: &domain.VCSPullRequestEvent{Provider: domain.VCSGitHub, Action: domain.VCSActionOpened,
+				Repository: "octo/hello", Number: 12, Title: "Add search", AuthorLogin: "alice"},
+		},
+		{
+			fixture: "pull_request_ready_for_review.json",
+			event:   "pull_request",
+			want: &domain.VCSPullRequestEvent{Provider: domain.VCSGitHub, Action: domain.VCSActionOpened,
+				Repository: "octo/hello", Number: 12, Title: "Add search", AuthorLogin: "alice"},
+		},
+		{
+			fixture: "pull_request_closed_merged.json",
+			event:   "pull_request",
+			want: &domain.VCSPullRequestEvent{Provider: domain.VCSGitHub, Action: domain.VCSActionMerged,
+				Repository: "octo/hello", Number: 12, Title: "Add search", AuthorLogin: "alice"},
+		},
+		{
+			fixture: "pull_request_closed_unmerged.json",
+			event:   "pull_request",
+			want: &domain.VCSPullRequestEvent{Provider: domain.VCSGitHub, Action: domain.VCSActionClosed,
+				Repository: "octo/hello", Number: 12, Title: "Add search", AuthorLogin: "alice"},
+		},
+		{
+			fixture: "pull_request_review_submitted.json",
+			event:   "pull_request_review",
+			want: &domain.VCSPullRequestEvent{Provider: domain.VCSGitHub, Action: domain.VCSActionReviewed,
+				Repository: "octo/hello", Number: 12, Title: "Add search", AuthorLogin: "alice", ReviewerLogin: "bob"},
+		},
+		{
+			// Остальные события GitHub не влияют на ревью
+			fixture: "pull_request_review_submitted.json",
+			event:   "issues",
+		},
+	}
+
+	parser := NewWebhookParser(testSecret)
+	for _, tt := range tests {
+		t.Run(tt.fixture+"/"+tt.event, func(t *testing.T) {
+			body := readFixture(t, tt.fixture)
+			header := http.Header{}
+			header.Set(EventHeader, tt.event)
+			header.Set(SignatureHeader, sign(testSecret, body))
+
+			got, err := parser.Parse(header, body)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Parse = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebhookParserSignature(t *testing.T) {
+	body := readFixture(t, "pull_request_opened.json")
+	tests := []struct {
+		name      string
+		signature string
+	}{
+		{name: "missing", signature: ""},
+		{name: "no prefix", signature: sign(testSecret, body)[len("sha256="):]},
+		{name: "not hex", signature: "sha256=zz"},
+		{name: "other secret", signature: sign("other", body)},
+		{name: "other body", signature: sign(testSecret, append([]byte(" "), body...))},
+	}
+
+	parser := NewWebhookParser(testSecret)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			header.Set(EventHeader, "pull_request")
+			header.Set(SignatureHeader, tt.signature)
+			if _, err := parser.Parse(header, body); !errors.Is(err, domain.ErrInvalidVCSSignature) {
+				t.Fatalf("Parse error = %v, want %v", err, domain.ErrInvalidVCSSignature)
+			}
+		})
+	}
+}
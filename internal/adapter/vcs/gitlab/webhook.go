@@ -0,0 +1,135 @@
+package gitlab
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+const (
+	TokenHeader = "X-Gitlab-Token"
+	EventHeader = "X-Gitlab-Event"
+)
+
+// WebhookParser проверяет секретный токен GitLab и разбирает Merge Request Hook
+type WebhookParser struct {
+	token []byte
+}
+
+func NewWebhookParser(token string) *WebhookParser {
+	return &WebhookParser{token: []byte(token)}
+}
+
+type userRef struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+}
+
+type mergeRequestPayload struct {
+	ObjectKind string  `json:"object_kind"`
+	User       userRef `json:"user"`
+	Project    struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+	ObjectAttributes struct {
+		IID      int    `json:"iid"`
+		Title    string `json:"title"`
+		Action   string `json:"action"`
+		Draft    bool   `json:"draft"`
+		AuthorID int64  `json:"author_id"`
+	} `json:"object_attributes"`
+	Changes struct {
+		Reviewers *struct {
+			Previous []userRef `json:"previous"`
+			Current  []userRef `json:"current"`
+		} `json:"reviewers"`
+	} `json:"changes"`
+}
+
+// Parse возвращает событие MR или nil, если событие не влияет на ревью
+func (p *WebhookParser) Parse(header http.Header, body []byte) (*domain.VCSPullRequestEvent, error) {
+	if subtle.ConstantTimeCompare([]byte(header.Get(TokenHeader)), p.token) != 1 {
+		return nil, domain.ErrInvalidVCSSignature
+	}
+	if header.Get(EventHeader) != "Merge Request Hook" {
+		return nil, nil
+	}
+
+	var payload mergeRequestPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidVCSPayload, err)
+	}
+	attrs := payload.ObjectAttributes
+	if payload.ObjectKind != "merge_request" || payload.Project.PathWithNamespace == "" || attrs.IID == 0 {
+		return nil, fmt.Errorf("%w: project and merge request iid are required", domain.ErrInvalidVCSPayload)
+	}
+
+	event := &domain.VCSPullRequestEvent{
+		Provider:   domain.VCSGitLab,
+		Repository: payload.Project.PathWithNamespace,
+		Number:     attrs.IID,
+		Title:      attrs.Title,
+	}
+	// Автора в хуке описывает только числовой author_id, username есть лишь у инициатора события.
+	// Он годится, только если событие вызвал сам автор; иначе автора ищем по author_id.
+	if attrs.AuthorID != 0 {
+		event.AuthorExternalID = strconv.FormatInt(attrs.AuthorID, 10)
+	}
+	if payload.User.ID != 0 && payload.User.ID == attrs.AuthorID {
+		event.AuthorLogin = payload.User.Username
+	}
+
+	switch attrs.Action {
+	case "open", "reopen":
+		if attrs.Draft {
+			return nil, nil
+		}
+		event.Action = domain.VCSActionOpened
+	case "merge":
+		event.Action = domain.VCSActionMerged
+	case "close":
+		event.Action = domain.VCSActionClosed
+	case "approved":
+		// Апрув приходит от имени ревьюера, а не автора MR
+		event.Action = domain.VCSActionReviewed
+		event.ReviewerLogin = payload.User.Username
+		if event.ReviewerLogin == "" {
+			return nil, fmt.Errorf("%w: user.username is required", domain.ErrInvalidVCSPayload)
+		}
+	case "update":
+		if payload.Changes.Reviewers == nil {
+			return nil, nil
+		}
+		removed := removedUsernames(payload.Changes.Reviewers.Previous, payload.Changes.Reviewers.Current)
+		if len(removed) == 0 {
+			return nil, nil
+		}
+		event.Action = domain.VCSActionReviewersChanged
+		event.RemovedReviewerLogins = removed
+	default:
+		return nil, nil
+	}
+
+	if event.Action == domain.VCSActionOpened && event.AuthorLogin == "" && event.AuthorExternalID == "" {
+		return nil, fmt.Errorf("%w: object_attributes.author_id is required", domain.ErrInvalidVCSPayload)
+	}
+	return event, nil
+}
+
+func removedUsernames(previous, current []userRef) []string {
+	kept := make(map[string]bool, len(current))
+	for _, u := range current {
+		kept[u.Username] = true
+	}
+	var removed []string
+	for _, u := range previous {
+		if !kept[u.Username] {
+			removed = append(removed, u.Username)
+		}
+	}
+	return removed
+}
@@ -0,0 +1,93 @@
+package gitlab
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+const testToken = "s3cr3t"
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
+
+func mergeRequestHeader(token string) http.Header {
+	header := http.Header{}
+	header.Set(EventHeader, "Merge Request Hook")
+	header.Set(TokenHeader, token)
+	return header
+}
+
+func TestWebhookParserFixtures(t *testing.T) {
+	event := func(action domain.VCSAction) *domain.VCSPullRequestEvent {
+		return &domain.VCSPullRequestEvent{Provider: domain.VCSGitLab, Action: action,
+			Repository: "platform/hello", Number: 5, Title: "Add search", AuthorExternalID: "7"}
+	}
+	withAuthor := func(e *domain.VCSPullRequestEvent, login string) *domain.VCSPullRequestEvent {
+		e.AuthorLogin = login
+		return e
+	}
+	opened := withAuthor(event(domain.VCSActionOpened), "alice")
+	reviewed := event(domain.VCSActionReviewed)
+	reviewed.ReviewerLogin = "bob"
+	reviewersChanged := withAuthor(event(domain.VCSActionReviewersChanged), "alice")
+	reviewersChanged.RemovedReviewerLogins = []string{"bob"}
+
+	tests := []struct {
+		fixture string
+		want    *domain.VCSPullRequestEvent
+	}{
+		{fixture: "merge_request_open.json", want: opened},
+		// MR переоткрыл не автор: логин инициатора автором не считается
+		{fixture: "merge_request_reopen.json", want: event(domain.VCSActionOpened)},
+		{fixture: "merge_request_merge.json", want: withAuthor(event(domain.VCSActionMerged), "alice")},
+		{fixture: "merge_request_close.json", want: event(domain.VCSActionClosed)},
+		{fixture: "merge_request_approved.json", want: reviewed},
+		{fixture: "merge_request_reviewers_removed.json", want: reviewersChanged},
+	}
+
+	parser := NewWebhookParser(testToken)
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			got, err := parser.Parse(mergeRequestHeader(testToken), readFixture(t, tt.fixture))
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Parse = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebhookParserToken(t *testing.T) {
+	body := readFixture(t, "merge_request_open.json")
+	parser := NewWebhookParser(testToken)
+
+	for _, token := range []string{"", "other", testToken + " "} {
+		if _, err := parser.Parse(mergeRequestHeader(token), body); !errors.Is(err, domain.ErrInvalidVCSSignature) {
+			t.Fatalf("Parse with token %q: error = %v, want %v", token, err, domain.ErrInvalidVCSSignature)
+		}
+	}
+}
+
+func TestWebhookParserIgnoresOtherHooks(t *testing.T) {
+	header := mergeRequestHeader(testToken)
+	header.Set(EventHeader, "Push Hook")
+
+	got, err := NewWebhookParser(testToken).Parse(header, readFixture(t, "merge_request_open.json"))
+	if err != nil || got != nil {
+		t.Fatalf("Parse = %+v, %v; want nil, nil", got, err)
+	}
+}
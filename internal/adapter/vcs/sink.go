@@ -0,0 +1,65 @@
+package vcs
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+// RequestEnqueuer планирует запросы ревью
+type RequestEnqueuer interface {
+	// EnqueueReviewRequests идемпотентен: повторный вызов для того же события не создаёт дубликатов
+	EnqueueReviewRequests(ctx context.Context, eventSequence int64, requests []domain.VCSReviewRequestDraft) error
+}
+
+// Sink — outbox-sink, который после создания PR и переназначения планирует запросы ревью в VCS.
+// Учитываются только PR, заведённые интеграцией провайдера с настроенным клиентом.
+type Sink struct {
+	enqueuer  RequestEnqueuer
+	providers map[domain.VCSProvider]bool
+}
+
+func NewSink(enqueuer RequestEnqueuer, providers ...domain.VCSProvider) *Sink {
+	s := &Sink{enqueuer: enqueuer, providers: make(map[domain.VCSProvider]bool, len(providers))}
+	for _, p := range providers {
+		s.providers[p] = true
+	}
+	return s
+}
+
+func (s *Sink) Name() string { return "vcs" }
+
+func (s *Sink) Publish(ctx context.Context, event domain.Event) error {
+	if event.Type != domain.EventPRCreated && event.Type != domain.EventReviewerReassigned {
+		return nil
+	}
+	provider, _, _, ok := domain.ParseVCSPullRequestID(event.AggregateID)
+	if !ok || !s.providers[provider] {
+		return nil
+	}
+
+	var p domain.PullRequestEventPayload
+	if err := json.Unmarshal(event.Payload, &p); err != nil {
+		return err
+	}
+
+	var requests []domain.VCSReviewRequestDraft
+	switch event.Type {
+	case domain.EventPRCreated:
+		if len(p.AssignedReviewers) > 0 {
+			requests = append(requests, domain.VCSReviewRequestDraft{PullRequestID: p.PullRequestID, Action: domain.VCSReviewActionRequest, ReviewerIDs: p.AssignedReviewers})
+		}
+	case domain.EventReviewerReassigned:
+		if p.OldReviewerID != "" {
+			requests = append(requests, domain.VCSReviewRequestDraft{PullRequestID: p.PullRequestID, Action: domain.VCSReviewActionRemove, ReviewerIDs: []string{p.OldReviewerID}})
+		}
+		if p.NewReviewerID != "" {
+			requests = append(requests, domain.VCSReviewRequestDraft{PullRequestID: p.PullRequestID, Action: domain.VCSReviewActionRequest, ReviewerIDs: []string{p.NewReviewerID}})
+		}
+	}
+	if len(requests) == 0 {
+		return nil
+	}
+	return s.enqueuer.EnqueueReviewRequests(ctx, event.Sequence, requests)
+}
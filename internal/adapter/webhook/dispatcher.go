@@ -0,0 +1,135 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/dispatch"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/logger"
+)
+
+const (
+	SignatureHeader = "X-Reviewer-Signature-256"
+	EventHeader     = "X-Reviewer-Event"
+	DeliveryHeader  = "X-Reviewer-Delivery"
+)
+
+// Store — хранилище журнала доставок
+type Store interface {
+	// ClaimDueDeliveries захватывает доставки, время которых пришло, на время lease
+	ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]domain.WebhookAttempt, error)
+	MarkDelivered(ctx context.Context, id int64, statusCode int) error
+	// MarkFailed записывает неудачную попытку. nextAttemptAt == nil — попытки исчерпаны
+	MarkFailed(ctx context.Context, id int64, statusCode *int, errMsg string, nextAttemptAt *time.Time) error
+}
+
+// DefaultConfig возвращает параметры по умолчанию
+func DefaultConfig() dispatch.Config {
+	return dispatch.Config{
+		PollInterval: 2 * time.Second,
+		BatchSize:    50,
+		MaxAttempts:  8,
+		BaseBackoff:  10 * time.Second,
+		MaxBackoff:   time.Hour,
+		Timeout:      10 * time.Second,
+	}
+}
+
+// Dispatcher доставляет события подписчикам: подписанные HMAC-SHA256 POST-запросы
+// с повторами по экспоненциальной задержке.
+type Dispatcher struct {
+	store  Store
+	client *http.Client
+	cfg    dispatch.Config
+}
+
+func NewDispatcher(store Store, cfg dispatch.Config) *Dispatcher {
+	return &Dispatcher{
+		store:  store,
+		client: &http.Client{Timeout: cfg.Timeout},
+		cfg:    cfg,
+	}
+}
+
+// Run отправляет запланированные доставки до отмены контекста
+func (d *Dispatcher) Run(ctx context.Context) {
+	dispatch.Run(ctx, d.cfg, "webhook deliveries", d.store.ClaimDueDeliveries, d.deliver)
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, delivery domain.WebhookAttempt) {
+	statusCode, err := d.send(ctx, delivery)
+	if err == nil {
+		if err := d.store.MarkDelivered(ctx, delivery.ID, statusCode); err != nil {
+			logger.FromContext(ctx).Error("failed to mark webhook delivery as delivered", "delivery_id", delivery.ID, "error", err)
+		}
+		return
+	}
+
+	var code *int
+	if statusCode != 0 {
+		code = &statusCode
+	}
+	next := d.cfg.NextAttempt(delivery.Attempts, err)
+	if err := d.store.MarkFailed(ctx, delivery.ID, code, err.Error(), next); err != nil {
+		logger.FromContext(ctx).Error("failed to record webhook delivery failure", "delivery_id", delivery.ID, "error", err)
+	}
+}
+
+// send отправляет событие и возвращает HTTP-статус ответа
+func (d *Dispatcher) send(ctx context.Context, delivery domain.WebhookAttempt) (int, error) {
+	body, err := json.Marshal(envelope{
+		ID:         delivery.Event.Sequence,
+		Type:       delivery.Event.Type,
+		OccurredAt: delivery.Event.OccurredAt,
+		Data:       delivery.Event.Payload,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, string(delivery.Event.Type))
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(SignatureHeader, Sign(delivery.Secret, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign возвращает подпись тела в формате "sha256=<hex>"
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// envelope — тело запроса к подписчику
+type envelope struct {
+	ID         int64            `json:"id"`
+	Type       domain.EventType `json:"type"`
+	OccurredAt time.Time        `json:"occurred_at"`
+	Data       json.RawMessage  `json:"data"`
+}
@@ -0,0 +1,29 @@
+package webhook
+
+import (
+	"context"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+// DeliveryEnqueuer планирует доставки события подходящим подпискам
+type DeliveryEnqueuer interface {
+	// EnqueueDeliveries идемпотентен: повторный вызов для того же события не создаёт дубликатов
+	EnqueueDeliveries(ctx context.Context, eventSequence int64, eventType domain.EventType) error
+}
+
+// Sink — outbox-sink, который превращает событие в доставки вебхуков.
+// Сама отправка выполняется Dispatcher асинхронно, с повторами.
+type Sink struct {
+	enqueuer DeliveryEnqueuer
+}
+
+func NewSink(enqueuer DeliveryEnqueuer) *Sink {
+	return &Sink{enqueuer: enqueuer}
+}
+
+func (s *Sink) Name() string { return "webhook" }
+
+func (s *Sink) Publish(ctx context.Context, event domain.Event) error {
+	return s.enqueuer.EnqueueDeliveries(ctx, event.Sequence, event.Type)
+}
@@ -0,0 +1,223 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Options — результат разбора командной строки
+type Options struct {
+	// ConfigPath — путь к YAML-файлу; пустой — файл не используется
+	ConfigPath string
+	// PrintConfig — вывести итоговые настройки без секретов и завершиться
+	PrintConfig bool
+}
+
+// Load собирает настройки: значения по умолчанию, затем YAML-файл (--config или CONFIG_FILE),
+// затем переменные окружения, затем флаги. Возвращает ошибку, если итог не прошёл Validate.
+func Load(args []string, getenv func(string) string) (Config, Options, error) {
+	cfg := Default()
+	var opts Options
+
+	fs := flag.NewFlagSet("server", flag.ContinueOnError)
+	fs.StringVar(&opts.ConfigPath, "config", getenv("CONFIG_FILE"), "path to YAML config file")
+	fs.BoolVar(&opts.PrintConfig, "print-config", false, "print effective config with secrets redacted and exit")
+	addr := fs.String("addr", "", "listen address, overrides server.addr")
+	dbURL := fs.String("db-url", "", "database URL, overrides db.url")
+	logLevel := fs.String("log-level", "", "log level, overrides log.level")
+	if err := fs.Parse(args); err != nil {
+		return cfg, opts, err
+	}
+
+	if opts.ConfigPath != "" {
+		if err := loadFile(opts.ConfigPath, &cfg); err != nil {
+			return cfg, opts, err
+		}
+	}
+	if err := applyEnv(&cfg, getenv); err != nil {
+		return cfg, opts, err
+	}
+
+	// Применяем только явно переданные флаги, чтобы пустые значения не затирали файл и окружение
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "addr":
+			cfg.Server.Addr = *addr
+		case "db-url":
+			cfg.DB.URL = *dbURL
+		case "log-level":
+			cfg.Log.Level = *logLevel
+		}
+	})
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, opts, fmt.Errorf("invalid config: %w", err)
+	}
+	return cfg, opts, nil
+}
+
+func loadFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	// Опечатка в ключе не должна молча оставлять значение по умолчанию
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// Print пишет настройки в YAML со скрытыми секретами
+func Print(w io.Writer, cfg Config) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(cfg.Redacted()); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// envBinding связывает переменную окружения с полем настроек
+type envBinding struct {
+	name  string
+	apply func(value string) error
+}
+
+// applyEnv переопределяет настройки переменными окружения. Имена прежних переменных
+// (DB_URL, REVIEW_SLA_HOURS и т.д.) сохранены, чтобы не ломать существующие развёртывания.
+func applyEnv(cfg *Config, getenv func(string) string) error {
+	bindings := []envBinding{
+		{"HTTP_ADDR", setString(&cfg.Server.Addr)},
+		{"SHUTDOWN_TIMEOUT", setDuration(&cfg.Server.ShutdownTimeout)},
+		{"SHUTDOWN_DRAIN_DELAY", setDuration(&cfg.Server.ShutdownDrainDelay)},
+		{"IDEMPOTENCY_TTL", setDuration(&cfg.Server.IdempotencyTTL)},
+
+		{"DB_URL", setString(&cfg.DB.URL)},
+		{"DB_MAX_OPEN_CONNS", setInt(&cfg.DB.MaxOpenConns)},
+		{"DB_MAX_IDLE_CONNS", setInt(&cfg.DB.MaxIdleConns)},
+		{"DB_CONN_MAX_LIFETIME", setDuration(&cfg.DB.ConnMaxLifetime)},
+		{"DB_CONNECT_TIMEOUT", setDuration(&cfg.DB.ConnectTimeout)},
+
+		{"ADMIN_TOKEN", setString(&cfg.Auth.AdminToken)},
+
+		{"REVIEWERS_PER_PR", setInt(&cfg.Assignment.ReviewersPerPR)},
+		{"REVIEW_SLA_HOURS", setHours(&cfg.Assignment.ReviewSLA)},
+		{"STALE_PR_REMIND_AFTER_HOURS", setHours(&cfg.Assignment.StaleRemindAfter)},
+		{"STALE_PR_ESCALATE_AFTER_HOURS", setHours(&cfg.Assignment.StaleEscalateAfter)},
+
+		{"STALE_PR_CHECK_INTERVAL", setDuration(&cfg.Jobs.StalePRCheckInterval)},
+		{"EMAIL_DIGEST_HOUR", setInt(&cfg.Jobs.EmailDigestHour)},
+		{"OUTBOX_LOG_EVENTS", setBool(&cfg.Jobs.OutboxLogEvents)},
+
+		{"LOG_LEVEL", setString(&cfg.Log.Level)},
+		{"OTEL_TRACES_EXPORTER", setString(&cfg.Tracing.Exporter)},
+
+		{"GITHUB_TOKEN", setString(&cfg.Integrations.GitHub.Token)},
+		{"GITHUB_API_URL", setString(&cfg.Integrations.GitHub.APIURL)},
+		{"GITHUB_WEBHOOK_SECRET", setString(&cfg.Integrations.GitHub.WebhookSecret)},
+		{"GITLAB_WEBHOOK_TOKEN", setString(&cfg.Integrations.GitLab.WebhookToken)},
+		{"SLACK_WEBHOOK_URLS", setTeamMap(&cfg.Integrations.Slack.WebhookURLs)},
+		{"SMTP_HOST", setString(&cfg.Integrations.SMTP.Host)},
+		{"SMTP_PORT", setInt(&cfg.Integrations.SMTP.Port)},
+		{"SMTP_USERNAME", setString(&cfg.Integrations.SMTP.Username)},
+		{"SMTP_PASSWORD", setString(&cfg.Integrations.SMTP.Password)},
+		{"SMTP_FROM", setString(&cfg.Integrations.SMTP.From)},
+		{"SMTP_TLS", setString(&cfg.Integrations.SMTP.TLS)},
+	}
+
+	var errs []error
+	for _, b := range bindings {
+		v := getenv(b.name)
+		if v == "" {
+			continue
+		}
+		if err := b.apply(v); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", b.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func setString(dst *string) func(string) error {
+	return func(v string) error {
+		*dst = v
+		return nil
+	}
+}
+
+func setInt(dst *int) func(string) error {
+	return func(v string) error {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("expected integer, got %q", v)
+		}
+		*dst = n
+		return nil
+	}
+}
+
+func setBool(dst *bool) func(string) error {
+	return func(v string) error {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("expected true or false, got %q", v)
+		}
+		*dst = b
+		return nil
+	}
+}
+
+func setDuration(dst *time.Duration) func(string) error {
+	return func(v string) error {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("expected duration like 30s or 10m, got %q", v)
+		}
+		*dst = d
+		return nil
+	}
+}
+
+// setHours — для переменных, исторически заданных в целых часах
+func setHours(dst *time.Duration) func(string) error {
+	return func(v string) error {
+		h, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("expected whole hours, got %q", v)
+		}
+		*dst = time.Duration(h) * time.Hour
+		return nil
+	}
+}
+
+// setTeamMap разбирает список вида "team1=value1,team2=value2"
+func setTeamMap(dst *map[string]string) func(string) error {
+	return func(v string) error {
+		result := make(map[string]string)
+		for _, item := range strings.Split(v, ",") {
+			item = strings.TrimSpace(item)
+			if item == "" {
+				continue
+			}
+			team, value, ok := strings.Cut(item, "=")
+			if !ok || strings.TrimSpace(team) == "" || strings.TrimSpace(value) == "" {
+				return fmt.Errorf("expected team=value, got %q", item)
+			}
+			result[strings.TrimSpace(team)] = strings.TrimSpace(value)
+		}
+		*dst = result
+		return nil
+	}
+}
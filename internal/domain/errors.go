@@ -3,13 +3,34 @@ package domain
 import "errors"
 
 var (
-	ErrPRExists            = errors.New("pull request already exists")
-	ErrPRNotFound          = errors.New("pull request not found")
-	ErrPRAlreadyMerged     = errors.New("pull request is already merged")
-	ErrReviewerNotAssigned = errors.New("reviewer is not assigned to this pull request")
-	ErrNoActiveReviewers   = errors.New("no active reviewers available for reassignment")
-	ErrAuthorNotFound      = errors.New("author not found")
-	ErrUserNotFound        = errors.New("user not found")
-	ErrTeamExists          = errors.New("team already exists")
-	ErrTeamNotFound        = errors.New("team not found")
+	ErrPRExists             = errors.New("pull request already exists")
+	ErrPRNotFound           = errors.New("pull request not found")
+	ErrPRAlreadyMerged      = errors.New("pull request is already merged")
+	ErrPRClosed             = errors.New("pull request is closed")
+	ErrPRNotClosed          = errors.New("pull request is not closed")
+	ErrReviewerNotAssigned  = errors.New("reviewer is not assigned to this pull request")
+	ErrNoActiveReviewers    = errors.New("no active reviewers available for reassignment")
+	ErrAuthorNotFound       = errors.New("author not found")
+	ErrUserNotFound         = errors.New("user not found")
+	ErrTeamExists           = errors.New("team already exists")
+	ErrTeamNotFound         = errors.New("team not found")
+	ErrPRVersionConflict    = errors.New("pull request was modified concurrently")
+	ErrPRVersionMismatch    = errors.New("pull request version does not match expected")
+	ErrWebhookNotFound      = errors.New("webhook subscription not found")
+	ErrInvalidWebhook       = errors.New("invalid webhook subscription")
+	ErrInvalidVCSSignature  = errors.New("invalid VCS webhook signature")
+	ErrInvalidVCSPayload    = errors.New("invalid VCS webhook payload")
+	ErrIdentityExists       = errors.New("user identity already exists")
+	ErrIdentityNotFound     = errors.New("user identity not found")
+	ErrInvalidIdentity      = errors.New("invalid user identity")
+	ErrInvalidEmail         = errors.New("invalid email address")
+	ErrInvalidReviewPolicy  = errors.New("invalid review policy")
+	ErrReviewPolicyNotFound = errors.New("review policy not found")
+	ErrInvalidReviewSLA     = errors.New("invalid review SLA")
+	ErrInvalidTeamImport    = errors.New("invalid team import")
+	ErrReconcilePlanStale   = errors.New("organization changed since the plan was made")
+	ErrInvalidSnapshot      = errors.New("invalid snapshot")
+	ErrSnapshotSchema       = errors.New("snapshot schema version does not match the database")
+	ErrDatabaseNotEmpty     = errors.New("database is not empty")
+	ErrInvalidPeriod        = errors.New("invalid period: from must be before to and the range must not exceed a year")
 )
@@ -0,0 +1,164 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventPRCreated          EventType = "pull_request.created"
+	EventReviewerReassigned EventType = "pull_request.reviewer_reassigned"
+	EventPRMerged           EventType = "pull_request.merged"
+	EventPRClosed           EventType = "pull_request.closed"
+	EventPRReopened         EventType = "pull_request.reopened"
+	EventPRReviewReminder   EventType = "pull_request.review_reminder"
+	EventPREscalated        EventType = "pull_request.escalated"
+	EventUserDeactivated    EventType = "user.deactivated"
+	EventUserActivated      EventType = "user.activated"
+)
+
+// EventTypes — все типы событий, на которые можно подписаться
+var EventTypes = []EventType{
+	EventPRCreated,
+	EventReviewerReassigned,
+	EventPRMerged,
+	EventPRClosed,
+	EventPRReopened,
+	EventPRReviewReminder,
+	EventPREscalated,
+	EventUserDeactivated,
+	EventUserActivated,
+}
+
+// IsKnownEventType проверяет, что тип события поддерживается
+func IsKnownEventType(t EventType) bool {
+	for _, known := range EventTypes {
+		if known == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Event — доменное событие, записываемое в outbox вместе с изменением состояния
+type Event struct {
+	Sequence    int64 // порядковый номер в outbox, присваивается при сохранении
+	Type        EventType
+	AggregateID string
+	Payload     json.RawMessage
+	OccurredAt  time.Time
+}
+
+// OutboxAttempt — событие outbox, захваченное relay для публикации
+type OutboxAttempt struct {
+	Event    Event
+	Attempts int // номер текущей попытки, начиная с 1
+}
+
+// OutboxStats — счётчики relay и отставание outbox
+type OutboxStats struct {
+	Published int64         // опубликовано событий
+	Failed    int64         // неудачных попыток публикации
+	Pending   int64         // неопубликованных событий
+	Lag       time.Duration // возраст самого старого неопубликованного события
+}
+
+// PullRequestEventPayload — данные событий жизненного цикла PR
+type PullRequestEventPayload struct {
+	PullRequestID     string     `json:"pull_request_id"`
+	PullRequestName   string     `json:"pull_request_name"`
+	AuthorID          string     `json:"author_id"`
+	Status            PRStatus   `json:"status"`
+	AssignedReviewers []string   `json:"assigned_reviewers"`
+	OldReviewerID     string     `json:"old_reviewer_id,omitempty"`
+	NewReviewerID     string     `json:"new_reviewer_id,omitempty"`
+	MergedAt          *time.Time `json:"merged_at,omitempty"`
+	Version           int        `json:"version"`
+	// Поля напоминаний и эскалаций зависших PR
+	OpenSeconds int64            `json:"open_seconds,omitempty"`
+	Escalation  EscalationAction `json:"escalation,omitempty"`
+	LeadID      string           `json:"lead_id,omitempty"`
+}
+
+// NewPullRequestEvent создаёт событие по текущему состоянию PR.
+// oldReviewerID и newReviewerID заполняются только для переназначения.
+func NewPullRequestEvent(eventType EventType, pr *PullRequest, oldReviewerID, newReviewerID string) (Event, error) {
+	payload, err := json.Marshal(PullRequestEventPayload{
+		PullRequestID:     pr.ID(),
+		PullRequestName:   pr.Name(),
+		AuthorID:          pr.AuthorID(),
+		Status:            pr.Status(),
+		AssignedReviewers: pr.AssignedReviewers(),
+		OldReviewerID:     oldReviewerID,
+		NewReviewerID:     newReviewerID,
+		MergedAt:          pr.MergedAt(),
+		Version:           pr.Version(),
+	})
+	if err != nil {
+		return Event{}, err
+	}
+
+	return Event{
+		Type:        eventType,
+		AggregateID: pr.ID(),
+		Payload:     payload,
+		OccurredAt:  time.Now().UTC(),
+	}, nil
+}
+
+// NewStalePullRequestEvent создаёт pull_request.review_reminder или pull_request.escalated
+// для PR, который открыт уже openFor. escalation и leadID заполняются только для эскалации.
+func NewStalePullRequestEvent(stage StaleStage, pr *PullRequest, openFor time.Duration, escalation EscalationAction, leadID string) (Event, error) {
+	eventType := EventPRReviewReminder
+	if stage == StaleStageEscalated {
+		eventType = EventPREscalated
+	}
+	event, err := NewPullRequestEvent(eventType, pr, "", "")
+	if err != nil {
+		return Event{}, err
+	}
+
+	var payload PullRequestEventPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return Event{}, err
+	}
+	payload.OpenSeconds = int64(openFor / time.Second)
+	payload.Escalation = escalation
+	payload.LeadID = leadID
+	if event.Payload, err = json.Marshal(payload); err != nil {
+		return Event{}, err
+	}
+	return event, nil
+}
+
+// UserEventPayload — данные событий изменения активности пользователя
+type UserEventPayload struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	IsActive bool   `json:"is_active"`
+}
+
+// NewUserActivityEvent создаёт user.activated или user.deactivated по текущему состоянию пользователя
+func NewUserActivityEvent(u *User) (Event, error) {
+	payload, err := json.Marshal(UserEventPayload{
+		UserID:   u.ID(),
+		Username: u.Username(),
+		IsActive: u.IsActive(),
+	})
+	if err != nil {
+		return Event{}, err
+	}
+
+	eventType := EventUserDeactivated
+	if u.IsActive() {
+		eventType = EventUserActivated
+	}
+	return Event{
+		Type:        eventType,
+		AggregateID: u.ID(),
+		Payload:     payload,
+		OccurredAt:  time.Now().UTC(),
+	}, nil
+}
@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// IdempotencyRecord — сохранённый результат запроса с Idempotency-Key.
+type IdempotencyRecord struct {
+	RequestHash string
+	StatusCode  int
+	Body        []byte
+	Completed   bool // false — первый запрос с этим ключом ещё выполняется
+}
+
+// IdempotencyStore хранит ответы по ключу идемпотентности.
+type IdempotencyStore interface {
+	// Reserve занимает ключ. Если ключ уже занят, возвращает существующую запись, иначе nil.
+	Reserve(ctx context.Context, key, route, requestHash string, ttl time.Duration) (*IdempotencyRecord, error)
+	Complete(ctx context.Context, key, route string, statusCode int, body []byte) error
+	Release(ctx context.Context, key, route string) error
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var providerPattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,31}$`)
+
+// UserIdentity — учётная запись пользователя во внешней системе (GitHub, GitLab, Slack, почта).
+// Пара (provider, external_id) уникальна: по ней интеграции и аутентификация находят пользователя.
+type UserIdentity struct {
+	provider   string
+	externalID string
+	userID     string
+	createdAt  time.Time
+}
+
+// NewUserIdentity создаёт привязку учётной записи к пользователю
+func NewUserIdentity(provider, externalID, userID string) (*UserIdentity, error) {
+	provider = strings.TrimSpace(provider)
+	externalID = strings.TrimSpace(externalID)
+	userID = strings.TrimSpace(userID)
+
+	if !providerPattern.MatchString(provider) {
+		return nil, fmt.Errorf("%w: provider must match %s", ErrInvalidIdentity, providerPattern)
+	}
+	if externalID == "" || len(externalID) > 255 {
+		return nil, fmt.Errorf("%w: external_id must be 1..255 characters", ErrInvalidIdentity)
+	}
+	if userID == "" {
+		return nil, fmt.Errorf("%w: user_id is required", ErrInvalidIdentity)
+	}
+
+	return &UserIdentity{
+		provider:   provider,
+		externalID: externalID,
+		userID:     userID,
+		createdAt:  time.Now().UTC(),
+	}, nil
+}
+
+// RestoreUserIdentity создаёт привязку из данных БД (используется только адаптером)
+func RestoreUserIdentity(provider, externalID, userID string, createdAt time.Time) *UserIdentity {
+	return &UserIdentity{
+		provider:   provider,
+		externalID: externalID,
+		userID:     userID,
+		createdAt:  createdAt,
+	}
+}
+
+func (i *UserIdentity) Provider() string     { return i.provider }
+func (i *UserIdentity) ExternalID() string   { return i.externalID }
+func (i *UserIdentity) UserID() string       { return i.userID }
+func (i *UserIdentity) CreatedAt() time.Time { return i.createdAt }
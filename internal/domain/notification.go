@@ -0,0 +1,38 @@
+package domain
+
+// NotificationChannel — канал доставки уведомлений
+type NotificationChannel string
+
+const (
+	NotificationSlack NotificationChannel = "slack"
+	NotificationEmail NotificationChannel = "email"
+)
+
+// NotificationDraft — уведомление, которое нужно поставить в очередь
+type NotificationDraft struct {
+	Channel NotificationChannel
+	Target  string // URL входящего вебхука, адрес почты и т.п.
+	Subject string
+	Body    string
+	// HTMLBody — необязательная HTML-версия (для писем)
+	HTMLBody string
+}
+
+// Notification — уведомление, захваченное диспетчером для отправки
+type Notification struct {
+	ID       int64
+	Attempts int // номер текущей попытки, начиная с 1
+	Channel  NotificationChannel
+	Target   string
+	Subject  string
+	Body     string
+	HTMLBody string
+}
+
+// DigestBatch — накопленные для дайджеста события одного пользователя
+type DigestBatch struct {
+	UserID   string
+	Username string
+	Email    string
+	Items    []string
+}
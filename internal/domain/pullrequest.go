@@ -11,6 +11,8 @@ type PRStatus string
 const (
 	PROpen   PRStatus = "OPEN"
 	PRMerged PRStatus = "MERGED"
+	// PRClosed — PR закрыт без мержа; его можно переоткрыть
+	PRClosed PRStatus = "CLOSED"
 )
 
 type PullRequest struct {
@@ -21,6 +23,7 @@ type PullRequest struct {
 	assignedReviewers []string
 	createdAt         time.Time
 	mergedAt          *time.Time
+	version           int
 }
 
 // NewPullRequest создаёт новый PR в статусе OPEN
@@ -45,6 +48,7 @@ func NewPullRequest(id, name, authorID string, reviewers []string) (*PullRequest
 		status:            PROpen,
 		assignedReviewers: reviewers,
 		createdAt:         time.Now().UTC(),
+		version:           1,
 	}, nil
 }
 
@@ -89,10 +93,24 @@ func (pr *PullRequest) MergedAt() *time.Time {
 	return &t
 }
 
+// Version возвращает версию PR для оптимистичной блокировки
+func (pr *PullRequest) Version() int {
+	return pr.version
+}
+
 // CanBeMerged проверяет, можно ли мержить
 func (pr *PullRequest) CanBeMerged() error {
-	if pr.status == PRMerged {
+	return pr.CheckOpen()
+}
+
+// CheckOpen проверяет, что PR открыт: только у открытого PR можно мержить, закрывать
+// и менять ревьюеров
+func (pr *PullRequest) CheckOpen() error {
+	switch pr.status {
+	case PRMerged:
 		return ErrPRAlreadyMerged
+	case PRClosed:
+		return ErrPRClosed
 	}
 	return nil
 }
@@ -105,6 +123,27 @@ func (pr *PullRequest) Merge() error {
 	pr.status = PRMerged
 	now := time.Now().UTC()
 	pr.mergedAt = &now
+	pr.version++
+	return nil
+}
+
+// Close переводит открытый PR в статус CLOSED
+func (pr *PullRequest) Close() error {
+	if err := pr.CheckOpen(); err != nil {
+		return err
+	}
+	pr.status = PRClosed
+	pr.version++
+	return nil
+}
+
+// Reopen возвращает закрытый PR в статус OPEN
+func (pr *PullRequest) Reopen() error {
+	if pr.status != PRClosed {
+		return ErrPRNotClosed
+	}
+	pr.status = PROpen
+	pr.version++
 	return nil
 }
 
@@ -131,6 +170,7 @@ func (pr *PullRequest) ReplaceReviewer(oldReviewerID, newReviewerID string) erro
 	if !found {
 		return ErrReviewerNotAssigned
 	}
+	pr.version++
 	return nil
 }
 
@@ -141,6 +181,7 @@ func RestorePullRequest(
 	assignedReviewers []string,
 	createdAt time.Time,
 	mergedAt *time.Time,
+	version int,
 ) (*PullRequest, error) {
 	if id == "" || name == "" || authorID == "" {
 		return nil, errors.New("invalid PR data")
@@ -153,5 +194,6 @@ func RestorePullRequest(
 		assignedReviewers: assignedReviewers,
 		createdAt:         createdAt,
 		mergedAt:          mergedAt,
+		version:           version,
 	}, nil
 }
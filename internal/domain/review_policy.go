@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// EscalationAction — что делать с PR, который так и не получил ревью после напоминания
+type EscalationAction string
+
+const (
+	// EscalationNotifyLead — уведомить лида команды
+	EscalationNotifyLead EscalationAction = "notify_lead"
+	// EscalationReassign — переназначить всех ревьюеров на других участников их команд
+	EscalationReassign EscalationAction = "reassign"
+)
+
+// StaleStage — этап обработки зависшего OPEN PR
+type StaleStage string
+
+const (
+	StaleStageNone      StaleStage = ""
+	StaleStageReminded  StaleStage = "REMINDED"
+	StaleStageEscalated StaleStage = "ESCALATED"
+)
+
+// ReviewPolicy — правила команды для PR, которые долго остаются OPEN.
+// Пороги отсчитываются от создания PR.
+type ReviewPolicy struct {
+	teamName      string
+	remindAfter   time.Duration
+	escalateAfter time.Duration
+	escalation    EscalationAction
+	leadID        string
+}
+
+// NewReviewPolicy создаёт политику команды с проверкой порогов и действия эскалации
+func NewReviewPolicy(teamName string, remindAfter, escalateAfter time.Duration, escalation EscalationAction, leadID string) (*ReviewPolicy, error) {
+	if teamName == "" {
+		return nil, fmt.Errorf("%w: team name is required", ErrInvalidReviewPolicy)
+	}
+	if remindAfter <= 0 {
+		return nil, fmt.Errorf("%w: remind threshold must be positive", ErrInvalidReviewPolicy)
+	}
+	if escalateAfter <= remindAfter {
+		return nil, fmt.Errorf("%w: escalate threshold must be greater than remind threshold", ErrInvalidReviewPolicy)
+	}
+	switch escalation {
+	case EscalationReassign:
+	case EscalationNotifyLead:
+		if leadID == "" {
+			return nil, fmt.Errorf("%w: lead is required for %s", ErrInvalidReviewPolicy, escalation)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown escalation %q", ErrInvalidReviewPolicy, escalation)
+	}
+	return &ReviewPolicy{
+		teamName:      teamName,
+		remindAfter:   remindAfter,
+		escalateAfter: escalateAfter,
+		escalation:    escalation,
+		leadID:        leadID,
+	}, nil
+}
+
+// RestoreReviewPolicy создаёт политику из данных БД (используется только адаптером)
+func RestoreReviewPolicy(teamName string, remindAfter, escalateAfter time.Duration, escalation EscalationAction, leadID string) *ReviewPolicy {
+	return &ReviewPolicy{
+		teamName:      teamName,
+		remindAfter:   remindAfter,
+		escalateAfter: escalateAfter,
+		escalation:    escalation,
+		leadID:        leadID,
+	}
+}
+
+func (p *ReviewPolicy) TeamName() string             { return p.teamName }
+func (p *ReviewPolicy) RemindAfter() time.Duration   { return p.remindAfter }
+func (p *ReviewPolicy) EscalateAfter() time.Duration { return p.escalateAfter }
+func (p *ReviewPolicy) Escalation() EscalationAction { return p.escalation }
+func (p *ReviewPolicy) LeadID() string               { return p.leadID }
+
+// ForTeam возвращает копию политики для другой команды (применение политики по умолчанию)
+func (p *ReviewPolicy) ForTeam(teamName string) *ReviewPolicy {
+	c := *p
+	c.teamName = teamName
+	return &c
+}
+
+// NextStage возвращает этап, который пора выполнить для PR возрастом age,
+// уже прошедшего этап current. StaleStageNone — делать ничего не нужно.
+// Если PR пролежал дольше порога эскалации без напоминания, сразу эскалируется.
+func (p *ReviewPolicy) NextStage(age time.Duration, current StaleStage) StaleStage {
+	switch {
+	case current == StaleStageEscalated:
+		return StaleStageNone
+	case age >= p.escalateAfter:
+		return StaleStageEscalated
+	case age >= p.remindAfter && current == StaleStageNone:
+		return StaleStageReminded
+	default:
+		return StaleStageNone
+	}
+}
+
+// StalePullRequest — OPEN PR вместе с политикой команды автора (или политикой по умолчанию)
+// и пройденным этапом напоминаний
+type StalePullRequest struct {
+	PullRequest *PullRequest
+	Policy      *ReviewPolicy
+	Stage       StaleStage
+}
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// ReviewSLA — обещание команды: первое действие ревьюера не позже FirstReviewWithin после назначения
+type ReviewSLA struct {
+	teamName          string
+	firstReviewWithin time.Duration
+}
+
+// NewReviewSLA создаёт SLA команды
+func NewReviewSLA(teamName string, firstReviewWithin time.Duration) (*ReviewSLA, error) {
+	if teamName == "" {
+		return nil, fmt.Errorf("%w: team name is required", ErrInvalidReviewSLA)
+	}
+	if firstReviewWithin <= 0 {
+		return nil, fmt.Errorf("%w: first review deadline must be positive", ErrInvalidReviewSLA)
+	}
+	return &ReviewSLA{teamName: teamName, firstReviewWithin: firstReviewWithin}, nil
+}
+
+func (s *ReviewSLA) TeamName() string                 { return s.teamName }
+func (s *ReviewSLA) FirstReviewWithin() time.Duration { return s.firstReviewWithin }
+
+// SLAStatus — состояние назначения без первого действия ревьюера
+type SLAStatus string
+
+const (
+	// SLAAtRisk — срок ещё не прошёл, но скоро истечёт
+	SLAAtRisk SLAStatus = "at_risk"
+	// SLABreached — срок истёк
+	SLABreached SLAStatus = "breached"
+)
+
+// SLAViolation — назначение ревьюера на OPEN PR, по которому SLA нарушен или скоро будет нарушен
+type SLAViolation struct {
+	PullRequestID   string
+	PullRequestName string
+	Team            string
+	ReviewerID      string
+	AssignedAt      time.Time
+	DueAt           time.Time
+	Status          SLAStatus
+}
+
+// SLABreachStats — статистика нарушений SLA по команде или ревьюеру
+type SLABreachStats struct {
+	Key string // имя команды или ID ревьюера
+	// Assignments — назначения за период
+	Assignments int
+	// Breached — назначения, где первое действие опоздало или его нет после срока
+	Breached int
+	// InProgress — назначения, у которых срок ещё не прошёл и действия пока не было
+	InProgress int
+}
+
+// BreachRate — доля нарушений среди назначений с известным исходом
+func (s SLABreachStats) BreachRate() float64 {
+	decided := s.Assignments - s.InProgress
+	if decided == 0 {
+		return 0
+	}
+	return float64(s.Breached) / float64(decided)
+}
+
+// SLAReport — отчёт о нарушениях SLA за период [From, To) по дате назначения
+type SLAReport struct {
+	From      time.Time
+	To        time.Time
+	Teams     []SLABreachStats
+	Reviewers []SLABreachStats
+}
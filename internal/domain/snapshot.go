@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Снимок данных — JSON Lines: заголовок, строки таблиц в порядке зависимостей, завершающая строка.
+const (
+	SnapshotFormat        = "reviewer-snapshot"
+	SnapshotFormatVersion = 1
+
+	SnapshotKindHeader = "header"
+	SnapshotKindEnd    = "end"
+)
+
+// SnapshotHeader — первая строка снимка
+type SnapshotHeader struct {
+	Kind          string    `json:"kind"`
+	Format        string    `json:"format"`
+	FormatVersion int       `json:"format_version"`
+	SchemaVersion uint      `json:"schema_version"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// SnapshotTrailer — последняя строка снимка; без неё снимок считается обрезанным
+type SnapshotTrailer struct {
+	Kind string `json:"kind"`
+	Rows int    `json:"rows"`
+}
+
+// SnapshotRow — строка таблицы; в файле kind — имя таблицы, data — строка в JSON
+type SnapshotRow struct {
+	Table string          `json:"kind"`
+	Data  json.RawMessage `json:"data"`
+}
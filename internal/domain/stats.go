@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// StatsBucket — шаг временного ряда статистики
+type StatsBucket string
+
+const (
+	BucketDay   StatsBucket = "day"
+	BucketWeek  StatsBucket = "week"
+	BucketMonth StatsBucket = "month"
+)
+
+// ParseStatsBucket проверяет шаг временного ряда; пустая строка — неделя
+func ParseStatsBucket(s string) (StatsBucket, error) {
+	switch b := StatsBucket(s); b {
+	case "":
+		return BucketWeek, nil
+	case BucketDay, BucketWeek, BucketMonth:
+		return b, nil
+	default:
+		return "", fmt.Errorf("%w: unknown bucket %q", ErrInvalidPeriod, s)
+	}
+}
+
+// StatsFilter — срез статистики: команда автора PR (пусто — все) и период [From, To)
+type StatsFilter struct {
+	TeamName string
+	From     time.Time
+	To       time.Time
+	Bucket   StatsBucket
+}
+
+// TimeToMerge — время от создания до мержа PR, смерженных за период
+type TimeToMerge struct {
+	Merged int
+	Median time.Duration
+	P90    time.Duration
+}
+
+// ThroughputPoint — сколько PR создано и смержено в периоде, начинающемся с PeriodStart
+type ThroughputPoint struct {
+	PeriodStart time.Time
+	Created     int
+	Merged      int
+}
+
+// ReviewerLoad — активность ревьюера за период
+type ReviewerLoad struct {
+	UserID string
+	// Assigned — назначения ревьюером
+	Assigned int
+	// Reviewed — первые действия по назначениям (ревью, комментарий, апрув)
+	Reviewed int
+	// ReassignedAway — сколько раз ревьюера заменили другим
+	ReassignedAway int
+}
+
+// AuthorReviewerPair — сколько раз ревьюер назначался на PR автора
+type AuthorReviewerPair struct {
+	AuthorID   string
+	ReviewerID string
+	Count      int
+}
+
+// PullRequestStats — статистика по PR и ревью за период
+type PullRequestStats struct {
+	Filter StatsFilter
+	// OpenAssignments — текущие назначения на OPEN PR по ревьюерам (не зависит от периода)
+	OpenAssignments map[string]int
+	TimeToMerge     TimeToMerge
+	Throughput      []ThroughputPoint
+	Reviewers       []ReviewerLoad
+	// Reassignments — всего замен ревьюеров за период
+	Reassignments int
+	Matrix        []AuthorReviewerPair
+}
+
+// ReviewSnapshot — текущее состояние ревью по всему сервису (для мониторинга)
+type ReviewSnapshot struct {
+	OpenPullRequests int
+	// OpenAssignmentsByTeam — назначения на OPEN PR по команде автора
+	OpenAssignmentsByTeam map[string]int
+	// Reassignments — всего замен ревьюеров за всё время
+	Reassignments int
+}
@@ -2,6 +2,12 @@ package domain
 
 import "fmt"
 
+// Membership — пользователь и его команда; TeamName пуст, если пользователь ни в какой команде не состоит
+type Membership struct {
+	User     User
+	TeamName string
+}
+
 type Team struct {
 	name    string
 	members []User
@@ -1,11 +1,17 @@
 package domain
 
-import "fmt"
+import (
+	"fmt"
+	"net/mail"
+)
 
 type User struct {
 	id       string
 	username string
 	isActive bool
+	// email необязателен: без него письма пользователю не отправляются
+	email       string
+	emailDigest bool
 }
 
 // NewUser создаёт нового пользователя
@@ -38,3 +44,32 @@ func (u *User) IsActive() bool {
 func (u *User) SetActive(active bool) {
 	u.isActive = active
 }
+
+// Email возвращает адрес для уведомлений (пустой, если не задан)
+func (u *User) Email() string {
+	return u.email
+}
+
+// EmailDigest — получать ли вместо писем на каждое событие один ежедневный дайджест
+func (u *User) EmailDigest() bool {
+	return u.emailDigest
+}
+
+// SetEmail задаёт адрес для уведомлений; пустая строка удаляет адрес
+func (u *User) SetEmail(email string) error {
+	if email == "" {
+		u.email = ""
+		return nil
+	}
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return fmt.Errorf("%w: %q", ErrInvalidEmail, email)
+	}
+	u.email = email
+	return nil
+}
+
+// SetEmailDigest включает или выключает ежедневный дайджест
+func (u *User) SetEmailDigest(digest bool) {
+	u.emailDigest = digest
+}
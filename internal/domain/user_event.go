@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type UserEventKind string
+
+const (
+	UserEventAssigned   UserEventKind = "assigned"
+	UserEventUnassigned UserEventKind = "unassigned"
+	UserEventMerged     UserEventKind = "merged"
+	UserEventClosed     UserEventKind = "closed"
+	UserEventReopened   UserEventKind = "reopened"
+)
+
+// UserEvent — событие из ленты конкретного пользователя (назначение, снятие, мерж, закрытие)
+type UserEvent struct {
+	Sequence          int64         `json:"id"`
+	Kind              UserEventKind `json:"kind"`
+	UserID            string        `json:"user_id"`
+	PullRequestID     string        `json:"pull_request_id"`
+	PullRequestName   string        `json:"pull_request_name"`
+	AuthorID          string        `json:"author_id"`
+	AssignedReviewers []string      `json:"assigned_reviewers"`
+	OccurredAt        time.Time     `json:"occurred_at"`
+}
+
+// UserEventsFromEvent раскладывает доменное событие PR на события пользователей:
+// назначенные ревьюеры получают assigned, снятый — unassigned, при мерже, закрытии и переоткрытии
+// ревьюеры и автор получают merged, closed и reopened.
+// Для остальных типов событий возвращает пустой список.
+func UserEventsFromEvent(event Event) ([]UserEvent, error) {
+	switch event.Type {
+	case EventPRCreated, EventReviewerReassigned, EventPRMerged, EventPRClosed, EventPRReopened:
+	default:
+		return nil, nil
+	}
+
+	var p PullRequestEventPayload
+	if err := json.Unmarshal(event.Payload, &p); err != nil {
+		return nil, err
+	}
+
+	newEvent := func(kind UserEventKind, userID string) UserEvent {
+		return UserEvent{
+			Sequence:          event.Sequence,
+			Kind:              kind,
+			UserID:            userID,
+			PullRequestID:     p.PullRequestID,
+			PullRequestName:   p.PullRequestName,
+			AuthorID:          p.AuthorID,
+			AssignedReviewers: p.AssignedReviewers,
+			OccurredAt:        event.OccurredAt,
+		}
+	}
+
+	var events []UserEvent
+	switch event.Type {
+	case EventPRCreated:
+		for _, r := range p.AssignedReviewers {
+			events = append(events, newEvent(UserEventAssigned, r))
+		}
+	case EventReviewerReassigned:
+		if p.OldReviewerID != "" {
+			events = append(events, newEvent(UserEventUnassigned, p.OldReviewerID))
+		}
+		if p.NewReviewerID != "" {
+			events = append(events, newEvent(UserEventAssigned, p.NewReviewerID))
+		}
+	default:
+		kind := map[EventType]UserEventKind{
+			EventPRMerged:   UserEventMerged,
+			EventPRClosed:   UserEventClosed,
+			EventPRReopened: UserEventReopened,
+		}[event.Type]
+		events = append(events, newEvent(kind, p.AuthorID))
+		for _, r := range p.AssignedReviewers {
+			events = append(events, newEvent(kind, r))
+		}
+	}
+	return events, nil
+}
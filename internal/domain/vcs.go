@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type VCSProvider string
+
+const (
+	VCSGitHub VCSProvider = "github"
+	VCSGitLab VCSProvider = "gitlab"
+)
+
+type VCSAction string
+
+const (
+	// VCSActionOpened — PR открыт, переоткрыт или выведен из черновика
+	VCSActionOpened VCSAction = "opened"
+	VCSActionMerged VCSAction = "merged"
+	// VCSActionClosed — PR закрыт без мержа
+	VCSActionClosed VCSAction = "closed"
+	// VCSActionReviewersChanged — в VCS сняли ревьюеров (RemovedReviewerLogins)
+	VCSActionReviewersChanged VCSAction = "reviewers_changed"
+	// VCSActionReviewed — ревьюер оставил ревью или апрув (ReviewerLogin)
+	VCSActionReviewed VCSAction = "reviewed"
+)
+
+// VCSPullRequestEvent — событие PR из системы контроля версий, независимое от провайдера
+type VCSPullRequestEvent struct {
+	Provider    VCSProvider
+	Action      VCSAction
+	Repository  string // полное имя репозитория, например "octo/hello"
+	Number      int
+	Title       string
+	AuthorLogin string
+	// AuthorExternalID — ID автора в VCS, если его логина в событии нет (author_id в хуке GitLab).
+	// Сопоставляется с пользователем только через user_identities.
+	AuthorExternalID string
+	// RemovedReviewerLogins — ревьюеры, снятые в VCS (для VCSActionReviewersChanged)
+	RemovedReviewerLogins []string
+	// ReviewerLogin — автор ревью (для VCSActionReviewed)
+	ReviewerLogin string
+}
+
+// PullRequestID — идентификатор PR в сервисе: "<provider>:<repository>#<number>",
+// для GitLab — "gitlab:<project>!<iid>", как merge request'ы обозначаются в самом GitLab
+func (e VCSPullRequestEvent) PullRequestID() string {
+	return fmt.Sprintf("%s:%s%s%d", e.Provider, e.Repository, numberSeparator(e.Provider), e.Number)
+}
+
+// ParseVCSPullRequestID разбирает идентификатор PR, созданного интеграцией.
+// ok == false для PR, заведённых вручную через API.
+func ParseVCSPullRequestID(id string) (provider VCSProvider, repository string, number int, ok bool) {
+	p, rest, found := strings.Cut(id, ":")
+	if !found {
+		return "", "", 0, false
+	}
+	provider = VCSProvider(p)
+	if provider != VCSGitHub && provider != VCSGitLab {
+		return "", "", 0, false
+	}
+
+	i := strings.LastIndex(rest, numberSeparator(provider))
+	if i <= 0 {
+		return "", "", 0, false
+	}
+	number, err := strconv.Atoi(rest[i+1:])
+	if err != nil || number <= 0 {
+		return "", "", 0, false
+	}
+	return provider, rest[:i], number, true
+}
+
+func numberSeparator(provider VCSProvider) string {
+	if provider == VCSGitLab {
+		return "!"
+	}
+	return "#"
+}
+
+// VCSReviewAction — действие с запросом ревью на PR во внешней системе
+type VCSReviewAction string
+
+const (
+	VCSReviewActionRequest VCSReviewAction = "REQUEST"
+	VCSReviewActionRemove  VCSReviewAction = "REMOVE"
+)
+
+// VCSReviewRequestDraft — запрос ревью, который нужно отправить в VCS
+type VCSReviewRequestDraft struct {
+	PullRequestID string
+	Action        VCSReviewAction
+	ReviewerIDs   []string
+}
+
+// VCSReviewRequest — запрос ревью, захваченный диспетчером
+type VCSReviewRequest struct {
+	ID            int64
+	Attempts      int // номер текущей попытки, начиная с 1
+	PullRequestID string
+	Action        VCSReviewAction
+	ReviewerIDs   []string
+}
@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "PENDING"
+	DeliveryDelivered DeliveryStatus = "DELIVERED"
+	DeliveryFailed    DeliveryStatus = "FAILED"
+)
+
+// WebhookSubscription — подписка внешней системы на события PR
+type WebhookSubscription struct {
+	id         int64
+	url        string
+	secret     string
+	eventTypes []EventType
+	isActive   bool
+	createdAt  time.Time
+}
+
+// NewWebhookSubscription создаёт новую активную подписку
+func NewWebhookSubscription(rawURL, secret string, eventTypes []EventType) (*WebhookSubscription, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%w: url must be an absolute http(s) URL", ErrInvalidWebhook)
+	}
+	if secret == "" {
+		return nil, fmt.Errorf("%w: secret is required", ErrInvalidWebhook)
+	}
+	if len(eventTypes) == 0 {
+		return nil, fmt.Errorf("%w: at least one event type is required", ErrInvalidWebhook)
+	}
+	for _, t := range eventTypes {
+		if !IsKnownEventType(t) {
+			return nil, fmt.Errorf("%w: unknown event type %s", ErrInvalidWebhook, t)
+		}
+	}
+
+	return &WebhookSubscription{
+		url:        rawURL,
+		secret:     secret,
+		eventTypes: eventTypes,
+		isActive:   true,
+		createdAt:  time.Now().UTC(),
+	}, nil
+}
+
+// RestoreWebhookSubscription создаёт подписку из данных БД (используется только адаптером)
+func RestoreWebhookSubscription(id int64, url, secret string, eventTypes []EventType, isActive bool, createdAt time.Time) *WebhookSubscription {
+	return &WebhookSubscription{
+		id:         id,
+		url:        url,
+		secret:     secret,
+		eventTypes: eventTypes,
+		isActive:   isActive,
+		createdAt:  createdAt,
+	}
+}
+
+func (s *WebhookSubscription) ID() int64            { return s.id }
+func (s *WebhookSubscription) URL() string          { return s.url }
+func (s *WebhookSubscription) Secret() string       { return s.secret }
+func (s *WebhookSubscription) IsActive() bool       { return s.isActive }
+func (s *WebhookSubscription) CreatedAt() time.Time { return s.createdAt }
+
+func (s *WebhookSubscription) EventTypes() []EventType {
+	types := make([]EventType, len(s.eventTypes))
+	copy(types, s.eventTypes)
+	return types
+}
+
+// WebhookDelivery — запись журнала доставки события подписчику
+type WebhookDelivery struct {
+	ID             int64
+	SubscriptionID int64
+	EventSequence  int64
+	EventType      EventType
+	Status         DeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastStatusCode *int
+	LastError      string
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+
+// WebhookAttempt — доставка, захваченная диспетчером для отправки
+type WebhookAttempt struct {
+	ID       int64
+	Attempts int // номер текущей попытки, начиная с 1
+	URL      string
+	Secret   string
+	Event    Event
+}
@@ -0,0 +1,15 @@
+package bulkImport
+
+import (
+	"context"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+type IdentityImporter interface {
+	ImportIdentities(ctx context.Context, identities []domain.UserIdentity) (created, updated int, err error)
+}
+
+type UserFinder interface {
+	GetUserByID(ctx context.Context, id string) (*domain.User, error)
+}
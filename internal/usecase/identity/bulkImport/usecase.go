@@ -0,0 +1,149 @@
+package bulkImport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/csvimport"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/tracing"
+)
+
+// maxRows — ограничение размера одного импорта
+const maxRows = 10000
+
+var requiredColumns = []string{"provider", "external_id", "user_id"}
+
+type Input struct {
+	// CSV с заголовком provider,external_id,user_id (порядок колонок любой)
+	CSV io.Reader
+}
+
+type Output struct {
+	Created int
+	Updated int
+}
+
+// RowError — ошибка в конкретной строке CSV (нумерация с 1, заголовок — строка 1)
+type RowError struct {
+	Line    int
+	Message string
+}
+
+// ImportError возвращается, если хотя бы одна строка некорректна; импорт при этом не выполняется
+type ImportError struct {
+	Rows []RowError
+}
+
+func (e *ImportError) Error() string {
+	return fmt.Sprintf("%d invalid rows", len(e.Rows))
+}
+
+func (e *ImportError) Unwrap() error { return domain.ErrInvalidIdentity }
+
+type Usecase struct {
+	importer   IdentityImporter
+	userFinder UserFinder
+}
+
+func NewUsecase(importer IdentityImporter, userFinder UserFinder) (*Usecase, error) {
+	if importer == nil || userFinder == nil {
+		return nil, errors.New("all dependencies are required")
+	}
+	return &Usecase{importer: importer, userFinder: userFinder}, nil
+}
+
+// Execute проверяет все строки и импортирует их атомарно: существующие привязки
+// перепривязываются к пользователю из файла, поэтому повторный импорт безопасен.
+func (u *Usecase) Execute(ctx context.Context, input Input) (*Output, error) {
+	ctx, span := tracing.Start(ctx, "identity/bulkImport.Execute")
+	defer span.End()
+
+	r, err := csvimport.NewReader(input.CSV, requiredColumns...)
+	if err != nil {
+		var lineErr *csvimport.LineError
+		if !errors.As(err, &lineErr) {
+			return nil, err
+		}
+		return nil, &ImportError{Rows: []RowError{rowError(lineErr)}}
+	}
+
+	var (
+		identities []domain.UserIdentity
+		rowErrors  []RowError
+		seen       = make(map[string]int)
+		knownUsers = make(map[string]bool)
+	)
+	for {
+		row, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		// Ошибка чтения источника повторяется на каждом вызове, продолжать бессмысленно
+		var lineErr *csvimport.LineError
+		if err != nil && !errors.As(err, &lineErr) {
+			return nil, err
+		}
+		line := row.Line
+		if lineErr != nil {
+			line = lineErr.Line
+		}
+		// Лимит считает и ошибочные строки, иначе файл из одних ошибок не ограничен
+		if len(identities)+len(rowErrors) >= maxRows {
+			return nil, &ImportError{Rows: []RowError{{Line: line, Message: fmt.Sprintf("too many rows, limit is %d", maxRows)}}}
+		}
+		if lineErr != nil {
+			rowErrors = append(rowErrors, rowError(lineErr))
+			continue
+		}
+
+		identity, err := domain.NewUserIdentity(row.Field("provider"), row.Field("external_id"), row.Field("user_id"))
+		if err != nil {
+			rowErrors = append(rowErrors, RowError{Line: line, Message: err.Error()})
+			continue
+		}
+
+		key := identity.Provider() + "\x00" + identity.ExternalID()
+		if prev, ok := seen[key]; ok {
+			rowErrors = append(rowErrors, RowError{Line: line, Message: fmt.Sprintf("duplicate of line %d", prev)})
+			continue
+		}
+		seen[key] = line
+
+		known, checked := knownUsers[identity.UserID()]
+		if !checked {
+			_, err := u.userFinder.GetUserByID(ctx, identity.UserID())
+			switch {
+			case err == nil:
+				known = true
+			case errors.Is(err, domain.ErrUserNotFound):
+				known = false
+			default:
+				return nil, err
+			}
+			knownUsers[identity.UserID()] = known
+		}
+		if !known {
+			rowErrors = append(rowErrors, RowError{Line: line, Message: fmt.Sprintf("user %q not found", identity.UserID())})
+			continue
+		}
+
+		identities = append(identities, *identity)
+	}
+
+	if len(rowErrors) > 0 {
+		return nil, &ImportError{Rows: rowErrors}
+	}
+
+	created, updated, err := u.importer.ImportIdentities(ctx, identities)
+	if err != nil {
+		return nil, err
+	}
+	return &Output{Created: created, Updated: updated}, nil
+}
+
+func rowError(err *csvimport.LineError) RowError {
+	return RowError{Line: err.Line, Message: err.Message}
+}
@@ -0,0 +1,118 @@
+package bulkImport
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+type fakeImporter struct {
+	imported []domain.UserIdentity
+}
+
+func (f *fakeImporter) ImportIdentities(_ context.Context, identities []domain.UserIdentity) (int, int, error) {
+	f.imported = identities
+	return len(identities), 0, nil
+}
+
+type fakeUsers struct{}
+
+func (fakeUsers) GetUserByID(_ context.Context, id string) (*domain.User, error) {
+	if id == "ghost" {
+		return nil, domain.ErrUserNotFound
+	}
+	return domain.NewUser(id, "name-"+id, true)
+}
+
+func newTestUsecase(t *testing.T) (*Usecase, *fakeImporter) {
+	t.Helper()
+	importer := &fakeImporter{}
+	uc, err := NewUsecase(importer, fakeUsers{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return uc, importer
+}
+
+func csvRows(n int, row string) string {
+	var b strings.Builder
+	b.WriteString("provider,external_id,user_id\n")
+	for range n {
+		b.WriteString(row)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func TestExecuteImportsValidRows(t *testing.T) {
+	uc, importer := newTestUsecase(t)
+	data := "user_id,provider,external_id\nu1,github,alice\nu2,gitlab,42\n"
+
+	out, err := uc.Execute(context.Background(), Input{CSV: strings.NewReader(data)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Created != 2 || len(importer.imported) != 2 {
+		t.Errorf("created = %d, imported = %d, want 2", out.Created, len(importer.imported))
+	}
+}
+
+func TestExecuteCollectsRowErrors(t *testing.T) {
+	uc, importer := newTestUsecase(t)
+	data := "provider,external_id,user_id\n" +
+		"github,alice,u1\n" +
+		"github,alice,u2\n" +
+		"github,b\"ob,u3\n" +
+		"github,carol,ghost\n"
+
+	_, err := uc.Execute(context.Background(), Input{CSV: strings.NewReader(data)})
+	var importErr *ImportError
+	if !errors.As(err, &importErr) {
+		t.Fatalf("error = %v, want *ImportError", err)
+	}
+	lines := make([]int, 0, len(importErr.Rows))
+	for _, r := range importErr.Rows {
+		lines = append(lines, r.Line)
+	}
+	if want := []int{3, 4, 5}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("error lines = %v, want %v", lines, want)
+	}
+	if importer.imported != nil {
+		t.Error("nothing must be imported when a row is invalid")
+	}
+}
+
+func TestExecuteLimitsRowsWithErrors(t *testing.T) {
+	uc, _ := newTestUsecase(t)
+	data := csvRows(maxRows+1, "github,b\"ad,u1")
+
+	_, err := uc.Execute(context.Background(), Input{CSV: strings.NewReader(data)})
+	var importErr *ImportError
+	if !errors.As(err, &importErr) {
+		t.Fatalf("error = %v, want *ImportError", err)
+	}
+	if len(importErr.Rows) != 1 || !strings.Contains(importErr.Rows[0].Message, "too many rows") {
+		t.Errorf("rows = %+v, want a single row limit error", importErr.Rows)
+	}
+}
+
+func TestExecuteStopsOnBodyOverLimit(t *testing.T) {
+	uc, importer := newTestUsecase(t)
+	data := csvRows(1000, "github,alice,u1")
+	body := http.MaxBytesReader(nil, io.NopCloser(strings.NewReader(data)), int64(len(data)/2))
+
+	_, err := uc.Execute(context.Background(), Input{CSV: body})
+	var tooLarge *http.MaxBytesError
+	if !errors.As(err, &tooLarge) {
+		t.Fatalf("error = %v, want *http.MaxBytesError", err)
+	}
+	if importer.imported != nil {
+		t.Error("nothing must be imported from a truncated body")
+	}
+}
@@ -0,0 +1,15 @@
+package create
+
+import (
+	"context"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+type IdentityCreator interface {
+	CreateIdentity(ctx context.Context, identity *domain.UserIdentity) error
+}
+
+type UserFinder interface {
+	GetUserByID(ctx context.Context, id string) (*domain.User, error)
+}
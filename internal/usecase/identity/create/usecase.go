@@ -0,0 +1,46 @@
+package create
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/tracing"
+)
+
+type Input struct {
+	Provider   string
+	ExternalID string
+	UserID     string
+}
+
+type Usecase struct {
+	creator    IdentityCreator
+	userFinder UserFinder
+}
+
+func NewUsecase(creator IdentityCreator, userFinder UserFinder) (*Usecase, error) {
+	if creator == nil || userFinder == nil {
+		return nil, errors.New("all dependencies are required")
+	}
+	return &Usecase{creator: creator, userFinder: userFinder}, nil
+}
+
+func (u *Usecase) Execute(ctx context.Context, input Input) (*domain.UserIdentity, error) {
+	ctx, span := tracing.Start(ctx, "identity/create.Execute")
+	defer span.End()
+
+	identity, err := domain.NewUserIdentity(input.Provider, input.ExternalID, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := u.userFinder.GetUserByID(ctx, identity.UserID()); err != nil {
+		return nil, err
+	}
+
+	if err := u.creator.CreateIdentity(ctx, identity); err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
@@ -0,0 +1,7 @@
+package delete
+
+import "context"
+
+type IdentityDeleter interface {
+	DeleteIdentity(ctx context.Context, provider, externalID string) error
+}
@@ -0,0 +1,30 @@
+package delete
+
+import (
+	"context"
+	"errors"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/tracing"
+)
+
+type Input struct {
+	Provider   string
+	ExternalID string
+}
+
+type Usecase struct {
+	deleter IdentityDeleter
+}
+
+func NewUsecase(deleter IdentityDeleter) (*Usecase, error) {
+	if deleter == nil {
+		return nil, errors.New("deleter is required")
+	}
+	return &Usecase{deleter: deleter}, nil
+}
+
+func (u *Usecase) Execute(ctx context.Context, input Input) error {
+	ctx, span := tracing.Start(ctx, "identity/delete.Execute")
+	defer span.End()
+
+	return u.deleter.DeleteIdentity(ctx, input.Provider, input.ExternalID)
+}
@@ -0,0 +1,11 @@
+package list
+
+import (
+	"context"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+type IdentityLister interface {
+	ListIdentities(ctx context.Context, userID, provider string) ([]domain.UserIdentity, error)
+}
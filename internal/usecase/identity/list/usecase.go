@@ -0,0 +1,33 @@
+package list
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/tracing"
+)
+
+// Input — необязательные фильтры; пустое значение означает «все»
+type Input struct {
+	UserID   string
+	Provider string
+}
+
+type Usecase struct {
+	lister IdentityLister
+}
+
+func NewUsecase(lister IdentityLister) (*Usecase, error) {
+	if lister == nil {
+		return nil, errors.New("lister is required")
+	}
+	return &Usecase{lister: lister}, nil
+}
+
+func (u *Usecase) Execute(ctx context.Context, input Input) ([]domain.UserIdentity, error) {
+	ctx, span := tracing.Start(ctx, "identity/list.Execute")
+	defer span.End()
+
+	return u.lister.ListIdentities(ctx, input.UserID, input.Provider)
+}
@@ -0,0 +1,11 @@
+package lookup
+
+import (
+	"context"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+type IdentityFinder interface {
+	LookupUser(ctx context.Context, provider, externalID string) (*domain.User, error)
+}
@@ -0,0 +1,33 @@
+package lookup
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/tracing"
+)
+
+type Input struct {
+	Provider   string
+	ExternalID string
+}
+
+type Usecase struct {
+	finder IdentityFinder
+}
+
+func NewUsecase(finder IdentityFinder) (*Usecase, error) {
+	if finder == nil {
+		return nil, errors.New("finder is required")
+	}
+	return &Usecase{finder: finder}, nil
+}
+
+// Execute находит пользователя по учётной записи во внешней системе
+func (u *Usecase) Execute(ctx context.Context, input Input) (*domain.User, error) {
+	ctx, span := tracing.Start(ctx, "identity/lookup.Execute")
+	defer span.End()
+
+	return u.finder.LookupUser(ctx, input.Provider, input.ExternalID)
+}
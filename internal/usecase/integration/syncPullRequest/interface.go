@@ -0,0 +1,47 @@
+package syncPullRequest
+
+import (
+	"context"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	prClose "github.com/Skorpsrgvch/reviewer-service/internal/usecase/pullrequest/close"
+	prCreate "github.com/Skorpsrgvch/reviewer-service/internal/usecase/pullrequest/create"
+	prMerge "github.com/Skorpsrgvch/reviewer-service/internal/usecase/pullrequest/merge"
+	prReassign "github.com/Skorpsrgvch/reviewer-service/internal/usecase/pullrequest/reassign"
+	prRecordReview "github.com/Skorpsrgvch/reviewer-service/internal/usecase/pullrequest/recordReview"
+	prReopen "github.com/Skorpsrgvch/reviewer-service/internal/usecase/pullrequest/reopen"
+)
+
+// UserResolver находит пользователя сервиса по логину в VCS
+type UserResolver interface {
+	ResolveUser(ctx context.Context, provider domain.VCSProvider, login string) (*domain.User, error)
+}
+
+// IdentityFinder находит пользователя по учётной записи во внешней системе
+type IdentityFinder interface {
+	LookupUser(ctx context.Context, provider, externalID string) (*domain.User, error)
+}
+
+type PullRequestCreator interface {
+	Execute(ctx context.Context, input prCreate.Input) (*domain.PullRequest, error)
+}
+
+type PullRequestMerger interface {
+	Execute(ctx context.Context, input prMerge.Input) (*domain.PullRequest, error)
+}
+
+type PullRequestCloser interface {
+	Execute(ctx context.Context, input prClose.Input) (*domain.PullRequest, error)
+}
+
+type PullRequestReopener interface {
+	Execute(ctx context.Context, input prReopen.Input) (*prReopen.Output, error)
+}
+
+type ReviewerReassigner interface {
+	Execute(ctx context.Context, input prReassign.Input) (*domain.PullRequest, string, error)
+}
+
+type ReviewRecorder interface {
+	Execute(ctx context.Context, input prRecordReview.Input) (*prRecordReview.Output, error)
+}
@@ -0,0 +1,271 @@
+package syncPullRequest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	prClose "github.com/Skorpsrgvch/reviewer-service/internal/usecase/pullrequest/close"
+	prCreate "github.com/Skorpsrgvch/reviewer-service/internal/usecase/pullrequest/create"
+	prMerge "github.com/Skorpsrgvch/reviewer-service/internal/usecase/pullrequest/merge"
+	prReassign "github.com/Skorpsrgvch/reviewer-service/internal/usecase/pullrequest/reassign"
+	prRecordReview "github.com/Skorpsrgvch/reviewer-service/internal/usecase/pullrequest/recordReview"
+	prReopen "github.com/Skorpsrgvch/reviewer-service/internal/usecase/pullrequest/reopen"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/logger"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/tracing"
+)
+
+type Result string
+
+const (
+	ResultCreated Result = "created"
+	ResultMerged  Result = "merged"
+	// ResultClosed — PR закрыт без мержа, ревьюеры сняты
+	ResultClosed Result = "closed"
+	// ResultReopened — закрытый PR переоткрыт с прежними ревьюерами
+	ResultReopened Result = "reopened"
+	// ResultReassigned — снятые в VCS ревьюеры заменены другими участниками команды
+	ResultReassigned Result = "reassigned"
+	// ResultReviewed — зафиксировано действие ревьюера для учёта SLA
+	ResultReviewed Result = "reviewed"
+	// ResultIgnored — событие не требует действий (PR уже заведён, уже смержен и т.п.)
+	ResultIgnored Result = "ignored"
+	// ResultSkipped — событие требовало действия, но выполнить его нельзя (неизвестный автор и т.п.)
+	ResultSkipped Result = "skipped"
+)
+
+type Output struct {
+	Result        Result
+	PullRequestID string
+	Reason        string
+}
+
+// Usecase переносит события PR из VCS в сервис через юзкейсы создания, мержа, закрытия,
+// переоткрытия, переназначения и фиксации ревью.
+// Не зависит от провайдера: провайдер-специфичен только разбор вебхука.
+type Usecase struct {
+	users      UserResolver
+	identities IdentityFinder
+	creator    PullRequestCreator
+	merger     PullRequestMerger
+	closer     PullRequestCloser
+	reopener   PullRequestReopener
+	reassigner ReviewerReassigner
+	reviews    ReviewRecorder
+}
+
+func NewUsecase(users UserResolver, identities IdentityFinder, creator PullRequestCreator, merger PullRequestMerger,
+	closer PullRequestCloser, reopener PullRequestReopener, reassigner ReviewerReassigner, reviews ReviewRecorder) (*Usecase, error) {
+	if users == nil || identities == nil || creator == nil || merger == nil || closer == nil || reopener == nil ||
+		reassigner == nil || reviews == nil {
+		return nil, errors.New("all dependencies are required")
+	}
+	return &Usecase{
+		users:      users,
+		identities: identities,
+		creator:    creator,
+		merger:     merger,
+		closer:     closer,
+		reopener:   reopener,
+		reassigner: reassigner,
+		reviews:    reviews,
+	}, nil
+}
+
+// Execute обрабатывает событие. Ожидаемые ситуации (неизвестный автор, нет ревьюеров)
+// возвращаются как ResultSkipped с причиной, а не как ошибка — чтобы VCS не повторяла доставку.
+func (u *Usecase) Execute(ctx context.Context, event domain.VCSPullRequestEvent) (*Output, error) {
+	ctx, span := tracing.Start(ctx, "integration/syncPullRequest.Execute")
+	defer span.End()
+
+	prID := event.PullRequestID()
+
+	switch event.Action {
+	case domain.VCSActionOpened:
+		return u.open(ctx, event, prID)
+	case domain.VCSActionMerged:
+		return u.merge(ctx, event, prID)
+	case domain.VCSActionClosed:
+		return u.close(ctx, event, prID)
+	case domain.VCSActionReviewersChanged:
+		return u.reassign(ctx, event, prID)
+	case domain.VCSActionReviewed:
+		return u.review(ctx, event, prID)
+	default:
+		return &Output{Result: ResultIgnored, PullRequestID: prID, Reason: fmt.Sprintf("action %q is not tracked", event.Action)}, nil
+	}
+}
+
+// open переоткрывает закрытый PR или заводит новый
+func (u *Usecase) open(ctx context.Context, event domain.VCSPullRequestEvent, prID string) (*Output, error) {
+	reopened, err := u.reopener.Execute(ctx, prReopen.Input{PullRequestID: prID})
+	switch {
+	case err == nil && reopened.Reopened:
+		return &Output{Result: ResultReopened, PullRequestID: prID}, nil
+	case err == nil:
+		// reopened / ready_for_review для уже заведённого открытого PR
+		return &Output{Result: ResultIgnored, PullRequestID: prID, Reason: "pull request already exists"}, nil
+	case errors.Is(err, domain.ErrPRAlreadyMerged):
+		return &Output{Result: ResultIgnored, PullRequestID: prID, Reason: "pull request is already merged"}, nil
+	case !errors.Is(err, domain.ErrPRNotFound):
+		return nil, err
+	}
+
+	author, err := u.resolveAuthor(ctx, event)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return u.skip(ctx, event, prID, fmt.Sprintf("unknown %s author %q", event.Provider, authorRef(event))), nil
+		}
+		return nil, err
+	}
+
+	_, err = u.creator.Execute(ctx, prCreate.Input{
+		PullRequestID:   prID,
+		PullRequestName: event.Title,
+		AuthorID:        author.ID(),
+	})
+	switch {
+	case err == nil:
+		return &Output{Result: ResultCreated, PullRequestID: prID}, nil
+	case errors.Is(err, domain.ErrPRExists):
+		// PR завели параллельно этому событию
+		return &Output{Result: ResultIgnored, PullRequestID: prID, Reason: "pull request already exists"}, nil
+	case errors.Is(err, domain.ErrAuthorNotFound):
+		return u.skip(ctx, event, prID, fmt.Sprintf("author %q is not a member of any team", author.ID())), nil
+	case errors.Is(err, domain.ErrNoActiveReviewers):
+		return u.skip(ctx, event, prID, "no active reviewers in author's team"), nil
+	default:
+		return nil, err
+	}
+}
+
+// resolveAuthor находит автора PR по логину, а если по логину не нашёлся или логина нет —
+// по ID учётной записи в VCS (например, числовому author_id из GitLab)
+func (u *Usecase) resolveAuthor(ctx context.Context, event domain.VCSPullRequestEvent) (*domain.User, error) {
+	if event.AuthorLogin != "" || event.AuthorExternalID == "" {
+		user, err := u.users.ResolveUser(ctx, event.Provider, event.AuthorLogin)
+		if !errors.Is(err, domain.ErrUserNotFound) || event.AuthorExternalID == "" {
+			return user, err
+		}
+	}
+	user, err := u.identities.LookupUser(ctx, string(event.Provider), event.AuthorExternalID)
+	if errors.Is(err, domain.ErrIdentityNotFound) {
+		return nil, domain.ErrUserNotFound
+	}
+	return user, err
+}
+
+func authorRef(event domain.VCSPullRequestEvent) string {
+	if event.AuthorLogin != "" {
+		return event.AuthorLogin
+	}
+	return event.AuthorExternalID
+}
+
+func (u *Usecase) merge(ctx context.Context, event domain.VCSPullRequestEvent, prID string) (*Output, error) {
+	_, err := u.merger.Execute(ctx, prMerge.Input{PullRequestID: prID})
+	switch {
+	case err == nil:
+		return &Output{Result: ResultMerged, PullRequestID: prID}, nil
+	case errors.Is(err, domain.ErrPRNotFound):
+		return u.skip(ctx, event, prID, "pull request is not tracked"), nil
+	case errors.Is(err, domain.ErrPRClosed):
+		return &Output{Result: ResultIgnored, PullRequestID: prID, Reason: "pull request is closed"}, nil
+	default:
+		return nil, err
+	}
+}
+
+// close закрывает PR, закрытый в VCS без мержа
+func (u *Usecase) close(ctx context.Context, event domain.VCSPullRequestEvent, prID string) (*Output, error) {
+	_, err := u.closer.Execute(ctx, prClose.Input{PullRequestID: prID})
+	switch {
+	case err == nil:
+		return &Output{Result: ResultClosed, PullRequestID: prID}, nil
+	case errors.Is(err, domain.ErrPRNotFound):
+		return u.skip(ctx, event, prID, "pull request is not tracked"), nil
+	case errors.Is(err, domain.ErrPRAlreadyMerged):
+		return &Output{Result: ResultIgnored, PullRequestID: prID, Reason: "pull request is already merged"}, nil
+	default:
+		return nil, err
+	}
+}
+
+// reassign заменяет ревьюеров, снятых в VCS, если они были назначены сервисом
+func (u *Usecase) reassign(ctx context.Context, event domain.VCSPullRequestEvent, prID string) (*Output, error) {
+	var (
+		reassigned int
+		reasons    []string
+	)
+	for _, login := range event.RemovedReviewerLogins {
+		reviewer, err := u.users.ResolveUser(ctx, event.Provider, login)
+		if err != nil {
+			if errors.Is(err, domain.ErrUserNotFound) {
+				reasons = append(reasons, fmt.Sprintf("unknown %s reviewer %q", event.Provider, login))
+				continue
+			}
+			return nil, err
+		}
+
+		_, _, err = u.reassigner.Execute(ctx, prReassign.Input{PullRequestID: prID, OldReviewerID: reviewer.ID()})
+		switch {
+		case err == nil:
+			reassigned++
+		case errors.Is(err, domain.ErrPRNotFound):
+			return u.skip(ctx, event, prID, "pull request is not tracked"), nil
+		case errors.Is(err, domain.ErrPRAlreadyMerged):
+			return &Output{Result: ResultIgnored, PullRequestID: prID, Reason: "pull request is already merged"}, nil
+		case errors.Is(err, domain.ErrPRClosed):
+			return &Output{Result: ResultIgnored, PullRequestID: prID, Reason: "pull request is closed"}, nil
+		case errors.Is(err, domain.ErrReviewerNotAssigned):
+			// Ревьюера назначили вручную в VCS, а не через сервис
+			reasons = append(reasons, fmt.Sprintf("reviewer %q is not assigned by the service", reviewer.ID()))
+		case errors.Is(err, domain.ErrNoActiveReviewers):
+			reasons = append(reasons, fmt.Sprintf("no replacement for reviewer %q", reviewer.ID()))
+		default:
+			return nil, err
+		}
+	}
+
+	reason := strings.Join(reasons, "; ")
+	if reassigned == 0 {
+		return u.skip(ctx, event, prID, reason), nil
+	}
+	return &Output{Result: ResultReassigned, PullRequestID: prID, Reason: reason}, nil
+}
+
+// review фиксирует ревью или апрув назначенного ревьюера
+func (u *Usecase) review(ctx context.Context, event domain.VCSPullRequestEvent, prID string) (*Output, error) {
+	reviewer, err := u.users.ResolveUser(ctx, event.Provider, event.ReviewerLogin)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return u.skip(ctx, event, prID, fmt.Sprintf("unknown %s reviewer %q", event.Provider, event.ReviewerLogin)), nil
+		}
+		return nil, err
+	}
+
+	_, err = u.reviews.Execute(ctx, prRecordReview.Input{PullRequestID: prID, ReviewerID: reviewer.ID()})
+	switch {
+	case err == nil:
+		return &Output{Result: ResultReviewed, PullRequestID: prID}, nil
+	case errors.Is(err, domain.ErrPRNotFound):
+		return u.skip(ctx, event, prID, "pull request is not tracked"), nil
+	case errors.Is(err, domain.ErrPRAlreadyMerged):
+		return &Output{Result: ResultIgnored, PullRequestID: prID, Reason: "pull request is already merged"}, nil
+	case errors.Is(err, domain.ErrPRClosed):
+		return &Output{Result: ResultIgnored, PullRequestID: prID, Reason: "pull request is closed"}, nil
+	case errors.Is(err, domain.ErrReviewerNotAssigned):
+		// Ревью от автора или от участника, которого сервис не назначал
+		return &Output{Result: ResultIgnored, PullRequestID: prID, Reason: fmt.Sprintf("%q is not an assigned reviewer", reviewer.ID())}, nil
+	default:
+		return nil, err
+	}
+}
+
+func (u *Usecase) skip(ctx context.Context, event domain.VCSPullRequestEvent, prID, reason string) *Output {
+	logger.FromContext(ctx).Info("VCS event skipped",
+		"provider", event.Provider, "action", event.Action, "pull_request_id", prID, "reason", reason)
+	return &Output{Result: ResultSkipped, PullRequestID: prID, Reason: reason}
+}
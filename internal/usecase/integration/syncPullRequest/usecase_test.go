@@ -0,0 +1,65 @@
+package syncPullRequest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+// fakeUsers находит пользователя по логину, совпадающему с его ID
+type fakeUsers map[string]bool
+
+func (f fakeUsers) ResolveUser(_ context.Context, _ domain.VCSProvider, login string) (*domain.User, error) {
+	if !f[login] {
+		return nil, domain.ErrUserNotFound
+	}
+	return domain.NewUser(login, login, true)
+}
+
+// fakeIdentities — привязки внешних ID к пользователям
+type fakeIdentities map[string]string
+
+func (f fakeIdentities) LookupUser(_ context.Context, _, externalID string) (*domain.User, error) {
+	userID, ok := f[externalID]
+	if !ok {
+		return nil, domain.ErrIdentityNotFound
+	}
+	return domain.NewUser(userID, userID, true)
+}
+
+func TestResolveAuthor(t *testing.T) {
+	u := &Usecase{
+		users:      fakeUsers{"alice": true},
+		identities: fakeIdentities{"42": "bob"},
+	}
+	tests := []struct {
+		name       string
+		login      string
+		externalID string
+		wantUser   string
+		wantErr    error
+	}{
+		{name: "by login", login: "alice", externalID: "42", wantUser: "alice"},
+		{name: "by external ID without login", externalID: "42", wantUser: "bob"},
+		{name: "by external ID when login is unknown", login: "robert", externalID: "42", wantUser: "bob"},
+		{name: "unknown login without external ID", login: "robert", wantErr: domain.ErrUserNotFound},
+		{name: "unknown login and external ID", login: "robert", externalID: "7", wantErr: domain.ErrUserNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := u.resolveAuthor(context.Background(), domain.VCSPullRequestEvent{
+				Provider:         domain.VCSGitLab,
+				AuthorLogin:      tt.login,
+				AuthorExternalID: tt.externalID,
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && user.ID() != tt.wantUser {
+				t.Errorf("author = %s, want %s", user.ID(), tt.wantUser)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package close
+
+import (
+	"context"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+type PullRequestFinder interface {
+	GetByID(ctx context.Context, id string) (*domain.PullRequest, error)
+}
+
+type PullRequestCloser interface {
+	// Close возвращает domain.ErrPRVersionConflict, если версия PR изменилась
+	Close(ctx context.Context, id string, closedAt time.Time, expectedVersion int) error
+}
@@ -0,0 +1,65 @@
+package close
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/tracing"
+)
+
+// maxAttempts — сколько раз повторяем закрытие при конфликте версий
+const maxAttempts = 3
+
+type Input struct {
+	PullRequestID string
+}
+
+// Usecase закрывает PR без мержа: ревьюеры перестают его ждать, напоминания и SLA
+// на него больше не распространяются
+type Usecase struct {
+	prFinder PullRequestFinder
+	prCloser PullRequestCloser
+}
+
+func NewUsecase(prFinder PullRequestFinder, prCloser PullRequestCloser) (*Usecase, error) {
+	if prFinder == nil || prCloser == nil {
+		return nil, errors.New("prFinder and prCloser are required")
+	}
+	return &Usecase{prFinder: prFinder, prCloser: prCloser}, nil
+}
+
+func (u *Usecase) Execute(ctx context.Context, input Input) (*domain.PullRequest, error) {
+	ctx, span := tracing.Start(ctx, "pullrequest/close.Execute")
+	defer span.End()
+
+	for attempt := 1; ; attempt++ {
+		pr, err := u.close(ctx, input)
+		if !errors.Is(err, domain.ErrPRVersionConflict) || attempt >= maxAttempts {
+			return pr, err
+		}
+	}
+}
+
+func (u *Usecase) close(ctx context.Context, input Input) (*domain.PullRequest, error) {
+	pr, err := u.prFinder.GetByID(ctx, input.PullRequestID)
+	if err != nil {
+		return nil, err
+	}
+
+	if pr.Status() == domain.PRClosed {
+		// Идемпотентность: возвращаем существующий PR
+		return pr, nil
+	}
+	if err := pr.CheckOpen(); err != nil {
+		return nil, err
+	}
+
+	if err := u.prCloser.Close(ctx, input.PullRequestID, time.Now().UTC(), pr.Version()); err != nil {
+		return nil, err
+	}
+
+	_ = pr.Close() // безопасно, потому что только что закрыли
+	return pr, nil
+}
@@ -6,6 +6,7 @@ import (
 	"math/rand"
 
 	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/tracing"
 )
 
 type Input struct {
@@ -15,23 +16,32 @@ type Input struct {
 }
 
 type Usecase struct {
-	prSaver    PullRequestSaver
-	userFinder UserFinder
-	teamFinder TeamFinder
+	prSaver        PullRequestSaver
+	userFinder     UserFinder
+	teamFinder     TeamFinder
+	reviewersPerPR int
 }
 
-func NewUsecase(prSaver PullRequestSaver, userFinder UserFinder, teamFinder TeamFinder) (*Usecase, error) {
+// NewUsecase создаёт юзкейс; reviewersPerPR — сколько ревьюеров назначать, если в команде хватает кандидатов
+func NewUsecase(prSaver PullRequestSaver, userFinder UserFinder, teamFinder TeamFinder, reviewersPerPR int) (*Usecase, error) {
 	if prSaver == nil || userFinder == nil || teamFinder == nil {
 		return nil, errors.New("all dependencies are required")
 	}
+	if reviewersPerPR <= 0 {
+		return nil, errors.New("reviewersPerPR must be positive")
+	}
 	return &Usecase{
-		prSaver:    prSaver,
-		userFinder: userFinder,
-		teamFinder: teamFinder,
+		prSaver:        prSaver,
+		userFinder:     userFinder,
+		teamFinder:     teamFinder,
+		reviewersPerPR: reviewersPerPR,
 	}, nil
 }
 
 func (u *Usecase) Execute(ctx context.Context, input Input) (*domain.PullRequest, error) {
+	ctx, span := tracing.Start(ctx, "pullrequest/create.Execute")
+	defer span.End()
+
 	// Проверка существования PR
 	exists, err := u.prSaver.PRExists(ctx, input.PullRequestID)
 	if err != nil {
@@ -67,12 +77,12 @@ func (u *Usecase) Execute(ctx context.Context, input Input) (*domain.PullRequest
 		return nil, domain.ErrNoActiveReviewers
 	}
 
-	// Выбираем до 2 случайных
+	// Выбираем до reviewersPerPR случайных
 	rand.Shuffle(len(candidates), func(i, j int) {
 		candidates[i], candidates[j] = candidates[j], candidates[i]
 	})
 	var assigned []string
-	for i := 0; i < len(candidates) && i < 2; i++ {
+	for i := 0; i < len(candidates) && i < u.reviewersPerPR; i++ {
 		assigned = append(assigned, candidates[i])
 	}
 
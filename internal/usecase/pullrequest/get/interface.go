@@ -0,0 +1,11 @@
+package get
+
+import (
+	"context"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+type PullRequestFinder interface {
+	GetByID(ctx context.Context, id string) (*domain.PullRequest, error)
+}
@@ -0,0 +1,32 @@
+package get
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/tracing"
+)
+
+type Input struct {
+	PullRequestID string
+}
+
+type Usecase struct {
+	prFinder PullRequestFinder
+}
+
+func NewUsecase(prFinder PullRequestFinder) (*Usecase, error) {
+	if prFinder == nil {
+		return nil, errors.New("prFinder is required")
+	}
+	return &Usecase{prFinder: prFinder}, nil
+}
+
+// Execute возвращает PR вместе с текущей версией (для ETag / If-Match).
+func (u *Usecase) Execute(ctx context.Context, input Input) (*domain.PullRequest, error) {
+	ctx, span := tracing.Start(ctx, "pullrequest/get.Execute")
+	defer span.End()
+
+	return u.prFinder.GetByID(ctx, input.PullRequestID)
+}
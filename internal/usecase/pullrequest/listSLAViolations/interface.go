@@ -0,0 +1,12 @@
+package listSLAViolations
+
+import (
+	"context"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+type SLAViolationFinder interface {
+	ListSLAViolations(ctx context.Context, teamName string, now, dueBefore time.Time, defaultSLA time.Duration) ([]domain.SLAViolation, error)
+}
@@ -0,0 +1,51 @@
+package listSLAViolations
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/tracing"
+)
+
+// DefaultNearBreach — за сколько до срока назначение считается под угрозой
+const DefaultNearBreach = 4 * time.Hour
+
+type Input struct {
+	TeamName string
+	// NearBreach — окно «скоро нарушится»; 0 — DefaultNearBreach
+	NearBreach time.Duration
+	Now        time.Time
+}
+
+// Usecase возвращает ревьюеров OPEN PR, которые не отреагировали в срок или скоро его нарушат
+type Usecase struct {
+	finder     SLAViolationFinder
+	defaultSLA time.Duration
+}
+
+// NewUsecase создаёт юзкейс. defaultSLA действует для команд без своего SLA.
+func NewUsecase(finder SLAViolationFinder, defaultSLA time.Duration) (*Usecase, error) {
+	if finder == nil {
+		return nil, errors.New("finder is required")
+	}
+	if defaultSLA <= 0 {
+		return nil, errors.New("default SLA must be positive")
+	}
+	return &Usecase{finder: finder, defaultSLA: defaultSLA}, nil
+}
+
+func (u *Usecase) Execute(ctx context.Context, input Input) ([]domain.SLAViolation, error) {
+	ctx, span := tracing.Start(ctx, "pullrequest/listSLAViolations.Execute")
+	defer span.End()
+
+	nearBreach := input.NearBreach
+	if nearBreach == 0 {
+		nearBreach = DefaultNearBreach
+	}
+	if nearBreach < 0 {
+		return nil, errors.New("near breach window must not be negative")
+	}
+	return u.finder.ListSLAViolations(ctx, input.TeamName, input.Now, input.Now.Add(nearBreach), u.defaultSLA)
+}
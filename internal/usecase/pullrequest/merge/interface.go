@@ -12,5 +12,6 @@ type PullRequestFinder interface {
 }
 
 type PullRequestMerger interface {
-	Merge(ctx context.Context, id string, mergedAt time.Time) error
+	// Merge возвращает domain.ErrPRVersionConflict, если версия PR изменилась
+	Merge(ctx context.Context, id string, mergedAt time.Time, expectedVersion int) error
 }
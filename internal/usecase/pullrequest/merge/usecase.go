@@ -6,10 +6,16 @@ import (
 	"time"
 
 	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/tracing"
 )
 
+// maxAttempts — сколько раз повторяем мерж при конфликте версий
+const maxAttempts = 3
+
 type Input struct {
 	PullRequestID string
+	// ExpectedVersion — версия из If-Match. Если задана, повторов при конфликте нет.
+	ExpectedVersion *int
 }
 
 type Usecase struct {
@@ -27,6 +33,27 @@ func NewUsecase(prFinder, prMerger interface{}) (*Usecase, error) {
 }
 
 func (u *Usecase) Execute(ctx context.Context, input Input) (*domain.PullRequest, error) {
+	ctx, span := tracing.Start(ctx, "pullrequest/merge.Execute")
+	defer span.End()
+
+	for attempt := 1; ; attempt++ {
+		pr, err := u.merge(ctx, input)
+		if !errors.Is(err, domain.ErrPRVersionConflict) {
+			return pr, err
+		}
+
+		// Клиент прислал If-Match — PR изменился после чтения, повторять нельзя
+		if input.ExpectedVersion != nil {
+			return nil, domain.ErrPRVersionMismatch
+		}
+		if attempt >= maxAttempts {
+			return nil, err
+		}
+	}
+}
+
+// merge выполняет одну попытку: чтение PR и условный мерж.
+func (u *Usecase) merge(ctx context.Context, input Input) (*domain.PullRequest, error) {
 	pr, err := u.prFinder.GetByID(ctx, input.PullRequestID)
 	if err != nil {
 		return nil, err
@@ -36,9 +63,16 @@ func (u *Usecase) Execute(ctx context.Context, input Input) (*domain.PullRequest
 		// Идемпотентность: возвращаем существующий PR
 		return pr, nil
 	}
+	if err := pr.CanBeMerged(); err != nil {
+		return nil, err
+	}
+
+	if input.ExpectedVersion != nil && pr.Version() != *input.ExpectedVersion {
+		return nil, domain.ErrPRVersionMismatch
+	}
 
 	// Выполняем мерж в БД
-	if err := u.prMerger.Merge(ctx, input.PullRequestID, time.Now().UTC()); err != nil {
+	if err := u.prMerger.Merge(ctx, input.PullRequestID, time.Now().UTC(), pr.Version()); err != nil {
 		return nil, err
 	}
 
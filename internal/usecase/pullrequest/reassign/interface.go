@@ -8,7 +8,8 @@ import (
 
 type PullRequestRepository interface {
 	GetByID(ctx context.Context, id string) (*domain.PullRequest, error)
-	UpdateReviewers(ctx context.Context, id string, reviewers []string) error
+	// UpdateReviewers возвращает domain.ErrPRVersionConflict, если версия PR изменилась
+	UpdateReviewers(ctx context.Context, id string, reviewers []string, expectedVersion int) error
 }
 
 type TeamRepository interface {
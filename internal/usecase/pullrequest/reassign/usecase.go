@@ -6,11 +6,17 @@ import (
 	"math/rand"
 
 	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/tracing"
 )
 
+// maxAttempts — сколько раз повторяем переназначение при конфликте версий
+const maxAttempts = 3
+
 type Input struct {
 	PullRequestID string
 	OldReviewerID string
+	// ExpectedVersion — версия из If-Match. Если задана, повторов при конфликте нет.
+	ExpectedVersion *int
 }
 
 type Output struct {
@@ -31,13 +37,38 @@ func NewUsecase(prRepo PullRequestRepository, userRepo UserRepository, teamRepo
 }
 
 func (u *Usecase) Execute(ctx context.Context, input Input) (*domain.PullRequest, string, error) {
+	ctx, span := tracing.Start(ctx, "pullrequest/reassign.Execute")
+	defer span.End()
+
+	for attempt := 1; ; attempt++ {
+		pr, newReviewer, err := u.reassign(ctx, input)
+		if !errors.Is(err, domain.ErrPRVersionConflict) {
+			return pr, newReviewer, err
+		}
+
+		// Клиент прислал If-Match — PR изменился после чтения, повторять нельзя
+		if input.ExpectedVersion != nil {
+			return nil, "", domain.ErrPRVersionMismatch
+		}
+		if attempt >= maxAttempts {
+			return nil, "", err
+		}
+	}
+}
+
+// reassign выполняет одну попытку: чтение PR, выбор замены и условное обновление.
+func (u *Usecase) reassign(ctx context.Context, input Input) (*domain.PullRequest, string, error) {
 	pr, err := u.prRepo.GetByID(ctx, input.PullRequestID)
 	if err != nil {
 		return nil, "", err
 	}
 
-	if pr.Status() == domain.PRMerged {
-		return nil, "", domain.ErrPRAlreadyMerged
+	if input.ExpectedVersion != nil && pr.Version() != *input.ExpectedVersion {
+		return nil, "", domain.ErrPRVersionMismatch
+	}
+
+	if err := pr.CheckOpen(); err != nil {
+		return nil, "", err
 	}
 
 	if !pr.IsReviewerAssigned(input.OldReviewerID) {
@@ -75,7 +106,7 @@ func (u *Usecase) Execute(ctx context.Context, input Input) (*domain.PullRequest
 	newReviewer := candidates[rand.Intn(len(candidates))]
 	newReviewers := replaceInSlice(pr.AssignedReviewers(), input.OldReviewerID, newReviewer)
 
-	if err := u.prRepo.UpdateReviewers(ctx, input.PullRequestID, newReviewers); err != nil {
+	if err := u.prRepo.UpdateReviewers(ctx, input.PullRequestID, newReviewers, pr.Version()); err != nil {
 		return nil, "", err
 	}
 
@@ -0,0 +1,17 @@
+package recordReview
+
+import (
+	"context"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+type PullRequestRepository interface {
+	GetByID(ctx context.Context, id string) (*domain.PullRequest, error)
+}
+
+type ReviewActionRecorder interface {
+	// RecordReviewAction возвращает время первого действия и true, если оно записано этим вызовом
+	RecordReviewAction(ctx context.Context, prID, reviewerID string, at time.Time) (time.Time, bool, error)
+}
@@ -0,0 +1,63 @@
+package recordReview
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/tracing"
+)
+
+type Input struct {
+	PullRequestID string
+	ReviewerID    string
+	// At — время действия; нулевое значение — текущее время
+	At time.Time
+}
+
+type Output struct {
+	FirstActionAt time.Time
+	// First — это действие первое для текущего назначения ревьюера
+	First bool
+}
+
+// Usecase фиксирует действие ревьюера (ревью, комментарий, апрув) для учёта SLA.
+// Учитывается только первое действие по каждому назначению.
+type Usecase struct {
+	prRepo   PullRequestRepository
+	recorder ReviewActionRecorder
+}
+
+func NewUsecase(prRepo PullRequestRepository, recorder ReviewActionRecorder) (*Usecase, error) {
+	if prRepo == nil || recorder == nil {
+		return nil, errors.New("prRepo and recorder are required")
+	}
+	return &Usecase{prRepo: prRepo, recorder: recorder}, nil
+}
+
+func (u *Usecase) Execute(ctx context.Context, input Input) (*Output, error) {
+	ctx, span := tracing.Start(ctx, "pullrequest/recordReview.Execute")
+	defer span.End()
+
+	pr, err := u.prRepo.GetByID(ctx, input.PullRequestID)
+	if err != nil {
+		return nil, err
+	}
+	if err := pr.CheckOpen(); err != nil {
+		return nil, err
+	}
+	if !pr.IsReviewerAssigned(input.ReviewerID) {
+		return nil, domain.ErrReviewerNotAssigned
+	}
+
+	at := input.At
+	if at.IsZero() {
+		at = time.Now().UTC()
+	}
+	firstActionAt, first, err := u.recorder.RecordReviewAction(ctx, pr.ID(), input.ReviewerID, at)
+	if err != nil {
+		return nil, err
+	}
+	return &Output{FirstActionAt: firstActionAt, First: first}, nil
+}
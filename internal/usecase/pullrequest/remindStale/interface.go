@@ -0,0 +1,25 @@
+package remindStale
+
+import (
+	"context"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	prReassign "github.com/Skorpsrgvch/reviewer-service/internal/usecase/pullrequest/reassign"
+)
+
+type StalePullRequestRepository interface {
+	// ListStalePullRequests возвращает OPEN PR, которым на момент now пора напомнить или эскалировать
+	// по политике команды автора; defaults — политика для команд без своей
+	ListStalePullRequests(ctx context.Context, now time.Time, defaults *domain.ReviewPolicy, limit int) ([]domain.StalePullRequest, error)
+	// RecordStaleStage возвращает false, если этап уже был выполнен
+	RecordStaleStage(ctx context.Context, prID string, stage domain.StaleStage, event domain.Event) (bool, error)
+}
+
+type PullRequestRepository interface {
+	GetByID(ctx context.Context, id string) (*domain.PullRequest, error)
+}
+
+type ReviewerReassigner interface {
+	Execute(ctx context.Context, input prReassign.Input) (*domain.PullRequest, string, error)
+}
@@ -0,0 +1,141 @@
+package remindStale
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	prReassign "github.com/Skorpsrgvch/reviewer-service/internal/usecase/pullrequest/reassign"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/tracing"
+)
+
+// batchSize — сколько зависших PR обрабатывается за один запуск
+const batchSize = 100
+
+type Input struct {
+	Now time.Time
+}
+
+type Output struct {
+	Reminded   int
+	Escalated  int
+	Reassigned int // заменённых ревьюеров при эскалации переназначением
+	// Skipped — ревьюеры, которых не удалось заменить при эскалации переназначением
+	Skipped []SkippedReviewer
+}
+
+// SkippedReviewer — ревьюер зависшего PR, оставшийся на месте, и причина
+type SkippedReviewer struct {
+	PullRequestID string
+	ReviewerID    string
+	Err           error
+}
+
+// Usecase напоминает ревьюерам о долго открытых PR и эскалирует те,
+// что пролежали дольше второго порога. Сами уведомления рассылают outbox-sink'и
+// по событиям pull_request.review_reminder и pull_request.escalated.
+type Usecase struct {
+	stale         StalePullRequestRepository
+	prRepo        PullRequestRepository
+	reassigner    ReviewerReassigner
+	defaultPolicy *domain.ReviewPolicy
+}
+
+// NewUsecase создаёт юзкейс. defaultPolicy применяется к командам без своей политики.
+func NewUsecase(stale StalePullRequestRepository, prRepo PullRequestRepository,
+	reassigner ReviewerReassigner, defaultPolicy *domain.ReviewPolicy) (*Usecase, error) {
+	if stale == nil || prRepo == nil || reassigner == nil || defaultPolicy == nil {
+		return nil, errors.New("all dependencies required")
+	}
+	return &Usecase{
+		stale:         stale,
+		prRepo:        prRepo,
+		reassigner:    reassigner,
+		defaultPolicy: defaultPolicy,
+	}, nil
+}
+
+func (u *Usecase) Execute(ctx context.Context, input Input) (Output, error) {
+	ctx, span := tracing.Start(ctx, "pullrequest/remindStale.Execute")
+	defer span.End()
+
+	var out Output
+
+	// Хранилище отбирает только PR, которым пора выполнить этап по политике их команды
+	candidates, err := u.stale.ListStalePullRequests(ctx, input.Now, u.defaultPolicy, batchSize)
+	if err != nil {
+		return out, err
+	}
+
+	for _, c := range candidates {
+		age := input.Now.Sub(c.PullRequest.CreatedAt())
+		switch c.Policy.NextStage(age, c.Stage) {
+		case domain.StaleStageReminded:
+			recorded, err := u.record(ctx, c.PullRequest, domain.StaleStageReminded, age, "", "")
+			if err != nil {
+				return out, err
+			}
+			if recorded {
+				out.Reminded++
+			}
+		case domain.StaleStageEscalated:
+			recorded, err := u.escalate(ctx, c.PullRequest, c.Policy, age, &out)
+			if err != nil {
+				return out, err
+			}
+			if recorded {
+				out.Escalated++
+			}
+		}
+	}
+	return out, nil
+}
+
+// escalate выполняет действие эскалации из политики. Если переназначить некого,
+// а лид у команды указан, вместо переназначения уведомляется лид. Заменённые и пропущенные
+// ревьюеры учитываются в out.
+func (u *Usecase) escalate(ctx context.Context, pr *domain.PullRequest, policy *domain.ReviewPolicy, age time.Duration, out *Output) (bool, error) {
+	action := policy.Escalation()
+	reassigned := 0
+
+	if action == domain.EscalationReassign {
+		for _, reviewerID := range pr.AssignedReviewers() {
+			_, _, err := u.reassigner.Execute(ctx, prReassign.Input{PullRequestID: pr.ID(), OldReviewerID: reviewerID})
+			switch {
+			case err == nil:
+				reassigned++
+				out.Reassigned++
+			case errors.Is(err, domain.ErrPRAlreadyMerged), errors.Is(err, domain.ErrPRClosed), errors.Is(err, domain.ErrPRNotFound):
+				// PR смержили, закрыли или удалили между выборкой и эскалацией
+				return false, nil
+			case errors.Is(err, domain.ErrNoActiveReviewers), errors.Is(err, domain.ErrReviewerNotAssigned),
+				errors.Is(err, domain.ErrUserNotFound), errors.Is(err, domain.ErrPRVersionConflict):
+				out.Skipped = append(out.Skipped, SkippedReviewer{PullRequestID: pr.ID(), ReviewerID: reviewerID, Err: err})
+			default:
+				return false, err
+			}
+		}
+		if reassigned == 0 && policy.LeadID() != "" {
+			action = domain.EscalationNotifyLead
+		}
+
+		// Событие эскалации описывает уже обновлённый состав ревьюеров
+		current, err := u.prRepo.GetByID(ctx, pr.ID())
+		if err != nil {
+			return false, err
+		}
+		pr = current
+	}
+
+	return u.record(ctx, pr, domain.StaleStageEscalated, age, action, policy.LeadID())
+}
+
+func (u *Usecase) record(ctx context.Context, pr *domain.PullRequest, stage domain.StaleStage, age time.Duration,
+	action domain.EscalationAction, leadID string) (bool, error) {
+	event, err := domain.NewStalePullRequestEvent(stage, pr, age, action, leadID)
+	if err != nil {
+		return false, err
+	}
+	return u.stale.RecordStaleStage(ctx, pr.ID(), stage, event)
+}
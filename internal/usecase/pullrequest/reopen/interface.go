@@ -0,0 +1,17 @@
+package reopen
+
+import (
+	"context"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+type PullRequestFinder interface {
+	GetByID(ctx context.Context, id string) (*domain.PullRequest, error)
+}
+
+type PullRequestReopener interface {
+	// Reopen возвращает domain.ErrPRVersionConflict, если версия PR изменилась
+	Reopen(ctx context.Context, id string, reopenedAt time.Time, expectedVersion int) error
+}
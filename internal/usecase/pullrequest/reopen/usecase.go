@@ -0,0 +1,70 @@
+package reopen
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/tracing"
+)
+
+// maxAttempts — сколько раз повторяем переоткрытие при конфликте версий
+const maxAttempts = 3
+
+type Input struct {
+	PullRequestID string
+}
+
+type Output struct {
+	PullRequest *domain.PullRequest
+	// Reopened — PR был закрыт и переоткрыт этим вызовом; false, если он уже открыт
+	Reopened bool
+}
+
+// Usecase переоткрывает закрытый PR с теми же ревьюерами
+type Usecase struct {
+	prFinder   PullRequestFinder
+	prReopener PullRequestReopener
+}
+
+func NewUsecase(prFinder PullRequestFinder, prReopener PullRequestReopener) (*Usecase, error) {
+	if prFinder == nil || prReopener == nil {
+		return nil, errors.New("prFinder and prReopener are required")
+	}
+	return &Usecase{prFinder: prFinder, prReopener: prReopener}, nil
+}
+
+func (u *Usecase) Execute(ctx context.Context, input Input) (*Output, error) {
+	ctx, span := tracing.Start(ctx, "pullrequest/reopen.Execute")
+	defer span.End()
+
+	for attempt := 1; ; attempt++ {
+		out, err := u.reopen(ctx, input)
+		if !errors.Is(err, domain.ErrPRVersionConflict) || attempt >= maxAttempts {
+			return out, err
+		}
+	}
+}
+
+func (u *Usecase) reopen(ctx context.Context, input Input) (*Output, error) {
+	pr, err := u.prFinder.GetByID(ctx, input.PullRequestID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch pr.Status() {
+	case domain.PROpen:
+		// Идемпотентность: возвращаем существующий PR
+		return &Output{PullRequest: pr}, nil
+	case domain.PRMerged:
+		return nil, domain.ErrPRAlreadyMerged
+	}
+
+	if err := u.prReopener.Reopen(ctx, input.PullRequestID, time.Now().UTC(), pr.Version()); err != nil {
+		return nil, err
+	}
+
+	_ = pr.Reopen() // безопасно, потому что только что переоткрыли
+	return &Output{PullRequest: pr, Reopened: true}, nil
+}
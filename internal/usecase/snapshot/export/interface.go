@@ -0,0 +1,16 @@
+package export
+
+import (
+	"context"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+type SnapshotExporter interface {
+	// ExportSnapshot передаёт строки согласованного снимка в emit в порядке внешних ключей
+	ExportSnapshot(ctx context.Context, emit func(row domain.SnapshotRow) error) error
+}
+
+type SchemaReader interface {
+	MigrationVersion(ctx context.Context) (version uint, dirty bool, err error)
+}
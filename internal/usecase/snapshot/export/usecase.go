@@ -0,0 +1,82 @@
+package export
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/tracing"
+)
+
+type Input struct {
+	// W — куда писать снимок в формате JSON Lines
+	W   io.Writer
+	Now time.Time
+}
+
+type Output struct {
+	SchemaVersion uint
+	Rows          int
+}
+
+type Usecase struct {
+	exporter     SnapshotExporter
+	schemaReader SchemaReader
+}
+
+func NewUsecase(exporter SnapshotExporter, schemaReader SchemaReader) (*Usecase, error) {
+	if exporter == nil || schemaReader == nil {
+		return nil, errors.New("all dependencies are required")
+	}
+	return &Usecase{exporter: exporter, schemaReader: schemaReader}, nil
+}
+
+// Execute пишет заголовок с версией схемы, строки таблиц и завершающую строку с их числом.
+// Версия схемы проверяется до записи в W, поэтому такая ошибка не оставляет частичного снимка.
+func (u *Usecase) Execute(ctx context.Context, input Input) (*Output, error) {
+	ctx, span := tracing.Start(ctx, "snapshot/export.Execute")
+	defer span.End()
+
+	version, dirty, err := u.schemaReader.MigrationVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if dirty {
+		return nil, fmt.Errorf("schema version %d is dirty, finish the migration before exporting", version)
+	}
+
+	w := bufio.NewWriter(input.W)
+	enc := json.NewEncoder(w)
+	err = enc.Encode(domain.SnapshotHeader{
+		Kind:          domain.SnapshotKindHeader,
+		Format:        domain.SnapshotFormat,
+		FormatVersion: domain.SnapshotFormatVersion,
+		SchemaVersion: version,
+		CreatedAt:     input.Now.UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	output := &Output{SchemaVersion: version}
+	err = u.exporter.ExportSnapshot(ctx, func(row domain.SnapshotRow) error {
+		output.Rows++
+		return enc.Encode(row)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := enc.Encode(domain.SnapshotTrailer{Kind: domain.SnapshotKindEnd, Rows: output.Rows}); err != nil {
+		return nil, err
+	}
+	if err := w.Flush(); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
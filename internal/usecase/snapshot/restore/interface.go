@@ -0,0 +1,17 @@
+package restore
+
+import (
+	"context"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+type SnapshotRestorer interface {
+	// RestoreSnapshot загружает строки из next (до io.EOF) одной транзакцией и возвращает их число по таблицам.
+	// Без force возвращает domain.ErrDatabaseNotEmpty, если данные уже есть.
+	RestoreSnapshot(ctx context.Context, force bool, next func() (*domain.SnapshotRow, error)) (map[string]int, error)
+}
+
+type SchemaReader interface {
+	MigrationVersion(ctx context.Context) (version uint, dirty bool, err error)
+}
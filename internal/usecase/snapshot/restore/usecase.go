@@ -0,0 +1,167 @@
+package restore
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/tracing"
+)
+
+// maxLineSize — ограничение длины одной строки снимка
+const maxLineSize = 16 << 20
+
+type Input struct {
+	// R — снимок в формате JSON Lines, созданный export
+	R io.Reader
+	// Force — перезаписать данные, если база не пуста
+	Force bool
+}
+
+type Output struct {
+	SchemaVersion uint
+	Tables        map[string]int
+	Rows          int
+}
+
+type Usecase struct {
+	restorer     SnapshotRestorer
+	schemaReader SchemaReader
+}
+
+func NewUsecase(restorer SnapshotRestorer, schemaReader SchemaReader) (*Usecase, error) {
+	if restorer == nil || schemaReader == nil {
+		return nil, errors.New("all dependencies are required")
+	}
+	return &Usecase{restorer: restorer, schemaReader: schemaReader}, nil
+}
+
+// Execute проверяет заголовок снимка и версию схемы, затем потоково загружает строки одной
+// транзакцией. Снимок без завершающей строки или с неверным числом строк не применяется.
+func (u *Usecase) Execute(ctx context.Context, input Input) (*Output, error) {
+	ctx, span := tracing.Start(ctx, "snapshot/restore.Execute")
+	defer span.End()
+
+	version, dirty, err := u.schemaReader.MigrationVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if dirty {
+		return nil, fmt.Errorf("schema version %d is dirty, finish the migration before restoring", version)
+	}
+
+	r := &snapshotReader{scanner: bufio.NewScanner(input.R)}
+	r.scanner.Buffer(make([]byte, 64<<10), maxLineSize)
+
+	header, err := r.readHeader()
+	if err != nil {
+		return nil, err
+	}
+	if header.SchemaVersion != version {
+		return nil, fmt.Errorf("%w: snapshot has %d, database has %d", domain.ErrSnapshotSchema, header.SchemaVersion, version)
+	}
+
+	tables, err := u.restorer.RestoreSnapshot(ctx, input.Force, r.next)
+	if err != nil {
+		return nil, err
+	}
+	return &Output{SchemaVersion: version, Tables: tables, Rows: r.rows}, nil
+}
+
+// snapshotReader читает строки снимка и проверяет его целостность
+type snapshotReader struct {
+	scanner *bufio.Scanner
+	line    int
+	rows    int
+	done    bool
+}
+
+func (r *snapshotReader) readLine() ([]byte, error) {
+	if !r.scanner.Scan() {
+		if err := r.scanner.Err(); err != nil {
+			if errors.Is(err, bufio.ErrTooLong) {
+				return nil, r.errorf("line is longer than %d bytes", maxLineSize)
+			}
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+	r.line++
+	return r.scanner.Bytes(), nil
+}
+
+func (r *snapshotReader) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w: line %d: %s", domain.ErrInvalidSnapshot, r.line+1, fmt.Sprintf(format, args...))
+}
+
+func (r *snapshotReader) readHeader() (*domain.SnapshotHeader, error) {
+	line, err := r.readLine()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: snapshot is empty", domain.ErrInvalidSnapshot)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var header domain.SnapshotHeader
+	if err := json.Unmarshal(line, &header); err != nil {
+		return nil, fmt.Errorf("%w: line 1: %v", domain.ErrInvalidSnapshot, err)
+	}
+	if header.Kind != domain.SnapshotKindHeader || header.Format != domain.SnapshotFormat {
+		return nil, fmt.Errorf("%w: line 1: not a %s header", domain.ErrInvalidSnapshot, domain.SnapshotFormat)
+	}
+	if header.FormatVersion != domain.SnapshotFormatVersion {
+		return nil, fmt.Errorf("%w: unsupported format version %d, expected %d",
+			domain.ErrInvalidSnapshot, header.FormatVersion, domain.SnapshotFormatVersion)
+	}
+	return &header, nil
+}
+
+// next возвращает очередную строку таблицы или io.EOF после завершающей строки
+func (r *snapshotReader) next() (*domain.SnapshotRow, error) {
+	if r.done {
+		return nil, io.EOF
+	}
+	line, err := r.readLine()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: snapshot is truncated, missing %q line", domain.ErrInvalidSnapshot, domain.SnapshotKindEnd)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var row domain.SnapshotRow
+	if err := json.Unmarshal(line, &row); err != nil {
+		return nil, fmt.Errorf("%w: line %d: %v", domain.ErrInvalidSnapshot, r.line, err)
+	}
+
+	if row.Table == domain.SnapshotKindEnd {
+		var trailer domain.SnapshotTrailer
+		if err := json.Unmarshal(line, &trailer); err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", domain.ErrInvalidSnapshot, r.line, err)
+		}
+		if trailer.Rows != r.rows {
+			return nil, fmt.Errorf("%w: end line expects %d rows, snapshot has %d", domain.ErrInvalidSnapshot, trailer.Rows, r.rows)
+		}
+		if _, err := r.readLine(); !errors.Is(err, io.EOF) {
+			if err != nil {
+				return nil, err
+			}
+			return nil, fmt.Errorf("%w: line %d: data after %q line", domain.ErrInvalidSnapshot, r.line, domain.SnapshotKindEnd)
+		}
+		r.done = true
+		return nil, io.EOF
+	}
+
+	data := bytes.TrimSpace(row.Data)
+	if len(data) == 0 || data[0] != '{' {
+		return nil, fmt.Errorf("%w: line %d: data must be an object", domain.ErrInvalidSnapshot, r.line)
+	}
+	r.rows++
+	return &row, nil
+}
@@ -1,7 +1,15 @@
 package get
 
-import "context"
+import (
+	"context"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
 
 type StatsReader interface {
-	GetReviewerStats(ctx context.Context) (map[string]int, error)
+	GetReviewerStats(ctx context.Context, teamName string) (map[string]int, error)
+	GetTimeToMerge(ctx context.Context, filter domain.StatsFilter) (domain.TimeToMerge, error)
+	GetThroughput(ctx context.Context, filter domain.StatsFilter) ([]domain.ThroughputPoint, error)
+	GetReviewerLoad(ctx context.Context, filter domain.StatsFilter) ([]domain.ReviewerLoad, error)
+	GetAuthorReviewerMatrix(ctx context.Context, filter domain.StatsFilter) ([]domain.AuthorReviewerPair, error)
 }
@@ -3,8 +3,29 @@ package get
 import (
 	"context"
 	"errors"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/tracing"
+)
+
+const (
+	// defaultPeriod — период статистики, если начало не задано
+	defaultPeriod = 30 * 24 * time.Hour
+	// maxPeriod ограничивает объём агрегации одним запросом
+	maxPeriod = 366 * 24 * time.Hour
 )
 
+type Input struct {
+	TeamName string
+	// From и To — границы периода, To не включается.
+	// Нулевой To — текущий момент, нулевой From — за 30 дней до To.
+	From   time.Time
+	To     time.Time
+	Bucket string
+	Now    time.Time
+}
+
 type Usecase struct {
 	statsReader StatsReader
 }
@@ -16,6 +37,43 @@ func NewUsecase(statsReader StatsReader) (*Usecase, error) {
 	return &Usecase{statsReader: statsReader}, nil
 }
 
-func (u *Usecase) Execute(ctx context.Context) (map[string]int, error) {
-	return u.statsReader.GetReviewerStats(ctx)
+func (u *Usecase) Execute(ctx context.Context, input Input) (*domain.PullRequestStats, error) {
+	ctx, span := tracing.Start(ctx, "stats/get.Execute")
+	defer span.End()
+
+	bucket, err := domain.ParseStatsBucket(input.Bucket)
+	if err != nil {
+		return nil, err
+	}
+	filter := domain.StatsFilter{TeamName: input.TeamName, From: input.From, To: input.To, Bucket: bucket}
+	if filter.To.IsZero() {
+		filter.To = input.Now
+	}
+	if filter.From.IsZero() {
+		filter.From = filter.To.Add(-defaultPeriod)
+	}
+	if !filter.From.Before(filter.To) || filter.To.Sub(filter.From) > maxPeriod {
+		return nil, domain.ErrInvalidPeriod
+	}
+
+	stats := &domain.PullRequestStats{Filter: filter}
+	if stats.OpenAssignments, err = u.statsReader.GetReviewerStats(ctx, filter.TeamName); err != nil {
+		return nil, err
+	}
+	if stats.TimeToMerge, err = u.statsReader.GetTimeToMerge(ctx, filter); err != nil {
+		return nil, err
+	}
+	if stats.Throughput, err = u.statsReader.GetThroughput(ctx, filter); err != nil {
+		return nil, err
+	}
+	if stats.Reviewers, err = u.statsReader.GetReviewerLoad(ctx, filter); err != nil {
+		return nil, err
+	}
+	for _, r := range stats.Reviewers {
+		stats.Reassignments += r.ReassignedAway
+	}
+	if stats.Matrix, err = u.statsReader.GetAuthorReviewerMatrix(ctx, filter); err != nil {
+		return nil, err
+	}
+	return stats, nil
 }
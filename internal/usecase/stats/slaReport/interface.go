@@ -0,0 +1,12 @@
+package slaReport
+
+import (
+	"context"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+type SLAReportReader interface {
+	SLAReport(ctx context.Context, teamName string, from, to, now time.Time, defaultSLA time.Duration) (*domain.SLAReport, error)
+}
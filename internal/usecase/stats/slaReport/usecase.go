@@ -0,0 +1,60 @@
+package slaReport
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/tracing"
+)
+
+const (
+	// defaultPeriod — период отчёта, если начало не задано
+	defaultPeriod = 30 * 24 * time.Hour
+	// maxPeriod ограничивает объём агрегации одним запросом
+	maxPeriod = 366 * 24 * time.Hour
+)
+
+type Input struct {
+	TeamName string
+	// From и To — границы периода по дате назначения, To не включается.
+	// Нулевой To — текущий момент, нулевой From — за 30 дней до To.
+	From time.Time
+	To   time.Time
+	Now  time.Time
+}
+
+type Usecase struct {
+	reader     SLAReportReader
+	defaultSLA time.Duration
+}
+
+// NewUsecase создаёт юзкейс. defaultSLA действует для команд без своего SLA.
+func NewUsecase(reader SLAReportReader, defaultSLA time.Duration) (*Usecase, error) {
+	if reader == nil {
+		return nil, errors.New("reader is required")
+	}
+	if defaultSLA <= 0 {
+		return nil, errors.New("default SLA must be positive")
+	}
+	return &Usecase{reader: reader, defaultSLA: defaultSLA}, nil
+}
+
+func (u *Usecase) Execute(ctx context.Context, input Input) (*domain.SLAReport, error) {
+	ctx, span := tracing.Start(ctx, "stats/slaReport.Execute")
+	defer span.End()
+
+	to := input.To
+	if to.IsZero() {
+		to = input.Now
+	}
+	from := input.From
+	if from.IsZero() {
+		from = to.Add(-defaultPeriod)
+	}
+	if !from.Before(to) || to.Sub(from) > maxPeriod {
+		return nil, domain.ErrInvalidPeriod
+	}
+	return u.reader.SLAReport(ctx, input.TeamName, from, to, input.Now, u.defaultSLA)
+}
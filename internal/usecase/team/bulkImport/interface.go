@@ -0,0 +1,19 @@
+package bulkImport
+
+import (
+	"context"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+type TeamImporter interface {
+	// ImportTeams сохраняет все команды атомарно
+	ImportTeams(ctx context.Context, teams []domain.Team) error
+	TeamExists(ctx context.Context, teamName string) (bool, error)
+}
+
+type UserFinder interface {
+	GetUserByID(ctx context.Context, id string) (*domain.User, error)
+	// GetTeamByUser возвращает domain.ErrUserNotFound, если пользователь не состоит в команде
+	GetTeamByUser(ctx context.Context, userID string) (string, error)
+}
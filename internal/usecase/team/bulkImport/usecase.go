@@ -0,0 +1,500 @@
+package bulkImport
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/csvimport"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/tracing"
+	"gopkg.in/yaml.v3"
+)
+
+// maxMembers — ограничение числа участников в одном импорте
+const maxMembers = 10000
+
+// Format — формат импортируемого документа
+type Format string
+
+const (
+	FormatYAML Format = "yaml"
+	FormatCSV  Format = "csv"
+)
+
+var requiredColumns = []string{"team_name", "user_id", "username"}
+
+type Input struct {
+	// Data — YAML вида {teams: [{team_name, members: [{user_id, username, is_active}]}]}
+	// или CSV с заголовком team_name,user_id,username[,is_active]. is_active по умолчанию true.
+	Data   io.Reader
+	Format Format
+	// DryRun — только посчитать изменения, ничего не сохраняя
+	DryRun bool
+}
+
+// Action — что импорт сделает с пользователем
+type Action string
+
+const (
+	ActionCreated   Action = "created"
+	ActionUpdated   Action = "updated"
+	ActionMoved     Action = "moved"
+	ActionUnchanged Action = "unchanged"
+)
+
+// UserChange — изменение одного пользователя
+type UserChange struct {
+	UserID   string
+	Username string
+	TeamName string
+	Action   Action
+	// PreviousTeam — команда до импорта (для moved)
+	PreviousTeam string
+	// Fields — изменённые поля существующего пользователя: username, is_active,
+	// team_name (пользователь без команды добавлен в неё)
+	Fields []string
+}
+
+type Output struct {
+	DryRun       bool
+	TeamsCreated []string
+	Users        []UserChange
+}
+
+// Count возвращает число пользователей с указанным действием
+func (o *Output) Count(action Action) int {
+	n := 0
+	for _, u := range o.Users {
+		if u.Action == action {
+			n++
+		}
+	}
+	return n
+}
+
+// RowError — ошибка в конкретной строке документа (нумерация с 1)
+type RowError struct {
+	Line    int
+	Message string
+}
+
+// ImportError возвращается, если хотя бы одна строка некорректна; импорт при этом не выполняется
+type ImportError struct {
+	Rows []RowError
+}
+
+func (e *ImportError) Error() string {
+	return fmt.Sprintf("%d invalid rows", len(e.Rows))
+}
+
+func (e *ImportError) Unwrap() error { return domain.ErrInvalidTeamImport }
+
+type importMember struct {
+	line     int
+	userID   string
+	username string
+	isActive bool
+}
+
+type importTeam struct {
+	line    int
+	name    string
+	members []importMember
+}
+
+type Usecase struct {
+	importer   TeamImporter
+	userFinder UserFinder
+}
+
+func NewUsecase(importer TeamImporter, userFinder UserFinder) (*Usecase, error) {
+	if importer == nil || userFinder == nil {
+		return nil, errors.New("all dependencies are required")
+	}
+	return &Usecase{importer: importer, userFinder: userFinder}, nil
+}
+
+// Execute проверяет весь документ, считает изменения относительно текущего состояния
+// и, если это не dry-run, применяет их одной транзакцией. Пользователи, которых нет в документе,
+// не затрагиваются.
+func (u *Usecase) Execute(ctx context.Context, input Input) (*Output, error) {
+	ctx, span := tracing.Start(ctx, "team/bulkImport.Execute")
+	defer span.End()
+
+	teams, err := ParseTeams(input.Data, input.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &Output{DryRun: input.DryRun}
+	for _, team := range teams {
+		exists, err := u.importer.TeamExists(ctx, team.Name())
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			output.TeamsCreated = append(output.TeamsCreated, team.Name())
+		}
+		for _, member := range team.Members() {
+			change, err := u.diffUser(ctx, team.Name(), member)
+			if err != nil {
+				return nil, err
+			}
+			output.Users = append(output.Users, change)
+		}
+	}
+
+	if input.DryRun {
+		return output, nil
+	}
+	if err := u.importer.ImportTeams(ctx, teams); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// ParseTeams разбирает и проверяет документ целиком. Если хотя бы одна строка некорректна,
+// возвращает *ImportError со всеми ошибками.
+func ParseTeams(data io.Reader, format Format) ([]domain.Team, error) {
+	var (
+		parsed    []importTeam
+		rowErrors []RowError
+		err       error
+	)
+	switch format {
+	case FormatYAML:
+		parsed, rowErrors, err = parseYAML(data)
+	case FormatCSV:
+		parsed, rowErrors, err = parseCSV(data)
+	default:
+		return nil, fmt.Errorf("%w: unsupported format %q", domain.ErrInvalidTeamImport, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	teams, validationErrors := validate(parsed)
+	rowErrors = append(rowErrors, validationErrors...)
+	if len(rowErrors) > 0 {
+		sort.SliceStable(rowErrors, func(i, j int) bool { return rowErrors[i].Line < rowErrors[j].Line })
+		return nil, &ImportError{Rows: rowErrors}
+	}
+	return teams, nil
+}
+
+func (u *Usecase) diffUser(ctx context.Context, teamName string, member domain.User) (UserChange, error) {
+	change := UserChange{
+		UserID:   member.ID(),
+		Username: member.Username(),
+		TeamName: teamName,
+		Action:   ActionCreated,
+	}
+
+	current, err := u.userFinder.GetUserByID(ctx, member.ID())
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return change, nil
+	}
+	if err != nil {
+		return change, err
+	}
+
+	if current.Username() != member.Username() {
+		change.Fields = append(change.Fields, "username")
+	}
+	if current.IsActive() != member.IsActive() {
+		change.Fields = append(change.Fields, "is_active")
+	}
+
+	previousTeam, err := u.userFinder.GetTeamByUser(ctx, member.ID())
+	if err != nil && !errors.Is(err, domain.ErrUserNotFound) {
+		return change, err
+	}
+
+	switch {
+	case previousTeam == "":
+		// Пользователь без команды никуда не переходит, а только добавляется в неё
+		change.Action = ActionUpdated
+		change.Fields = append(change.Fields, "team_name")
+	case previousTeam != teamName:
+		change.Action = ActionMoved
+		change.PreviousTeam = previousTeam
+	case len(change.Fields) > 0:
+		change.Action = ActionUpdated
+	default:
+		change.Action = ActionUnchanged
+	}
+	return change, nil
+}
+
+// validate проверяет команды и участников целиком, собирая все ошибки сразу
+func validate(parsed []importTeam) ([]domain.Team, []RowError) {
+	var (
+		teams      []domain.Team
+		rowErrors  []RowError
+		teamLines  = make(map[string]int)
+		userLines  = make(map[string]int)
+		memberRows int
+	)
+	for _, t := range parsed {
+		if t.name == "" {
+			rowErrors = append(rowErrors, RowError{Line: t.line, Message: "team_name is required"})
+			continue
+		}
+		if prev, ok := teamLines[t.name]; ok {
+			rowErrors = append(rowErrors, RowError{Line: t.line, Message: fmt.Sprintf("team %q is already listed on line %d", t.name, prev)})
+			continue
+		}
+		teamLines[t.name] = t.line
+
+		memberRows += len(t.members)
+		if memberRows > maxMembers {
+			return nil, []RowError{{Line: t.line, Message: fmt.Sprintf("too many members, limit is %d", maxMembers)}}
+		}
+
+		var members []domain.User
+		for _, m := range t.members {
+			user, err := domain.NewUser(m.userID, m.username, m.isActive)
+			if err != nil {
+				rowErrors = append(rowErrors, RowError{Line: m.line, Message: err.Error()})
+				continue
+			}
+			if prev, ok := userLines[m.userID]; ok {
+				rowErrors = append(rowErrors, RowError{Line: m.line, Message: fmt.Sprintf("user %q is already listed on line %d", m.userID, prev)})
+				continue
+			}
+			userLines[m.userID] = m.line
+			members = append(members, *user)
+		}
+
+		team, err := domain.NewTeam(t.name, members)
+		if err != nil {
+			// Команда не должна выпадать из импорта молча, даже если ошибки её участников уже записаны
+			message := fmt.Sprintf("team %q: %v", t.name, err)
+			if len(t.members) == 0 {
+				message = fmt.Sprintf("team %q has no members", t.name)
+			}
+			rowErrors = append(rowErrors, RowError{Line: t.line, Message: message})
+			continue
+		}
+		teams = append(teams, *team)
+	}
+	return teams, rowErrors
+}
+
+type yamlDocument struct {
+	Teams []yamlTeam `yaml:"teams"`
+}
+
+type yamlTeam struct {
+	TeamName string       `yaml:"team_name"`
+	Members  []yamlMember `yaml:"members"`
+}
+
+type yamlMember struct {
+	UserID   string `yaml:"user_id"`
+	Username string `yaml:"username"`
+	IsActive *bool  `yaml:"is_active"`
+}
+
+func parseYAML(r io.Reader) ([]importTeam, []RowError, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		doc       yamlDocument
+		rowErrors []RowError
+		typeErr   *yaml.TypeError
+	)
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	// Опечатка в ключе не должна молча превращаться в пустое значение
+	dec.KnownFields(true)
+	err = dec.Decode(&doc)
+	switch {
+	case errors.Is(err, io.EOF):
+		return nil, []RowError{{Line: 1, Message: "document is empty"}}, nil
+	case errors.As(err, &typeErr):
+		// Ошибки типов не прерывают декодирование, поэтому остальные строки тоже проверяем
+		rowErrors = yamlRowErrors(err)
+	case err != nil:
+		return nil, yamlRowErrors(err), nil
+	}
+
+	// Номера строк берём из дерева узлов: структура уже проверена декодированием
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, yamlRowErrors(err), nil
+	}
+	teamNodes := sequenceItems(mappingValue(documentRoot(&root), "teams"))
+
+	teams := make([]importTeam, 0, len(doc.Teams))
+	for i, t := range doc.Teams {
+		team := importTeam{line: nodeLine(teamNodes, i), name: strings.TrimSpace(t.TeamName)}
+		var memberNodes []*yaml.Node
+		if i < len(teamNodes) {
+			memberNodes = sequenceItems(mappingValue(teamNodes[i], "members"))
+		}
+		for j, m := range t.Members {
+			isActive := true
+			if m.IsActive != nil {
+				isActive = *m.IsActive
+			}
+			team.members = append(team.members, importMember{
+				line:     nodeLine(memberNodes, j),
+				userID:   strings.TrimSpace(m.UserID),
+				username: strings.TrimSpace(m.Username),
+				isActive: isActive,
+			})
+		}
+		teams = append(teams, team)
+	}
+	if len(teams) == 0 && len(rowErrors) == 0 {
+		rowErrors = append(rowErrors, RowError{Line: 1, Message: "no teams in document"})
+	}
+	return teams, rowErrors, nil
+}
+
+// yamlRowErrors превращает ошибки вида "line N: ..." в построчные
+func yamlRowErrors(err error) []RowError {
+	messages := []string{err.Error()}
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) {
+		messages = typeErr.Errors
+	}
+
+	rows := make([]RowError, 0, len(messages))
+	for _, msg := range messages {
+		msg = strings.TrimPrefix(msg, "yaml: ")
+		row := RowError{Line: 1, Message: msg}
+		var line int
+		if _, scanErr := fmt.Sscanf(msg, "line %d:", &line); scanErr == nil {
+			row.Line = line
+			row.Message = strings.TrimSpace(msg[strings.Index(msg, ":")+1:])
+		}
+		// "field x not found in type bulkImport.yamlMember" — имя внутреннего типа пользователю не нужно
+		if field, _, ok := strings.Cut(strings.TrimPrefix(row.Message, "field "), " not found in type "); ok {
+			row.Message = fmt.Sprintf("unknown field %q", field)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func documentRoot(n *yaml.Node) *yaml.Node {
+	if n.Kind == yaml.DocumentNode && len(n.Content) > 0 {
+		return n.Content[0]
+	}
+	return n
+}
+
+func mappingValue(n *yaml.Node, key string) *yaml.Node {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func sequenceItems(n *yaml.Node) []*yaml.Node {
+	if n == nil || n.Kind != yaml.SequenceNode {
+		return nil
+	}
+	return n.Content
+}
+
+func nodeLine(nodes []*yaml.Node, i int) int {
+	if i < len(nodes) {
+		return nodes[i].Line
+	}
+	return 0
+}
+
+func parseCSV(r io.Reader) ([]importTeam, []RowError, error) {
+	cr, err := csvimport.NewReader(r, requiredColumns...)
+	if err != nil {
+		var lineErr *csvimport.LineError
+		if !errors.As(err, &lineErr) {
+			return nil, nil, err
+		}
+		return nil, []RowError{rowError(lineErr)}, nil
+	}
+
+	var (
+		teams     []importTeam
+		rowErrors []RowError
+		teamIndex = make(map[string]int)
+		rows      int
+	)
+	for {
+		row, err := cr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		// Ошибка чтения источника повторяется на каждом вызове, продолжать бессмысленно
+		var lineErr *csvimport.LineError
+		if err != nil && !errors.As(err, &lineErr) {
+			return nil, nil, err
+		}
+		line := row.Line
+		if lineErr != nil {
+			line = lineErr.Line
+		}
+		// Лимит считает и ошибочные строки, иначе файл из одних ошибок не ограничен
+		rows++
+		if rows > maxMembers {
+			return nil, []RowError{{Line: line, Message: fmt.Sprintf("too many rows, limit is %d", maxMembers)}}, nil
+		}
+		if lineErr != nil {
+			rowErrors = append(rowErrors, rowError(lineErr))
+			continue
+		}
+
+		member := importMember{
+			line:     line,
+			userID:   strings.TrimSpace(row.Field("user_id")),
+			username: strings.TrimSpace(row.Field("username")),
+			isActive: true,
+		}
+		if v := strings.TrimSpace(row.Field("is_active")); v != "" {
+			active, err := strconv.ParseBool(v)
+			if err != nil {
+				rowErrors = append(rowErrors, RowError{Line: line, Message: fmt.Sprintf("is_active must be true or false, got %q", v)})
+				continue
+			}
+			member.isActive = active
+		}
+
+		// Строки одной команды могут идти вперемешку; команда — по первому упоминанию
+		teamName := strings.TrimSpace(row.Field("team_name"))
+		if teamName == "" {
+			rowErrors = append(rowErrors, RowError{Line: line, Message: "team_name is required"})
+			continue
+		}
+		i, ok := teamIndex[teamName]
+		if !ok {
+			i = len(teams)
+			teamIndex[teamName] = i
+			teams = append(teams, importTeam{line: line, name: teamName})
+		}
+		teams[i].members = append(teams[i].members, member)
+	}
+	if len(teams) == 0 && len(rowErrors) == 0 {
+		rowErrors = append(rowErrors, RowError{Line: 1, Message: "no rows in document"})
+	}
+	return teams, rowErrors, nil
+}
+
+func rowError(err *csvimport.LineError) RowError {
+	return RowError{Line: err.Line, Message: err.Message}
+}
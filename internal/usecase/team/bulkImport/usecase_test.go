@@ -0,0 +1,141 @@
+package bulkImport
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+type fakeImporter struct {
+	teams    map[string]bool
+	imported []domain.Team
+}
+
+func (f *fakeImporter) ImportTeams(_ context.Context, teams []domain.Team) error {
+	f.imported = teams
+	return nil
+}
+
+func (f *fakeImporter) TeamExists(_ context.Context, teamName string) (bool, error) {
+	return f.teams[teamName], nil
+}
+
+// fakeUsers — пользователи с командами; пустая команда — пользователь вне команд
+type fakeUsers map[string]string
+
+func (f fakeUsers) GetUserByID(_ context.Context, id string) (*domain.User, error) {
+	if _, ok := f[id]; !ok {
+		return nil, domain.ErrUserNotFound
+	}
+	return domain.NewUser(id, "name-"+id, true)
+}
+
+func (f fakeUsers) GetTeamByUser(_ context.Context, userID string) (string, error) {
+	team, ok := f[userID]
+	if !ok || team == "" {
+		return "", domain.ErrUserNotFound
+	}
+	return team, nil
+}
+
+func newTestUsecase(t *testing.T, users fakeUsers) (*Usecase, *fakeImporter) {
+	t.Helper()
+	importer := &fakeImporter{teams: map[string]bool{"backend": true}}
+	uc, err := NewUsecase(importer, users)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return uc, importer
+}
+
+func csvRows(n int, row string) string {
+	var b strings.Builder
+	b.WriteString("team_name,user_id,username\n")
+	for range n {
+		b.WriteString(row)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func TestExecuteReportsChanges(t *testing.T) {
+	uc, importer := newTestUsecase(t, fakeUsers{"u1": "backend", "u2": "frontend", "u3": ""})
+	data := "team_name,user_id,username\n" +
+		"backend,u1,name-u1\n" +
+		"backend,u2,name-u2\n" +
+		"backend,u3,name-u3\n" +
+		"platform,u4,name-u4\n"
+
+	out, err := uc.Execute(context.Background(), Input{Data: strings.NewReader(data), Format: FormatCSV})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []UserChange{
+		{UserID: "u1", Username: "name-u1", TeamName: "backend", Action: ActionUnchanged},
+		{UserID: "u2", Username: "name-u2", TeamName: "backend", Action: ActionMoved, PreviousTeam: "frontend"},
+		{UserID: "u3", Username: "name-u3", TeamName: "backend", Action: ActionUpdated, Fields: []string{"team_name"}},
+		{UserID: "u4", Username: "name-u4", TeamName: "platform", Action: ActionCreated},
+	}
+	if !reflect.DeepEqual(out.Users, want) {
+		t.Errorf("users = %+v, want %+v", out.Users, want)
+	}
+	if !reflect.DeepEqual(out.TeamsCreated, []string{"platform"}) {
+		t.Errorf("teams created = %v, want [platform]", out.TeamsCreated)
+	}
+	if len(importer.imported) != 2 {
+		t.Errorf("imported %d teams, want 2", len(importer.imported))
+	}
+}
+
+func TestParseTeamsReportsTeamWithoutValidMembers(t *testing.T) {
+	data := "teams:\n" +
+		"  - team_name: backend\n" +
+		"    members:\n" +
+		"      - user_id: \"\"\n" +
+		"        username: nobody\n"
+
+	_, err := ParseTeams(strings.NewReader(data), FormatYAML)
+	var importErr *ImportError
+	if !errors.As(err, &importErr) {
+		t.Fatalf("error = %v, want *ImportError", err)
+	}
+	var teamErrors int
+	for _, r := range importErr.Rows {
+		if strings.HasPrefix(r.Message, `team "backend"`) {
+			teamErrors++
+		}
+	}
+	if teamErrors != 1 {
+		t.Errorf("rows = %+v, want one error for team backend", importErr.Rows)
+	}
+}
+
+func TestParseTeamsLimitsRowsWithErrors(t *testing.T) {
+	data := csvRows(maxMembers+1, "backend,u\"1,name")
+
+	_, err := ParseTeams(strings.NewReader(data), FormatCSV)
+	var importErr *ImportError
+	if !errors.As(err, &importErr) {
+		t.Fatalf("error = %v, want *ImportError", err)
+	}
+	if len(importErr.Rows) != 1 || !strings.Contains(importErr.Rows[0].Message, "too many rows") {
+		t.Errorf("rows = %+v, want a single row limit error", importErr.Rows)
+	}
+}
+
+func TestParseTeamsStopsOnBodyOverLimit(t *testing.T) {
+	data := csvRows(1000, "backend,u1,name")
+	body := http.MaxBytesReader(nil, io.NopCloser(strings.NewReader(data)), int64(len(data)/2))
+
+	_, err := ParseTeams(body, FormatCSV)
+	var tooLarge *http.MaxBytesError
+	if !errors.As(err, &tooLarge) {
+		t.Fatalf("error = %v, want *http.MaxBytesError", err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 
 	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/tracing"
 )
 
 type Input struct {
@@ -24,6 +25,9 @@ func NewUsecase(teamSaver TeamSaver) (*Usecase, error) {
 }
 
 func (u *Usecase) Execute(ctx context.Context, input Input) error {
+	ctx, span := tracing.Start(ctx, "team/create.Execute")
+	defer span.End()
+
 	team, err := domain.NewTeam(input.TeamName, input.Members)
 	if err != nil {
 		return err
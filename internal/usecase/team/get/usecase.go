@@ -5,6 +5,7 @@ import (
 	"errors"
 
 	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/tracing"
 )
 
 // Input — входные данные юзкейса.
@@ -27,6 +28,9 @@ func NewUsecase(teamFinder TeamFinder) (*Usecase, error) {
 
 // Execute выполняет получение команды по имени.
 func (u *Usecase) Execute(ctx context.Context, input Input) (*domain.Team, error) {
+	ctx, span := tracing.Start(ctx, "team/get.Execute")
+	defer span.End()
+
 	if input.TeamName == "" {
 		return nil, errors.New("team name is required")
 	}
@@ -0,0 +1,16 @@
+package getReviewPolicy
+
+import (
+	"context"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+type ReviewPolicyFinder interface {
+	// GetReviewPolicy возвращает domain.ErrReviewPolicyNotFound, если у команды нет своей политики
+	GetReviewPolicy(ctx context.Context, teamName string) (*domain.ReviewPolicy, error)
+}
+
+type TeamFinder interface {
+	FindTeamByName(ctx context.Context, teamName string) (*domain.Team, error)
+}
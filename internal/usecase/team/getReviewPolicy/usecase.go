@@ -0,0 +1,50 @@
+package getReviewPolicy
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/tracing"
+)
+
+type Input struct {
+	TeamName string
+}
+
+type Output struct {
+	Policy *domain.ReviewPolicy
+	// IsDefault — у команды нет своей политики, действует политика по умолчанию
+	IsDefault bool
+}
+
+type Usecase struct {
+	finder        ReviewPolicyFinder
+	teamFinder    TeamFinder
+	defaultPolicy *domain.ReviewPolicy
+}
+
+func NewUsecase(finder ReviewPolicyFinder, teamFinder TeamFinder, defaultPolicy *domain.ReviewPolicy) (*Usecase, error) {
+	if finder == nil || teamFinder == nil || defaultPolicy == nil {
+		return nil, errors.New("all dependencies required")
+	}
+	return &Usecase{finder: finder, teamFinder: teamFinder, defaultPolicy: defaultPolicy}, nil
+}
+
+func (u *Usecase) Execute(ctx context.Context, input Input) (Output, error) {
+	ctx, span := tracing.Start(ctx, "team/getReviewPolicy.Execute")
+	defer span.End()
+
+	if _, err := u.teamFinder.FindTeamByName(ctx, input.TeamName); err != nil {
+		return Output{}, err
+	}
+
+	policy, err := u.finder.GetReviewPolicy(ctx, input.TeamName)
+	if errors.Is(err, domain.ErrReviewPolicyNotFound) {
+		return Output{Policy: u.defaultPolicy.ForTeam(input.TeamName), IsDefault: true}, nil
+	}
+	if err != nil {
+		return Output{}, err
+	}
+	return Output{Policy: policy}, nil
+}
@@ -0,0 +1,15 @@
+package reconcile
+
+import (
+	"context"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+type OrgStore interface {
+	// ListMemberships возвращает всех пользователей с их командами
+	ListMemberships(ctx context.Context) ([]domain.Membership, error)
+	TeamExists(ctx context.Context, teamName string) (bool, error)
+	// ReconcileTeams атомарно сохраняет команды и убирает из команд пользователей removed
+	ReconcileTeams(ctx context.Context, teams []domain.Team, removed []domain.User) error
+}
@@ -0,0 +1,242 @@
+package reconcile
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/internal/usecase/team/bulkImport"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/tracing"
+)
+
+type Input struct {
+	// Data — желаемое состояние в формате импорта команд (YAML или CSV)
+	Data   io.Reader
+	Format bulkImport.Format
+	// DeactivateMissing — выключить пользователей, которых нет в файле
+	DeactivateMissing bool
+	// Apply — применить план; иначе только посчитать его
+	Apply bool
+	// PlanHash — хеш плана, который нужно применить. Если план с тех пор изменился,
+	// возвращается domain.ErrReconcilePlanStale. Пустой — применить текущий план без проверки.
+	PlanHash string
+}
+
+// Action — что синхронизация сделает с пользователем
+type Action string
+
+const (
+	ActionCreated Action = "created"
+	ActionUpdated Action = "updated"
+	ActionMoved   Action = "moved"
+	// ActionRemoved — пользователя нет в файле, он исключается из команды
+	ActionRemoved Action = "removed"
+	// ActionDeactivated — пользователя нет в файле и он не состоит в команде, только выключается
+	ActionDeactivated Action = "deactivated"
+)
+
+// UserChange — изменение одного пользователя
+type UserChange struct {
+	UserID   string
+	Username string
+	// TeamName — команда после синхронизации (пустая для removed и deactivated)
+	TeamName string
+	Action   Action
+	// PreviousTeam — команда до синхронизации (для moved и removed)
+	PreviousTeam string
+	// IsActive — активность после синхронизации
+	IsActive bool
+	// Fields — изменённые поля: username, is_active
+	Fields []string
+}
+
+type Output struct {
+	Applied  bool
+	PlanHash string
+	// TeamsCreated — команды из файла, которых ещё нет
+	TeamsCreated []string
+	// TeamsEmptied — команды, которых нет в файле: они остаются без участников,
+	// но сохраняются вместе с политиками ревью и SLA
+	TeamsEmptied []string
+	Changes      []UserChange
+	Unchanged    int
+}
+
+// Count возвращает число пользователей с указанным действием
+func (o *Output) Count(action Action) int {
+	n := 0
+	for _, c := range o.Changes {
+		if c.Action == action {
+			n++
+		}
+	}
+	return n
+}
+
+type Usecase struct {
+	store OrgStore
+}
+
+func NewUsecase(store OrgStore) (*Usecase, error) {
+	if store == nil {
+		return nil, errors.New("store is required")
+	}
+	return &Usecase{store: store}, nil
+}
+
+// Execute приводит команды к состоянию из файла: пользователи из файла создаются, обновляются
+// и переносятся, остальные исключаются из команд (и выключаются при DeactivateMissing).
+// Без Apply возвращает только план.
+func (u *Usecase) Execute(ctx context.Context, input Input) (*Output, error) {
+	ctx, span := tracing.Start(ctx, "team/reconcile.Execute")
+	defer span.End()
+
+	teams, err := bulkImport.ParseTeams(input.Data, input.Format)
+	if err != nil {
+		return nil, err
+	}
+	memberships, err := u.store.ListMemberships(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	output, removed, err := u.plan(ctx, teams, memberships, input.DeactivateMissing)
+	if err != nil {
+		return nil, err
+	}
+	if !input.Apply {
+		return output, nil
+	}
+
+	if input.PlanHash != "" && input.PlanHash != output.PlanHash {
+		return nil, domain.ErrReconcilePlanStale
+	}
+	if err := u.store.ReconcileTeams(ctx, teams, removed); err != nil {
+		return nil, err
+	}
+	output.Applied = true
+	return output, nil
+}
+
+// plan сравнивает желаемое состояние с текущим. removed — пользователи, которых нужно
+// исключить из команд, с уже выставленной итоговой активностью.
+func (u *Usecase) plan(ctx context.Context, teams []domain.Team, memberships []domain.Membership, deactivateMissing bool) (*Output, []domain.User, error) {
+	current := make(map[string]domain.Membership, len(memberships))
+	teamSizes := make(map[string]int)
+	for _, m := range memberships {
+		current[m.User.ID()] = m
+		if m.TeamName != "" {
+			teamSizes[m.TeamName]++
+		}
+	}
+
+	output := &Output{}
+	desired := make(map[string]bool)
+	desiredTeams := make(map[string]bool, len(teams))
+	for _, team := range teams {
+		desiredTeams[team.Name()] = true
+		exists, err := u.store.TeamExists(ctx, team.Name())
+		if err != nil {
+			return nil, nil, err
+		}
+		if !exists {
+			output.TeamsCreated = append(output.TeamsCreated, team.Name())
+		}
+
+		for _, member := range team.Members() {
+			desired[member.ID()] = true
+			change, changed := diffMember(team.Name(), member, current)
+			if !changed {
+				output.Unchanged++
+				continue
+			}
+			output.Changes = append(output.Changes, change)
+		}
+	}
+
+	var removed []domain.User
+	for _, m := range memberships {
+		if desired[m.User.ID()] {
+			continue
+		}
+		user := m.User
+		change := UserChange{UserID: user.ID(), Username: user.Username(), PreviousTeam: m.TeamName}
+		if deactivateMissing && user.IsActive() {
+			user.SetActive(false)
+			change.Fields = []string{"is_active"}
+		}
+		change.IsActive = user.IsActive()
+		switch {
+		case m.TeamName != "":
+			change.Action = ActionRemoved
+		case len(change.Fields) > 0:
+			change.Action = ActionDeactivated
+		default:
+			output.Unchanged++
+			continue
+		}
+		output.Changes = append(output.Changes, change)
+		removed = append(removed, user)
+	}
+
+	for team, size := range teamSizes {
+		if size > 0 && !desiredTeams[team] {
+			output.TeamsEmptied = append(output.TeamsEmptied, team)
+		}
+	}
+	sort.Strings(output.TeamsEmptied)
+
+	output.PlanHash = planHash(output, deactivateMissing)
+	return output, removed, nil
+}
+
+func diffMember(teamName string, member domain.User, current map[string]domain.Membership) (UserChange, bool) {
+	change := UserChange{
+		UserID:   member.ID(),
+		Username: member.Username(),
+		TeamName: teamName,
+		Action:   ActionCreated,
+		IsActive: member.IsActive(),
+	}
+	existing, ok := current[member.ID()]
+	if !ok {
+		return change, true
+	}
+
+	if existing.User.Username() != member.Username() {
+		change.Fields = append(change.Fields, "username")
+	}
+	if existing.User.IsActive() != member.IsActive() {
+		change.Fields = append(change.Fields, "is_active")
+	}
+	switch {
+	case existing.TeamName != teamName:
+		change.Action = ActionMoved
+		change.PreviousTeam = existing.TeamName
+	case len(change.Fields) > 0:
+		change.Action = ActionUpdated
+	default:
+		return change, false
+	}
+	return change, true
+}
+
+// planHash однозначно описывает план: apply с тем же хешем применит ровно те изменения,
+// что были показаны, даже если в базе с тех пор изменилось что-то несущественное для плана.
+func planHash(output *Output, deactivateMissing bool) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "deactivate_missing=%t\n", deactivateMissing)
+	fmt.Fprintf(h, "teams_created=%s\n", strings.Join(output.TeamsCreated, ","))
+	fmt.Fprintf(h, "teams_emptied=%s\n", strings.Join(output.TeamsEmptied, ","))
+	for _, c := range output.Changes {
+		fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\x00%s\x00%t\x00%s\n",
+			c.Action, c.UserID, c.Username, c.TeamName, c.PreviousTeam, c.IsActive, strings.Join(c.Fields, ","))
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
@@ -0,0 +1,19 @@
+package setReviewPolicy
+
+import (
+	"context"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+type TeamFinder interface {
+	FindTeamByName(ctx context.Context, teamName string) (*domain.Team, error)
+}
+
+type UserFinder interface {
+	GetUserByID(ctx context.Context, id string) (*domain.User, error)
+}
+
+type ReviewPolicySaver interface {
+	SaveReviewPolicy(ctx context.Context, policy *domain.ReviewPolicy) error
+}
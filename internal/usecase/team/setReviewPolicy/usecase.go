@@ -0,0 +1,56 @@
+package setReviewPolicy
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/tracing"
+)
+
+type Input struct {
+	TeamName      string
+	RemindAfter   time.Duration
+	EscalateAfter time.Duration
+	Escalation    domain.EscalationAction
+	// LeadID — лид команды; обязателен для эскалации notify_lead
+	LeadID string
+}
+
+type Usecase struct {
+	teamFinder TeamFinder
+	userFinder UserFinder
+	saver      ReviewPolicySaver
+}
+
+func NewUsecase(teamFinder TeamFinder, userFinder UserFinder, saver ReviewPolicySaver) (*Usecase, error) {
+	if teamFinder == nil || userFinder == nil || saver == nil {
+		return nil, errors.New("all dependencies required")
+	}
+	return &Usecase{teamFinder: teamFinder, userFinder: userFinder, saver: saver}, nil
+}
+
+func (u *Usecase) Execute(ctx context.Context, input Input) (*domain.ReviewPolicy, error) {
+	ctx, span := tracing.Start(ctx, "team/setReviewPolicy.Execute")
+	defer span.End()
+
+	policy, err := domain.NewReviewPolicy(input.TeamName, input.RemindAfter, input.EscalateAfter, input.Escalation, input.LeadID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := u.teamFinder.FindTeamByName(ctx, input.TeamName); err != nil {
+		return nil, err
+	}
+	if input.LeadID != "" {
+		if _, err := u.userFinder.GetUserByID(ctx, input.LeadID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := u.saver.SaveReviewPolicy(ctx, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
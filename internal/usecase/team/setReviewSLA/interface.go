@@ -0,0 +1,15 @@
+package setReviewSLA
+
+import (
+	"context"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+type TeamFinder interface {
+	FindTeamByName(ctx context.Context, teamName string) (*domain.Team, error)
+}
+
+type ReviewSLASaver interface {
+	SaveReviewSLA(ctx context.Context, sla *domain.ReviewSLA) error
+}
@@ -0,0 +1,44 @@
+package setReviewSLA
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/tracing"
+)
+
+type Input struct {
+	TeamName          string
+	FirstReviewWithin time.Duration
+}
+
+type Usecase struct {
+	teamFinder TeamFinder
+	saver      ReviewSLASaver
+}
+
+func NewUsecase(teamFinder TeamFinder, saver ReviewSLASaver) (*Usecase, error) {
+	if teamFinder == nil || saver == nil {
+		return nil, errors.New("teamFinder and saver are required")
+	}
+	return &Usecase{teamFinder: teamFinder, saver: saver}, nil
+}
+
+func (u *Usecase) Execute(ctx context.Context, input Input) (*domain.ReviewSLA, error) {
+	ctx, span := tracing.Start(ctx, "team/setReviewSLA.Execute")
+	defer span.End()
+
+	sla, err := domain.NewReviewSLA(input.TeamName, input.FirstReviewWithin)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := u.teamFinder.FindTeamByName(ctx, input.TeamName); err != nil {
+		return nil, err
+	}
+	if err := u.saver.SaveReviewSLA(ctx, sla); err != nil {
+		return nil, err
+	}
+	return sla, nil
+}
@@ -0,0 +1,16 @@
+package getEvents
+
+import (
+	"context"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+type UserFinder interface {
+	GetUserByID(ctx context.Context, id string) (*domain.User, error)
+}
+
+type EventRepository interface {
+	LatestSequence(ctx context.Context) (int64, error)
+	ListUserEventsAfter(ctx context.Context, userID string, afterSequence int64, limit int) ([]domain.Event, error)
+}
@@ -0,0 +1,91 @@
+package getEvents
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/tracing"
+)
+
+const (
+	defaultLimit = 100
+	maxLimit     = 500
+)
+
+type Input struct {
+	UserID        string
+	AfterSequence int64
+	// FromLatest — начать с конца ленты: вместо истории вернуть только текущий порядковый номер
+	FromLatest bool
+	Limit      int
+}
+
+type Output struct {
+	Events []domain.UserEvent
+	// NextSequence — порядковый номер, с которого продолжать чтение
+	NextSequence int64
+	HasMore      bool
+}
+
+type Usecase struct {
+	eventRepo  EventRepository
+	userFinder UserFinder
+}
+
+func NewUsecase(eventRepo EventRepository, userFinder UserFinder) (*Usecase, error) {
+	if eventRepo == nil || userFinder == nil {
+		return nil, errors.New("dependencies required")
+	}
+	return &Usecase{eventRepo: eventRepo, userFinder: userFinder}, nil
+}
+
+// Execute возвращает события пользователя после AfterSequence (для возобновления по Last-Event-ID)
+// или, с FromLatest, номер, с которого начнётся новая лента
+func (u *Usecase) Execute(ctx context.Context, input Input) (*Output, error) {
+	ctx, span := tracing.Start(ctx, "user/getEvents.Execute")
+	defer span.End()
+
+	if _, err := u.userFinder.GetUserByID(ctx, input.UserID); err != nil {
+		return nil, err
+	}
+
+	if input.FromLatest {
+		latest, err := u.eventRepo.LatestSequence(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &Output{NextSequence: latest}, nil
+	}
+
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	events, err := u.eventRepo.ListUserEventsAfter(ctx, input.UserID, input.AfterSequence, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &Output{
+		NextSequence: input.AfterSequence,
+		HasMore:      len(events) == limit,
+	}
+	for _, event := range events {
+		userEvents, err := domain.UserEventsFromEvent(event)
+		if err != nil {
+			return nil, err
+		}
+		for _, ue := range userEvents {
+			if ue.UserID == input.UserID {
+				output.Events = append(output.Events, ue)
+			}
+		}
+		output.NextSequence = event.Sequence
+	}
+	return output, nil
+}
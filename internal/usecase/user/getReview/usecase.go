@@ -5,6 +5,7 @@ import (
 	"errors"
 
 	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/tracing"
 )
 
 type Input struct {
@@ -29,6 +30,9 @@ func NewUsecase(prRepo PullRequestRepository, userFinder UserFinder) (*Usecase,
 }
 
 func (u *Usecase) Execute(ctx context.Context, input Input) (*Output, error) {
+	ctx, span := tracing.Start(ctx, "user/getReview.Execute")
+	defer span.End()
+
 	_, err := u.userFinder.GetUserByID(ctx, input.UserID)
 	if err != nil {
 		return nil, err
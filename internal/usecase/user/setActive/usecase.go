@@ -5,6 +5,7 @@ import (
 	"errors"
 
 	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/tracing"
 )
 
 type Input struct {
@@ -30,6 +31,9 @@ func NewUsecase(userFinder UserFinder, userUpdater UserUpdater) (*Usecase, error
 }
 
 func (u *Usecase) Execute(ctx context.Context, input Input) (*Output, error) {
+	ctx, span := tracing.Start(ctx, "user/setActive.Execute")
+	defer span.End()
+
 	user, err := u.userFinder.GetUserByID(ctx, input.UserID)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,15 @@
+package setEmail
+
+import (
+	"context"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+type UserFinder interface {
+	GetUserByID(ctx context.Context, id string) (*domain.User, error)
+}
+
+type UserEmailUpdater interface {
+	UpdateUserEmail(ctx context.Context, user *domain.User) error
+}
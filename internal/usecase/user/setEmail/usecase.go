@@ -0,0 +1,48 @@
+package setEmail
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/tracing"
+)
+
+type Input struct {
+	UserID string
+	// Email — пустая строка отключает почтовые уведомления
+	Email  string
+	Digest bool
+}
+
+type Usecase struct {
+	userFinder  UserFinder
+	userUpdater UserEmailUpdater
+}
+
+func NewUsecase(userFinder UserFinder, userUpdater UserEmailUpdater) (*Usecase, error) {
+	if userFinder == nil || userUpdater == nil {
+		return nil, errors.New("userFinder and userUpdater are required")
+	}
+	return &Usecase{userFinder: userFinder, userUpdater: userUpdater}, nil
+}
+
+func (u *Usecase) Execute(ctx context.Context, input Input) (*domain.User, error) {
+	ctx, span := tracing.Start(ctx, "user/setEmail.Execute")
+	defer span.End()
+
+	user, err := u.userFinder.GetUserByID(ctx, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := user.SetEmail(input.Email); err != nil {
+		return nil, err
+	}
+	user.SetEmailDigest(input.Digest)
+
+	if err := u.userUpdater.UpdateUserEmail(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
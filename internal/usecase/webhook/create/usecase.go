@@ -0,0 +1,38 @@
+package create
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/tracing"
+)
+
+type Input struct {
+	URL        string
+	Secret     string
+	EventTypes []domain.EventType
+}
+
+type Usecase struct {
+	saver SubscriptionSaver
+}
+
+func NewUsecase(saver SubscriptionSaver) (*Usecase, error) {
+	if saver == nil {
+		return nil, errors.New("saver is required")
+	}
+	return &Usecase{saver: saver}, nil
+}
+
+// Execute создаёт подписку на события PR.
+func (u *Usecase) Execute(ctx context.Context, input Input) (*domain.WebhookSubscription, error) {
+	ctx, span := tracing.Start(ctx, "webhook/create.Execute")
+	defer span.End()
+
+	sub, err := domain.NewWebhookSubscription(input.URL, input.Secret, input.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+	return u.saver.CreateSubscription(ctx, sub)
+}
@@ -0,0 +1,7 @@
+package delete
+
+import "context"
+
+type SubscriptionDeleter interface {
+	DeleteSubscription(ctx context.Context, id int64) error
+}
@@ -0,0 +1,29 @@
+package delete
+
+import (
+	"context"
+	"errors"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/tracing"
+)
+
+type Input struct {
+	SubscriptionID int64
+}
+
+type Usecase struct {
+	deleter SubscriptionDeleter
+}
+
+func NewUsecase(deleter SubscriptionDeleter) (*Usecase, error) {
+	if deleter == nil {
+		return nil, errors.New("deleter is required")
+	}
+	return &Usecase{deleter: deleter}, nil
+}
+
+func (u *Usecase) Execute(ctx context.Context, input Input) error {
+	ctx, span := tracing.Start(ctx, "webhook/delete.Execute")
+	defer span.End()
+
+	return u.deleter.DeleteSubscription(ctx, input.SubscriptionID)
+}
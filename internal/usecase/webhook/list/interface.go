@@ -0,0 +1,11 @@
+package list
+
+import (
+	"context"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+)
+
+type SubscriptionLister interface {
+	ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error)
+}
@@ -0,0 +1,27 @@
+package list
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/tracing"
+)
+
+type Usecase struct {
+	lister SubscriptionLister
+}
+
+func NewUsecase(lister SubscriptionLister) (*Usecase, error) {
+	if lister == nil {
+		return nil, errors.New("lister is required")
+	}
+	return &Usecase{lister: lister}, nil
+}
+
+func (u *Usecase) Execute(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	ctx, span := tracing.Start(ctx, "webhook/list.Execute")
+	defer span.End()
+
+	return u.lister.ListSubscriptions(ctx)
+}
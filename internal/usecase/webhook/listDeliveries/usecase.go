@@ -0,0 +1,45 @@
+package listDeliveries
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Skorpsrgvch/reviewer-service/internal/domain"
+	"github.com/Skorpsrgvch/reviewer-service/pkg/tracing"
+)
+
+const (
+	defaultLimit = 50
+	maxLimit     = 500
+)
+
+type Input struct {
+	SubscriptionID int64
+	Limit          int
+}
+
+type Usecase struct {
+	lister DeliveryLister
+}
+
+func NewUsecase(lister DeliveryLister) (*Usecase, error) {
+	if lister == nil {
+		return nil, errors.New("lister is required")
+	}
+	return &Usecase{lister: lister}, nil
+}
+
+// Execute возвращает журнал доставок подписки, начиная с последних.
+func (u *Usecase) Execute(ctx context.Context, input Input) ([]domain.WebhookDelivery, error) {
+	ctx, span := tracing.Start(ctx, "webhook/listDeliveries.Execute")
+	defer span.End()
+
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+	return u.lister.ListDeliveries(ctx, input.SubscriptionID, limit)
+}
@@ -0,0 +1,6 @@
+ALTER TABLE outbox ADD COLUMN attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE outbox ADD COLUMN next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW();
+ALTER TABLE outbox ADD COLUMN last_error TEXT;
+
+DROP INDEX IF EXISTS idx_outbox_unpublished;
+CREATE INDEX idx_outbox_unpublished ON outbox(next_attempt_at, id) WHERE published_at IS NULL;
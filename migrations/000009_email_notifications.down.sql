@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS email_digest_items;
+
+DELETE FROM notifications WHERE event_id IS NULL;
+ALTER TABLE notifications ALTER COLUMN event_id SET NOT NULL;
+ALTER TABLE notifications DROP COLUMN IF EXISTS html_body;
+
+ALTER TABLE users DROP COLUMN IF EXISTS email_digest;
+ALTER TABLE users DROP COLUMN IF EXISTS email;
@@ -0,0 +1,19 @@
+ALTER TABLE users ADD COLUMN email TEXT;
+ALTER TABLE users ADD COLUMN email_digest BOOLEAN NOT NULL DEFAULT false;
+
+-- HTML-версия письма; для Slack пустая. Дайджест не привязан к одному событию
+ALTER TABLE notifications ADD COLUMN html_body TEXT NOT NULL DEFAULT '';
+ALTER TABLE notifications ALTER COLUMN event_id DROP NOT NULL;
+
+-- События, отложенные до ежедневного дайджеста
+CREATE TABLE email_digest_items (
+    id BIGSERIAL PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event_id BIGINT NOT NULL REFERENCES outbox(id) ON DELETE CASCADE,
+    summary TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    sent_at TIMESTAMP,
+    UNIQUE (user_id, event_id)
+);
+
+CREATE INDEX idx_email_digest_items_pending ON email_digest_items(user_id) WHERE sent_at IS NULL;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_pr_open_created;
+DROP TABLE IF EXISTS stale_pr_actions;
+DROP TABLE IF EXISTS team_review_policies;
@@ -0,0 +1,19 @@
+-- Правила команд для зависших OPEN PR: когда напомнить ревьюерам и когда эскалировать
+CREATE TABLE team_review_policies (
+    team_name TEXT PRIMARY KEY REFERENCES teams(name) ON DELETE CASCADE,
+    remind_after_seconds BIGINT NOT NULL CHECK (remind_after_seconds > 0),
+    escalate_after_seconds BIGINT NOT NULL CHECK (escalate_after_seconds > remind_after_seconds),
+    escalation TEXT NOT NULL CHECK (escalation IN ('notify_lead', 'reassign')),
+    lead_user_id TEXT REFERENCES users(id) ON DELETE SET NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+-- Выполненные этапы по PR: каждый этап срабатывает не больше одного раза
+CREATE TABLE stale_pr_actions (
+    pull_request_id TEXT NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+    stage TEXT NOT NULL CHECK (stage IN ('REMINDED', 'ESCALATED')),
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (pull_request_id, stage)
+);
+
+CREATE INDEX idx_pr_open_created ON pull_requests(created_at) WHERE status = 'OPEN';
@@ -0,0 +1,29 @@
+-- SLA команды на первое действие ревьюера после назначения
+CREATE TABLE team_review_slas (
+    team_name TEXT PRIMARY KEY REFERENCES teams(name) ON DELETE CASCADE,
+    first_review_seconds BIGINT NOT NULL CHECK (first_review_seconds > 0),
+    updated_at TIMESTAMP NOT NULL
+);
+
+-- История назначений ревьюеров: когда назначен, когда впервые отреагировал, когда снят
+CREATE TABLE review_assignments (
+    id BIGSERIAL PRIMARY KEY,
+    pull_request_id TEXT NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+    reviewer_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    assigned_at TIMESTAMP NOT NULL,
+    first_action_at TIMESTAMP,
+    unassigned_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX ux_review_assignments_current
+    ON review_assignments(pull_request_id, reviewer_id) WHERE unassigned_at IS NULL;
+CREATE INDEX idx_review_assignments_assigned_at ON review_assignments(assigned_at);
+CREATE INDEX idx_review_assignments_waiting
+    ON review_assignments(assigned_at) WHERE first_action_at IS NULL AND unassigned_at IS NULL;
+
+-- Текущие назначения существующих PR считаем сделанными при создании PR
+INSERT INTO review_assignments (pull_request_id, reviewer_id, assigned_at)
+SELECT DISTINCT pr.id, r.reviewer_id, pr.created_at
+FROM pull_requests pr
+CROSS JOIN LATERAL unnest(pr.assigned_reviewers) AS r(reviewer_id)
+WHERE EXISTS (SELECT 1 FROM users u WHERE u.id = r.reviewer_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_review_assignments_unassigned;
+DROP INDEX IF EXISTS idx_review_assignments_first_action;
+DROP INDEX IF EXISTS idx_team_members_user;
+DROP INDEX IF EXISTS idx_pr_merged_at;
+DROP INDEX IF EXISTS idx_pr_created_at;
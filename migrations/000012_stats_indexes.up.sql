@@ -0,0 +1,7 @@
+-- Индексы для статистики по периодам и командам
+CREATE INDEX idx_pr_created_at ON pull_requests(created_at);
+CREATE INDEX idx_pr_merged_at ON pull_requests(merged_at) WHERE merged_at IS NOT NULL;
+-- Поиск команды автора: первичный ключ team_members начинается с team_name
+CREATE INDEX idx_team_members_user ON team_members(user_id);
+CREATE INDEX idx_review_assignments_first_action ON review_assignments(first_action_at) WHERE first_action_at IS NOT NULL;
+CREATE INDEX idx_review_assignments_unassigned ON review_assignments(unassigned_at) WHERE unassigned_at IS NOT NULL;
@@ -0,0 +1,22 @@
+-- Получатели событий PR для ленты пользователя (SSE): фильтр по JSON в payload индексом не покрыть
+CREATE TABLE outbox_users (
+    user_id TEXT NOT NULL,
+    event_id BIGINT NOT NULL REFERENCES outbox(id) ON DELETE CASCADE,
+    PRIMARY KEY (user_id, event_id)
+);
+
+-- Повторяет domain.UserEventsFromEvent: после down и повторного up в outbox могут быть события любых типов
+INSERT INTO outbox_users (user_id, event_id)
+SELECT DISTINCT user_id, event_id
+FROM (
+    SELECT r.user_id, o.id AS event_id
+    FROM outbox o, jsonb_array_elements_text(o.payload -> 'assigned_reviewers') AS r(user_id)
+    WHERE o.event_type IN ('pull_request.created', 'pull_request.merged', 'pull_request.closed', 'pull_request.reopened')
+    UNION ALL
+    SELECT o.payload ->> 'old_reviewer_id', o.id FROM outbox o WHERE o.event_type = 'pull_request.reviewer_reassigned'
+    UNION ALL
+    SELECT o.payload ->> 'new_reviewer_id', o.id FROM outbox o WHERE o.event_type = 'pull_request.reviewer_reassigned'
+    UNION ALL
+    SELECT o.payload ->> 'author_id', o.id FROM outbox o WHERE o.event_type IN ('pull_request.merged', 'pull_request.closed', 'pull_request.reopened')
+) AS recipients
+WHERE user_id <> '';
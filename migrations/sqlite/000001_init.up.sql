@@ -0,0 +1,42 @@
+CREATE TABLE teams (
+    name TEXT PRIMARY KEY
+);
+
+CREATE TABLE users (
+    id TEXT PRIMARY KEY,
+    username TEXT NOT NULL,
+    is_active BOOLEAN NOT NULL
+);
+
+CREATE TABLE team_members (
+    team_name TEXT NOT NULL REFERENCES teams(name) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    PRIMARY KEY (team_name, user_id)
+);
+
+-- Время хранится текстом в UTC с микросекундами фиксированной ширины ('2006-01-02 15:04:05.000000'),
+-- поэтому сравнение строк совпадает с хронологическим
+CREATE TABLE pull_requests (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    author_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status TEXT NOT NULL CHECK (status IN ('OPEN', 'MERGED')),
+    created_at TIMESTAMP NOT NULL,
+    merged_at TIMESTAMP
+);
+
+-- Массивов в SQLite нет: назначенные ревьюеры PR хранятся отдельной таблицей, position — их порядок
+CREATE TABLE pull_request_reviewers (
+    pull_request_id TEXT NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+    reviewer_id TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (pull_request_id, reviewer_id)
+);
+
+-- Индексы
+CREATE INDEX idx_users_active ON users(is_active);
+CREATE INDEX idx_team_members_team ON team_members(team_name);
+CREATE INDEX idx_pr_author ON pull_requests(author_id);
+CREATE INDEX idx_pr_status ON pull_requests(status);
+-- Замена GIN-индекса по assigned_reviewers: поиск PR ревьюера
+CREATE INDEX idx_pr_reviewers ON pull_request_reviewers(reviewer_id);
@@ -0,0 +1,12 @@
+CREATE TABLE idempotency_keys (
+    key TEXT NOT NULL,
+    route TEXT NOT NULL,
+    request_hash TEXT NOT NULL,
+    status_code INTEGER,
+    response_body BLOB,
+    created_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (key, route)
+);
+
+CREATE INDEX idx_idempotency_keys_expires ON idempotency_keys(expires_at);
@@ -0,0 +1,8 @@
+-- ADD COLUMN в SQLite не принимает NOW(): существующие события получают время в прошлом,
+-- то есть, как и в Postgres, готовы к отправке сразу
+ALTER TABLE outbox ADD COLUMN attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE outbox ADD COLUMN next_attempt_at TIMESTAMP NOT NULL DEFAULT '1970-01-01 00:00:00.000000';
+ALTER TABLE outbox ADD COLUMN last_error TEXT;
+
+DROP INDEX IF EXISTS idx_outbox_unpublished;
+CREATE INDEX idx_outbox_unpublished ON outbox(next_attempt_at, id) WHERE published_at IS NULL;
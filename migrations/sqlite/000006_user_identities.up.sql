@@ -0,0 +1,10 @@
+-- Сопоставление учёток внешних систем (GitHub, GitLab, ...) с пользователями сервиса
+CREATE TABLE user_identities (
+    provider TEXT NOT NULL,
+    external_id TEXT NOT NULL,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000', 'now')),
+    PRIMARY KEY (provider, external_id)
+);
+
+CREATE INDEX idx_user_identities_user ON user_identities(user_id);
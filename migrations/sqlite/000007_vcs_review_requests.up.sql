@@ -0,0 +1,19 @@
+-- Запросы ревью в VCS (GitHub requested_reviewers и т.п.), отправляемые асинхронно после назначения.
+-- reviewer_ids — JSON-массив
+CREATE TABLE vcs_review_requests (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    event_id INTEGER NOT NULL REFERENCES outbox(id) ON DELETE CASCADE,
+    pull_request_id TEXT NOT NULL,
+    action TEXT NOT NULL CHECK (action IN ('REQUEST', 'REMOVE')),
+    reviewer_ids TEXT NOT NULL CHECK (json_valid(reviewer_ids)),
+    status TEXT NOT NULL CHECK (status IN ('PENDING', 'DONE', 'FAILED')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL,
+    last_error TEXT,
+    created_at TIMESTAMP NOT NULL,
+    completed_at TIMESTAMP,
+    UNIQUE (event_id, action)
+);
+
+CREATE INDEX idx_vcs_review_requests_due ON vcs_review_requests(next_attempt_at) WHERE status = 'PENDING';
+CREATE INDEX idx_vcs_review_requests_pr ON vcs_review_requests(pull_request_id);
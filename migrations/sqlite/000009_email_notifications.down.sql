@@ -0,0 +1,29 @@
+DROP TABLE IF EXISTS email_digest_items;
+
+CREATE TABLE notifications_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    event_id INTEGER NOT NULL REFERENCES outbox(id) ON DELETE CASCADE,
+    channel TEXT NOT NULL,
+    target TEXT NOT NULL,
+    subject TEXT NOT NULL DEFAULT '',
+    body TEXT NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('PENDING', 'SENT', 'FAILED')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL,
+    last_error TEXT,
+    created_at TIMESTAMP NOT NULL,
+    sent_at TIMESTAMP,
+    UNIQUE (event_id, channel, target)
+);
+INSERT INTO notifications_old (id, event_id, channel, target, subject, body, status, attempts,
+                               next_attempt_at, last_error, created_at, sent_at)
+SELECT id, event_id, channel, target, subject, body, status, attempts,
+       next_attempt_at, last_error, created_at, sent_at
+FROM notifications
+WHERE event_id IS NOT NULL;
+DROP TABLE notifications;
+ALTER TABLE notifications_old RENAME TO notifications;
+CREATE INDEX idx_notifications_due ON notifications(next_attempt_at) WHERE status = 'PENDING';
+
+ALTER TABLE users DROP COLUMN email_digest;
+ALTER TABLE users DROP COLUMN email;
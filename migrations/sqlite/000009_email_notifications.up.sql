@@ -0,0 +1,42 @@
+ALTER TABLE users ADD COLUMN email TEXT;
+ALTER TABLE users ADD COLUMN email_digest BOOLEAN NOT NULL DEFAULT false;
+
+-- HTML-версия письма; для Slack пустая. Дайджест не привязан к одному событию.
+-- SQLite не умеет снимать NOT NULL с колонки, поэтому таблица пересоздаётся
+CREATE TABLE notifications_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    event_id INTEGER REFERENCES outbox(id) ON DELETE CASCADE,
+    channel TEXT NOT NULL,
+    target TEXT NOT NULL,
+    subject TEXT NOT NULL DEFAULT '',
+    body TEXT NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('PENDING', 'SENT', 'FAILED')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL,
+    last_error TEXT,
+    created_at TIMESTAMP NOT NULL,
+    sent_at TIMESTAMP,
+    html_body TEXT NOT NULL DEFAULT '',
+    UNIQUE (event_id, channel, target)
+);
+INSERT INTO notifications_new (id, event_id, channel, target, subject, body, status, attempts,
+                               next_attempt_at, last_error, created_at, sent_at)
+SELECT id, event_id, channel, target, subject, body, status, attempts,
+       next_attempt_at, last_error, created_at, sent_at
+FROM notifications;
+DROP TABLE notifications;
+ALTER TABLE notifications_new RENAME TO notifications;
+CREATE INDEX idx_notifications_due ON notifications(next_attempt_at) WHERE status = 'PENDING';
+
+-- События, отложенные до ежедневного дайджеста
+CREATE TABLE email_digest_items (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event_id INTEGER NOT NULL REFERENCES outbox(id) ON DELETE CASCADE,
+    summary TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    sent_at TIMESTAMP,
+    UNIQUE (user_id, event_id)
+);
+
+CREATE INDEX idx_email_digest_items_pending ON email_digest_items(user_id) WHERE sent_at IS NULL;
@@ -0,0 +1,21 @@
+UPDATE pull_requests SET status = 'OPEN' WHERE status = 'CLOSED';
+
+CREATE TABLE pull_requests_new (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    author_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status TEXT NOT NULL CHECK (status IN ('OPEN', 'MERGED')),
+    created_at TIMESTAMP NOT NULL,
+    merged_at TIMESTAMP,
+    version INTEGER NOT NULL DEFAULT 1
+);
+INSERT INTO pull_requests_new (id, name, author_id, status, created_at, merged_at, version)
+SELECT id, name, author_id, status, created_at, merged_at, version
+FROM pull_requests;
+DROP TABLE pull_requests;
+ALTER TABLE pull_requests_new RENAME TO pull_requests;
+CREATE INDEX idx_pr_author ON pull_requests(author_id);
+CREATE INDEX idx_pr_status ON pull_requests(status);
+CREATE INDEX idx_pr_open_created ON pull_requests(created_at) WHERE status = 'OPEN';
+CREATE INDEX idx_pr_created_at ON pull_requests(created_at);
+CREATE INDEX idx_pr_merged_at ON pull_requests(merged_at) WHERE merged_at IS NOT NULL;
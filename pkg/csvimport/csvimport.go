@@ -0,0 +1,88 @@
+// Package csvimport — чтение CSV для массовых импортов: колонки по заголовку в любом порядке,
+// ошибки с номером строки файла, чтобы импорт мог собрать их все и вернуть одним ответом.
+package csvimport
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// LineError — ошибка в конкретной строке CSV (нумерация с 1, заголовок — строка 1)
+type LineError struct {
+	Line    int
+	Message string
+}
+
+func (e *LineError) Error() string { return fmt.Sprintf("line %d: %s", e.Line, e.Message) }
+
+// Row — прочитанная строка данных
+type Row struct {
+	Line    int
+	record  []string
+	columns map[string]int
+}
+
+// Field возвращает значение колонки name или "", если такой колонки в файле нет
+func (r Row) Field(name string) string {
+	i, ok := r.columns[name]
+	if !ok {
+		return ""
+	}
+	return r.record[i]
+}
+
+// Reader читает строки CSV после заголовка
+type Reader struct {
+	r       *csv.Reader
+	columns map[string]int
+}
+
+// NewReader читает заголовок и проверяет, что в нём есть все колонки required.
+// Имена колонок сравниваются без учёта регистра и пробелов по краям.
+func NewReader(r io.Reader, required ...string) (*Reader, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if !errors.Is(err, io.EOF) && !errors.As(err, &parseErr) {
+			return nil, err
+		}
+		return nil, &LineError{Line: 1, Message: "missing header"}
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range required {
+		if _, ok := columns[name]; !ok {
+			return nil, &LineError{Line: 1, Message: fmt.Sprintf("missing column %q", name)}
+		}
+	}
+	return &Reader{r: cr, columns: columns}, nil
+}
+
+// Next возвращает следующую строку или io.EOF в конце файла.
+// Неразборчивая строка возвращается как *LineError, после неё чтение можно продолжить.
+// Любая другая ошибка — это ошибка чтения источника (например, превышен размер тела запроса):
+// она возвращается как есть и повторяется при каждом вызове, поэтому чтение нужно прервать.
+func (r *Reader) Next() (Row, error) {
+	record, err := r.r.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return Row{}, io.EOF
+		}
+		// После ошибки позиции полей не заполнены, номер строки есть только в ParseError
+		var parseErr *csv.ParseError
+		if !errors.As(err, &parseErr) {
+			return Row{}, err
+		}
+		return Row{}, &LineError{Line: parseErr.Line, Message: err.Error()}
+	}
+	line, _ := r.r.FieldPos(0)
+	return Row{Line: line, record: record, columns: r.columns}, nil
+}
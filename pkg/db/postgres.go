@@ -3,20 +3,35 @@ package db
 import (
 	"context"
 	"database/sql"
+	"net/url"
+	"strings"
 	"time"
 
 	_ "github.com/lib/pq"
+	"github.com/uptrace/opentelemetry-go-extra/otelsql"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 )
 
-func NewPostgresDB(ctx context.Context, dsn string) (*sql.DB, error) {
-	db, err := sql.Open("postgres", dsn)
+// PoolConfig — параметры пула соединений
+type PoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+}
+
+func NewPostgresDB(ctx context.Context, dsn string, pool PoolConfig) (*sql.DB, error) {
+	// Каждый запрос к БД — отдельный спан с текстом SQL (db.statement)
+	db, err := otelsql.Open("postgres", dsn,
+		otelsql.WithAttributes(semconv.DBSystemPostgreSQL),
+		otelsql.WithDBName(dbName(dsn)),
+	)
 	if err != nil {
 		return nil, err
 	}
 
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(25)
-	db.SetConnMaxLifetime(5 * time.Minute)
+	db.SetMaxOpenConns(pool.MaxOpenConns)
+	db.SetMaxIdleConns(pool.MaxIdleConns)
+	db.SetConnMaxLifetime(pool.ConnMaxLifetime)
 
 	if err := db.PingContext(ctx); err != nil {
 		db.Close()
@@ -25,3 +40,12 @@ func NewPostgresDB(ctx context.Context, dsn string) (*sql.DB, error) {
 
 	return db, nil
 }
+
+// dbName достаёт имя базы из URL подключения для атрибута db.name
+func dbName(dsn string) string {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(u.Path, "/")
+}
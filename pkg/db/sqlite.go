@@ -0,0 +1,51 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/uptrace/opentelemetry-go-extra/otelsql"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// sqliteDefaults — параметры подключения go-sqlite3, которые сервис ожидает от базы:
+// внешние ключи (каскадное удаление), WAL для параллельного чтения, ожидание занятой базы
+// вместо ошибки и BEGIN IMMEDIATE, чтобы транзакции записи не конфликтовали при повышении блокировки
+var sqliteDefaults = map[string]string{
+	"_foreign_keys": "on",
+	"_journal_mode": "WAL",
+	"_busy_timeout": "5000",
+	"_txlock":       "immediate",
+}
+
+// NewSQLiteDB открывает файл базы SQLite. params дополняют и переопределяют параметры по умолчанию.
+func NewSQLiteDB(ctx context.Context, path string, params url.Values, pool PoolConfig) (*sql.DB, error) {
+	query := url.Values{}
+	for k, v := range sqliteDefaults {
+		query.Set(k, v)
+	}
+	for k, v := range params {
+		query[k] = v
+	}
+
+	db, err := otelsql.Open("sqlite3", path+"?"+query.Encode(),
+		otelsql.WithAttributes(semconv.DBSystemSqlite),
+		otelsql.WithDBName(path),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	db.SetMaxOpenConns(pool.MaxOpenConns)
+	db.SetMaxIdleConns(pool.MaxIdleConns)
+	db.SetConnMaxLifetime(pool.ConnMaxLifetime)
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
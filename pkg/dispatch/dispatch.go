@@ -0,0 +1,115 @@
+// Package dispatch — общий цикл фоновых диспетчеров очередей доставки (вебхуки, запросы ревью в VCS,
+// уведомления): захват пачки на время аренды, обработка по одной, повторы с экспоненциальной задержкой.
+package dispatch
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Skorpsrgvch/reviewer-service/pkg/logger"
+)
+
+// PermanentError — ошибка, которую бесполезно повторять (неверный адрес, нет доступа и т.п.):
+// задача сразу переводится в FAILED
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string { return e.Err.Error() }
+func (e *PermanentError) Unwrap() error { return e.Err }
+
+// Permanent помечает ошибку как постоянную
+func Permanent(err error) error {
+	return &PermanentError{Err: err}
+}
+
+// IsPermanent сообщает, помечена ли ошибка как постоянная
+func IsPermanent(err error) bool {
+	var permanent *PermanentError
+	return errors.As(err, &permanent)
+}
+
+// Backoff — экспоненциальная задержка: Base перед второй попыткой, дальше удваивается, но не больше Max
+type Backoff struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+// Delay возвращает задержку после попытки attempt (начиная с 1)
+func (b Backoff) Delay(attempt int) time.Duration {
+	delay := b.Base
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= b.Max {
+			return b.Max
+		}
+	}
+	return delay
+}
+
+// Config — параметры диспетчера
+type Config struct {
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	// Timeout — ограничение на одну попытку
+	Timeout time.Duration
+}
+
+// Backoff возвращает задержку между попытками
+func (c Config) Backoff() Backoff {
+	return Backoff{Base: c.BaseBackoff, Max: c.MaxBackoff}
+}
+
+// Lease — время аренды пачки
+func (c Config) Lease() time.Duration {
+	return Lease(c.Timeout, c.BatchSize)
+}
+
+// Lease возвращает время аренды пачки из batchSize задач по timeout на каждую.
+// Аренда должна пережить все попытки пачки, иначе задачу захватит другая реплика.
+func Lease(timeout time.Duration, batchSize int) time.Duration {
+	return timeout*time.Duration(batchSize) + time.Minute
+}
+
+// NextAttempt возвращает время следующей попытки после неудачной попытки attempt
+// или nil, если ошибка постоянная или попытки исчерпаны
+func (c Config) NextAttempt(attempt int, err error) *time.Time {
+	if IsPermanent(err) || attempt >= c.MaxAttempts {
+		return nil
+	}
+	t := time.Now().UTC().Add(c.Backoff().Delay(attempt))
+	return &t
+}
+
+// Run раз в cfg.PollInterval захватывает через claim до cfg.BatchSize задач на время аренды
+// и передаёт их в process по одной, пока контекст не отменён. name — что захватывается, для логов.
+func Run[T any](ctx context.Context, cfg Config, name string,
+	claim func(ctx context.Context, limit int, lease time.Duration) ([]T, error),
+	process func(ctx context.Context, item T),
+) {
+	ticker := time.NewTicker(cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		items, err := claim(ctx, cfg.BatchSize, cfg.Lease())
+		if err != nil && ctx.Err() == nil {
+			logger.FromContext(ctx).Error("failed to claim "+name, "error", err)
+		}
+		for _, item := range items {
+			if ctx.Err() != nil {
+				return
+			}
+			process(ctx, item)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
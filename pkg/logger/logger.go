@@ -0,0 +1,58 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+)
+
+type ctxKey struct{}
+
+// New создаёт JSON-логгер с минимальным уровнем level
+func New(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level}))
+}
+
+// ParseLevel разбирает уровень логирования: debug, info, warn, error
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	err := level.UnmarshalText([]byte(s))
+	return level, err
+}
+
+// WithContext кладёт логгер в контекст
+func WithContext(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// FromContext возвращает логгер из контекста (с request_id и т.п.) или логгер по умолчанию
+func FromContext(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(ctxKey{}).(*slog.Logger); ok {
+		return l
+	}
+	return slog.Default()
+}
+
+// ErrorChain разворачивает цепочку обёрнутых ошибок, от внешней к исходной.
+// Для errors.Join и fmt.Errorf с несколькими %w обходит все ветви.
+func ErrorChain(err error) []string {
+	var chain []string
+	var walk func(err error)
+	walk = func(err error) {
+		for err != nil {
+			chain = append(chain, err.Error())
+			switch e := err.(type) {
+			case interface{ Unwrap() []error }:
+				for _, inner := range e.Unwrap() {
+					walk(inner)
+				}
+				return
+			default:
+				err = errors.Unwrap(err)
+			}
+		}
+	}
+	walk(err)
+	return chain
+}